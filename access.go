@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"stock-bot/models"
+	"stock-bot/services"
+
+	"github.com/google/uuid"
+)
+
+// requireAdmin wraps a command handler so only admin users can run it
+func (h *commandHandlers) requireAdmin(next services.CommandHandler) services.CommandHandler {
+	return func(ctx context.Context, cmd services.BotCommand) error {
+		if !h.isAdmin(cmd.UserID) {
+			slog.Info("Denied admin command to non-admin user", "command", cmd.Name, "user", cmd.UserID)
+			return h.bot.Reply(ctx, cmd.ChatID, locale.Text("⛔ This command is only available to admins."))
+		}
+		return next(ctx, cmd)
+	}
+}
+
+// requireChatDelivery wraps a command handler that changes what a chat is sent, which only Telegram chats can
+// set; Line chats receive the channel broadcast
+func (h *commandHandlers) requireChatDelivery(next services.CommandHandler) services.CommandHandler {
+	return func(ctx context.Context, cmd services.BotCommand) error {
+		if cmd.Channel == models.ChannelLine {
+			return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Line chats receive every report and alert of the channel, so this command is only available on Telegram."))
+		}
+		return next(ctx, cmd)
+	}
+}
+
+// isAdmin reports whether a Telegram user is configured as an admin or holds the admin role
+func (h *commandHandlers) isAdmin(userID string) bool {
+	// Read on every check so an admin removed from the config file loses the rights right away
+	if slices.Contains(currentConfig().AdminUserIDs, userID) {
+		return true
+	}
+
+	role, err := h.db.GetRole(userID)
+	if err != nil {
+		slog.Error("Error loading role", "user", userID, "error", err)
+		return false
+	}
+	return role == models.RoleAdmin
+}
+
+// handleGrant assigns a role to a user
+func (h *commandHandlers) handleGrant(ctx context.Context, cmd services.BotCommand) error {
+	if len(cmd.Args) != 2 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /grant USER_ID admin|subscriber"))
+	}
+
+	userID, role := cmd.Args[0], strings.ToLower(cmd.Args[1])
+	if role != models.RoleAdmin && role != models.RoleSubscriber {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Role must be admin or subscriber"))
+	}
+
+	if err := h.db.SetRole(userID, role); err != nil {
+		return fmt.Errorf("could not save role: %w", err)
+	}
+	return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("✅ User %s is now %s", userID, role))
+}
+
+// handlePause suspends scheduled reports and alerts
+func (h *commandHandlers) handlePause(ctx context.Context, cmd services.BotCommand) error {
+	schedulerPaused.Store(true)
+	slog.Info("Scheduler paused", "user", cmd.UserID)
+	return h.bot.Reply(ctx, cmd.ChatID, locale.Text("⏸ Scheduled reports and alerts are paused. Send /resume to continue."))
+}
+
+// handleResume resumes scheduled reports and alerts, or fetching of a paused symbol
+func (h *commandHandlers) handleResume(ctx context.Context, cmd services.BotCommand) error {
+	if len(cmd.Args) > 0 {
+		symbol, ok := h.resolveSymbol(ctx, cmd.Args[0])
+		if !ok {
+			return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /resume [SYMBOL]"))
+		}
+		resumed, err := symbolHealth.resume(symbol)
+		if err != nil {
+			return err
+		}
+		if !resumed {
+			return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("%s is not paused.", symbol))
+		}
+		slog.Info("Fetching resumed", "symbol", symbol, "user", cmd.UserID)
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("▶️ Fetching %s again.", symbol))
+	}
+
+	schedulerPaused.Store(false)
+	slog.Info("Scheduler resumed", "user", cmd.UserID)
+	return h.bot.Reply(ctx, cmd.ChatID, locale.Text("▶️ Scheduled reports and alerts resumed."))
+}
+
+// handleAnnounce stages an announcement to all subscribed chats and asks for confirmation
+func (h *commandHandlers) handleAnnounce(ctx context.Context, cmd services.BotCommand) error {
+	text := strings.TrimSpace(strings.TrimPrefix(cmd.Text, strings.Fields(cmd.Text)[0]))
+	if text == "" {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /announce MESSAGE"))
+	}
+
+	h.announcementsMu.Lock()
+	h.announcements[cmd.ChatID] = text
+	h.announcementsMu.Unlock()
+
+	recipients := h.delivery.RecipientCount(models.KindAnnouncement)
+	return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("📣 Preview:\n\n%s\n\nSend to %d chat(s)? Reply /confirm to send or /cancel to discard.", text, recipients))
+}
+
+// handleConfirm sends the staged announcement and reports delivery counts
+func (h *commandHandlers) handleConfirm(ctx context.Context, cmd services.BotCommand) error {
+	h.announcementsMu.Lock()
+	text, ok := h.announcements[cmd.ChatID]
+	delete(h.announcements, cmd.ChatID)
+	h.announcementsMu.Unlock()
+
+	if !ok {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("No announcement waiting for confirmation. Use /announce MESSAGE first."))
+	}
+
+	slog.Info("Broadcasting announcement", "user", cmd.UserID)
+	// Each confirmed announcement is a run of its own, so repeating one the same day sends it again
+	result, err := h.delivery.ForRun(uuid.NewString()).Deliver(models.KindAnnouncement, func(m services.Messenger, _ models.User) error {
+		return m.SendText("📣 "+text, nil)
+	})
+	if err != nil {
+		slog.Error("Error broadcasting announcement", "error", err)
+	}
+
+	return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("✅ Announcement delivered to %d chat(s), %d failed.", result.Delivered, result.Failed))
+}
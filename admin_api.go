@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+
+	"github.com/google/uuid"
+)
+
+// readyPingTimeout bounds the MongoDB ping of the readiness check
+const readyPingTimeout = 2 * time.Second
+
+// Daily reports requested through the admin API by run ID, run by the scheduler between its checks
+var reportRequests = make(chan string, 1)
+
+// tickerStatus is the last price fetched for a symbol
+type tickerStatus struct {
+	Price     float64   `json:"price"`
+	Source    string    `json:"source,omitempty"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// runStatus records scheduler activity for the /status endpoint
+type runStatus struct {
+	mu              sync.RWMutex
+	startedAt       time.Time
+	lastRun         time.Time
+	lastRealtimeRun time.Time
+	lastReport      time.Time
+	lastReportDate  string
+	lastPrune       time.Time
+	prunedPrices    int64
+	prices          map[string]tickerStatus
+}
+
+// Scheduler activity shared with the HTTP server
+var botStatus = &runStatus{startedAt: time.Now(), prices: make(map[string]tickerStatus)}
+
+// recordRun notes the start of a scheduler check
+func (s *runStatus) recordRun(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastRun = now
+}
+
+// recordRealtimeRun notes a realtime price check
+func (s *runStatus) recordRealtimeRun(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastRealtimeRun = now
+}
+
+// recordReport notes a daily report and the date it was sent for
+func (s *runStatus) recordReport(now time.Time, date string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastReport = now
+	s.lastReportDate = date
+}
+
+// recordPrune notes a retention run and how many intraday prices it removed
+func (s *runStatus) recordPrune(now time.Time, removed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastPrune = now
+	s.prunedPrices = removed
+}
+
+// recordPrices stores the latest price of each fetched symbol
+func (s *runStatus) recordPrices(quotes map[string]models.Quote) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for symbol, quote := range quotes {
+		s.prices[symbol] = tickerStatus{Price: quote.Price, Source: quote.Source, FetchedAt: now}
+	}
+}
+
+// lastRunAt returns when the scheduler last checked
+func (s *runStatus) lastRunAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastRun
+}
+
+// statusResponse is the JSON body of /status
+type statusResponse struct {
+	Version         string                                 `json:"version"`
+	StartedAt       time.Time                              `json:"startedAt"`
+	Paused          bool                                   `json:"paused"`
+	LastRun         time.Time                              `json:"lastRun"`
+	LastRealtimeRun time.Time                              `json:"lastRealtimeRun"`
+	LastReport      time.Time                              `json:"lastReport"`
+	LastReportDate  string                                 `json:"lastReportDate"`
+	LastPrune       time.Time                              `json:"lastPrune"`
+	PrunedPrices    int64                                  `json:"prunedPrices"`
+	Tickers         []string                               `json:"tickers"`
+	Prices          map[string]tickerStatus                `json:"prices"`
+	Browser         services.BrowserStats                  `json:"browser"`
+	ScrapeProfiles  map[string]services.ScrapeProfileStats `json:"scrapeProfiles"`
+}
+
+// snapshot copies the status for serving
+func (s *runStatus) snapshot() statusResponse {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return statusResponse{
+		Version:         version,
+		StartedAt:       s.startedAt,
+		Paused:          schedulerPaused.Load(),
+		LastRun:         s.lastRun,
+		LastRealtimeRun: s.lastRealtimeRun,
+		LastReport:      s.lastReport,
+		LastReportDate:  s.lastReportDate,
+		LastPrune:       s.lastPrune,
+		PrunedPrices:    s.prunedPrices,
+		Tickers:         models.Tickers(),
+		Prices:          maps.Clone(s.prices),
+		Browser:         priceFetcher.Browser.Stats(),
+		ScrapeProfiles:  priceFetcher.ScrapeProfileStats(),
+	}
+}
+
+// handleHealthz reports that the process is alive
+func (h *httpHandlers) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok\n"))
+}
+
+// handleReadyz reports ready once MongoDB answers and the scheduler has run recently
+func (h *httpHandlers) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyPingTimeout)
+	defer cancel()
+
+	if err := h.db.Ping(ctx); err != nil {
+		http.Error(w, "database unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	// The scheduler checks every checkInterval minutes; allow one missed tick
+	lastRun := botStatus.lastRunAt()
+	if lastRun.IsZero() || time.Since(lastRun) > 2*time.Duration(checkInterval)*time.Minute {
+		http.Error(w, "scheduler not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Write([]byte("ready\n"))
+}
+
+// handleStatus serves scheduler activity and the last price of each symbol as JSON
+func (h *httpHandlers) handleStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(botStatus.snapshot()); err != nil {
+		slog.Error("Error encoding status", "error", err)
+	}
+}
+
+// handleTriggerReport queues a daily report for the scheduler to send right away
+func (h *httpHandlers) handleTriggerReport(w http.ResponseWriter, r *http.Request) {
+	select {
+	case reportRequests <- uuid.NewString():
+		slog.Info("Daily report requested through the admin API")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("report queued\n"))
+	default:
+		http.Error(w, "a report is already queued", http.StatusConflict)
+	}
+}
+
+// requireToken only lets through requests carrying the admin API token; without a token the endpoint
+// is open when public, or disabled when it changes state
+func (h *httpHandlers) requireToken(next http.HandlerFunc, public bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Read on every request so a rotated token takes effect without a restart
+		adminToken := currentConfig().AdminAPIToken
+		if adminToken == "" {
+			if public {
+				next(w, r)
+				return
+			}
+			http.Error(w, "set ADMIN_API_TOKEN to enable this endpoint", http.StatusForbidden)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
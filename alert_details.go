@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// alertDetailsTimeout bounds the extra lookups made for verbose alerts
+const alertDetailsTimeout = 20 * time.Second
+
+// needsAlertDetails reports whether any recipient of the alerts reads them in the verbose style, either a chat that
+// chose it or a channel whose default it is, which also covers broadcast channels and chats without their own style
+func needsAlertDetails(config models.Config, users []models.User, alerts []models.PriceAlert) bool {
+	for _, channelStyle := range []string{config.TelegramAlertStyle, config.LineAlertStyle, config.SlackAlertStyle} {
+		if style, _ := models.ParseAlertStyle(channelStyle); style == models.AlertVerbose {
+			return true
+		}
+	}
+	for _, user := range users {
+		if user.AlertStyle != models.AlertVerbose {
+			continue
+		}
+		for _, alert := range alerts {
+			if user.Watches(alert.Symbol) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// enrichAlerts adds today's volume and the latest headline to alerts for verbose recipients
+func enrichAlerts(ctx context.Context, alerts []models.PriceAlert) {
+	ctx, cancel := context.WithTimeout(ctx, alertDetailsTimeout)
+	defer cancel()
+
+	history := services.NewHistoryFetcher()
+	search := services.NewSymbolSearcher()
+	now := time.Now()
+
+	// A symbol may have several alerts, e.g. from the previous close and from the open; fetch its details once
+	enriched := make(map[string]models.PriceAlert)
+	for i := range alerts {
+		symbol := alerts[i].Symbol
+		if details, ok := enriched[symbol]; ok {
+			alerts[i].Volume, alerts[i].Headline = details.Volume, details.Headline
+			continue
+		}
+
+		// The last daily bar carries the volume traded so far today
+		points, err := history.FetchDailyCloses(ctx, symbol, now.AddDate(0, 0, -5), now)
+		if err != nil {
+			slog.Error("Error fetching volume for alert", "symbol", symbol, "error", err)
+		} else if len(points) > 0 {
+			alerts[i].Volume = points[len(points)-1].Volume
+		}
+
+		headline, err := search.Headline(ctx, symbol)
+		if err != nil {
+			slog.Error("Error fetching headline for alert", "symbol", symbol, "error", err)
+		} else {
+			alerts[i].Headline = headline
+		}
+		enriched[symbol] = alerts[i]
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"stock-bot/models"
+)
+
+func TestNeedsAlertDetails(t *testing.T) {
+	alerts := []models.PriceAlert{{Symbol: "AAPL"}}
+
+	tests := []struct {
+		name   string
+		config models.Config
+		users  []models.User
+		want   bool
+	}{
+		{"standard everywhere", models.Config{}, []models.User{{ChatID: "1"}}, false},
+		{"verbose chat watching the symbol", models.Config{}, []models.User{{ChatID: "1", AlertStyle: models.AlertVerbose}}, true},
+		{"verbose chat watching others", models.Config{}, []models.User{{ChatID: "1", AlertStyle: models.AlertVerbose, Watchlist: []string{"MSFT"}}}, false},
+		{"verbose channel default", models.Config{SlackAlertStyle: "verbose"}, nil, true},
+		{"compact chat", models.Config{TelegramAlertStyle: "compact"}, []models.User{{ChatID: "1", AlertStyle: models.AlertCompact}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := needsAlertDetails(tt.config, tt.users, alerts); got != tt.want {
+				t.Errorf("needsAlertDetails = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
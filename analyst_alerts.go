@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// Analyst rating and insider filing alert constants
+const (
+	fmpCheckInterval   = 4 * time.Hour      // How often ratings and filings are polled
+	analystAlertMaxAge = 3 * 24 * time.Hour // Older rating changes are stored without alerting
+)
+
+// Time of the last Financial Modeling Prep poll
+var lastFMPCheck time.Time
+
+// checkAnalystRatings stores new rating changes for watched symbols and alerts on upgrades and downgrades
+func checkAnalystRatings(ctx context.Context, db *services.Database, delivery *services.Delivery, fetcher *services.FMPClient) {
+	var changes []models.AnalystRating
+	for _, symbol := range models.Tickers() {
+		// The first import only seeds history so old ratings don't trigger alerts
+		stored, err := db.CountAnalystRatings(symbol)
+		if err != nil {
+			slog.Error("Error counting analyst ratings", "symbol", symbol, "error", err)
+			continue
+		}
+
+		ratings, err := fetcher.FetchAnalystRatings(ctx, symbol)
+		if err != nil {
+			slog.Error("Error fetching analyst ratings", "symbol", symbol, "error", err)
+			continue
+		}
+
+		inserted, err := db.SaveAnalystRatings(ratings)
+		if err != nil {
+			slog.Error("Error saving analyst ratings", "symbol", symbol, "error", err)
+			continue
+		}
+		if stored == 0 {
+			slog.Info("Seeded analyst ratings", "count", len(inserted), "symbol", symbol)
+			continue
+		}
+
+		for _, rating := range inserted {
+			isChange := rating.Action == models.RatingUpgrade || rating.Action == models.RatingDowngrade
+			if isChange && time.Since(rating.PublishedAt) <= analystAlertMaxAge {
+				changes = append(changes, rating)
+			}
+		}
+	}
+
+	if len(changes) == 0 {
+		return
+	}
+
+	slog.Info("Sending analyst rating change alerts", "count", len(changes))
+	if _, err := delivery.Deliver(models.KindAnalyst, func(m services.Messenger, user models.User) error {
+		message := formatAnalystAlerts(changes, user)
+		if message == "" {
+			return nil
+		}
+		return m.SendText(message, nil)
+	}); err != nil {
+		slog.Error("Error sending analyst rating alerts", "error", err)
+	}
+}
+
+// formatAnalystAlerts renders rating changes for a recipient, skipping symbols they don't watch or muted
+func formatAnalystAlerts(changes []models.AnalystRating, user models.User) string {
+	var message strings.Builder
+	now := time.Now()
+
+	for _, rating := range changes {
+		if !user.Watches(rating.Symbol) || user.IsMuted(rating.Symbol, now) {
+			continue
+		}
+
+		icon := "🔻"
+		if rating.Action == models.RatingUpgrade {
+			icon = "🔺"
+		}
+
+		message.WriteString(fmt.Sprintf("%s %s %s by %s: %s → %s", icon, rating.Symbol, rating.Action, rating.Firm, rating.FromGrade, rating.ToGrade))
+		if rating.PriceTarget > 0 {
+			message.WriteString(fmt.Sprintf(", target %s", models.FormatPrice(rating.Symbol, rating.PriceTarget)))
+		}
+		message.WriteString("\n")
+		if rating.URL != "" {
+			message.WriteString(rating.URL + "\n")
+		}
+		message.WriteString("\n")
+	}
+
+	if message.Len() == 0 {
+		return ""
+	}
+	return "🏦 Analyst Rating Changes\n\n" + strings.TrimSpace(message.String())
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// Backfill settings
+const (
+	jobBackfillPrefix = "backfill:"     // Job name prefix recording the first day backfilled for a symbol
+	backfillPause     = 2 * time.Second // Pause between symbols, keeping under the history sources' rate limits
+	backfillSlack     = 7               // Days a recorded backfill may start after the configured range and still count
+)
+
+// backfillRunning keeps backfill runs from overlapping when a run outlasts the scheduler interval
+var backfillRunning atomic.Bool
+
+// backfillFailedOn is the date each symbol no source had history for was last tried, so it is tried once a day
+var backfillFailedOn = make(map[string]string)
+
+// defaultBackfillSources are tried in order unless BACKFILL_SOURCES is set
+var defaultBackfillSources = []string{"yahoo", "stooq"}
+
+// buildHistorySources returns the configured history sources in order, skipping unknown ones
+func buildHistorySources(names []string) []services.DailyHistorySource {
+	if len(names) == 0 {
+		names = defaultBackfillSources
+	}
+
+	var sources []services.DailyHistorySource
+	for _, name := range names {
+		switch name {
+		case "yahoo":
+			sources = append(sources, services.NewHistoryFetcher())
+		case "stooq":
+			sources = append(sources, services.NewStooqHistory())
+		default:
+			slog.Warn("Unknown backfill source, skipping", "source", name)
+		}
+	}
+	return sources
+}
+
+// startBackfill runs backfillHistory in the background unless a run is still going; shutdown waits for it, so the
+// database isn't closed while closes are being saved
+func startBackfill(ctx context.Context, db *services.Database, config models.Config) {
+	if config.BackfillDays <= 0 || !backfillRunning.CompareAndSwap(false, true) {
+		return
+	}
+
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		defer backfillRunning.Store(false)
+		backfillHistory(ctx, db, config)
+	}()
+}
+
+// backfillHistory downloads BACKFILL_DAYS of daily closes for every watched symbol that hasn't been backfilled that
+// far, so charts, indicators and 52-week ranges work from the day a symbol is added. Closes already stored are kept,
+// and each symbol's backfill is recorded in the scheduler state so it is only downloaded once, or once per process
+// without MongoDB
+func backfillHistory(ctx context.Context, db *services.Database, config models.Config) {
+	runs, err := db.GetJobRuns()
+	if err != nil {
+		slog.Error("Error loading backfill state", "error", err)
+		return
+	}
+
+	now := time.Now()
+	today := now.Format("2006-01-02")
+	from := now.AddDate(0, 0, -config.BackfillDays)
+	covered := from.AddDate(0, 0, backfillSlack).Format("2006-01-02")
+
+	var pending []string
+	for _, symbol := range symbolHealth.active(models.Tickers()) {
+		if last := runs[jobBackfillPrefix+symbol]; (last == "" || last > covered) && backfillFailedOn[symbol] != today {
+			pending = append(pending, symbol)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	slog.Info("Backfilling price history", "symbols", len(pending), "days", config.BackfillDays)
+	sources := buildHistorySources(config.BackfillSources)
+	total := 0
+	for i, symbol := range pending {
+		if i > 0 {
+			select {
+			case <-time.After(backfillPause):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		inserted, ok := backfillSymbol(ctx, db, sources, symbol, from, now)
+		if !ok {
+			backfillFailedOn[symbol] = today
+			continue
+		}
+		total += inserted
+		recordJobRun(db, jobBackfillPrefix+symbol, from.Format("2006-01-02"))
+	}
+	slog.Info("Price history backfilled", "symbols", len(pending), "closes", total)
+
+	// Recompute the 52-week ranges from the full history rather than the partial one
+	if total > 0 {
+		refreshYearRanges(ctx, db)
+	}
+}
+
+// backfillSymbol stores a symbol's daily closes from the first source that has them and returns how many were new
+func backfillSymbol(ctx context.Context, db *services.Database, sources []services.DailyHistorySource, symbol string, from, to time.Time) (int, bool) {
+	var failed []string
+	for _, source := range sources {
+		fetchCtx, cancel := context.WithTimeout(ctx, commandFetchTimeout)
+		points, err := source.FetchDailyCloses(fetchCtx, symbol, from, to)
+		cancel()
+		if err != nil {
+			slog.Debug("History source failed", "source", source.Name(), "symbol", symbol, "error", err)
+			failed = append(failed, source.Name())
+			continue
+		}
+		// Today's close is captured at the close; a bar for it now holds an intraday price
+		points = models.CompletedCloses(symbol, points, to)
+
+		inserted, err := db.SaveHistoricalCloses(symbol, points)
+		if err != nil {
+			slog.Error("Error saving backfilled closes", "symbol", symbol, "error", err)
+			return 0, false
+		}
+		slog.Info("Backfilled closes", "symbol", symbol, "source", source.Name(), "closes", len(points), "new", inserted)
+		return inserted, true
+	}
+
+	slog.Warn("No history source had closes to backfill", "symbol", symbol, "sources", strings.Join(failed, ","))
+	return 0, false
+}
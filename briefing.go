@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// futuresContract is an index future shown in the morning briefing
+type futuresContract struct {
+	symbol string
+	name   string
+}
+
+// Index futures that show where the US market is heading before the open
+var morningFutures = []futuresContract{
+	{symbol: "ES=F", name: "S&P 500"},
+	{symbol: "NQ=F", name: "Nasdaq 100"},
+}
+
+// sendMorningBriefing follows the daily report with overnight futures, closing streaks and the day's macro events
+func sendMorningBriefing(ctx context.Context, db *services.Database, delivery *services.Delivery, config models.Config, now time.Time) {
+	sections := []string{futuresSection(ctx), streaksSection()}
+	if config.FMPAPIKey != "" {
+		sections = append(sections, economicEventsSection(db, now))
+	}
+
+	var message strings.Builder
+	for _, section := range sections {
+		if section == "" {
+			continue
+		}
+		if message.Len() > 0 {
+			message.WriteString("\n")
+		}
+		message.WriteString(section)
+	}
+	if message.Len() == 0 {
+		return
+	}
+
+	if _, err := delivery.Deliver(models.KindDailyReport, func(m services.Messenger, _ models.User) error {
+		return m.SendText(strings.TrimSpace(message.String()), nil)
+	}); err != nil {
+		slog.Error("Error sending morning briefing", "error", err)
+	}
+}
+
+// futuresSection lists index futures levels and their change since the previous settlement
+func futuresSection(ctx context.Context) string {
+	var section strings.Builder
+	for _, contract := range morningFutures {
+		fetchCtx, cancel := context.WithTimeout(ctx, commandFetchTimeout)
+		quote, err := priceSource.Fetch(fetchCtx, contract.symbol)
+		cancel()
+		if err != nil {
+			slog.Error("Error fetching futures", "contract", contract.name, "error", err)
+			continue
+		}
+
+		icon := "🔺"
+		if quote.Change < 0 {
+			icon = "🔻"
+		}
+		section.WriteString(fmt.Sprintf("%s %s futures: %.2f (%+.2f, %+.2f%%)\n", icon, contract.name, quote.Price, quote.Change, quote.ChangePercent))
+	}
+
+	if section.Len() == 0 {
+		return ""
+	}
+	return "🌙 Overnight Futures\n" + section.String()
+}
@@ -0,0 +1,200 @@
+// Package calendar knows the NYSE, KRX, TSE, LSE, XETRA and Euronext trading sessions, holidays and early closes
+package calendar
+
+import (
+	"time"
+	_ "time/tzdata" // Exchange time zones must resolve in minimal containers too
+)
+
+// Regular and early-close session times in New York, in minutes after midnight
+const (
+	openMinute       = 9*60 + 30
+	closeMinute      = 16 * 60
+	earlyCloseMinute = 13 * 60
+)
+
+// newYork is the exchange time zone; sessions follow its daylight saving time changes
+var newYork = mustLoad("America/New_York")
+
+// Session is a day's regular trading session
+type Session struct {
+	Open       time.Time
+	Close      time.Time
+	EarlyClose bool
+}
+
+// mustLoad loads a time zone from the embedded database
+func mustLoad(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// Location returns the exchange time zone
+func Location() *time.Location {
+	return newYork
+}
+
+// SessionOn returns the trading session of the New York date of t, or false on weekends and holidays
+func SessionOn(t time.Time) (Session, bool) {
+	local := t.In(newYork)
+	year, month, day := local.Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, newYork)
+
+	if !IsTradingDay(date) {
+		return Session{}, false
+	}
+
+	closeAt := closeMinute
+	early := IsEarlyClose(date)
+	if early {
+		closeAt = earlyCloseMinute
+	}
+	return Session{
+		Open:       date.Add(openMinute * time.Minute),
+		Close:      date.Add(time.Duration(closeAt) * time.Minute),
+		EarlyClose: early,
+	}, true
+}
+
+// Longest pre-market and after-hours extensions, from 4:00 AM to 8:00 PM New York time
+const (
+	MaxPreMarket  = 5*time.Hour + 30*time.Minute
+	MaxPostMarket = 4 * time.Hour
+)
+
+// IsOpen reports whether the regular session is in progress at t
+func IsOpen(t time.Time) bool {
+	return IsOpenExtended(t, 0, 0)
+}
+
+// IsOpenExtended reports whether t falls in the session extended by the given pre-market and after-hours windows
+func IsOpenExtended(t time.Time, preMarket, postMarket time.Duration) bool {
+	session, ok := SessionOn(t)
+	return ok && !t.Before(session.Open.Add(-preMarket)) && t.Before(session.Close.Add(postMarket))
+}
+
+// IsTradingDay reports whether the New York date of t is a weekday that is not a holiday
+func IsTradingDay(t time.Time) bool {
+	local := t.In(newYork)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := Holiday(local)
+	return !holiday
+}
+
+// Holiday returns the name of the NYSE holiday on the New York date of t, if it is one
+func Holiday(t time.Time) (string, bool) {
+	local := t.In(newYork)
+	for _, h := range holidays(local.Year()) {
+		if sameDate(h.date, local) {
+			return h.name, true
+		}
+	}
+	return "", false
+}
+
+// IsEarlyClose reports whether the market closes at 1 PM on the New York date of t:
+// the day before Independence Day, the day after Thanksgiving and Christmas Eve
+func IsEarlyClose(t time.Time) bool {
+	local := t.In(newYork)
+	year := local.Year()
+	if !IsTradingDay(local) {
+		return false
+	}
+
+	switch {
+	case local.Month() == time.July && local.Day() == 3:
+		return true
+	case sameDate(local, nthWeekday(year, time.November, time.Thursday, 4).AddDate(0, 0, 1)):
+		return true
+	case local.Month() == time.December && local.Day() == 24:
+		return true
+	}
+	return false
+}
+
+// holiday is a dated market holiday
+type holiday struct {
+	name string
+	date time.Time
+}
+
+// holidays lists the NYSE holidays of a year on the dates they are observed
+func holidays(year int) []holiday {
+	list := []holiday{
+		{"Martin Luther King Jr. Day", nthWeekday(year, time.January, time.Monday, 3)},
+		{"Washington's Birthday", nthWeekday(year, time.February, time.Monday, 3)},
+		{"Good Friday", easter(year).AddDate(0, 0, -2)},
+		{"Memorial Day", lastWeekday(year, time.May, time.Monday)},
+		{"Independence Day", observed(date(year, time.July, 4))},
+		{"Labor Day", nthWeekday(year, time.September, time.Monday, 1)},
+		{"Thanksgiving Day", nthWeekday(year, time.November, time.Thursday, 4)},
+		{"Christmas Day", observed(date(year, time.December, 25))},
+	}
+	if year >= 2022 {
+		list = append(list, holiday{"Juneteenth", observed(date(year, time.June, 19))})
+	}
+
+	// New Year's Day on a Saturday is not observed on the Friday before, as that would fall in the previous year
+	if newYear := date(year, time.January, 1); newYear.Weekday() != time.Saturday {
+		list = append(list, holiday{"New Year's Day", observed(newYear)})
+	}
+	return list
+}
+
+// observed moves a holiday on a Saturday to the Friday before and one on a Sunday to the Monday after
+func observed(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Saturday:
+		return t.AddDate(0, 0, -1)
+	case time.Sunday:
+		return t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// nthWeekday returns the nth given weekday of a month
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	first := date(year, month, 1)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+7*(n-1))
+}
+
+// lastWeekday returns the last given weekday of a month
+func lastWeekday(year int, month time.Month, weekday time.Weekday) time.Time {
+	last := date(year, month+1, 0)
+	offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+	return last.AddDate(0, 0, -offset)
+}
+
+// easter returns Easter Sunday of a year with the anonymous Gregorian algorithm
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+// date returns midnight of a New York date
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, newYork)
+}
+
+// sameDate reports whether two times fall on the same New York date
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.In(newYork).Date()
+	by, bm, bd := b.In(newYork).Date()
+	return ay == by && am == bm && ad == bd
+}
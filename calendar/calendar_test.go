@@ -0,0 +1,110 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHoliday(t *testing.T) {
+	// NYSE holidays as published for 2024 to 2026
+	tests := []struct {
+		date time.Time
+		want string
+	}{
+		{date(2024, time.January, 1), "New Year's Day"},
+		{date(2024, time.January, 15), "Martin Luther King Jr. Day"},
+		{date(2024, time.February, 19), "Washington's Birthday"},
+		{date(2024, time.March, 29), "Good Friday"},
+		{date(2024, time.May, 27), "Memorial Day"},
+		{date(2024, time.June, 19), "Juneteenth"},
+		{date(2024, time.July, 4), "Independence Day"},
+		{date(2024, time.September, 2), "Labor Day"},
+		{date(2024, time.November, 28), "Thanksgiving Day"},
+		{date(2024, time.December, 25), "Christmas Day"},
+
+		{date(2025, time.January, 1), "New Year's Day"},
+		{date(2025, time.January, 20), "Martin Luther King Jr. Day"},
+		{date(2025, time.February, 17), "Washington's Birthday"},
+		{date(2025, time.April, 18), "Good Friday"},
+		{date(2025, time.May, 26), "Memorial Day"},
+		{date(2025, time.June, 19), "Juneteenth"},
+		{date(2025, time.July, 4), "Independence Day"},
+		{date(2025, time.September, 1), "Labor Day"},
+		{date(2025, time.November, 27), "Thanksgiving Day"},
+		{date(2025, time.December, 25), "Christmas Day"},
+
+		{date(2026, time.January, 1), "New Year's Day"},
+		{date(2026, time.January, 19), "Martin Luther King Jr. Day"},
+		{date(2026, time.February, 16), "Washington's Birthday"},
+		{date(2026, time.April, 3), "Good Friday"},
+		{date(2026, time.May, 25), "Memorial Day"},
+		{date(2026, time.June, 19), "Juneteenth"},
+		{date(2026, time.July, 3), "Independence Day"}, // July 4 is a Saturday
+		{date(2026, time.September, 7), "Labor Day"},
+		{date(2026, time.November, 26), "Thanksgiving Day"},
+		{date(2026, time.December, 25), "Christmas Day"},
+	}
+
+	perYear := make(map[int]int)
+	for _, tt := range tests {
+		perYear[tt.date.Year()]++
+		t.Run(tt.date.Format("2006-01-02"), func(t *testing.T) {
+			// Noon UTC is still the same date in New York
+			got, ok := Holiday(tt.date.Add(12 * time.Hour).UTC())
+			if !ok || got != tt.want {
+				t.Errorf("Holiday = %q, %v, want %q", got, ok, tt.want)
+			}
+			if IsTradingDay(tt.date) {
+				t.Error("IsTradingDay = true on a holiday")
+			}
+		})
+	}
+
+	// No other days are holidays
+	for year, want := range perYear {
+		if got := len(holidays(year)); got != want {
+			t.Errorf("%d has %d holidays, want %d", year, got, want)
+		}
+	}
+}
+
+func TestIsEarlyClose(t *testing.T) {
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{"2024 day before Independence Day", date(2024, time.July, 3), true},
+		{"2024 day after Thanksgiving", date(2024, time.November, 29), true},
+		{"2024 Christmas Eve", date(2024, time.December, 24), true},
+		{"2025 day before Independence Day", date(2025, time.July, 3), true},
+		{"2025 day after Thanksgiving", date(2025, time.November, 28), true},
+		{"2025 Christmas Eve", date(2025, time.December, 24), true},
+		{"2026 July 3 is the observed holiday", date(2026, time.July, 3), false},
+		{"2026 July 2 is a full day", date(2026, time.July, 2), false},
+		{"2026 day after Thanksgiving", date(2026, time.November, 27), true},
+		{"2026 Christmas Eve", date(2026, time.December, 24), true},
+		{"2026 New Year's Eve is a full day", date(2026, time.December, 31), false},
+		{"2027 Christmas Eve is the observed holiday", date(2027, time.December, 24), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsEarlyClose(tt.date); got != tt.want {
+				t.Errorf("IsEarlyClose(%s) = %v, want %v", tt.date.Format("2006-01-02"), got, tt.want)
+			}
+
+			session, ok := SessionOn(tt.date)
+			if !ok {
+				return
+			}
+			closeHour := 16
+			if tt.want {
+				closeHour = 13
+			}
+			if session.Close.Hour() != closeHour || session.EarlyClose != tt.want {
+				t.Errorf("SessionOn closes at %s, early %v", session.Close.Format("15:04"), session.EarlyClose)
+			}
+		})
+	}
+}
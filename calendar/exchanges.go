@@ -0,0 +1,192 @@
+package calendar
+
+import "time"
+
+// exchangeHours are the regular trading hours of an exchange in its own time zone, in minutes after midnight
+type exchangeHours struct {
+	loc        *time.Location
+	open       int
+	close      int
+	breakStart int // Midday break, zero when the exchange trades through
+	breakEnd   int
+	holidays   func(year int) []holiday
+}
+
+// exchanges holds the sessions of the exchanges besides NYSE and KRX, by the names models uses for them
+var exchanges = map[string]exchangeHours{
+	"TSE":      {loc: mustLoad("Asia/Tokyo"), open: 9 * 60, close: 15*60 + 30, breakStart: 11*60 + 30, breakEnd: 12*60 + 30, holidays: tseHolidays},
+	"LSE":      {loc: mustLoad("Europe/London"), open: 8 * 60, close: 16*60 + 30, holidays: lseHolidays},
+	"XETRA":    {loc: mustLoad("Europe/Berlin"), open: 9 * 60, close: 17*60 + 30, holidays: xetraHolidays},
+	"EURONEXT": {loc: mustLoad("Europe/Paris"), open: 9 * 60, close: 17*60 + 30, holidays: euronextHolidays},
+}
+
+// IsExchangeOpen reports whether the regular session of TSE, LSE, XETRA or EURONEXT is in progress at t, outside
+// the Tokyo lunch break, weekends and holidays; unknown exchanges are reported closed. Early closes, such as
+// London's half days before Christmas and New Year, aren't included
+func IsExchangeOpen(exchange string, t time.Time) bool {
+	hours, ok := exchanges[exchange]
+	if !ok {
+		return false
+	}
+
+	local := t.In(hours.loc)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+	if _, holiday := ExchangeHoliday(exchange, t); holiday {
+		return false
+	}
+
+	minute := local.Hour()*60 + local.Minute()
+	if hours.breakStart > 0 && minute >= hours.breakStart && minute < hours.breakEnd {
+		return false
+	}
+	return minute >= hours.open && minute < hours.close
+}
+
+// ExchangeHoliday returns the name of the holiday on the local date of t at TSE, LSE, XETRA or EURONEXT, if it is one
+func ExchangeHoliday(exchange string, t time.Time) (string, bool) {
+	hours, ok := exchanges[exchange]
+	if !ok {
+		return "", false
+	}
+
+	year, month, day := t.In(hours.loc).Date()
+	for _, h := range hours.holidays(year) {
+		if hy, hm, hd := h.date.Date(); hy == year && hm == month && hd == day {
+			return h.name, true
+		}
+	}
+	return "", false
+}
+
+// lseHolidays lists the London Stock Exchange holidays of a year, the English bank holidays
+func lseHolidays(year int) []holiday {
+	easterSunday := easter(year)
+	list := []holiday{
+		{"New Year's Day", nextMonday(date(year, time.January, 1))},
+		{"Good Friday", easterSunday.AddDate(0, 0, -2)},
+		{"Easter Monday", easterSunday.AddDate(0, 0, 1)},
+		{"Early May Bank Holiday", nthWeekday(year, time.May, time.Monday, 1)},
+		{"Spring Bank Holiday", lastWeekday(year, time.May, time.Monday)},
+		{"Summer Bank Holiday", lastWeekday(year, time.August, time.Monday)},
+	}
+
+	// Christmas and Boxing Day on a weekend move to the following Monday and Tuesday
+	christmas := date(year, time.December, 25)
+	switch christmas.Weekday() {
+	case time.Friday:
+		list = append(list, holiday{"Christmas Day", christmas}, holiday{"Boxing Day", christmas.AddDate(0, 0, 3)})
+	case time.Saturday:
+		list = append(list, holiday{"Christmas Day", christmas.AddDate(0, 0, 2)}, holiday{"Boxing Day", christmas.AddDate(0, 0, 3)})
+	case time.Sunday:
+		list = append(list, holiday{"Boxing Day", christmas.AddDate(0, 0, 1)}, holiday{"Christmas Day", christmas.AddDate(0, 0, 2)})
+	default:
+		list = append(list, holiday{"Christmas Day", christmas}, holiday{"Boxing Day", christmas.AddDate(0, 0, 1)})
+	}
+	return list
+}
+
+// xetraHolidays lists the Frankfurt Stock Exchange holidays of a year
+func xetraHolidays(year int) []holiday {
+	easterSunday := easter(year)
+	return []holiday{
+		{"New Year's Day", date(year, time.January, 1)},
+		{"Good Friday", easterSunday.AddDate(0, 0, -2)},
+		{"Easter Monday", easterSunday.AddDate(0, 0, 1)},
+		{"Labour Day", date(year, time.May, 1)},
+		{"Christmas Eve", date(year, time.December, 24)},
+		{"Christmas Day", date(year, time.December, 25)},
+		{"Boxing Day", date(year, time.December, 26)},
+		{"New Year's Eve", date(year, time.December, 31)},
+	}
+}
+
+// euronextHolidays lists the Euronext holidays of a year, shared by Paris, Amsterdam and its other markets
+func euronextHolidays(year int) []holiday {
+	easterSunday := easter(year)
+	return []holiday{
+		{"New Year's Day", date(year, time.January, 1)},
+		{"Good Friday", easterSunday.AddDate(0, 0, -2)},
+		{"Easter Monday", easterSunday.AddDate(0, 0, 1)},
+		{"Labour Day", date(year, time.May, 1)},
+		{"Christmas Day", date(year, time.December, 25)},
+		{"Boxing Day", date(year, time.December, 26)},
+	}
+}
+
+// tseHolidays lists the Tokyo Stock Exchange holidays of a year: the year-end closure and the Japanese national
+// holidays, with a holiday on a Sunday observed on the next free weekday and a day between two holidays closed too
+func tseHolidays(year int) []holiday {
+	list := []holiday{
+		{"New Year's Day", date(year, time.January, 1)},
+		{"Market holiday", date(year, time.January, 2)},
+		{"Market holiday", date(year, time.January, 3)},
+		{"Coming of Age Day", nthWeekday(year, time.January, time.Monday, 2)},
+		{"National Foundation Day", date(year, time.February, 11)},
+		{"Emperor's Birthday", date(year, time.February, 23)},
+		{"Vernal Equinox Day", date(year, time.March, vernalEquinoxDay(year))},
+		{"Showa Day", date(year, time.April, 29)},
+		{"Constitution Memorial Day", date(year, time.May, 3)},
+		{"Greenery Day", date(year, time.May, 4)},
+		{"Children's Day", date(year, time.May, 5)},
+		{"Marine Day", nthWeekday(year, time.July, time.Monday, 3)},
+		{"Mountain Day", date(year, time.August, 11)},
+		{"Respect for the Aged Day", nthWeekday(year, time.September, time.Monday, 3)},
+		{"Autumnal Equinox Day", date(year, time.September, autumnalEquinoxDay(year))},
+		{"Sports Day", nthWeekday(year, time.October, time.Monday, 2)},
+		{"Culture Day", date(year, time.November, 3)},
+		{"Labor Thanksgiving Day", date(year, time.November, 23)},
+		{"Market holiday", date(year, time.December, 31)},
+	}
+
+	isHoliday := func(t time.Time) bool {
+		for _, h := range list {
+			if h.date.Equal(t) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// A weekday between two holidays is a citizens' holiday, as happens in September
+	for _, h := range list {
+		if between := h.date.AddDate(0, 0, 1); between.Weekday() != time.Sunday && !isHoliday(between) && isHoliday(between.AddDate(0, 0, 1)) {
+			list = append(list, holiday{"Citizens' Holiday", between})
+		}
+	}
+
+	// A holiday on a Sunday is observed on the next day that isn't one
+	for _, h := range list {
+		if h.date.Weekday() != time.Sunday {
+			continue
+		}
+		substitute := h.date.AddDate(0, 0, 1)
+		for isHoliday(substitute) {
+			substitute = substitute.AddDate(0, 0, 1)
+		}
+		list = append(list, holiday{"Substitute Holiday", substitute})
+	}
+	return list
+}
+
+// vernalEquinoxDay returns the March day of the Japanese vernal equinox holiday, valid from 1980 to 2099
+func vernalEquinoxDay(year int) int {
+	return int(20.8431+0.242194*float64(year-1980)) - (year-1980)/4
+}
+
+// autumnalEquinoxDay returns the September day of the Japanese autumnal equinox holiday, valid from 1980 to 2099
+func autumnalEquinoxDay(year int) int {
+	return int(23.2488+0.242194*float64(year-1980)) - (year-1980)/4
+}
+
+// nextMonday moves a date on a weekend to the Monday after
+func nextMonday(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Saturday:
+		return t.AddDate(0, 0, 2)
+	case time.Sunday:
+		return t.AddDate(0, 0, 1)
+	}
+	return t
+}
@@ -0,0 +1,71 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsExchangeOpen(t *testing.T) {
+	at := func(zone string, year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, mustLoad(zone))
+	}
+
+	tests := []struct {
+		name     string
+		exchange string
+		at       time.Time
+		want     bool
+	}{
+		{"Tokyo morning", "TSE", at("Asia/Tokyo", 2026, time.March, 4, 10, 0), true},
+		{"Tokyo lunch break", "TSE", at("Asia/Tokyo", 2026, time.March, 4, 12, 0), false},
+		{"Tokyo afternoon until 15:30", "TSE", at("Asia/Tokyo", 2026, time.March, 4, 15, 20), true},
+		{"Tokyo after the close", "TSE", at("Asia/Tokyo", 2026, time.March, 4, 15, 30), false},
+		{"Tokyo year-end closure", "TSE", at("Asia/Tokyo", 2026, time.January, 2, 10, 0), false},
+		{"Tokyo vernal equinox", "TSE", at("Asia/Tokyo", 2026, time.March, 20, 10, 0), false},
+		{"Tokyo citizens' holiday", "TSE", at("Asia/Tokyo", 2026, time.September, 22, 10, 0), false},
+		{"Tokyo substitute holiday", "TSE", at("Asia/Tokyo", 2026, time.May, 6, 10, 0), false},
+		{"London open", "LSE", at("Europe/London", 2026, time.March, 4, 8, 0), true},
+		{"London close", "LSE", at("Europe/London", 2026, time.March, 4, 16, 30), false},
+		{"London Easter Monday", "LSE", at("Europe/London", 2026, time.April, 6, 12, 0), false},
+		{"London Boxing Day substitute", "LSE", at("Europe/London", 2026, time.December, 28, 12, 0), false},
+		{"Frankfurt afternoon", "XETRA", at("Europe/Berlin", 2026, time.March, 4, 17, 0), true},
+		{"Frankfurt Christmas Eve", "XETRA", at("Europe/Berlin", 2026, time.December, 24, 12, 0), false},
+		{"Paris open", "EURONEXT", at("Europe/Paris", 2026, time.March, 4, 9, 0), true},
+		{"Paris Labour Day", "EURONEXT", at("Europe/Paris", 2026, time.May, 1, 12, 0), false},
+		{"Paris weekend", "EURONEXT", at("Europe/Paris", 2026, time.March, 7, 12, 0), false},
+		{"unknown exchange", "NSE", at("Asia/Kolkata", 2026, time.March, 4, 12, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsExchangeOpen(tt.exchange, tt.at); got != tt.want {
+				t.Errorf("IsExchangeOpen(%s, %v) = %v, want %v", tt.exchange, tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKRXHoliday(t *testing.T) {
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{"Seollal", time.Date(2026, time.February, 17, 12, 0, 0, 0, seoul), true},
+		{"Seollal substitute", time.Date(2027, time.February, 9, 12, 0, 0, 0, seoul), true},
+		{"Chuseok", time.Date(2025, time.October, 7, 12, 0, 0, 0, seoul), true},
+		{"Chuseok substitute", time.Date(2025, time.October, 8, 12, 0, 0, 0, seoul), true},
+		{"Buddha's Birthday", time.Date(2027, time.May, 13, 12, 0, 0, 0, seoul), true},
+		{"fixed-date holiday", time.Date(2026, time.March, 1, 12, 0, 0, 0, seoul), true},
+		{"day after Seollal", time.Date(2026, time.February, 19, 12, 0, 0, 0, seoul), false},
+		{"Seoul date of a UTC evening", time.Date(2026, time.February, 15, 20, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := KRXHoliday(tt.date); got != tt.want {
+				t.Errorf("KRXHoliday(%v) = %v, want %v", tt.date, got, tt.want)
+			}
+		})
+	}
+}
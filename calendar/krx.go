@@ -0,0 +1,106 @@
+package calendar
+
+import "time"
+
+// Regular KRX session times in Seoul, in minutes after midnight
+const (
+	krxOpenMinute  = 9 * 60
+	krxCloseMinute = 15*60 + 30
+)
+
+// seoul is the Korea Exchange time zone, which has no daylight saving time
+var seoul = mustLoad("Asia/Seoul")
+
+// KRXLocation returns the Korea Exchange time zone
+func KRXLocation() *time.Location {
+	return seoul
+}
+
+// KRXSessionOn returns the KRX trading session of the Seoul date of t, or false on weekends and holidays
+func KRXSessionOn(t time.Time) (Session, bool) {
+	local := t.In(seoul)
+	year, month, day := local.Date()
+	date := time.Date(year, month, day, 0, 0, 0, 0, seoul)
+
+	if !IsKRXTradingDay(date) {
+		return Session{}, false
+	}
+	return Session{
+		Open:  date.Add(krxOpenMinute * time.Minute),
+		Close: date.Add(krxCloseMinute * time.Minute),
+	}, true
+}
+
+// IsKRXOpen reports whether the KRX regular session, 9:00 AM to 3:30 PM KST, is in progress at t
+func IsKRXOpen(t time.Time) bool {
+	session, ok := KRXSessionOn(t)
+	return ok && !t.Before(session.Open) && t.Before(session.Close)
+}
+
+// IsKRXTradingDay reports whether the Seoul date of t is a weekday that is not a KRX holiday
+func IsKRXTradingDay(t time.Time) bool {
+	local := t.In(seoul)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+	_, holiday := KRXHoliday(local)
+	return !holiday
+}
+
+// KRXHoliday returns the name of the KRX holiday on the Seoul date of t, if it is one: the fixed-date holidays, and
+// the lunar ones (Seollal, Buddha's Birthday, Chuseok) with their substitute days from 2024 to 2030. Substitutes
+// for the fixed-date holidays and election days are not included
+func KRXHoliday(t time.Time) (string, bool) {
+	year, month, day := t.In(seoul).Date()
+	if name, ok := krxLunarHolidays[[3]int{year, int(month), day}]; ok {
+		return name, true
+	}
+	name, ok := krxHolidays[[2]int{int(month), day}]
+	return name, ok
+}
+
+// krxHolidays lists the fixed-date KRX holidays by month and day
+var krxHolidays = map[[2]int]string{
+	{1, 1}:   "New Year's Day",
+	{3, 1}:   "Independence Movement Day",
+	{5, 1}:   "Labor Day",
+	{5, 5}:   "Children's Day",
+	{6, 6}:   "Memorial Day",
+	{8, 15}:  "Liberation Day",
+	{10, 3}:  "National Foundation Day",
+	{10, 9}:  "Hangul Day",
+	{12, 25}: "Christmas Day",
+	{12, 31}: "Year-end closing",
+}
+
+// krxLunarHolidays lists the lunar-calendar KRX holidays by year, month and day, as they follow no solar rule;
+// a holiday falling on a Sunday or another holiday adds a substitute day
+var krxLunarHolidays = map[[3]int]string{
+	{2024, 2, 9}: "Seollal", {2024, 2, 12}: "Seollal substitute holiday",
+	{2024, 5, 15}: "Buddha's Birthday",
+	{2024, 9, 16}: "Chuseok", {2024, 9, 17}: "Chuseok", {2024, 9, 18}: "Chuseok",
+
+	{2025, 1, 27}: "Temporary holiday", {2025, 1, 28}: "Seollal", {2025, 1, 29}: "Seollal", {2025, 1, 30}: "Seollal",
+	{2025, 5, 6}:  "Buddha's Birthday substitute holiday",
+	{2025, 10, 6}: "Chuseok", {2025, 10, 7}: "Chuseok", {2025, 10, 8}: "Chuseok substitute holiday",
+
+	{2026, 2, 16}: "Seollal", {2026, 2, 17}: "Seollal", {2026, 2, 18}: "Seollal",
+	{2026, 5, 25}: "Buddha's Birthday substitute holiday",
+	{2026, 9, 24}: "Chuseok", {2026, 9, 25}: "Chuseok",
+
+	{2027, 2, 8}: "Seollal", {2027, 2, 9}: "Seollal substitute holiday",
+	{2027, 5, 13}: "Buddha's Birthday",
+	{2027, 9, 14}: "Chuseok", {2027, 9, 15}: "Chuseok", {2027, 9, 16}: "Chuseok",
+
+	{2028, 1, 26}: "Seollal", {2028, 1, 27}: "Seollal", {2028, 1, 28}: "Seollal",
+	{2028, 5, 2}:  "Buddha's Birthday",
+	{2028, 10, 2}: "Chuseok", {2028, 10, 4}: "Chuseok", {2028, 10, 5}: "Chuseok substitute holiday",
+
+	{2029, 2, 12}: "Seollal", {2029, 2, 13}: "Seollal", {2029, 2, 14}: "Seollal",
+	{2029, 5, 21}: "Buddha's Birthday substitute holiday",
+	{2029, 9, 21}: "Chuseok", {2029, 9, 24}: "Chuseok substitute holiday",
+
+	{2030, 2, 4}: "Seollal", {2030, 2, 5}: "Seollal substitute holiday",
+	{2030, 5, 9}:  "Buddha's Birthday",
+	{2030, 9, 11}: "Chuseok", {2030, 9, 12}: "Chuseok", {2030, 9, 13}: "Chuseok",
+}
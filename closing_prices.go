@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// closeJobPrefix prefixes the scheduler state job name of each exchange's closing price capture
+const closeJobPrefix = "close:"
+
+// maxCloseCaptureAttempts is how many scheduler runs try to capture an exchange's closing prices before the
+// symbols still missing are given up for the day
+const maxCloseCaptureAttempts = 3
+
+// The exchange-local date each exchange's closing prices were last captured, restored from MongoDB on startup
+var lastCloseCapture = make(map[string]string)
+
+// closeCapture is the progress of an exchange's closing price capture that hasn't completed yet
+type closeCapture struct {
+	date     string
+	saved    map[string]bool
+	attempts int
+}
+
+// Captures still waiting for some of their symbols, by exchange
+var pendingCloseCaptures = make(map[string]*closeCapture)
+
+// captureClosingPrices saves the final prices of each exchange's symbols as closing records once
+// its capture time has passed, so the next day's changes are measured against them, then checks
+// them for moving average crossovers and earnings reactions. Symbols whose price couldn't be fetched
+// or saved are retried on the next runs, and the capture completes once all of them are saved or
+// the attempts run out
+func captureClosingPrices(ctx context.Context, db *services.Database, delivery *services.Delivery, config models.Config, now time.Time) {
+	for exchange, symbols := range groupByExchange(models.Tickers()) {
+		loc, err := time.LoadLocation(models.ExchangeTimeZones[exchange])
+		if err != nil {
+			slog.Error("Error loading exchange time zone", "exchange", exchange, "error", err)
+			continue
+		}
+
+		local := now.In(loc)
+		date := local.Format("2006-01-02")
+		captureAt, ok := config.ClosingTimes[exchange]
+		if !ok || lastCloseCapture[exchange] == date || models.ClockTime(local.Hour()*60+local.Minute()) < captureAt {
+			continue
+		}
+		// Stock exchanges don't trade on weekends or, in the US and Korea, on exchange holidays, so there is no new close
+		if !isExchangeTradingDay(exchange, local) {
+			continue
+		}
+
+		capture := pendingCloseCaptures[exchange]
+		if capture == nil || capture.date != date {
+			capture = &closeCapture{date: date, saved: make(map[string]bool)}
+			pendingCloseCaptures[exchange] = capture
+		}
+		capture.attempts++
+
+		// Symbols paused as possibly delisted aren't fetched, so they aren't waited for
+		var missing []string
+		for _, symbol := range symbolHealth.active(symbols) {
+			if !capture.saved[symbol] {
+				missing = append(missing, symbol)
+			}
+		}
+		if len(missing) > 0 {
+			slog.Info("Capturing closing prices", "exchange", exchange, "count", len(missing), "attempt", capture.attempts)
+			saveClosingPrices(ctx, db, capture, missing, now)
+		}
+
+		var unsaved []string
+		for _, symbol := range missing {
+			if !capture.saved[symbol] {
+				unsaved = append(unsaved, symbol)
+			}
+		}
+		if len(unsaved) > 0 {
+			if capture.attempts < maxCloseCaptureAttempts {
+				slog.Warn("Closing prices incomplete, retrying on the next run", "exchange", exchange, "symbols", unsaved)
+				continue
+			}
+			slog.Error("Giving up on closing prices", "exchange", exchange, "symbols", unsaved, "attempts", capture.attempts)
+		}
+		delete(pendingCloseCaptures, exchange)
+
+		lastCloseCapture[exchange] = date
+		recordJobRun(db, closeJobPrefix+exchange, date)
+
+		// Moving averages only change with a new close, and earnings reactions are measured close to close
+		checkCrossovers(db, delivery, config, symbols)
+		checkEarningsReactions(db, delivery, exchange, symbols, local)
+
+		// US opens reset when the market opens and around-the-clock ones at the daily report;
+		// other exchanges start their next session once their close is captured
+		if exchange != models.ExchangeUS && exchange != models.Exchange24H {
+			intraday.resetOpens(func(symbol string) bool { return models.ExchangeOf(symbol) == exchange })
+		}
+	}
+}
+
+// saveClosingPrices fetches the symbols' prices and saves each as a closing record, noting the ones saved. Records
+// are saved one at a time so a failure leaves no doubt about which symbols to retry
+func saveClosingPrices(ctx context.Context, db *services.Database, capture *closeCapture, symbols []string, now time.Time) {
+	quotes, err := fetchPrices(ctx, db, symbols)
+	if err != nil {
+		slog.Error("Error fetching closing prices", "error", err)
+		return
+	}
+	for symbol, quote := range quotes {
+		if err := db.SavePrices([]models.MongoDTO{models.NewPriceRecord(quote, true, now)}); err != nil {
+			slog.Error("Error saving closing price", "symbol", symbol, "error", err)
+			continue
+		}
+		capture.saved[symbol] = true
+	}
+}
+
+// groupByExchange splits symbols by the exchange they trade on, keeping their order
+func groupByExchange(symbols []string) map[string][]string {
+	groups := make(map[string][]string)
+	for _, symbol := range symbols {
+		exchange := models.ExchangeOf(symbol)
+		groups[exchange] = append(groups[exchange], symbol)
+	}
+	return groups
+}
+
+// restoreCloseCaptures fills in the closing price capture dates from the saved scheduler state
+func restoreCloseCaptures(runs map[string]string) {
+	for job, date := range runs {
+		if exchange, ok := strings.CutPrefix(job, closeJobPrefix); ok {
+			lastCloseCapture[exchange] = date
+		}
+	}
+}
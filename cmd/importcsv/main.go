@@ -0,0 +1,95 @@
+// Command importcsv loads externally downloaded price history CSVs (Yahoo or stooq format) into the database.
+//
+// Usage:
+//
+//	importcsv [-symbol SYMBOL] FILE...
+//
+// Without -symbol the symbol is taken from each file name, e.g. AAPL.csv or aapl.us.txt.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"stock-bot/config"
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+func main() {
+	symbolFlag := flag.String("symbol", "", "symbol to store the prices under (default: derived from the file name)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-symbol SYMBOL] FILE...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Import into the same storage the bot keeps its prices in
+	storage, err := config.LoadStorage()
+	if err != nil {
+		slog.Error("Configuration error", "error", err)
+		os.Exit(1)
+	}
+	db, err := services.OpenStore(storage)
+	if err != nil {
+		slog.Error("Database connection error", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			slog.Error("Error closing database connection", "error", err)
+		}
+	}()
+
+	failed := false
+	for _, path := range flag.Args() {
+		if err := importFile(db, path, *symbolFlag); err != nil {
+			slog.Error("Error importing", "path", path, "error", err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// importFile parses one CSV file and stores its closing prices
+func importFile(db services.Store, path, symbol string) error {
+	if symbol == "" {
+		derived, ok := services.SymbolFromCSVPath(path)
+		if !ok {
+			return fmt.Errorf("cannot derive a symbol from the file name, use -symbol")
+		}
+		symbol = derived
+	} else if normalized, ok := models.NormalizeSymbol(symbol); ok {
+		symbol = normalized
+	} else {
+		return fmt.Errorf("invalid symbol %q", symbol)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	points, err := services.ParseHistoryCSV(file)
+	if err != nil {
+		return err
+	}
+
+	inserted, err := db.SaveHistoricalCloses(symbol, points)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Imported closing prices", "symbol", symbol, "rows", len(points), "inserted", inserted)
+	return nil
+}
@@ -0,0 +1,77 @@
+// Command migrateprices converts price records stored as strings by earlier versions into numbers,
+// so they can be used in aggregation queries.
+//
+// Usage:
+//
+//	migrateprices [-dry-run]
+//
+// The bot reads both forms, so the migration can run while it is up, and running it again is harmless.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"stock-bot/services"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "only count the records that would be converted")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-dry-run]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		slog.Warn(".env file not found, using environment variables")
+	}
+
+	db, err := services.NewDatabase(os.Getenv("MONGODB_URI"))
+	if err != nil {
+		slog.Error("Database connection error", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			slog.Error("Error closing database connection", "error", err)
+		}
+	}()
+
+	if err := migrate(context.Background(), db, *dryRun); err != nil {
+		slog.Error("Error migrating prices", "error", err)
+		os.Exit(1)
+	}
+}
+
+// migrate converts the string prices and reports any that couldn't be parsed
+func migrate(ctx context.Context, db *services.Database, dryRun bool) error {
+	pending, err := db.CountStringPrices(ctx)
+	if err != nil {
+		return err
+	}
+	if dryRun || pending == 0 {
+		slog.Info("Price records stored as strings", "count", pending)
+		return nil
+	}
+
+	converted, err := db.MigrateStringPrices(ctx)
+	if err != nil {
+		return err
+	}
+
+	remaining, err := db.CountStringPrices(ctx)
+	if err != nil {
+		return err
+	}
+	if remaining > 0 {
+		slog.Warn("Some prices aren't numbers and were left as strings", "count", remaining)
+	}
+	slog.Info("Migrated prices", "converted", converted, "remaining", remaining)
+	return nil
+}
@@ -0,0 +1,617 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/i18n"
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// Chat command constants
+const (
+	quoteCacheTTL       = 2 * time.Minute  // How long on-demand quotes are served from cache
+	commandFetchTimeout = 45 * time.Second // Upper bound for fetching data in a command
+	defaultHistoryDays  = 10               // Number of closes shown by /history by default
+	maxHistoryDays      = 60               // Maximum number of closes shown by /history
+)
+
+// commandHandlers groups the dependencies used by chat commands
+type commandHandlers struct {
+	bot        services.ChatBot
+	db         *services.Database
+	config     models.Config
+	cache      *services.MemoryQuoteCache
+	history    *services.HistoryFetcher
+	search     *services.SymbolSearcher
+	onboarding *onboardingSessions
+	delivery   *services.Delivery
+
+	// Announcements awaiting /confirm, keyed by the admin's chat ID
+	announcementsMu sync.Mutex
+	announcements   map[string]string
+}
+
+// chartPeriods maps /chart period arguments to a number of days
+var chartPeriods = map[string]int{
+	"1w": 7,
+	"1m": 30,
+	"3m": 90,
+	"1y": 365,
+}
+
+// startCommandBot starts the interactive Telegram command loop in the background
+func startCommandBot(ctx context.Context, db *services.Database, delivery *services.Delivery, config models.Config) error {
+	bot, err := services.NewTelegramBot(config.TelegramBotToken)
+	if err != nil {
+		return err
+	}
+	newCommandHandlers(bot, db, delivery, config).register()
+
+	// Commands in progress at shutdown finish before the process exits
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		bot.Run(ctx)
+		bot.Wait()
+	}()
+	return nil
+}
+
+// startLineBot creates the Line webhook bot answering the same chat commands; its webhook is served by the
+// embedded HTTP server
+func startLineBot(ctx context.Context, db *services.Database, delivery *services.Delivery, config models.Config) (*services.LineBot, error) {
+	bot, err := services.NewLineBot(config.LineChannelToken, config.LineChannelSecret, config.PublicURL)
+	if err != nil {
+		return nil, err
+	}
+	newCommandHandlers(bot, db, delivery, config).register()
+
+	// Commands in progress at shutdown finish before the process exits
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		<-ctx.Done()
+		bot.Wait()
+	}()
+	return bot, nil
+}
+
+// newCommandHandlers creates the chat command handlers replying through a bot
+func newCommandHandlers(bot services.ChatBot, db *services.Database, delivery *services.Delivery, config models.Config) *commandHandlers {
+	return &commandHandlers{
+		bot:     bot,
+		db:      db,
+		config:  config,
+		cache:   services.NewMemoryQuoteCache(quoteCacheTTL),
+		history: services.NewHistoryFetcher(),
+		search:  services.NewSymbolSearcher(),
+		onboarding: &onboardingSessions{
+			sessions: make(map[string]*onboardingSession),
+		},
+		delivery:      delivery,
+		announcements: make(map[string]string),
+	}
+}
+
+// newChatUser returns the default preferences of a chat that has none saved yet
+func newChatUser(cmd services.BotCommand) models.User {
+	return models.User{ChatID: cmd.ChatID, Channel: cmd.Channel, Username: cmd.Username}
+}
+
+// register binds every chat command to its handler
+func (h *commandHandlers) register() {
+	h.bot.Handle("start", h.handleStart)
+	h.bot.Handle("cancel", h.handleCancel)
+	h.bot.Handle("help", h.handleHelp)
+	h.bot.Handle("list", h.handleList)
+	h.bot.Handle("price", h.handlePrice)
+	h.bot.Handle("chart", h.handleChart)
+	h.bot.Handle("history", h.handleHistory)
+	h.bot.Handle("search", h.handleSearch)
+	h.bot.Handle("notify", h.requireChatDelivery(h.handleNotify))
+	h.bot.Handle("alertstyle", h.requireChatDelivery(h.handleAlertStyle))
+	h.bot.Handle("mute", h.requireChatDelivery(h.handleMute))
+	h.bot.Handle("unmute", h.requireChatDelivery(h.handleUnmute))
+	h.bot.Handle("alert", h.requireChatDelivery(h.handleAlert))
+	h.bot.Handle("alerts", h.requireChatDelivery(h.handleAlerts))
+	h.bot.Handle("portfolio", h.handlePortfolio)
+	h.bot.Handle("buy", h.handleBuy)
+	h.bot.Handle("sell", h.handleSell)
+	h.bot.Handle("add", h.requireAdmin(h.handleAdd))
+	h.bot.Handle("remove", h.requireAdmin(h.handleRemove))
+	h.bot.Handle("setthreshold", h.requireAdmin(h.handleSetThreshold))
+	h.bot.Handle("grant", h.requireAdmin(h.handleGrant))
+	h.bot.Handle("pause", h.requireAdmin(h.handlePause))
+	h.bot.Handle("resume", h.requireAdmin(h.handleResume))
+	h.bot.Handle("announce", h.requireAdmin(h.handleAnnounce))
+	h.bot.Handle("confirm", h.requireAdmin(h.handleConfirm))
+	h.bot.HandleText(h.handleText)
+}
+
+// handleText routes plain messages to onboarding, or maps questions such as "how is nvidia doing" onto commands
+func (h *commandHandlers) handleText(ctx context.Context, cmd services.BotCommand) error {
+	// Replies during setup belong to the onboarding conversation
+	if handled, err := h.continueOnboarding(ctx, cmd); handled {
+		return err
+	}
+
+	// In groups, names such as "apple" or tickers such as "IT" come up in ordinary conversation, so only
+	// questions, requests and messages addressed to the bot are answered
+	if cmd.Group && !cmd.Mention && !services.AsksForQuote(cmd.Text) {
+		return nil
+	}
+
+	intent, ok := services.ParseIntent(cmd.Text)
+	if !ok {
+		// Ordinary group conversation is ignored
+		return nil
+	}
+
+	slog.Info("Interpreted message as command", "chat", cmd.ChatID, "command", intent.Kind, "symbol", intent.Symbol)
+
+	cmd.Args = []string{intent.Symbol}
+	switch intent.Kind {
+	case services.IntentChart:
+		if intent.Period != "" {
+			cmd.Args = append(cmd.Args, intent.Period)
+		}
+		return h.handleChart(ctx, cmd)
+	case services.IntentHistory:
+		return h.handleHistory(ctx, cmd)
+	default:
+		return h.handlePrice(ctx, cmd)
+	}
+}
+
+// handlePrice replies with the current price, day change and volume of any symbol
+func (h *commandHandlers) handlePrice(ctx context.Context, cmd services.BotCommand) error {
+	if len(cmd.Args) == 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /price SYMBOL"))
+	}
+
+	symbol, ok := h.resolveSymbol(ctx, cmd.Args[0])
+	if !ok {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Invalid symbol: %s", cmd.Args[0]))
+	}
+
+	quote, err := h.getQuote(ctx, symbol)
+	if err != nil {
+		return fmt.Errorf("could not fetch a quote for %s: %w", symbol, err)
+	}
+
+	return h.bot.Reply(ctx, cmd.ChatID, formatQuote(quote))
+}
+
+// getQuote returns a cached quote for the symbol or fetches a fresh one
+func (h *commandHandlers) getQuote(ctx context.Context, symbol string) (models.Quote, error) {
+	if quote, ok := h.cache.Get(symbol); ok {
+		slog.Debug("Serving cached quote", "symbol", symbol)
+		return quote, nil
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, commandFetchTimeout)
+	defer cancel()
+
+	quote, err := priceSource.FetchCached(fetchCtx, symbol)
+	if err != nil {
+		return models.Quote{}, err
+	}
+
+	h.cache.Set(quote)
+	return quote, nil
+}
+
+// handleChart replies with a rendered closing price chart for a symbol
+func (h *commandHandlers) handleChart(ctx context.Context, cmd services.BotCommand) error {
+	if len(cmd.Args) == 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /chart SYMBOL [1w|1m|3m|1y]"))
+	}
+
+	symbol, ok := h.resolveSymbol(ctx, cmd.Args[0])
+	if !ok {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Invalid symbol: %s", cmd.Args[0]))
+	}
+
+	period := "1m"
+	if len(cmd.Args) > 1 {
+		period = strings.ToLower(cmd.Args[1])
+	}
+	days, ok := chartPeriods[period]
+	if !ok {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Period must be one of 1w, 1m, 3m, 1y"))
+	}
+
+	points, err := h.loadHistory(ctx, symbol, days)
+	if err != nil {
+		return fmt.Errorf("could not load history for %s: %w", symbol, err)
+	}
+
+	chart, err := services.RenderPriceChart(points)
+	if err != nil {
+		return fmt.Errorf("could not render chart for %s: %w", symbol, err)
+	}
+
+	first, last := points[0], points[len(points)-1]
+	caption := fmt.Sprintf("%s %s: %s → %s (%+.2f%%)",
+		symbol,
+		period,
+		models.FormatPrice(symbol, first.Close),
+		models.FormatPrice(symbol, last.Close),
+		(last.Close-first.Close)/first.Close*100,
+	)
+
+	return h.bot.ReplyPhoto(ctx, cmd.ChatID, chart, caption)
+}
+
+// handleHistory replies with a table of the most recent closing prices and daily changes
+func (h *commandHandlers) handleHistory(ctx context.Context, cmd services.BotCommand) error {
+	if len(cmd.Args) == 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Usage: /history SYMBOL [days, max %d]", maxHistoryDays))
+	}
+
+	symbol, ok := h.resolveSymbol(ctx, cmd.Args[0])
+	if !ok {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Invalid symbol: %s", cmd.Args[0]))
+	}
+
+	days := defaultHistoryDays
+	if len(cmd.Args) > 1 {
+		parsed, err := strconv.Atoi(cmd.Args[1])
+		if err != nil || parsed < 1 || parsed > maxHistoryDays {
+			return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Days must be a number between 1 and %d", maxHistoryDays))
+		}
+		days = parsed
+	}
+
+	// Look back far enough in calendar days to cover weekends and holidays
+	stored, err := h.db.GetPriceHistory(symbol, days*2+7)
+	if err != nil {
+		return fmt.Errorf("could not load history for %s: %w", symbol, err)
+	}
+
+	points := toPricePoints(stored)
+	if len(points) == 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("No closing prices stored for %s yet. Try /chart %s to backfill.", symbol, symbol))
+	}
+
+	return h.bot.ReplyMarkdown(ctx, cmd.ChatID, formatHistoryTable(symbol, points, days))
+}
+
+// formatHistoryTable renders up to rows recent closing prices as a monospace table, newest first
+func formatHistoryTable(symbol string, points []models.PricePoint, rows int) string {
+	var table strings.Builder
+	table.WriteString(locale.Sprintf("*%s* closing prices", services.EscapeMarkdownV2(symbol)) + "\n```\n")
+	// Padded by display width, as Hangul headers take two columns a character
+	table.WriteString(i18n.PadRight(locale.Text("Date"), 10) + " " + i18n.PadLeft(locale.Text("Close"), 12) + " " + i18n.PadLeft(locale.Text("Change"), 8) + "\n")
+
+	oldest := max(len(points)-rows, 0)
+	for i := len(points) - 1; i >= oldest; i-- {
+		change := "-"
+		if i > 0 && points[i-1].Close != 0 {
+			change = fmt.Sprintf("%+.2f%%", (points[i].Close-points[i-1].Close)/points[i-1].Close*100)
+		}
+
+		table.WriteString(services.EscapeMarkdownV2Code(fmt.Sprintf("%-10s %12s %8s\n",
+			points[i].Timestamp.Format("2006-01-02"),
+			models.FormatPrice(symbol, points[i].Close),
+			change,
+		)))
+	}
+
+	table.WriteString("```")
+	return table.String()
+}
+
+// handleNotify shows or toggles which message kinds the chat receives
+func (h *commandHandlers) handleNotify(ctx context.Context, cmd services.BotCommand) error {
+	user, err := h.db.GetUser(cmd.ChatID)
+	if err != nil {
+		user = newChatUser(cmd)
+	}
+
+	if len(cmd.Args) == 0 {
+		var message strings.Builder
+		message.WriteString(locale.Text("Notification settings:") + "\n")
+		for _, kind := range models.MessageKinds {
+			state := "on"
+			if !user.Receives(kind) {
+				state = "off"
+			}
+			message.WriteString(fmt.Sprintf("• %s: %s\n", kind, locale.Text(state)))
+		}
+		message.WriteString("\n" + locale.Text("Usage: /notify TYPE on|off"))
+		return h.bot.Reply(ctx, cmd.ChatID, message.String())
+	}
+
+	if len(cmd.Args) != 2 || !slices.Contains(models.MessageKinds, models.MessageKind(strings.ToLower(cmd.Args[0]))) {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Usage: /notify TYPE on|off (types: %s)", joinKinds(models.MessageKinds)))
+	}
+
+	kind := models.MessageKind(strings.ToLower(cmd.Args[0]))
+	var enabled bool
+	switch strings.ToLower(cmd.Args[1]) {
+	case "on", "yes":
+		enabled = true
+	case "off", "no":
+		enabled = false
+	default:
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Please use on or off"))
+	}
+
+	user.SetReceives(kind, enabled)
+	if err := h.db.SaveUser(user); err != nil {
+		return fmt.Errorf("could not save notification settings: %w", err)
+	}
+
+	state := "off"
+	if enabled {
+		state = "on"
+	}
+	return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("✅ %s notifications turned %s", kind, locale.Text(state)))
+}
+
+// handleAlertStyle shows or changes how much detail price alerts carry in this chat
+func (h *commandHandlers) handleAlertStyle(ctx context.Context, cmd services.BotCommand) error {
+	user, err := h.db.GetUser(cmd.ChatID)
+	if err != nil {
+		user = newChatUser(cmd)
+	}
+
+	if len(cmd.Args) == 0 {
+		current := string(user.AlertStyle)
+		if current == "" {
+			current = locale.Text("channel default")
+		}
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Alert style: %s\n\nUsage: /alertstyle compact|standard|verbose", current))
+	}
+
+	style, err := models.ParseAlertStyle(cmd.Args[0])
+	if err != nil {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /alertstyle compact|standard|verbose"))
+	}
+
+	user.AlertStyle = style
+	if err := h.db.SaveUser(user); err != nil {
+		return fmt.Errorf("could not save alert style: %w", err)
+	}
+	return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("✅ Alerts in this chat are now %s", style))
+}
+
+// joinKinds renders message kinds as a comma separated list
+func joinKinds(kinds []models.MessageKind) string {
+	names := make([]string, len(kinds))
+	for i, kind := range kinds {
+		names[i] = string(kind)
+	}
+	return strings.Join(names, ", ")
+}
+
+// handleMute silences alerts for a symbol in this chat, optionally for a limited time
+func (h *commandHandlers) handleMute(ctx context.Context, cmd services.BotCommand) error {
+	if len(cmd.Args) == 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /mute SYMBOL [duration, e.g. 2h, 3d, 1w, or today]"))
+	}
+
+	symbol, ok := h.resolveSymbol(ctx, cmd.Args[0])
+	if !ok {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Invalid symbol: %s", cmd.Args[0]))
+	}
+
+	var until time.Time
+	if len(cmd.Args) > 1 && strings.EqualFold(cmd.Args[1], "today") {
+		until = h.endOfDay(time.Now())
+	} else if len(cmd.Args) > 1 {
+		duration, err := parseMuteDuration(cmd.Args[1])
+		if err != nil {
+			return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Duration must look like 30m, 2h, 3d or 1w"))
+		}
+		until = time.Now().Add(duration)
+	}
+
+	user, err := h.db.GetUser(cmd.ChatID)
+	if err != nil {
+		user = newChatUser(cmd)
+	}
+	if user.MutedUntil == nil {
+		user.MutedUntil = make(map[string]time.Time)
+	}
+	user.MutedUntil[symbol] = until
+
+	if err := h.db.SaveUser(user); err != nil {
+		return fmt.Errorf("could not save mute: %w", err)
+	}
+
+	if until.IsZero() {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("🔇 Alerts for %s muted until you send /unmute %s", symbol, symbol))
+	}
+	return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("🔇 Alerts for %s muted until %s", symbol, until.Format("2006-01-02 15:04")))
+}
+
+// handleUnmute re-enables alerts for a muted symbol in this chat
+func (h *commandHandlers) handleUnmute(ctx context.Context, cmd services.BotCommand) error {
+	if len(cmd.Args) == 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /unmute SYMBOL"))
+	}
+
+	symbol, ok := h.resolveSymbol(ctx, cmd.Args[0])
+	if !ok {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Invalid symbol: %s", cmd.Args[0]))
+	}
+
+	user, err := h.db.GetUser(cmd.ChatID)
+	if err != nil || !user.IsMuted(symbol, time.Now()) {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("%s is not muted", symbol))
+	}
+
+	delete(user.MutedUntil, symbol)
+	if err := h.db.SaveUser(user); err != nil {
+		return fmt.Errorf("could not save unmute: %w", err)
+	}
+	return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("🔔 Alerts for %s unmuted", symbol))
+}
+
+// endOfDay returns the next midnight in the configured time zone, when a mute "for today" ends
+func (h *commandHandlers) endOfDay(now time.Time) time.Time {
+	loc, err := time.LoadLocation(h.config.TimeZone)
+	if err != nil {
+		loc = time.Local
+	}
+	year, month, day := now.In(loc).Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, loc)
+}
+
+// parseMuteDuration parses durations like "30m", "2h", "3d" or "1w"
+func parseMuteDuration(value string) (time.Duration, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	multipliers := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+
+	for suffix, unit := range multipliers {
+		if count, found := strings.CutSuffix(value, suffix); found {
+			n, err := strconv.Atoi(count)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return duration, nil
+}
+
+// handleSetThreshold adjusts the global or per-symbol alert threshold at runtime
+func (h *commandHandlers) handleSetThreshold(ctx context.Context, cmd services.BotCommand) error {
+	if len(cmd.Args) == 0 {
+		thresholds, err := loadAlertThresholds(h.db)
+		if err != nil {
+			return fmt.Errorf("could not load thresholds: %w", err)
+		}
+		return h.bot.Reply(ctx, cmd.ChatID, formatThresholds(thresholds)+"\n\n"+locale.Text("Usage: /setthreshold [SYMBOL] PCT"))
+	}
+
+	symbol := ""
+	percentArg := cmd.Args[0]
+	if len(cmd.Args) > 1 {
+		normalized, ok := h.resolveSymbol(ctx, cmd.Args[0])
+		if !ok {
+			return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Invalid symbol: %s", cmd.Args[0]))
+		}
+		symbol = normalized
+		percentArg = cmd.Args[1]
+	}
+
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(percentArg, "%"), 64)
+	if err != nil || percent <= 0 || percent > 100 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Threshold must be a percentage between 0 and 100, e.g. /setthreshold NVDA 3.5"))
+	}
+
+	if err := h.db.SetAlertThreshold(symbol, percent); err != nil {
+		return fmt.Errorf("could not save threshold: %w", err)
+	}
+
+	if symbol == "" {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("✅ Global alert threshold set to %.2f%%", percent))
+	}
+	return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("✅ Alert threshold for %s set to %.2f%%", symbol, percent))
+}
+
+// formatThresholds lists the global and per-symbol alert thresholds
+func formatThresholds(thresholds models.AlertThresholds) string {
+	var message strings.Builder
+	message.WriteString(locale.Sprintf("Global alert threshold: %.2f%%", thresholds.Global))
+
+	symbols := make([]string, 0, len(thresholds.Symbols))
+	for symbol := range thresholds.Symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		message.WriteString(fmt.Sprintf("\n%s: %.2f%%", symbol, thresholds.Symbols[symbol]))
+	}
+	return message.String()
+}
+
+// loadHistory returns stored closing prices for the last N days, backfilling from Yahoo when coverage is missing
+func (h *commandHandlers) loadHistory(ctx context.Context, symbol string, days int) ([]models.PricePoint, error) {
+	stored, err := h.db.GetPriceHistory(symbol, days)
+	if err != nil {
+		return nil, err
+	}
+	points := toPricePoints(stored)
+
+	// Backfill when the stored history starts well after the requested period
+	start := time.Now().AddDate(0, 0, -days)
+	if len(points) > 0 && !points[0].Timestamp.After(start.AddDate(0, 0, 5)) {
+		return points, nil
+	}
+
+	slog.Info("Backfilling history", "symbol", symbol, "days", days)
+	fetchCtx, cancel := context.WithTimeout(ctx, commandFetchTimeout)
+	defer cancel()
+
+	backfilled, err := h.history.FetchDailyCloses(fetchCtx, symbol, start, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// The chart shows today's price so far, but only completed days are stored as closes
+	if _, err := h.db.SaveHistoricalCloses(symbol, models.CompletedCloses(symbol, backfilled, time.Now())); err != nil {
+		slog.Error("Error saving backfilled history", "symbol", symbol, "error", err)
+	}
+
+	return backfilled, nil
+}
+
+// toPricePoints converts stored price records into chart points
+func toPricePoints(records []models.MongoDTO) []models.PricePoint {
+	points := make([]models.PricePoint, 0, len(records))
+	for _, record := range records {
+		points = append(points, models.PricePoint{Timestamp: record.Timestamp, Close: float64(record.Price), Volume: record.Volume})
+	}
+	return points
+}
+
+// formatQuote renders a quote as a short chat message
+func formatQuote(quote models.Quote) string {
+	direction := "⚪"
+	if quote.Change > 0 {
+		direction = "🟢"
+	} else if quote.Change < 0 {
+		direction = "🔴"
+	}
+
+	var message strings.Builder
+	message.WriteString(fmt.Sprintf("%s %s %s\n", direction, quote.Symbol, models.FormatPrice(quote.Symbol, quote.Price)))
+	message.WriteString(locale.Sprintf("Change: %+.2f (%+.2f%%)", quote.Change, quote.ChangePercent) + "\n")
+	if quote.Volume > 0 {
+		message.WriteString(locale.Sprintf("Volume: %s", formatVolume(quote.Volume)) + "\n")
+	}
+	message.WriteString(locale.Sprintf("As of %s", quote.Timestamp.Format("2006-01-02 15:04:05")))
+	return message.String()
+}
+
+// formatVolume adds thousands separators to a share volume
+func formatVolume(volume int64) string {
+	digits := strconv.FormatInt(volume, 10)
+	var formatted strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			formatted.WriteByte(',')
+		}
+		formatted.WriteRune(digit)
+	}
+	return formatted.String()
+}
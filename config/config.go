@@ -0,0 +1,687 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"stock-bot/calendar"
+	"stock-bot/i18n"
+	"stock-bot/models"
+
+	"github.com/joho/godotenv"
+)
+
+// Environment variable keys
+const (
+	envConfigFile     = "CONFIG_FILE"
+	envMongoURI       = "MONGODB_URI"
+	envPostgresURL    = "POSTGRES_URL"
+	envStorageDriver  = "STORAGE_DRIVER"
+	envSQLitePath     = "SQLITE_PATH"
+	envRedisURL       = "REDIS_URL"
+	envQuoteCacheTTL  = "QUOTE_CACHE_SECONDS"
+	envTelegramToken  = "TELEGRAM_BOT_TOKEN"
+	envTelegramChatID = "TELEGRAM_CHAT_ID"
+	envLineToken      = "LINE_CHANNEL_ACCESS_TOKEN"
+	envLineSecret     = "LINE_CHANNEL_SECRET"
+	envTelegramFormat = "TELEGRAM_FORMAT"
+	envLineFormat     = "LINE_FORMAT"
+	envTelegramStyle  = "TELEGRAM_ALERT_STYLE"
+	envLineStyle      = "LINE_ALERT_STYLE"
+	envTemplateDir    = "MESSAGE_TEMPLATE_DIR"
+	envLanguage       = "BOT_LANGUAGE"
+	envSlackWebhook   = "SLACK_WEBHOOK_URL"
+	envSlackToken     = "SLACK_BOT_TOKEN"
+	envSlackChannel   = "SLACK_CHANNEL"
+	envSlackStyle     = "SLACK_ALERT_STYLE"
+	envSMTPHost       = "SMTP_HOST"
+	envSMTPPort       = "SMTP_PORT"
+	envSMTPUser       = "SMTP_USER"
+	envSMTPPass       = "SMTP_PASS"
+	envSMTPFrom       = "SMTP_FROM"
+	envSMTPTo         = "SMTP_TO"
+	envWebhookURLs    = "WEBHOOK_URLS"
+	envWebhookSecret  = "WEBHOOK_SECRET"
+	envNtfyURL        = "NTFY_URL"
+	envNtfyToken      = "NTFY_TOKEN"
+	envPushoverToken  = "PUSHOVER_TOKEN"
+	envPushoverUser   = "PUSHOVER_USER"
+	envPushPriority   = "PUSH_HIGH_PRIORITY_PERCENT"
+	envTwilioSID      = "TWILIO_ACCOUNT_SID"
+	envTwilioToken    = "TWILIO_AUTH_TOKEN"
+	envTwilioFrom     = "TWILIO_FROM"
+	envTwilioTo       = "TWILIO_TO"
+	envSMSCritical    = "SMS_CRITICAL_PERCENT"
+	envSMSDailyCap    = "SMS_DAILY_CAP"
+	envTimezone       = "TIMEZONE"
+	envCheckHour      = "CHECK_HOUR"
+	envClosingTimes   = "CLOSING_TIMES"
+	envPreMarket      = "PRE_MARKET_MINUTES"
+	envPostMarket     = "POST_MARKET_MINUTES"
+	envAlertThreshold = "ALERT_THRESHOLD"
+	envCryptoAlert    = "CRYPTO_ALERT_THRESHOLD"
+	envFXAlert        = "FX_ALERT_THRESHOLD"
+	envAlertCooldown  = "ALERT_COOLDOWN_MINUTES"
+	envAlertStep      = "ALERT_STEP_PERCENT"
+	envOpenThreshold  = "OPEN_ALERT_THRESHOLD"
+	envMoveThreshold  = "MOVE_ALERT_THRESHOLD"
+	envMoveWindow     = "MOVE_ALERT_MINUTES"
+	envVolumeMultiple = "VOLUME_ALERT_MULTIPLE"
+	envCurrencies     = "SYMBOL_CURRENCIES"
+	envThresholds     = "SYMBOL_THRESHOLDS"
+	envPriorities     = "SYMBOL_PRIORITIES"
+	envAdminUserIDs   = "ADMIN_USER_IDS"
+	envFMPAPIKey      = "FMP_API_KEY"
+	envAlphaVantage   = "ALPHAVANTAGE_API_KEY"
+	envFinnhubAPIKey  = "FINNHUB_API_KEY"
+	envStreaming      = "REALTIME_STREAMING"
+	envPriceSources   = "PRICE_SOURCES"
+	envTickers        = "TICKERS"
+	envFXRates        = "REPORT_FX_RATES"
+	envEconomicLead   = "ECONOMIC_ALERT_MINUTES"
+	envDelistLimit    = "DELISTED_FAILURE_LIMIT"
+	envFailureAlert   = "FETCH_FAILURE_ALERT_PERCENT"
+	envStreakDays     = "STREAK_ALERT_DAYS"
+	envRetentionDays  = "INTRADAY_RETENTION_DAYS"
+	envBackfillDays   = "BACKFILL_DAYS"
+	envBackfillSource = "BACKFILL_SOURCES"
+	envBrowserTabs    = "BROWSER_MAX_TABS"
+	envBrowserNavs    = "BROWSER_MAX_NAVIGATIONS"
+	envBrowserAge     = "BROWSER_MAX_AGE_HOURS"
+	envCrossSymbols   = "CROSSOVER_SYMBOLS"
+	envCrossPeriods   = "CROSSOVER_PERIODS"
+	envCrossAverage   = "CROSSOVER_AVERAGE"
+	envSignalWeights  = "SIGNAL_WEIGHTS"
+	envSheetsCreds    = "GOOGLE_SHEETS_CREDENTIALS"
+	envSheetID        = "GOOGLE_SHEET_ID"
+	envIFTTTKey       = "IFTTT_WEBHOOK_KEY"
+	envZapierHookURL  = "ZAPIER_HOOK_URL"
+	envMQTTBrokerURL  = "MQTT_BROKER_URL"
+	envMQTTUsername   = "MQTT_USERNAME"
+	envMQTTPassword   = "MQTT_PASSWORD"
+	envMQTTQoS        = "MQTT_QOS"
+	envMQTTTopic      = "MQTT_TOPIC_PREFIX"
+	envHADiscovery    = "HOME_ASSISTANT_DISCOVERY"
+	envNATSURL        = "NATS_URL"
+	envGrafanaURL     = "GRAFANA_URL"
+	envHTTPAddr       = "HTTP_ADDR"
+	envPublicURL      = "PUBLIC_URL"
+	envAdminAPIToken  = "ADMIN_API_TOKEN"
+	envGrafanaAPIKey  = "GRAFANA_API_KEY"
+	envGrafanaDash    = "GRAFANA_DASHBOARD_UID"
+	envKafkaBrokers   = "KAFKA_BROKERS"
+	envEventPrefix    = "EVENT_TOPIC_PREFIX"
+	envLogLevel       = "LOG_LEVEL"
+	envLogFormat      = "LOG_FORMAT"
+)
+
+// Load builds the configuration from the defaults, the optional config file and the environment, in increasing precedence
+func Load() (models.Config, error) {
+	config, err := LoadStorage()
+	if err != nil {
+		return config, err
+	}
+
+	// Redis cache of the latest quote per symbol (optional) and how long its entries live
+	setFromEnv(&config.RedisURL, envRedisURL)
+	if secondsStr := os.Getenv(envQuoteCacheTTL); secondsStr != "" {
+		if seconds, err := strconv.Atoi(secondsStr); err == nil && seconds > 0 {
+			config.QuoteCacheTTL = time.Duration(seconds) * time.Second
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envQuoteCacheTTL, "default", config.QuoteCacheTTL)
+		}
+	}
+
+	// Telegram settings
+	setFromEnv(&config.TelegramBotToken, envTelegramToken)
+	setFromEnv(&config.TelegramChatID, envTelegramChatID)
+
+	// Admin users; the owner of the configured Telegram chat is the admin by default
+	if adminIDs := os.Getenv(envAdminUserIDs); adminIDs != "" {
+		config.AdminUserIDs = splitList(adminIDs)
+	} else if config.TelegramChatID != "" {
+		config.AdminUserIDs = []string{config.TelegramChatID}
+	}
+
+	// Financial Modeling Prep key for analyst rating changes (optional)
+	setFromEnv(&config.FMPAPIKey, envFMPAPIKey)
+
+	// Alpha Vantage key to fetch quotes from its API instead of Yahoo (optional)
+	setFromEnv(&config.AlphaVantageAPIKey, envAlphaVantage)
+
+	// Price sources tried in order for each symbol (default: alphavantage when keyed, yahoo-api, chromedp)
+	if sources := os.Getenv(envPriceSources); sources != "" {
+		config.PriceSources = splitList(strings.ToLower(sources))
+	}
+
+	// Symbols to monitor instead of the built-in list, seeding the watchlist on first start (optional)
+	if tickers := os.Getenv(envTickers); tickers != "" {
+		config.Tickers = normalizeTickers(strings.Split(tickers, ","), envTickers)
+	}
+
+	// Exchange rates appended to the daily report, e.g. USD/KRW,EUR/USD (optional)
+	if rates := os.Getenv(envFXRates); rates != "" {
+		config.ReportFXRates = normalizeFXPairs(strings.Split(rates, ","), envFXRates)
+	}
+
+	// Finnhub key and whether equity alerts follow its live trade stream instead of polling (optional)
+	setFromEnv(&config.FinnhubAPIKey, envFinnhubAPIKey)
+	if streaming := os.Getenv(envStreaming); streaming != "" {
+		enabled, err := strconv.ParseBool(streaming)
+		if err != nil {
+			slog.Warn("Invalid value, polling for realtime prices", "setting", envStreaming)
+		} else if enabled && config.FinnhubAPIKey == "" {
+			slog.Warn("Streaming requires a Finnhub key, polling for realtime prices", "setting", envStreaming, "required", envFinnhubAPIKey)
+			enabled = false
+		}
+		config.RealtimeStreaming = enabled
+	}
+
+	// Minutes before a high-impact economic event to send a reminder; unset disables reminders
+	if leadStr := os.Getenv(envEconomicLead); leadStr != "" {
+		if minutes, err := strconv.Atoi(leadStr); err == nil && minutes > 0 {
+			config.EconomicAlertLead = time.Duration(minutes) * time.Minute
+		} else {
+			slog.Warn("Invalid value, economic event reminders disabled", "setting", envEconomicLead)
+		}
+	}
+
+	// Line settings
+	setFromEnv(&config.LineChannelToken, envLineToken)
+	setFromEnv(&config.LineChannelSecret, envLineSecret)
+
+	// Slack settings: an incoming webhook, or a bot token with a channel for chat.postMessage
+	setFromEnv(&config.SlackWebhookURL, envSlackWebhook)
+	setFromEnv(&config.SlackBotToken, envSlackToken)
+	setFromEnv(&config.SlackChannel, envSlackChannel)
+
+	// SMTP settings for HTML email; SMTP_TO is a comma-separated list of recipients
+	setFromEnv(&config.SMTP.Host, envSMTPHost)
+	setFromEnv(&config.SMTP.Port, envSMTPPort)
+	setFromEnv(&config.SMTP.Username, envSMTPUser)
+	setFromEnv(&config.SMTP.Password, envSMTPPass)
+	setFromEnv(&config.SMTP.From, envSMTPFrom)
+	if to := os.Getenv(envSMTPTo); to != "" {
+		config.SMTP.To = splitList(to)
+	}
+
+	// Generic JSON webhooks: a comma-separated list of URLs, signed with the secret when one is set
+	if urls := os.Getenv(envWebhookURLs); urls != "" {
+		config.WebhookURLs = splitList(urls)
+	}
+	setFromEnv(&config.WebhookSecret, envWebhookSecret)
+
+	// Push notifications through ntfy and Pushover; alerts moving at least the percentage arrive at high priority,
+	// and at twice it as urgent where the service allows; 0 sends every alert at normal priority
+	setFromEnv(&config.NtfyURL, envNtfyURL)
+	setFromEnv(&config.NtfyToken, envNtfyToken)
+	setFromEnv(&config.PushoverToken, envPushoverToken)
+	setFromEnv(&config.PushoverUser, envPushoverUser)
+	if percentStr := os.Getenv(envPushPriority); percentStr != "" {
+		if percent, err := strconv.ParseFloat(strings.TrimSuffix(percentStr, "%"), 64); err == nil && percent >= 0 {
+			config.PushHighPriorityPercent = percent
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envPushPriority, "default", config.PushHighPriorityPercent)
+		}
+	}
+
+	// Twilio SMS for critical alerts: only moves of at least SMS_CRITICAL_PERCENT are texted, to each number in
+	// the comma-separated TWILIO_TO, and no more than SMS_DAILY_CAP texts go out a day
+	setFromEnv(&config.Twilio.AccountSID, envTwilioSID)
+	setFromEnv(&config.Twilio.AuthToken, envTwilioToken)
+	setFromEnv(&config.Twilio.From, envTwilioFrom)
+	if to := os.Getenv(envTwilioTo); to != "" {
+		config.Twilio.To = splitList(to)
+	}
+	if percentStr := os.Getenv(envSMSCritical); percentStr != "" {
+		if percent, err := strconv.ParseFloat(strings.TrimSuffix(percentStr, "%"), 64); err == nil && percent > 0 {
+			config.Twilio.CriticalPercent = percent
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envSMSCritical, "default", config.Twilio.CriticalPercent)
+		}
+	}
+	if capStr := os.Getenv(envSMSDailyCap); capStr != "" {
+		if dailyCap, err := strconv.Atoi(capStr); err == nil && dailyCap >= 0 {
+			config.Twilio.DailyCap = dailyCap
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envSMSDailyCap, "default", config.Twilio.DailyCap)
+		}
+	}
+
+	// Report and alert format per messenger: rich (default) or plain
+	setFromEnv(&config.TelegramFormat, envTelegramFormat)
+	setFromEnv(&config.LineFormat, envLineFormat)
+
+	// Default alert verbosity per messenger: compact, standard (default) or verbose
+	setFromEnv(&config.TelegramAlertStyle, envTelegramStyle)
+	setFromEnv(&config.LineAlertStyle, envLineStyle)
+	setFromEnv(&config.SlackAlertStyle, envSlackStyle)
+
+	// Directory of report and alert templates overriding the built-in wording, per channel (optional)
+	setFromEnv(&config.MessageTemplateDir, envTemplateDir)
+
+	// Language of chat command replies and of the Telegram and Line reports and alerts (en, ko)
+	setFromEnv(&config.Language, envLanguage)
+	language, err := i18n.ParseLanguage(config.Language)
+	if err != nil {
+		slog.Warn("Invalid value, using default", "setting", envLanguage, "default", i18n.English)
+	}
+	config.Language = string(language)
+
+	// Ensure at least one messaging service is configured
+	if config.TelegramBotToken == "" && config.LineChannelToken == "" && config.SlackWebhookURL == "" && config.SlackBotToken == "" && config.SMTP.Host == "" && len(config.WebhookURLs) == 0 &&
+		config.NtfyURL == "" && config.PushoverToken == "" {
+		return config, fmt.Errorf("at least one messaging service (Telegram, Line, Slack, email, webhook, ntfy or Pushover) must be configured")
+	}
+
+	// Timezone settings
+	setFromEnv(&config.TimeZone, envTimezone)
+
+	// Check hour settings
+	if hourStr := os.Getenv(envCheckHour); hourStr != "" {
+		if hour, err := strconv.Atoi(hourStr); err == nil {
+			config.CheckHour = hour
+		} else {
+			config.CheckHour = -1
+		}
+	}
+	if config.CheckHour < 0 || config.CheckHour >= 24 {
+		config.CheckHour = models.DefaultConfig().CheckHour
+		slog.Warn("Invalid check hour, using default", "default", config.CheckHour)
+	}
+
+	// Default percent change that triggers a price alert, until changed with /setthreshold
+	if thresholdStr := os.Getenv(envAlertThreshold); thresholdStr != "" {
+		if threshold, err := strconv.ParseFloat(strings.TrimSuffix(thresholdStr, "%"), 64); err == nil && threshold > 0 {
+			config.PriceAlertThreshold = threshold
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envAlertThreshold, "default", config.PriceAlertThreshold)
+		}
+	}
+
+	// Threshold for crypto pairs, which swing more than equities; 0 applies the global threshold
+	if thresholdStr := os.Getenv(envCryptoAlert); thresholdStr != "" {
+		if threshold, err := strconv.ParseFloat(strings.TrimSuffix(thresholdStr, "%"), 64); err == nil && threshold >= 0 {
+			config.CryptoAlertThreshold = threshold
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envCryptoAlert, "default", config.CryptoAlertThreshold)
+		}
+	}
+
+	// Threshold for currency pairs, which rarely move more than a percent a day; 0 applies the global threshold
+	if thresholdStr := os.Getenv(envFXAlert); thresholdStr != "" {
+		if threshold, err := strconv.ParseFloat(strings.TrimSuffix(thresholdStr, "%"), 64); err == nil && threshold >= 0 {
+			config.FXAlertThreshold = threshold
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envFXAlert, "default", config.FXAlertThreshold)
+		}
+	}
+
+	// Time before a symbol is alerted again to the same chat
+	if cooldownStr := os.Getenv(envAlertCooldown); cooldownStr != "" {
+		if minutes, err := strconv.Atoi(cooldownStr); err == nil && minutes >= 0 {
+			config.AlertCooldown = time.Duration(minutes) * time.Minute
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envAlertCooldown, "default", config.AlertCooldown)
+		}
+	}
+
+	// Further move beyond the last alerted change that is alerted within the cooldown; 0 disables
+	if stepStr := os.Getenv(envAlertStep); stepStr != "" {
+		if step, err := strconv.ParseFloat(strings.TrimSuffix(stepStr, "%"), 64); err == nil && step >= 0 {
+			config.AlertStepPercent = step
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envAlertStep, "default", config.AlertStepPercent)
+		}
+	}
+
+	// Intraday alerts on the change from the session open and over the last minutes; off unless set, 0 disables each
+	if thresholdStr := os.Getenv(envOpenThreshold); thresholdStr != "" {
+		if threshold, err := strconv.ParseFloat(strings.TrimSuffix(thresholdStr, "%"), 64); err == nil && threshold >= 0 {
+			config.OpenAlertThreshold = threshold
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envOpenThreshold, "default", config.OpenAlertThreshold)
+		}
+	}
+	if thresholdStr := os.Getenv(envMoveThreshold); thresholdStr != "" {
+		if threshold, err := strconv.ParseFloat(strings.TrimSuffix(thresholdStr, "%"), 64); err == nil && threshold >= 0 {
+			config.MoveAlertThreshold = threshold
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envMoveThreshold, "default", config.MoveAlertThreshold)
+		}
+	}
+	if windowStr := os.Getenv(envMoveWindow); windowStr != "" {
+		if minutes, err := strconv.Atoi(windowStr); err == nil && minutes > 0 {
+			config.MoveAlertWindow = time.Duration(minutes) * time.Minute
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envMoveWindow, "default", config.MoveAlertWindow)
+		}
+	}
+
+	// Multiple of the 20-day average volume that triggers an unusual volume alert; 0 disables
+	if multipleStr := os.Getenv(envVolumeMultiple); multipleStr != "" {
+		if multiple, err := strconv.ParseFloat(strings.TrimSuffix(multipleStr, "x"), 64); err == nil && multiple >= 0 {
+			config.VolumeAlertMultiple = multiple
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envVolumeMultiple, "default", config.VolumeAlertMultiple)
+		}
+	}
+
+	// Consecutive resolution failures before a symbol is treated as possibly delisted; 0 disables
+	if limitStr := os.Getenv(envDelistLimit); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit >= 0 {
+			config.DelistFailureLimit = limit
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envDelistLimit, "default", config.DelistFailureLimit)
+		}
+	}
+
+	// Percentage of failed fetches in a cycle that triggers an admin alert; 0 disables
+	if percentStr := os.Getenv(envFailureAlert); percentStr != "" {
+		if percent, err := strconv.ParseFloat(percentStr, 64); err == nil && percent >= 0 && percent <= 100 {
+			config.FetchFailureAlertPercent = percent
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envFailureAlert, "default", config.FetchFailureAlertPercent)
+		}
+	}
+
+	// Consecutive up or down closes that trigger a streak alert; 0 disables
+	if daysStr := os.Getenv(envStreakDays); daysStr != "" {
+		if days, err := strconv.Atoi(daysStr); err == nil && days >= 0 {
+			config.StreakAlertDays = days
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envStreakDays, "default", config.StreakAlertDays)
+		}
+	}
+
+	// Days intraday prices are kept before the daily pruning removes them; closing prices are kept forever, 0 keeps everything
+	if daysStr := os.Getenv(envRetentionDays); daysStr != "" {
+		if days, err := strconv.Atoi(daysStr); err == nil && days >= 0 {
+			config.IntradayRetentionDays = days
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envRetentionDays, "default", config.IntradayRetentionDays)
+		}
+	}
+
+	// Days of daily closes downloaded for each watched symbol that has none stored yet, from the sources in order; 0 disables
+	if daysStr := os.Getenv(envBackfillDays); daysStr != "" {
+		if days, err := strconv.Atoi(daysStr); err == nil && days >= 0 {
+			config.BackfillDays = days
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envBackfillDays, "default", config.BackfillDays)
+		}
+	}
+	if sources := os.Getenv(envBackfillSource); sources != "" {
+		config.BackfillSources = splitList(strings.ToLower(sources))
+	}
+
+	// Headless browser: tabs open at once, and the page loads and hours after which it is restarted; 0 never restarts
+	if tabsStr := os.Getenv(envBrowserTabs); tabsStr != "" {
+		if tabs, err := strconv.Atoi(tabsStr); err == nil && tabs > 0 {
+			config.Browser.MaxTabs = tabs
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envBrowserTabs, "default", config.Browser.MaxTabs)
+		}
+	}
+	if navigationsStr := os.Getenv(envBrowserNavs); navigationsStr != "" {
+		if navigations, err := strconv.Atoi(navigationsStr); err == nil && navigations >= 0 {
+			config.Browser.MaxNavigations = navigations
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envBrowserNavs, "default", config.Browser.MaxNavigations)
+		}
+	}
+	if hoursStr := os.Getenv(envBrowserAge); hoursStr != "" {
+		if hours, err := strconv.Atoi(hoursStr); err == nil && hours >= 0 {
+			config.Browser.MaxAge = time.Duration(hours) * time.Hour
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envBrowserAge, "default", config.Browser.MaxAge)
+		}
+	}
+
+	// Moving average crossover alerts: the symbols they are on for (or "all"), the fast and slow periods and sma or ema
+	if symbols := os.Getenv(envCrossSymbols); symbols != "" {
+		config.Crossover.Symbols = splitList(strings.ToUpper(symbols))
+	}
+	if periods := os.Getenv(envCrossPeriods); periods != "" {
+		fast, slow, err := models.ParseCrossoverPeriods(periods)
+		if err != nil {
+			return config, fmt.Errorf("invalid %s value: %w", envCrossPeriods, err)
+		}
+		config.Crossover.Fast, config.Crossover.Slow = fast, slow
+	}
+	if average := strings.ToLower(os.Getenv(envCrossAverage)); average != "" {
+		if average == models.AverageSMA || average == models.AverageEMA {
+			config.Crossover.Average = average
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envCrossAverage, "default", config.Crossover.Average)
+		}
+	}
+
+	// Weights of the trend, RSI, volume and sentiment components of trading signals
+	if weights := os.Getenv(envSignalWeights); weights != "" {
+		parsed, err := models.ParseSignalWeights(weights)
+		if err != nil {
+			return config, fmt.Errorf("invalid %s value: %w", envSignalWeights, err)
+		}
+		config.SignalWeights = parsed
+	}
+
+	// Google Sheet that daily closes and alerts are appended to (optional)
+	setFromEnv(&config.GoogleSheetsCredentials, envSheetsCreds)
+	setFromEnv(&config.GoogleSheetID, envSheetID)
+
+	// IFTTT Webhooks and Zapier catch hook integrations (optional)
+	setFromEnv(&config.IFTTTWebhookKey, envIFTTTKey)
+	setFromEnv(&config.ZapierHookURL, envZapierHookURL)
+
+	// MQTT broker for quotes and alerts (optional)
+	setFromEnv(&config.MQTT.BrokerURL, envMQTTBrokerURL)
+	setFromEnv(&config.MQTT.Username, envMQTTUsername)
+	setFromEnv(&config.MQTT.Password, envMQTTPassword)
+	if qosStr := os.Getenv(envMQTTQoS); qosStr != "" {
+		if qos, err := strconv.Atoi(qosStr); err == nil && qos >= 0 && qos <= 2 {
+			config.MQTT.QoS = byte(qos)
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envMQTTQoS, "default", 0)
+		}
+	}
+	if prefix := os.Getenv(envMQTTTopic); prefix != "" {
+		config.MQTT.TopicPrefix = strings.TrimSuffix(prefix, "/")
+	}
+	if discovery := os.Getenv(envHADiscovery); discovery != "" {
+		enabled, err := strconv.ParseBool(discovery)
+		if err != nil {
+			slog.Warn("Invalid value, Home Assistant discovery disabled", "setting", envHADiscovery)
+		}
+		config.MQTT.HomeAssistantDiscovery = enabled
+	}
+
+	// Embedded HTTP server for feeds, health checks and the admin API (optional)
+	setFromEnv(&config.HTTPAddr, envHTTPAddr)
+	setFromEnv(&config.PublicURL, envPublicURL)
+	config.PublicURL = strings.TrimSuffix(config.PublicURL, "/")
+
+	// Bearer token for the /status and /trigger endpoints; the trigger is disabled without it
+	setFromEnv(&config.AdminAPIToken, envAdminAPIToken)
+
+	// Log verbosity (debug, info, warn, error) and output format (text, json)
+	setFromEnv(&config.LogLevel, envLogLevel)
+	setFromEnv(&config.LogFormat, envLogFormat)
+	config.LogLevel = strings.ToLower(config.LogLevel)
+	config.LogFormat = strings.ToLower(config.LogFormat)
+
+	// Grafana annotations for fired alerts (optional)
+	setFromEnv(&config.GrafanaURL, envGrafanaURL)
+	setFromEnv(&config.GrafanaAPIKey, envGrafanaAPIKey)
+	setFromEnv(&config.GrafanaDashboardUID, envGrafanaDash)
+
+	// Event bus for quote.fetched, alert.fired and report.sent events (optional)
+	setFromEnv(&config.NATSURL, envNATSURL)
+	if brokers := os.Getenv(envKafkaBrokers); brokers != "" {
+		config.KafkaBrokers = splitList(brokers)
+	}
+	if prefix := os.Getenv(envEventPrefix); prefix != "" {
+		config.EventTopicPrefix = strings.TrimSuffix(prefix, ".")
+	}
+
+	// Per-symbol fetch priority tiers
+	if priorities := os.Getenv(envPriorities); priorities != "" {
+		parsed, err := models.ParseSymbolPriorities(priorities)
+		if err != nil {
+			return config, fmt.Errorf("invalid %s value: %w", envPriorities, err)
+		}
+		config.SymbolPriorities = parsed
+		models.SymbolPriorities = parsed
+	}
+
+	// Pre-market and after-hours windows in which US equities are checked besides the regular session
+	if minutesStr := os.Getenv(envPreMarket); minutesStr != "" {
+		if minutes, err := strconv.Atoi(minutesStr); err == nil && minutes >= 0 {
+			config.PreMarketWindow = time.Duration(minutes) * time.Minute
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envPreMarket, "default", config.PreMarketWindow)
+		}
+	}
+	if minutesStr := os.Getenv(envPostMarket); minutesStr != "" {
+		if minutes, err := strconv.Atoi(minutesStr); err == nil && minutes >= 0 {
+			config.PostMarketWindow = time.Duration(minutes) * time.Minute
+		} else {
+			slog.Warn("Invalid value, using default", "setting", envPostMarket, "default", config.PostMarketWindow)
+		}
+	}
+	config.PreMarketWindow = min(config.PreMarketWindow, calendar.MaxPreMarket)
+	config.PostMarketWindow = min(config.PostMarketWindow, calendar.MaxPostMarket)
+
+	// When closing prices are captured per exchange, in the exchange's time zone
+	if times := os.Getenv(envClosingTimes); times != "" {
+		parsed, err := models.ParseClosingTimes(times)
+		if err != nil {
+			return config, fmt.Errorf("invalid %s value: %w", envClosingTimes, err)
+		}
+		maps.Copy(config.ClosingTimes, parsed)
+	}
+
+	// Per-symbol alert thresholds; /setthreshold overrides them at runtime
+	if thresholds := os.Getenv(envThresholds); thresholds != "" {
+		parsed, err := models.ParseSymbolThresholds(thresholds)
+		if err != nil {
+			return config, fmt.Errorf("invalid %s value: %w", envThresholds, err)
+		}
+		config.SymbolThresholds = parsed
+	}
+
+	// Per-symbol display currency overrides
+	if currencies := os.Getenv(envCurrencies); currencies != "" {
+		parsed, err := models.ParseSymbolCurrencies(currencies)
+		if err != nil {
+			return config, fmt.Errorf("invalid %s value: %w", envCurrencies, err)
+		}
+		config.SymbolCurrencies = parsed
+		models.SymbolCurrencies = parsed
+	}
+
+	return config, nil
+}
+
+// LoadStorage reads the config file and the storage settings, for the tools sharing the bot's database
+func LoadStorage() (models.Config, error) {
+	// Load .env file
+	if err := godotenv.Load(); err != nil {
+		slog.Warn(".env file not found, using environment variables")
+	}
+
+	config := models.DefaultConfig()
+
+	// Config file (optional); environment variables override its settings
+	if path := findConfigFile(); path != "" {
+		file, err := readFile(path)
+		if err != nil {
+			return config, err
+		}
+		file.apply(&config)
+		slog.Info("Loaded configuration file", "path", path)
+	}
+
+	// MongoDB URI; optional with another storage driver
+	setFromEnv(&config.MongoURI, envMongoURI)
+
+	// Where price history and the watchlist are kept (mongodb, postgres, sqlite); a PostgreSQL URL alone selects postgres
+	setFromEnv(&config.StorageDriver, envStorageDriver)
+	setFromEnv(&config.PostgresURL, envPostgresURL)
+	setFromEnv(&config.SQLitePath, envSQLitePath)
+	config.StorageDriver = strings.ToLower(config.StorageDriver)
+	if config.StorageDriver == "" && config.PostgresURL != "" {
+		config.StorageDriver = models.StoragePostgres
+	}
+	switch config.StorageDriver {
+	case models.StorageMongoDB, models.StorageSQLite:
+	case models.StoragePostgres:
+		if config.PostgresURL == "" {
+			return config, fmt.Errorf("required setting %s (database.postgresUrl) not set for the postgres storage driver", envPostgresURL)
+		}
+	case "":
+		config.StorageDriver = models.StorageMongoDB
+	default:
+		slog.Warn("Invalid value, using default", "setting", envStorageDriver, "default", models.StorageMongoDB)
+		config.StorageDriver = models.StorageMongoDB
+	}
+	if config.MongoURI == "" && config.StorageDriver == models.StorageMongoDB {
+		return config, fmt.Errorf("required setting %s (database.uri) not set for the mongodb storage driver", envMongoURI)
+	}
+
+	return config, nil
+}
+
+// setFromEnv overrides a setting with an environment variable when it is set
+func setFromEnv(target *string, key string) {
+	setString(target, os.Getenv(key))
+}
+
+// splitList splits a comma-separated value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// normalizeTickers upper-cases and de-duplicates configured symbols, skipping malformed ones
+func normalizeTickers(fields []string, setting string) []string {
+	var tickers []string
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		symbol, ok := models.NormalizeSymbol(field)
+		if !ok {
+			slog.Warn("Invalid value, skipping", "setting", setting, "value", field)
+			continue
+		}
+		if !slices.Contains(tickers, symbol) {
+			tickers = append(tickers, symbol)
+		}
+	}
+	return tickers
+}
+
+// normalizeFXPairs normalizes currency pairs written as USD/KRW or KRW=X, skipping other symbols with a warning
+func normalizeFXPairs(fields []string, setting string) []string {
+	var pairs []string
+	for _, symbol := range normalizeTickers(fields, setting) {
+		if _, _, ok := models.ParseFXPair(symbol); !ok {
+			slog.Warn("Not a currency pair, skipping", "setting", setting, "value", symbol)
+			continue
+		}
+		pairs = append(pairs, symbol)
+	}
+	return pairs
+}
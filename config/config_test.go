@@ -0,0 +1,77 @@
+package config
+
+import (
+	"testing"
+
+	"stock-bot/models"
+)
+
+// setMinimalEnv configures a messenger and clears the storage settings
+func setMinimalEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("TELEGRAM_BOT_TOKEN", "token")
+	t.Setenv("TELEGRAM_CHAT_ID", "1")
+	for _, name := range []string{envMongoURI, envStorageDriver, envPostgresURL, envSQLitePath, "CONFIG_FILE"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestLoadStorageDriverWithoutMongo(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		driver  string
+		wantErr bool
+	}{
+		{"mongodb needs a URI", nil, models.StorageMongoDB, true},
+		{"mongodb", map[string]string{envMongoURI: "mongodb://localhost"}, models.StorageMongoDB, false},
+		{"postgres URL alone", map[string]string{envPostgresURL: "postgres://localhost/stock"}, models.StoragePostgres, false},
+		{"postgres without a URL", map[string]string{envStorageDriver: "postgres"}, models.StoragePostgres, true},
+		{"sqlite", map[string]string{envStorageDriver: "SQLite"}, models.StorageSQLite, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setMinimalEnv(t)
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			config, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && config.StorageDriver != tt.driver {
+				t.Errorf("StorageDriver = %q, want %q", config.StorageDriver, tt.driver)
+			}
+		})
+	}
+}
+
+func TestLoadIntradayRetention(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{"off unless set", "", 0},
+		{"configured", "30", 30},
+		{"invalid keeps it off", "-5", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setMinimalEnv(t)
+			t.Setenv(envMongoURI, "mongodb://localhost")
+			t.Setenv(envRetentionDays, tt.value)
+
+			config, err := Load()
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if config.IntradayRetentionDays != tt.want {
+				t.Errorf("IntradayRetentionDays = %d, want %d", config.IntradayRetentionDays, tt.want)
+			}
+		})
+	}
+}
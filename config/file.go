@@ -0,0 +1,418 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config file related error definitions
+var (
+	ErrConfigFileRead   = errors.New("failed to read config file")
+	ErrConfigFileParse  = errors.New("failed to parse config file")
+	ErrConfigFileFormat = errors.New("unsupported config file format")
+)
+
+// defaultConfigFiles are looked up in the working directory when CONFIG_FILE is not set
+var defaultConfigFiles = []string{"config.yaml", "config.yml", "config.json"}
+
+// File is the layout of config.yaml or config.json; every setting is optional and environment variables take precedence
+type File struct {
+	Database   DatabaseFile  `yaml:"database" json:"database"`
+	Language   string        `yaml:"language" json:"language"`
+	Tickers    []string      `yaml:"tickers" json:"tickers"`
+	FXRates    []string      `yaml:"fxRates" json:"fxRates"`
+	Alerts     AlertsFile    `yaml:"alerts" json:"alerts"`
+	Schedule   ScheduleFile  `yaml:"schedule" json:"schedule"`
+	Messengers MessengerFile `yaml:"messengers" json:"messengers"`
+	Browser    BrowserFile   `yaml:"browser" json:"browser"`
+	Scraping   ScrapingFile  `yaml:"scraping" json:"scraping"`
+	Logging    LoggingFile   `yaml:"logging" json:"logging"`
+}
+
+// DatabaseFile holds the MongoDB connection settings, where price history is stored, how long intraday prices are kept,
+// how many days of history are backfilled and the optional Redis quote cache
+type DatabaseFile struct {
+	URI                   string `yaml:"uri" json:"uri"`
+	Driver                string `yaml:"driver" json:"driver"`
+	PostgresURL           string `yaml:"postgresUrl" json:"postgresUrl"`
+	SQLitePath            string `yaml:"sqlitePath" json:"sqlitePath"`
+	RedisURL              string `yaml:"redisUrl" json:"redisUrl"`
+	QuoteCacheSeconds     int    `yaml:"quoteCacheSeconds" json:"quoteCacheSeconds"`
+	IntradayRetentionDays *int   `yaml:"intradayRetentionDays" json:"intradayRetentionDays"`
+	BackfillDays          *int   `yaml:"backfillDays" json:"backfillDays"`
+}
+
+// AlertsFile holds alert thresholds; unset numbers keep their defaults
+type AlertsFile struct {
+	Threshold float64 `yaml:"threshold" json:"threshold"`
+	// Threshold for crypto pairs; 0 applies the global threshold
+	CryptoThreshold *float64 `yaml:"cryptoThreshold" json:"cryptoThreshold"`
+	// Threshold for currency pairs; 0 applies the global threshold
+	FXThreshold *float64 `yaml:"fxThreshold" json:"fxThreshold"`
+	// Per-symbol thresholds, e.g. 2 for mega-caps and 8 for volatile names
+	Symbols         map[string]float64 `yaml:"symbols" json:"symbols"`
+	CooldownMinutes *int               `yaml:"cooldownMinutes" json:"cooldownMinutes"`
+	StepPercent     *float64           `yaml:"stepPercent" json:"stepPercent"`
+	// Intraday alerts on the change from the session open and over the last MoveMinutes
+	OpenThreshold       *float64      `yaml:"openThreshold" json:"openThreshold"`
+	MoveThreshold       *float64      `yaml:"moveThreshold" json:"moveThreshold"`
+	MoveMinutes         int           `yaml:"moveMinutes" json:"moveMinutes"`
+	VolumeMultiple      *float64      `yaml:"volumeMultiple" json:"volumeMultiple"`
+	StreakDays          *int          `yaml:"streakDays" json:"streakDays"`
+	Crossover           CrossoverFile `yaml:"crossover" json:"crossover"`
+	DelistFailureLimit  *int          `yaml:"delistFailureLimit" json:"delistFailureLimit"`
+	FetchFailurePercent *float64      `yaml:"fetchFailurePercent" json:"fetchFailurePercent"`
+}
+
+// CrossoverFile holds the moving average crossover alert settings
+type CrossoverFile struct {
+	Symbols []string `yaml:"symbols" json:"symbols"`
+	Fast    int      `yaml:"fast" json:"fast"`
+	Slow    int      `yaml:"slow" json:"slow"`
+	Average string   `yaml:"average" json:"average"`
+}
+
+// ScheduleFile holds when reports are sent and reminders fire
+type ScheduleFile struct {
+	TimeZone             string `yaml:"timeZone" json:"timeZone"`
+	CheckHour            *int   `yaml:"checkHour" json:"checkHour"`
+	EconomicAlertMinutes int    `yaml:"economicAlertMinutes" json:"economicAlertMinutes"`
+	// Pre-market and after-hours windows in which US equities are checked
+	PreMarketMinutes  int `yaml:"preMarketMinutes" json:"preMarketMinutes"`
+	PostMarketMinutes int `yaml:"postMarketMinutes" json:"postMarketMinutes"`
+	// Closing price capture time per exchange (US, KRX, TSE, LSE, XETRA, EURONEXT, 24H) as HH:MM local time
+	ClosingTimes map[string]string `yaml:"closingTimes" json:"closingTimes"`
+	// Realtime check interval per asset class (equity, crypto, fx)
+	RealtimeMinutes map[models.AssetClass]int `yaml:"realtimeMinutes" json:"realtimeMinutes"`
+}
+
+// BrowserFile holds the tab limit of the headless browser and when it is restarted; 0 disables a restart limit
+type BrowserFile struct {
+	MaxTabs        int  `yaml:"maxTabs" json:"maxTabs"`
+	MaxNavigations *int `yaml:"maxNavigations" json:"maxNavigations"`
+	MaxAgeHours    *int `yaml:"maxAgeHours" json:"maxAgeHours"`
+}
+
+// ScrapingFile holds the scrape profiles tried before the built-in Yahoo and Google Finance ones; a profile named
+// yahoo or google replaces the built-in one
+type ScrapingFile struct {
+	Profiles []ScrapeProfileFile `yaml:"profiles" json:"profiles"`
+}
+
+// ScrapeProfileFile holds a site's quote page URL with {symbol}, the CSS selectors of each field tried in order and
+// the wait strategy: visible, load or a duration such as 3s
+type ScrapeProfileFile struct {
+	Name          string   `yaml:"name" json:"name"`
+	URL           string   `yaml:"url" json:"url"`
+	Price         []string `yaml:"price" json:"price"`
+	Change        []string `yaml:"change" json:"change"`
+	ChangePercent []string `yaml:"changePercent" json:"changePercent"`
+	Volume        []string `yaml:"volume" json:"volume"`
+	Wait          string   `yaml:"wait" json:"wait"`
+}
+
+// LoggingFile holds the log verbosity and output format
+type LoggingFile struct {
+	Level  string `yaml:"level" json:"level"`
+	Format string `yaml:"format" json:"format"`
+}
+
+// MessengerFile holds the settings of each messaging service and the directory of custom message templates
+type MessengerFile struct {
+	TemplateDir string `yaml:"templateDir" json:"templateDir"`
+	Telegram    struct {
+		Token      string `yaml:"token" json:"token"`
+		ChatID     string `yaml:"chatId" json:"chatId"`
+		Format     string `yaml:"format" json:"format"`
+		AlertStyle string `yaml:"alertStyle" json:"alertStyle"`
+	} `yaml:"telegram" json:"telegram"`
+	Line struct {
+		Token         string `yaml:"token" json:"token"`
+		ChannelSecret string `yaml:"channelSecret" json:"channelSecret"`
+		Format        string `yaml:"format" json:"format"`
+		AlertStyle    string `yaml:"alertStyle" json:"alertStyle"`
+	} `yaml:"line" json:"line"`
+	Slack struct {
+		WebhookURL string `yaml:"webhookUrl" json:"webhookUrl"`
+		Token      string `yaml:"token" json:"token"`
+		Channel    string `yaml:"channel" json:"channel"`
+		AlertStyle string `yaml:"alertStyle" json:"alertStyle"`
+	} `yaml:"slack" json:"slack"`
+	Email   models.SMTPConfig `yaml:"email" json:"email"`
+	Webhook struct {
+		URLs   []string `yaml:"urls" json:"urls"`
+		Secret string   `yaml:"secret" json:"secret"`
+	} `yaml:"webhook" json:"webhook"`
+	Ntfy struct {
+		URL   string `yaml:"url" json:"url"`
+		Token string `yaml:"token" json:"token"`
+	} `yaml:"ntfy" json:"ntfy"`
+	Pushover struct {
+		Token string `yaml:"token" json:"token"`
+		User  string `yaml:"user" json:"user"`
+	} `yaml:"pushover" json:"pushover"`
+	// Alert move in percent from which push notifications are high priority
+	PushHighPriorityPercent *float64 `yaml:"pushHighPriorityPercent" json:"pushHighPriorityPercent"`
+	Twilio                  struct {
+		AccountSID      string   `yaml:"accountSid" json:"accountSid"`
+		AuthToken       string   `yaml:"authToken" json:"authToken"`
+		From            string   `yaml:"from" json:"from"`
+		To              []string `yaml:"to" json:"to"`
+		CriticalPercent *float64 `yaml:"criticalPercent" json:"criticalPercent"`
+		DailyCap        *int     `yaml:"dailyCap" json:"dailyCap"`
+	} `yaml:"twilio" json:"twilio"`
+}
+
+// findConfigFile returns the configured file path, or the first default file present in the working directory
+func findConfigFile() string {
+	if path := os.Getenv(envConfigFile); path != "" {
+		return path
+	}
+	for _, name := range defaultConfigFiles {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// readFile parses a YAML or JSON config file, chosen by its extension
+func readFile(path string) (File, error) {
+	var file File
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file, fmt.Errorf("%w: %v", ErrConfigFileRead, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	default:
+		return file, fmt.Errorf("%w: %s", ErrConfigFileFormat, path)
+	}
+	if err != nil {
+		return file, fmt.Errorf("%w: %v", ErrConfigFileParse, err)
+	}
+
+	return file, nil
+}
+
+// apply copies the settings present in the file onto the configuration
+func (f File) apply(config *models.Config) {
+	setString(&config.MongoURI, f.Database.URI)
+	setString(&config.StorageDriver, f.Database.Driver)
+	setString(&config.PostgresURL, f.Database.PostgresURL)
+	setString(&config.SQLitePath, f.Database.SQLitePath)
+	setString(&config.RedisURL, f.Database.RedisURL)
+	if f.Database.QuoteCacheSeconds > 0 {
+		config.QuoteCacheTTL = time.Duration(f.Database.QuoteCacheSeconds) * time.Second
+	}
+	if f.Database.IntradayRetentionDays != nil {
+		config.IntradayRetentionDays = *f.Database.IntradayRetentionDays
+	}
+	if f.Database.BackfillDays != nil {
+		config.BackfillDays = *f.Database.BackfillDays
+	}
+	setString(&config.Language, f.Language)
+	if len(f.Tickers) > 0 {
+		config.Tickers = normalizeTickers(f.Tickers, "tickers")
+	}
+	if len(f.FXRates) > 0 {
+		config.ReportFXRates = normalizeFXPairs(f.FXRates, "fxRates")
+	}
+
+	if f.Alerts.Threshold > 0 {
+		config.PriceAlertThreshold = f.Alerts.Threshold
+	}
+	if f.Alerts.CryptoThreshold != nil {
+		config.CryptoAlertThreshold = *f.Alerts.CryptoThreshold
+	}
+	if f.Alerts.FXThreshold != nil {
+		config.FXAlertThreshold = *f.Alerts.FXThreshold
+	}
+	for symbol, percent := range f.Alerts.Symbols {
+		if percent <= 0 || percent > 100 {
+			slog.Warn("Invalid alert threshold, skipping", "symbol", symbol, "percent", percent)
+			continue
+		}
+		if config.SymbolThresholds == nil {
+			config.SymbolThresholds = make(map[string]float64)
+		}
+		config.SymbolThresholds[strings.ToUpper(symbol)] = percent
+	}
+	if f.Alerts.CooldownMinutes != nil {
+		config.AlertCooldown = time.Duration(*f.Alerts.CooldownMinutes) * time.Minute
+	}
+	if f.Alerts.StepPercent != nil {
+		config.AlertStepPercent = *f.Alerts.StepPercent
+	}
+	if f.Alerts.OpenThreshold != nil {
+		config.OpenAlertThreshold = *f.Alerts.OpenThreshold
+	}
+	if f.Alerts.MoveThreshold != nil {
+		config.MoveAlertThreshold = *f.Alerts.MoveThreshold
+	}
+	if f.Alerts.MoveMinutes > 0 {
+		config.MoveAlertWindow = time.Duration(f.Alerts.MoveMinutes) * time.Minute
+	}
+	if f.Alerts.VolumeMultiple != nil {
+		config.VolumeAlertMultiple = *f.Alerts.VolumeMultiple
+	}
+	if f.Alerts.StreakDays != nil {
+		config.StreakAlertDays = *f.Alerts.StreakDays
+	}
+	if len(f.Alerts.Crossover.Symbols) > 0 {
+		config.Crossover.Symbols = nil
+		for _, symbol := range f.Alerts.Crossover.Symbols {
+			config.Crossover.Symbols = append(config.Crossover.Symbols, strings.ToUpper(strings.TrimSpace(symbol)))
+		}
+	}
+	if f.Alerts.Crossover.Fast > 0 && f.Alerts.Crossover.Slow > f.Alerts.Crossover.Fast {
+		config.Crossover.Fast, config.Crossover.Slow = f.Alerts.Crossover.Fast, f.Alerts.Crossover.Slow
+	}
+	switch average := strings.ToLower(f.Alerts.Crossover.Average); average {
+	case models.AverageSMA, models.AverageEMA:
+		config.Crossover.Average = average
+	case "":
+	default:
+		slog.Warn("Invalid crossover average, skipping", "average", f.Alerts.Crossover.Average)
+	}
+	if f.Alerts.DelistFailureLimit != nil {
+		config.DelistFailureLimit = *f.Alerts.DelistFailureLimit
+	}
+	if f.Alerts.FetchFailurePercent != nil {
+		config.FetchFailureAlertPercent = *f.Alerts.FetchFailurePercent
+	}
+
+	setString(&config.TimeZone, f.Schedule.TimeZone)
+	if f.Schedule.CheckHour != nil {
+		config.CheckHour = *f.Schedule.CheckHour
+	}
+	if f.Schedule.EconomicAlertMinutes > 0 {
+		config.EconomicAlertLead = time.Duration(f.Schedule.EconomicAlertMinutes) * time.Minute
+	}
+	if f.Schedule.PreMarketMinutes > 0 {
+		config.PreMarketWindow = time.Duration(f.Schedule.PreMarketMinutes) * time.Minute
+	}
+	if f.Schedule.PostMarketMinutes > 0 {
+		config.PostMarketWindow = time.Duration(f.Schedule.PostMarketMinutes) * time.Minute
+	}
+	for exchange, value := range f.Schedule.ClosingTimes {
+		exchange = strings.ToUpper(exchange)
+		clock, err := models.ParseClockTime(value)
+		if _, known := models.ExchangeTimeZones[exchange]; !known || err != nil {
+			slog.Warn("Invalid closing time, skipping", "exchange", exchange, "time", value)
+			continue
+		}
+		config.ClosingTimes[exchange] = clock
+	}
+	for class, minutes := range f.Schedule.RealtimeMinutes {
+		if _, known := config.RealtimeIntervals[class]; known && minutes > 0 {
+			config.RealtimeIntervals[class] = time.Duration(minutes) * time.Minute
+		} else {
+			slog.Warn("Invalid realtime interval, skipping", "class", class)
+		}
+	}
+
+	setString(&config.MessageTemplateDir, f.Messengers.TemplateDir)
+
+	telegram := f.Messengers.Telegram
+	setString(&config.TelegramBotToken, telegram.Token)
+	setString(&config.TelegramChatID, telegram.ChatID)
+	setString(&config.TelegramFormat, telegram.Format)
+	setString(&config.TelegramAlertStyle, telegram.AlertStyle)
+
+	line := f.Messengers.Line
+	setString(&config.LineChannelToken, line.Token)
+	setString(&config.LineChannelSecret, line.ChannelSecret)
+	setString(&config.LineFormat, line.Format)
+	setString(&config.LineAlertStyle, line.AlertStyle)
+
+	slack := f.Messengers.Slack
+	setString(&config.SlackWebhookURL, slack.WebhookURL)
+	setString(&config.SlackBotToken, slack.Token)
+	setString(&config.SlackChannel, slack.Channel)
+	setString(&config.SlackAlertStyle, slack.AlertStyle)
+
+	email := f.Messengers.Email
+	setString(&config.SMTP.Host, email.Host)
+	setString(&config.SMTP.Port, email.Port)
+	setString(&config.SMTP.Username, email.Username)
+	setString(&config.SMTP.Password, email.Password)
+	setString(&config.SMTP.From, email.From)
+	if len(email.To) > 0 {
+		config.SMTP.To = email.To
+	}
+
+	webhook := f.Messengers.Webhook
+	if len(webhook.URLs) > 0 {
+		config.WebhookURLs = webhook.URLs
+	}
+	setString(&config.WebhookSecret, webhook.Secret)
+
+	setString(&config.NtfyURL, f.Messengers.Ntfy.URL)
+	setString(&config.NtfyToken, f.Messengers.Ntfy.Token)
+	setString(&config.PushoverToken, f.Messengers.Pushover.Token)
+	setString(&config.PushoverUser, f.Messengers.Pushover.User)
+	if f.Messengers.PushHighPriorityPercent != nil {
+		config.PushHighPriorityPercent = *f.Messengers.PushHighPriorityPercent
+	}
+
+	twilio := f.Messengers.Twilio
+	setString(&config.Twilio.AccountSID, twilio.AccountSID)
+	setString(&config.Twilio.AuthToken, twilio.AuthToken)
+	setString(&config.Twilio.From, twilio.From)
+	if len(twilio.To) > 0 {
+		config.Twilio.To = twilio.To
+	}
+	if twilio.CriticalPercent != nil {
+		config.Twilio.CriticalPercent = *twilio.CriticalPercent
+	}
+	if twilio.DailyCap != nil {
+		config.Twilio.DailyCap = *twilio.DailyCap
+	}
+
+	if f.Browser.MaxTabs > 0 {
+		config.Browser.MaxTabs = f.Browser.MaxTabs
+	}
+	if f.Browser.MaxNavigations != nil {
+		config.Browser.MaxNavigations = *f.Browser.MaxNavigations
+	}
+	if f.Browser.MaxAgeHours != nil {
+		config.Browser.MaxAge = time.Duration(*f.Browser.MaxAgeHours) * time.Hour
+	}
+	var profiles []models.ScrapeProfile
+	for _, entry := range f.Scraping.Profiles {
+		profile := models.ScrapeProfile(entry)
+		if err := profile.Validate(); err != nil {
+			slog.Warn("Invalid scrape profile, skipping", "error", err)
+			continue
+		}
+		profiles = append(profiles, profile)
+	}
+	if len(profiles) > 0 {
+		config.ScrapeProfiles = models.MergeScrapeProfiles(profiles, models.DefaultScrapeProfiles())
+	}
+	setString(&config.LogLevel, f.Logging.Level)
+	setString(&config.LogFormat, f.Logging.Format)
+}
+
+// setString overwrites a setting unless the new value is empty
+func setString(target *string, value string) {
+	if value != "" {
+		*target = value
+	}
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"stock-bot/models"
+)
+
+func TestFileApply(t *testing.T) {
+	defaults := models.DefaultConfig()
+
+	tests := []struct {
+		name  string
+		yaml  string
+		check func(c models.Config) bool
+	}{
+		{
+			"empty file keeps the defaults",
+			"{}",
+			func(c models.Config) bool { return reflect.DeepEqual(c, defaults) },
+		},
+		{
+			"thresholds",
+			"alerts: {threshold: 3, cryptoThreshold: 0, fxThreshold: 1.5}",
+			func(c models.Config) bool {
+				return c.PriceAlertThreshold == 3 && c.CryptoAlertThreshold == 0 && c.FXAlertThreshold == 1.5
+			},
+		},
+		{
+			"zero global threshold keeps the default",
+			"alerts: {threshold: 0}",
+			func(c models.Config) bool { return c.PriceAlertThreshold == defaults.PriceAlertThreshold },
+		},
+		{
+			"symbol thresholds upper-cased, invalid ones skipped",
+			"alerts: {symbols: {aapl: 2, tsla: 150, nvda: -1}}",
+			func(c models.Config) bool {
+				return reflect.DeepEqual(c.SymbolThresholds, map[string]float64{"AAPL": 2})
+			},
+		},
+		{
+			"intraday alerts",
+			"alerts: {openThreshold: 4, moveThreshold: 3, moveMinutes: 30}",
+			func(c models.Config) bool {
+				return c.OpenAlertThreshold == 4 && c.MoveAlertThreshold == 3 && c.MoveAlertWindow == 30*time.Minute
+			},
+		},
+		{
+			"crossover",
+			"alerts: {crossover: {symbols: [aapl, ' msft'], fast: 50, slow: 200, average: EMA}}",
+			func(c models.Config) bool {
+				return reflect.DeepEqual(c.Crossover, models.CrossoverConfig{Symbols: []string{"AAPL", "MSFT"}, Fast: 50, Slow: 200, Average: models.AverageEMA})
+			},
+		},
+		{
+			"crossover with a slow period not above the fast one",
+			"alerts: {crossover: {fast: 50, slow: 20, average: wma}}",
+			func(c models.Config) bool { return reflect.DeepEqual(c.Crossover, defaults.Crossover) },
+		},
+		{
+			"closing times of known exchanges",
+			"schedule: {closingTimes: {krx: '15:50', nse: '15:40', us: '25:00'}}",
+			func(c models.Config) bool {
+				return c.ClosingTimes[models.ExchangeKRX] == 15*60+50 && c.ClosingTimes[models.ExchangeUS] == defaults.ClosingTimes[models.ExchangeUS] && len(c.ClosingTimes) == len(defaults.ClosingTimes)
+			},
+		},
+		{
+			"realtime intervals of known classes",
+			"schedule: {realtimeMinutes: {crypto: 1, bonds: 5, fx: 0}}",
+			func(c models.Config) bool {
+				return c.RealtimeIntervals[models.AssetCrypto] == time.Minute && c.RealtimeIntervals[models.AssetFX] == defaults.RealtimeIntervals[models.AssetFX] && len(c.RealtimeIntervals) == len(defaults.RealtimeIntervals)
+			},
+		},
+		{
+			"check hour of zero",
+			"schedule: {checkHour: 0, timeZone: Asia/Seoul}",
+			func(c models.Config) bool { return c.CheckHour == 0 && c.TimeZone == "Asia/Seoul" },
+		},
+		{
+			"messengers",
+			"messengers: {telegram: {token: t, chatId: '1'}, webhook: {urls: [https://example.com/hook]}, twilio: {dailyCap: 0}}",
+			func(c models.Config) bool {
+				return c.TelegramBotToken == "t" && c.TelegramChatID == "1" && reflect.DeepEqual(c.WebhookURLs, []string{"https://example.com/hook"}) && c.Twilio.DailyCap == 0
+			},
+		},
+		{
+			"browser restart limits turned off",
+			"browser: {maxTabs: 0, maxNavigations: 0, maxAgeHours: 0}",
+			func(c models.Config) bool {
+				return c.Browser == models.BrowserConfig{MaxTabs: defaults.Browser.MaxTabs}
+			},
+		},
+		{
+			"retention and backfill",
+			"database: {intradayRetentionDays: 14, backfillDays: 0, quoteCacheSeconds: 30}",
+			func(c models.Config) bool {
+				return c.IntradayRetentionDays == 14 && c.BackfillDays == 0 && c.QuoteCacheTTL == 30*time.Second
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			file, err := readFile(path)
+			if err != nil {
+				t.Fatalf("readFile: %v", err)
+			}
+
+			config := models.DefaultConfig()
+			file.apply(&config)
+			if !tt.check(config) {
+				t.Errorf("unexpected config after applying %s: %+v", tt.yaml, config)
+			}
+		})
+	}
+}
+
+func TestReadFileFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{"config.json", `{"alerts": {"threshold": 3}}`, nil},
+		{"config.yml", "alerts: {threshold: 3}", nil},
+		{"config.toml", "[alerts]\nthreshold = 3", ErrConfigFileFormat},
+		{"config.yaml", "alerts: [", ErrConfigFileParse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.name)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			file, err := readFile(path)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("readFile error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && file.Alerts.Threshold != 3 {
+				t.Errorf("threshold = %v, want 3", file.Alerts.Threshold)
+			}
+		})
+	}
+}
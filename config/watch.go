@@ -0,0 +1,125 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDelay groups the several events editors emit for one save into a single reload
+const reloadDelay = time.Second
+
+// secretSettings are left out of change logs; only the fact that they changed is logged
+var secretSettings = []string{"Token", "Key", "Secret", "Password", "URI", "URL", "Credentials", "SMTP", "MQTT", "Twilio", "Pushover"}
+
+// Watch reloads the configuration whenever the config file changes and passes the result to apply.
+// A file that fails to load is logged and ignored, so the running configuration stays in effect
+func Watch(ctx context.Context, apply func(models.Config)) error {
+	path := findConfigFile()
+	if path == "" {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrConfigFileRead, err)
+	}
+
+	// Editors often replace the file instead of writing it, so the directory is watched
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("%w: %v", ErrConfigFileRead, err)
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		var reload <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) == filepath.Clean(path) && event.Op.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+					reload = time.After(reloadDelay)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.Error("Error watching configuration file", "path", path, "error", err)
+			case <-reload:
+				reload = nil
+				config, err := Load()
+				if err != nil {
+					slog.Error("Error reloading configuration, keeping the current settings", "error", err)
+					continue
+				}
+				apply(config)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	slog.Info("Watching configuration file for changes", "path", path)
+	return nil
+}
+
+// Diff describes the settings that differ between two configurations, one line per setting
+func Diff(old, new models.Config) []string {
+	var changes []string
+
+	oldValue, newValue := reflect.ValueOf(old), reflect.ValueOf(new)
+	fields := oldValue.Type()
+	for i := range fields.NumField() {
+		before, after := oldValue.Field(i).Interface(), newValue.Field(i).Interface()
+		if reflect.DeepEqual(before, after) {
+			continue
+		}
+
+		name := fields.Field(i).Name
+		if isSecret(name) {
+			changes = append(changes, fmt.Sprintf("%s changed", name))
+		} else {
+			changes = append(changes, fmt.Sprintf("%s: %v → %v", name, before, after))
+		}
+	}
+
+	return changes
+}
+
+// Retain returns new with the settings whose names start with one of the prefixes kept at their old values
+func Retain(old, new models.Config, prefixes []string) models.Config {
+	oldValue, newValue := reflect.ValueOf(old), reflect.ValueOf(&new).Elem()
+	fields := oldValue.Type()
+	for i := range fields.NumField() {
+		name := fields.Field(i).Name
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) {
+				newValue.Field(i).Set(oldValue.Field(i))
+				break
+			}
+		}
+	}
+	return new
+}
+
+// isSecret reports whether a setting may hold credentials
+func isSecret(name string) bool {
+	for _, secret := range secretSettings {
+		if strings.Contains(name, secret) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"stock-bot/models"
+)
+
+func TestRetain(t *testing.T) {
+	old, new := models.DefaultConfig(), models.DefaultConfig()
+	new.TimeZone = "Asia/Seoul"
+	new.SMTP.Host = "smtp.example.com"
+	new.CheckHour = old.CheckHour + 1
+
+	kept := Retain(old, new, []string{"TimeZone", "SMTP"})
+	if kept.TimeZone != old.TimeZone || kept.SMTP.Host != old.SMTP.Host {
+		t.Errorf("Retain changed TimeZone to %q and SMTP host to %q, want the old values", kept.TimeZone, kept.SMTP.Host)
+	}
+	if kept.CheckHour != new.CheckHour {
+		t.Errorf("Retain CheckHour = %d, want the new %d", kept.CheckHour, new.CheckHour)
+	}
+}
+
+func TestDiffHidesSecrets(t *testing.T) {
+	old, new := models.DefaultConfig(), models.DefaultConfig()
+	new.LineChannelSecret = "line-secret"
+	new.WebhookSecret = "webhook-secret"
+	new.Twilio.AuthToken = "twilio-token"
+	new.PushoverUser = "pushover-user"
+	new.TelegramBotToken = "telegram-token"
+	new.CheckHour = old.CheckHour + 1
+
+	changes := strings.Join(Diff(old, new), "\n")
+	for _, secret := range []string{"line-secret", "webhook-secret", "twilio-token", "pushover-user", "telegram-token"} {
+		if strings.Contains(changes, secret) {
+			t.Errorf("Diff logged %q:\n%s", secret, changes)
+		}
+	}
+	for _, name := range []string{"LineChannelSecret changed", "WebhookSecret changed", "Twilio changed", "PushoverUser changed"} {
+		if !strings.Contains(changes, name) {
+			t.Errorf("Diff is missing %q:\n%s", name, changes)
+		}
+	}
+	if !strings.Contains(changes, "CheckHour: ") {
+		t.Errorf("Diff is missing the CheckHour values:\n%s", changes)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"stock-bot/config"
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// restartSettings only take effect after a restart, as the connections, sources and integrations using them are
+// set up once; names match as prefixes, so "Grafana" covers GrafanaURL and GrafanaAPIKey. A reload keeps them at
+// their start-up values, so currentConfig always agrees with what was set up
+var restartSettings = []string{
+	// Storage and caching
+	"MongoURI", "StorageDriver", "PostgresURL", "SQLitePath", "RedisURL", "QuoteCacheTTL",
+	// Messaging channels and message rendering
+	"Telegram", "Line", "Slack", "SMTP", "Webhook", "Ntfy", "Push", "Twilio", "MessageTemplateDir", "Language",
+	// Price sources and streaming
+	"PriceSources", "AlphaVantageAPIKey", "FinnhubAPIKey", "RealtimeStreaming", "Browser",
+	// Outbound integrations
+	"MQTT", "NATSURL", "KafkaBrokers", "EventTopicPrefix", "Grafana", "GoogleSheet", "IFTTT", "Zapier",
+	// Server, time zone and logging
+	"TimeZone", "HTTPAddr", "PublicURL", "LogFormat",
+}
+
+// The configuration in effect, replaced when the config file changes
+var (
+	liveConfig   models.Config
+	liveConfigMu sync.RWMutex
+)
+
+// currentConfig returns the configuration in effect
+func currentConfig() models.Config {
+	liveConfigMu.RLock()
+	defer liveConfigMu.RUnlock()
+	return liveConfig
+}
+
+// setCurrentConfig replaces the configuration in effect
+func setCurrentConfig(config models.Config) {
+	liveConfigMu.Lock()
+	defer liveConfigMu.Unlock()
+	liveConfig = config
+}
+
+// alertThreshold returns the default alert threshold for price changes in percent
+func alertThreshold() float64 {
+	return currentConfig().PriceAlertThreshold
+}
+
+// loadAlertThresholds combines the thresholds set with /setthreshold with the configured ones, which they override
+func loadAlertThresholds(db *services.Database) (models.AlertThresholds, error) {
+	config := currentConfig()
+	thresholds, err := db.GetAlertThresholds(config.PriceAlertThreshold)
+	// Crypto swings more and currencies less than equities, so each has its own threshold unless set to 0
+	thresholds.Classes = make(map[models.AssetClass]float64)
+	if config.CryptoAlertThreshold > 0 {
+		thresholds.Classes[models.AssetCrypto] = config.CryptoAlertThreshold
+	}
+	if config.FXAlertThreshold > 0 {
+		thresholds.Classes[models.AssetFX] = config.FXAlertThreshold
+	}
+	for symbol, percent := range config.SymbolThresholds {
+		if _, stored := thresholds.Symbols[symbol]; !stored {
+			thresholds.Symbols[symbol] = percent
+		}
+	}
+	return thresholds, err
+}
+
+// watchConfig applies config file changes to the watchlist, alert threshold, check intervals, scrape profiles, log
+// level and the other live settings without a restart; changes to restartSettings are logged and left for a restart
+func watchConfig(ctx context.Context, db *services.Database) {
+	err := config.Watch(ctx, func(updated models.Config) {
+		previous := currentConfig()
+		changes := config.Diff(previous, updated)
+		if len(changes) == 0 {
+			return
+		}
+
+		for _, change := range changes {
+			if slices.ContainsFunc(restartSettings, func(prefix string) bool { return strings.HasPrefix(change, prefix) }) {
+				slog.Warn("Configuration changed, takes effect after a restart", "change", change)
+			} else {
+				slog.Info("Configuration changed", "change", change)
+			}
+		}
+
+		updated = config.Retain(previous, updated, restartSettings)
+		setCurrentConfig(updated)
+		if previous.LogLevel != updated.LogLevel {
+			setLogLevel(updated.LogLevel)
+		}
+		if !slices.Equal(previous.Tickers, updated.Tickers) {
+			syncConfiguredTickers(ctx, db, previous.Tickers, updated.Tickers)
+		}
+		priceFetcher.SetScrapeProfiles(updated.ScrapeProfiles)
+	})
+	if err != nil {
+		slog.Warn("Configuration hot reload disabled", "error", err)
+	}
+}
+
+// syncConfiguredTickers adds symbols newly listed in the config file to the watchlist and removes the ones taken out,
+// unless an admin added them with /add; the scheduler picks them up on its next run
+func syncConfiguredTickers(ctx context.Context, db *services.Database, previous, updated []string) {
+	var added []string
+	for _, symbol := range updated {
+		if !slices.Contains(previous, symbol) {
+			added = append(added, symbol)
+		}
+	}
+	if len(added) > 0 {
+		added, _ = validateTickers(ctx, added)
+	}
+	for _, symbol := range added {
+		if _, err := db.AddToWatchlist(symbol, "config"); err != nil {
+			slog.Error("Error adding to the watchlist", "symbol", symbol, "error", err)
+		}
+	}
+
+	for _, symbol := range previous {
+		if slices.Contains(updated, symbol) {
+			continue
+		}
+		// Configured symbols are stored as added by "config", or by "default" when they seeded an empty watchlist
+		for _, addedBy := range []string{"config", "default"} {
+			if _, err := db.RemoveFromWatchlistAddedBy(symbol, addedBy); err != nil {
+				slog.Error("Error removing from the watchlist", "symbol", symbol, "error", err)
+			}
+		}
+	}
+}
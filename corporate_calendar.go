@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// Days of upcoming earnings and ex-dividend dates to ingest and serve
+const corporateCalendarDays = 90
+
+// ingestCorporateCalendar stores upcoming earnings and ex-dividend dates of watched symbols
+func ingestCorporateCalendar(ctx context.Context, db *services.Database, fetcher *services.FMPClient, now time.Time) {
+	events, err := fetcher.FetchCorporateCalendar(ctx, now, now.AddDate(0, 0, corporateCalendarDays), models.Tickers())
+	if err != nil {
+		slog.Error("Error fetching earnings and dividend calendar", "error", err)
+		return
+	}
+
+	if err := db.SaveCorporateEvents(events); err != nil {
+		slog.Error("Error saving earnings and dividend calendar", "error", err)
+		return
+	}
+	slog.Info("Ingested earnings and ex-dividend dates", "count", len(events))
+}
+
+// handleICalFeed serves upcoming earnings and ex-dividend dates as an iCalendar feed
+func (h *httpHandlers) handleICalFeed(w http.ResponseWriter, r *http.Request) {
+	// Keep a week of past events so recent dates don't vanish from calendars immediately
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	events, err := h.db.GetCorporateEvents(today.AddDate(0, 0, -7), today.AddDate(0, 0, corporateCalendarDays))
+	if err != nil {
+		slog.Error("Error loading corporate events for calendar", "error", err)
+		http.Error(w, "could not load events", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="stock-bot.ics"`)
+	w.Write(services.BuildICalendar(appName+" earnings and dividends", events, time.Now()))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"stock-bot/indicators"
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// checkCrossovers alerts on golden and death crosses of the configured moving averages in the stored
+// closes of symbols whose closing prices were just captured
+func checkCrossovers(db *services.Database, delivery *services.Delivery, config models.Config, symbols []string) {
+	crossover := config.Crossover
+	var enabled []string
+	for _, symbol := range symbols {
+		if crossover.Enabled(symbol) {
+			enabled = append(enabled, symbol)
+		}
+	}
+	if len(enabled) == 0 {
+		return
+	}
+
+	average := indicators.SMA
+	if crossover.Average == models.AverageEMA {
+		average = indicators.EMA
+	}
+
+	type crossAlert struct {
+		symbol string
+		cross  indicators.Cross
+		price  float64
+	}
+	var alerts []crossAlert
+	// Trading days are about two thirds of calendar days; the extra history lets the EMA settle
+	for symbol, points := range loadStoredCloses(db, enabled, crossover.Slow*3) {
+		values := make([]float64, len(points))
+		for i, point := range points {
+			values[i] = point.Close
+		}
+		if cross := indicators.Crossover(values, crossover.Fast, crossover.Slow, average); cross != indicators.CrossNone {
+			alerts = append(alerts, crossAlert{symbol: symbol, cross: cross, price: values[len(values)-1]})
+		}
+	}
+	if len(alerts) == 0 {
+		return
+	}
+
+	now := time.Now()
+	label := fmt.Sprintf("%d/%d-day %s", crossover.Fast, crossover.Slow, strings.ToUpper(crossover.Average))
+	_, err := delivery.Deliver(models.KindAlert, func(m services.Messenger, user models.User) error {
+		var lines []string
+		for _, alert := range alerts {
+			if !user.Watches(alert.symbol) || user.IsMuted(alert.symbol, now) {
+				continue
+			}
+			switch alert.cross {
+			case indicators.CrossGolden:
+				lines = append(lines, fmt.Sprintf("🌟 %s golden cross at %s", alert.symbol, models.FormatPrice(alert.symbol, alert.price)))
+			case indicators.CrossDeath:
+				lines = append(lines, fmt.Sprintf("💀 %s death cross at %s", alert.symbol, models.FormatPrice(alert.symbol, alert.price)))
+			}
+		}
+		if len(lines) == 0 {
+			return nil
+		}
+		return m.SendText(fmt.Sprintf("✖️ Moving Average Crossover (%s)\n\n%s", label, strings.Join(lines, "\n")), nil)
+	})
+	if err != nil {
+		slog.Error("Error sending crossover alerts", "error", err)
+		return
+	}
+	slog.Info("Crossover alerts sent", "count", len(alerts))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// dailyHistoryDays is how many calendar days of closes are loaded for streaks and signals
+const dailyHistoryDays = 120
+
+// loadDailyCloses downloads recent daily closes and volumes of every active symbol and stores them. The bar of a
+// session still in progress holds an intraday price, so it is left out of both the store and the streaks and signals
+func loadDailyCloses(ctx context.Context, db *services.Database) map[string][]models.PricePoint {
+	history := services.NewHistoryFetcher()
+	now := time.Now()
+
+	closes := make(map[string][]models.PricePoint)
+	for _, symbol := range symbolHealth.active(models.Tickers()) {
+		fetchCtx, cancel := context.WithTimeout(ctx, commandFetchTimeout)
+		points, err := history.FetchDailyCloses(fetchCtx, symbol, now.AddDate(0, 0, -dailyHistoryDays), now)
+		cancel()
+		if err != nil {
+			slog.Error("Error fetching daily closes", "symbol", symbol, "error", err)
+			continue
+		}
+
+		points = models.CompletedCloses(symbol, points, now)
+
+		// Keep the stored close history complete while we have it
+		if _, err := db.SaveHistoricalCloses(symbol, points); err != nil {
+			slog.Error("Error saving closes", "symbol", symbol, "error", err)
+		}
+		closes[symbol] = points
+	}
+	return closes
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// dividendWeekDays is the span of upcoming ex-dividend dates listed in the weekly summary
+const dividendWeekDays = 7
+
+// upcomingDividends returns the stored ex-dividend dates of watched symbols in the days ahead of now
+func upcomingDividends(db *services.Database, now time.Time, days int) ([]models.CorporateEvent, error) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	events, err := db.GetCorporateEvents(today, today.AddDate(0, 0, days))
+	if err != nil {
+		return nil, err
+	}
+
+	var dividends []models.CorporateEvent
+	for _, event := range events {
+		if event.Type == models.CorporateExDividend && event.Dividend > 0 {
+			dividends = append(dividends, event)
+		}
+	}
+	return dividends, nil
+}
+
+// sendDividendReminders tells each chat which of its symbols go ex-dividend on their exchange's next trading day,
+// the last chance to buy and still receive the payment
+func sendDividendReminders(ctx context.Context, db *services.Database, delivery *services.Delivery, now time.Time) {
+	events, err := upcomingDividends(db, now, earningsLookbackDays)
+	if err != nil {
+		slog.Error("Error loading ex-dividend dates for reminders", "error", err)
+		return
+	}
+
+	var upcoming []models.CorporateEvent
+	for _, event := range events {
+		exchange := models.ExchangeOf(event.Symbol)
+		if sameDay(event.Date, nextTradingDay(exchange, exchangeDate(exchange, now))) {
+			upcoming = append(upcoming, event)
+		}
+	}
+	if len(upcoming) == 0 {
+		return
+	}
+
+	prices := fetchDividendPrices(ctx, upcoming)
+	if _, err := delivery.Deliver(models.KindDividends, func(m services.Messenger, user models.User) error {
+		var lines []string
+		for _, event := range upcoming {
+			if user.Watches(event.Symbol) {
+				lines = append(lines, formatDividend(event, prices[event.Symbol]))
+			}
+		}
+		if len(lines) == 0 {
+			return nil
+		}
+		return m.SendText(locale.Text("💰 Ex-Dividend Next Session")+"\n\n"+strings.Join(lines, "\n")+
+			"\n\n"+locale.Text("Shares bought from the ex-date on don't receive the payment"), nil)
+	}); err != nil {
+		slog.Error("Error sending ex-dividend reminders", "error", err)
+		return
+	}
+	slog.Info("Ex-dividend reminders sent", "count", len(upcoming))
+}
+
+// dividendsSection lists the ex-dividend dates of the coming week for the weekly summary
+func dividendsSection(ctx context.Context, db *services.Database, now time.Time) string {
+	events, err := upcomingDividends(db, now, dividendWeekDays)
+	if err != nil {
+		slog.Error("Error loading ex-dividend dates for weekly report", "error", err)
+		return ""
+	}
+	if len(events) == 0 {
+		return ""
+	}
+
+	prices := fetchDividendPrices(ctx, events)
+	lines := make([]string, 0, len(events))
+	for _, event := range events {
+		lines = append(lines, event.Date.Format(locale.Text("Mon Jan 2"))+": "+formatDividend(event, prices[event.Symbol]))
+	}
+	return locale.Text("💰 Upcoming Ex-Dividend Dates") + "\n" + strings.Join(lines, "\n")
+}
+
+// fetchDividendPrices fetches the current price of each dividend's symbol to express the payment as a yield
+func fetchDividendPrices(ctx context.Context, events []models.CorporateEvent) map[string]float64 {
+	ctx, cancel := context.WithTimeout(ctx, commandFetchTimeout)
+	defer cancel()
+
+	prices := make(map[string]float64)
+	for _, event := range events {
+		if _, ok := prices[event.Symbol]; ok {
+			continue
+		}
+		quote, err := priceSource.Fetch(ctx, event.Symbol)
+		if err != nil {
+			slog.Warn("Error fetching price for dividend yield", "symbol", event.Symbol, "error", err)
+			continue
+		}
+		prices[event.Symbol] = quote.Price
+	}
+	return prices
+}
+
+// formatDividend renders a payment with its yield at the current price, e.g. "AAPL $0.25 a share (0.13%), paid Feb 15";
+// the yield is of the single payment, not annualized, as the payment frequency isn't known
+func formatDividend(event models.CorporateEvent, price float64) string {
+	line := locale.Sprintf("%s %s a share", event.Symbol, models.FormatPrice(event.Symbol, event.Dividend))
+	if price > 0 {
+		line += locale.Sprintf(" (%.2f%% of %s)", event.Dividend/price*100, models.FormatPrice(event.Symbol, price))
+	}
+	if !event.PaymentDate.IsZero() {
+		line += locale.Sprintf(", paid %s", event.PaymentDate.Format(locale.Text("Jan 2")))
+	}
+	return line
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"stock-bot/calendar"
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// earningsLookbackDays covers the longest gap between an earnings date and the session reacting to it,
+// an after-close release before a long weekend
+const earningsLookbackDays = 5
+
+// ingestEarningsCalendar stores upcoming earnings dates of watched symbols from Finnhub
+func ingestEarningsCalendar(ctx context.Context, db *services.Database, fetcher *services.FinnhubSource, now time.Time) {
+	events, err := fetcher.FetchEarningsCalendar(ctx, now, now.AddDate(0, 0, corporateCalendarDays), models.Tickers())
+	if err != nil {
+		slog.Error("Error fetching earnings calendar", "error", err)
+		return
+	}
+
+	if err := db.SaveCorporateEvents(events); err != nil {
+		slog.Error("Error saving earnings calendar", "error", err)
+		return
+	}
+	slog.Info("Ingested earnings dates", "count", len(events))
+}
+
+// sendEarningsReminders tells each chat which of its symbols report earnings on their exchange's next trading day
+func sendEarningsReminders(db *services.Database, delivery *services.Delivery, now time.Time) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	events, err := db.GetCorporateEvents(today, today.AddDate(0, 0, earningsLookbackDays))
+	if err != nil {
+		slog.Error("Error loading earnings dates for reminders", "error", err)
+		return
+	}
+
+	var upcoming []models.CorporateEvent
+	for _, event := range events {
+		exchange := models.ExchangeOf(event.Symbol)
+		if event.Type == models.CorporateEarnings &&
+			sameDay(event.Date, nextTradingDay(exchange, exchangeDate(exchange, now))) {
+			upcoming = append(upcoming, event)
+		}
+	}
+	if len(upcoming) == 0 {
+		return
+	}
+
+	if _, err := delivery.Deliver(models.KindEarnings, func(m services.Messenger, user models.User) error {
+		var lines []string
+		for _, event := range upcoming {
+			if user.Watches(event.Symbol) {
+				lines = append(lines, formatEarningsReminder(event))
+			}
+		}
+		if len(lines) == 0 {
+			return nil
+		}
+		return m.SendText("📅 Earnings Next Session\n\n"+strings.Join(lines, "\n"), nil)
+	}); err != nil {
+		slog.Error("Error sending earnings reminders", "error", err)
+		return
+	}
+	slog.Info("Earnings reminders sent", "count", len(upcoming))
+}
+
+// checkEarningsReactions alerts how each symbol closed the first session after its earnings release,
+// once its exchange's closes for the day are saved
+func checkEarningsReactions(db *services.Database, delivery *services.Delivery, exchange string, symbols []string, local time.Time) {
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	events, err := db.GetCorporateEvents(today.AddDate(0, 0, -earningsLookbackDays), today.AddDate(0, 0, 1))
+	if err != nil {
+		slog.Error("Error loading earnings dates for reactions", "error", err)
+		return
+	}
+
+	reacting := make(map[string]models.CorporateEvent)
+	for _, event := range events {
+		if event.Type == models.CorporateEarnings && models.ExchangeOf(event.Symbol) == exchange &&
+			sameDay(reactionDay(exchange, event), local) {
+			reacting[event.Symbol] = event
+		}
+	}
+	if len(reacting) == 0 {
+		return
+	}
+
+	var lines []string
+	var reacted []string
+	closes := loadStoredCloses(db, symbols, earningsLookbackDays+7)
+	for _, symbol := range symbols {
+		event, ok := reacting[symbol]
+		points := closes[symbol]
+		if !ok || len(points) < 2 {
+			continue
+		}
+		last, previous := points[len(points)-1], points[len(points)-2]
+		if !sameUTCDate(last.Timestamp, local) || previous.Close == 0 {
+			continue
+		}
+		change := (last.Close - previous.Close) / previous.Close * 100
+		lines = append(lines, fmt.Sprintf("%s %s %+.2f%% to %s after reporting %s %s",
+			directionIcon(change), symbol, change, models.FormatPrice(symbol, last.Close), event.Date.Format("Jan 2"), earningsTiming(event.Timing)))
+		reacted = append(reacted, symbol)
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	if _, err := delivery.Deliver(models.KindEarnings, func(m services.Messenger, user models.User) error {
+		var due []string
+		for i, symbol := range reacted {
+			if user.Watches(symbol) {
+				due = append(due, lines[i])
+			}
+		}
+		if len(due) == 0 {
+			return nil
+		}
+		return m.SendText("📣 Earnings Reaction\n\n"+strings.Join(due, "\n"), nil)
+	}); err != nil {
+		slog.Error("Error sending earnings reactions", "error", err)
+		return
+	}
+	slog.Info("Earnings reactions sent", "exchange", exchange, "count", len(reacted))
+}
+
+// formatEarningsReminder renders an upcoming release, e.g. "AAPL after the close, EPS estimate 2.10"
+func formatEarningsReminder(event models.CorporateEvent) string {
+	line := fmt.Sprintf("%s %s", event.Symbol, earningsTiming(event.Timing))
+	if event.EPSEstimate != nil {
+		line += fmt.Sprintf(", EPS estimate %.2f", *event.EPSEstimate)
+	}
+	return line
+}
+
+// earningsTiming describes when in the day a release comes out
+func earningsTiming(timing string) string {
+	switch timing {
+	case "bmo":
+		return "before the open"
+	case "amc":
+		return "after the close"
+	case "dmh":
+		return "during market hours"
+	}
+	return "(time not announced)"
+}
+
+// directionIcon is the chart icon of a price change
+func directionIcon(change float64) string {
+	if change < 0 {
+		return "📉"
+	}
+	return "📈"
+}
+
+// reactionDay is the session that first trades on a release: the release date itself unless it comes
+// after the close, in which case the next trading day
+func reactionDay(exchange string, event models.CorporateEvent) time.Time {
+	date := time.Date(event.Date.Year(), event.Date.Month(), event.Date.Day(), 12, 0, 0, 0, exchangeLocation(exchange))
+	if event.Timing == "amc" {
+		return nextTradingDay(exchange, date)
+	}
+	return date
+}
+
+// exchangeDate returns noon of the exchange-local date of t, which stays on that date in any time zone conversion
+func exchangeDate(exchange string, t time.Time) time.Time {
+	local := t.In(exchangeLocation(exchange))
+	return time.Date(local.Year(), local.Month(), local.Day(), 12, 0, 0, 0, local.Location())
+}
+
+// exchangeLocation returns the time zone of an exchange, or UTC if it can't be loaded
+func exchangeLocation(exchange string) *time.Location {
+	loc, err := time.LoadLocation(models.ExchangeTimeZones[exchange])
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// nextTradingDay returns the first trading day of an exchange after the given exchange-local date
+func nextTradingDay(exchange string, date time.Time) time.Time {
+	next := date.AddDate(0, 0, 1)
+	for !isExchangeTradingDay(exchange, next) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// isExchangeTradingDay reports whether an exchange trades on the exchange-local date of t; stock exchanges
+// are closed on weekends and, in the US and Korea, on their holidays
+func isExchangeTradingDay(exchange string, t time.Time) bool {
+	switch exchange {
+	case models.Exchange24H:
+		return true
+	case models.ExchangeUS:
+		return calendar.IsTradingDay(t)
+	case models.ExchangeKRX:
+		return calendar.IsKRXTradingDay(t)
+	}
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+// sameDay reports whether two times carry the same calendar date in their own time zones
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
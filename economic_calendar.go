@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// Economic calendar constants
+const (
+	economicCalendarDays = 7              // Days of upcoming events to ingest
+	economicBriefingSpan = 24 * time.Hour // Window of events shown in the daily briefing
+)
+
+// Countries whose macro releases move the watched markets
+var economicCountries = []string{"US"}
+
+// Date of the last economic calendar ingestion
+var lastEconomicIngestDate string
+
+// ingestEconomicCalendar stores the upcoming week of macro events
+func ingestEconomicCalendar(ctx context.Context, db *services.Database, fetcher *services.FMPClient, now time.Time) {
+	events, err := fetcher.FetchEconomicCalendar(ctx, now, now.AddDate(0, 0, economicCalendarDays), economicCountries)
+	if err != nil {
+		slog.Error("Error fetching economic calendar", "error", err)
+		return
+	}
+
+	if err := db.SaveEconomicEvents(events); err != nil {
+		slog.Error("Error saving economic calendar", "error", err)
+		return
+	}
+	slog.Info("Ingested economic calendar events", "count", len(events))
+}
+
+// economicEventsSection lists the high-impact events of the next day for the morning briefing
+func economicEventsSection(db *services.Database, now time.Time) string {
+	events, err := db.GetEconomicEvents(now, now.Add(economicBriefingSpan), models.ImpactHigh)
+	if err != nil {
+		slog.Error("Error loading economic events for briefing", "error", err)
+		return ""
+	}
+	if len(events) == 0 {
+		return ""
+	}
+
+	var section strings.Builder
+	section.WriteString("📅 High-Impact Events Today\n")
+	for _, event := range events {
+		section.WriteString(formatEconomicEvent(event, now.Location()) + "\n")
+	}
+	return section.String()
+}
+
+// checkEconomicReminders alerts shortly before each high-impact event
+func checkEconomicReminders(db *services.Database, delivery *services.Delivery, lead time.Duration, now time.Time) {
+	// Look at least one scheduler interval ahead so no event falls between checks
+	window := max(lead, time.Duration(checkInterval)*time.Minute)
+
+	events, err := db.GetEconomicEvents(now, now.Add(window), models.ImpactHigh)
+	if err != nil {
+		slog.Error("Error loading upcoming economic events", "error", err)
+		return
+	}
+
+	for _, event := range events {
+		if event.Reminded {
+			continue
+		}
+
+		message := fmt.Sprintf("⏰ In %d minutes\n%s", int(event.Time.Sub(now).Round(time.Minute).Minutes()), formatEconomicEvent(event, now.Location()))
+		if _, err := delivery.Deliver(models.KindEconomic, func(m services.Messenger, _ models.User) error {
+			return m.SendText(message, nil)
+		}); err != nil {
+			slog.Error("Error sending reminder", "event", event.Event, "error", err)
+			continue
+		}
+
+		if err := db.MarkEconomicEventReminded(event); err != nil {
+			slog.Error("Error marking reminder", "event", event.Event, "error", err)
+		}
+	}
+}
+
+// formatEconomicEvent renders an event line with its local time, estimate and previous value
+func formatEconomicEvent(event models.EconomicEvent, loc *time.Location) string {
+	line := fmt.Sprintf("%s %s %s", event.Time.In(loc).Format("15:04"), event.Country, event.Event)
+
+	var values []string
+	if event.Estimate != nil {
+		values = append(values, fmt.Sprintf("est %g", *event.Estimate))
+	}
+	if event.Previous != nil {
+		values = append(values, fmt.Sprintf("prev %g", *event.Previous))
+	}
+	if len(values) > 0 {
+		line += " (" + strings.Join(values, ", ") + ")"
+	}
+	return line
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// fetchFailureAlertCooldown limits how often admins are told about a high failure rate
+const fetchFailureAlertCooldown = 6 * time.Hour
+
+// Fetch failure categories shown in the daily report
+const (
+	failureNotFound = "not found"
+	failureTimeout  = "timeout"
+	failureNetwork  = "fetch failed"
+	failurePaused   = "paused, possibly delisted"
+	failureOther    = "error"
+)
+
+// fetchFailureMonitor remembers which symbols failed in the latest fetch cycle and alerts admins on high failure rates,
+// at the threshold and to the admins of the configuration in effect
+type fetchFailureMonitor struct {
+	delivery *services.Delivery
+
+	mu        sync.Mutex
+	latest    map[string]string // Symbol to failure category
+	lastAlert time.Time
+}
+
+// Global fetch failure monitor, set up once the messenger is ready
+var fetchFailures *fetchFailureMonitor
+
+// newFetchFailureMonitor creates a monitor that alerts the configured admins
+func newFetchFailureMonitor(delivery *services.Delivery) *fetchFailureMonitor {
+	return &fetchFailureMonitor{delivery: delivery}
+}
+
+// record stores the failures of a fetch cycle, including symbols skipped because they are paused
+func (m *fetchFailureMonitor) record(requested []string, results map[string]models.PriceResult) {
+	if m == nil {
+		return
+	}
+
+	failures := make(map[string]string)
+	attempted, failed := 0, 0
+	for _, symbol := range requested {
+		result, fetched := results[symbol]
+		if !fetched {
+			failures[symbol] = failurePaused
+			continue
+		}
+		attempted++
+		if result.Error != nil {
+			failures[symbol] = fetchErrorCategory(result.Error)
+			failed++
+		}
+	}
+
+	config := currentConfig()
+	threshold := config.FetchFailureAlertPercent // Failure rate in percent that triggers an admin alert; 0 disables
+
+	m.mu.Lock()
+	m.latest = failures
+	shouldAlert := threshold > 0 && attempted > 0 &&
+		float64(failed)/float64(attempted)*100 >= threshold &&
+		time.Since(m.lastAlert) >= fetchFailureAlertCooldown
+	if shouldAlert {
+		m.lastAlert = time.Now()
+	}
+	m.mu.Unlock()
+
+	if !shouldAlert {
+		return
+	}
+
+	rate := float64(failed) / float64(attempted) * 100
+	slog.Warn("Fetch failure rate exceeds threshold, notifying admins", "rate", rate, "failed", failed, "attempted", attempted, "threshold", threshold)
+	message := fmt.Sprintf("⚠️ %d of %d symbols (%.0f%%) failed to fetch in the last cycle.\n%s",
+		failed, attempted, rate, formatFailureList(failures))
+	if err := m.delivery.NotifyChats(config.AdminUserIDs, message); err != nil {
+		slog.Error("Error notifying admins about fetch failures", "error", err)
+	}
+}
+
+// failures returns the failure categories of the latest fetch cycle
+func (m *fetchFailureMonitor) failures() map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.latest
+}
+
+// fetchErrorCategory maps a fetch error to a short category for reports
+func fetchErrorCategory(err error) string {
+	switch {
+	case errors.Is(err, services.ErrElementNotFound):
+		return failureNotFound
+	case errors.Is(err, services.ErrBrowserTimeout), errors.Is(err, context.DeadlineExceeded),
+		strings.Contains(err.Error(), "deadline exceeded"):
+		return failureTimeout
+	case errors.Is(err, services.ErrPriceFetchFailed):
+		return failureNetwork
+	default:
+		return failureOther
+	}
+}
+
+// sendFetchFailures follows the daily report with the symbols whose data was unavailable
+func sendFetchFailures(delivery *services.Delivery) {
+	failures := fetchFailures.failures()
+	if len(failures) == 0 {
+		return
+	}
+
+	text := "⚠️ Data unavailable\n" + formatFailureList(failures)
+	_, err := delivery.Deliver(models.KindDailyReport, func(m services.Messenger, _ models.User) error {
+		return m.SendText(text, nil)
+	})
+	if err != nil {
+		slog.Error("Error sending fetch failure summary", "error", err)
+	}
+}
+
+// formatFailureList lists failed symbols alphabetically with their failure category
+func formatFailureList(failures map[string]string) string {
+	symbols := make([]string, 0, len(failures))
+	for symbol := range failures {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	lines := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		lines = append(lines, fmt.Sprintf("• %s: %s", symbol, failures[symbol]))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// sendExchangeRates follows the daily report with the configured currency pairs, so holders of
+// foreign stocks see what their positions are worth at home
+func sendExchangeRates(ctx context.Context, delivery *services.Delivery, config models.Config) {
+	if len(config.ReportFXRates) == 0 {
+		return
+	}
+
+	var message strings.Builder
+	message.WriteString("💱 Exchange Rates\n\n")
+
+	var fetched int
+	for _, pair := range config.ReportFXRates {
+		quote, err := priceSource.Fetch(ctx, pair)
+		if err != nil {
+			slog.Error("Error fetching exchange rate", "symbol", pair, "error", err)
+			continue
+		}
+		fetched++
+
+		message.WriteString(fmt.Sprintf("%s: %s", models.FXPairName(pair), models.FormatPrice(pair, quote.Price)))
+		// Reference rate sources publish no change
+		if quote.ChangePercent != 0 {
+			message.WriteString(fmt.Sprintf(" (%+.2f%%)", quote.ChangePercent))
+		}
+		message.WriteString("\n")
+	}
+	if fetched == 0 {
+		return
+	}
+
+	if _, err := delivery.Deliver(models.KindDailyReport, func(m services.Messenger, _ models.User) error {
+		return m.SendText(message.String(), nil)
+	}); err != nil {
+		slog.Error("Error sending exchange rates", "error", err)
+	}
+}
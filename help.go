@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// commandInfo describes a chat command for /help
+type commandInfo struct {
+	usage       string
+	description string
+	admin       bool
+}
+
+// commandHelp lists the chat commands in the order shown by /help
+var commandHelp = []commandInfo{
+	{usage: "/price SYMBOL", description: "Live quote with day change and volume"},
+	{usage: "/chart SYMBOL [1w|1m|3m|1y]", description: "Price chart"},
+	{usage: "/history SYMBOL [days]", description: "Recent daily closes"},
+	{usage: "/search NAME", description: "Find a ticker by company name"},
+	{usage: "/list", description: "Monitored symbols"},
+	{usage: "/notify TYPE on|off", description: "Choose which messages you receive"},
+	{usage: "/alertstyle compact|standard|verbose", description: "Choose how much detail alerts carry"},
+	{usage: "/mute SYMBOL [duration]", description: "Silence alerts for a symbol"},
+	{usage: "/unmute SYMBOL", description: "Receive alerts for a symbol again"},
+	{usage: "/alert SYMBOL above|below PRICE [repeat]", description: "Alert when a price level is reached"},
+	{usage: "/alerts [delete N|all]", description: "List or delete your price targets"},
+	{usage: "/portfolio", description: "Value, daily P&L and gains of your holdings"},
+	{usage: "/buy SYMBOL SHARES PRICE", description: "Add shares to your portfolio"},
+	{usage: "/sell SYMBOL SHARES", description: "Remove shares from your portfolio"},
+	{usage: "/start", description: "Set up your watchlist and preferences"},
+	{usage: "/cancel", description: "Stop the setup conversation"},
+	{usage: "/help", description: "Show this list"},
+	{usage: "/add SYMBOL", description: "Monitor a symbol from the next scheduled run", admin: true},
+	{usage: "/remove SYMBOL", description: "Stop monitoring a symbol", admin: true},
+	{usage: "/setthreshold [SYMBOL] PCT", description: "Alert threshold, default or per symbol", admin: true},
+	{usage: "/grant USER_ID admin|subscriber", description: "Assign a role", admin: true},
+	{usage: "/pause", description: "Pause scheduled reports and alerts", admin: true},
+	{usage: "/resume [SYMBOL]", description: "Resume scheduled work or a paused symbol", admin: true},
+	{usage: "/announce MESSAGE", description: "Broadcast a message to all subscribers", admin: true},
+	{usage: "/confirm", description: "Send the pending announcement", admin: true},
+}
+
+// handleHelp lists the available commands; admin commands are only shown to admins
+func (h *commandHandlers) handleHelp(ctx context.Context, cmd services.BotCommand) error {
+	var message strings.Builder
+	message.WriteString(locale.Text("🤖 Commands") + "\n\n")
+	for _, info := range commandHelp {
+		if !info.admin {
+			message.WriteString(fmt.Sprintf("%s\n  %s\n", info.usage, locale.Text(info.description)))
+		}
+	}
+
+	if h.isAdmin(cmd.UserID) {
+		message.WriteString("\n" + locale.Text("🔑 Admin commands") + "\n\n")
+		for _, info := range commandHelp {
+			if info.admin {
+				message.WriteString(fmt.Sprintf("%s\n  %s\n", info.usage, locale.Text(info.description)))
+			}
+		}
+	}
+
+	message.WriteString("\n" + locale.Text("You can also ask in plain words, e.g. \"how is nvidia doing\"."))
+	return h.bot.Reply(ctx, cmd.ChatID, message.String())
+}
+
+// handleList shows the monitored symbols with their priority, and the chat's own watchlist
+func (h *commandHandlers) handleList(ctx context.Context, cmd services.BotCommand) error {
+	active := symbolHealth.active(models.Tickers())
+
+	var message strings.Builder
+	message.WriteString(locale.Sprintf("📋 Monitored symbols (%d)", len(models.Tickers())) + "\n\n")
+	for _, symbol := range models.SortByPriority(models.Tickers()) {
+		line := symbol
+		if priority := models.PriorityOf(symbol); priority != models.PriorityNormal {
+			line += " " + locale.Sprintf("(%s priority)", priority)
+		}
+		if !slices.Contains(active, symbol) {
+			line += " ⏸ " + locale.Text("paused")
+		}
+		message.WriteString(line + "\n")
+	}
+
+	if user, err := h.db.GetUser(cmd.ChatID); err == nil && len(user.Watchlist) > 0 {
+		message.WriteString("\n" + locale.Sprintf("⭐ Your watchlist: %s", strings.Join(user.Watchlist, ", ")) + "\n")
+	}
+
+	return h.bot.Reply(ctx, cmd.ChatID, message.String())
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// Embedded HTTP server constants
+const (
+	httpShutdownTimeout = 5 * time.Second
+	feedEntryLimit      = 50 // Most recent events served in feeds
+)
+
+// httpHandlers serves the embedded HTTP endpoints
+type httpHandlers struct {
+	db     *services.Database
+	config models.Config
+	search *services.SymbolSearcher
+}
+
+// startHTTPServer serves the embedded HTTP endpoints until the context is cancelled; the Line webhook and the
+// chart images of its replies are served when a Line bot is given
+func startHTTPServer(ctx context.Context, db *services.Database, config models.Config, lineBot *services.LineBot) {
+	h := &httpHandlers{db: db, config: config, search: services.NewSymbolSearcher()}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /feed.atom", h.handleAtomFeed)
+	mux.HandleFunc("GET /feed.rss", h.handleRSSFeed)
+	mux.HandleFunc("GET /calendar.ics", h.handleICalFeed)
+	mux.HandleFunc("GET /search", h.handleSearch)
+	mux.HandleFunc("GET /healthz", h.handleHealthz)
+	mux.HandleFunc("GET /readyz", h.handleReadyz)
+	mux.HandleFunc("GET /status", h.requireToken(h.handleStatus, true))
+	mux.HandleFunc("POST /trigger/report", h.requireToken(h.handleTriggerReport, false))
+	if lineBot != nil {
+		mux.Handle("POST /line/webhook", lineBot)
+		mux.HandleFunc("GET /line/images/{id}", lineBot.ServeImage)
+	}
+
+	server := &http.Server{
+		Addr:              config.HTTPAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("Error shutting down HTTP server", "error", err)
+		}
+	}()
+
+	go func() {
+		slog.Info("HTTP server listening", "addr", config.HTTPAddr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("HTTP server error", "error", err)
+		}
+	}()
+}
+
+// handleAtomFeed serves recent alerts and reports as an Atom feed
+func (h *httpHandlers) handleAtomFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, "application/atom+xml; charset=utf-8", services.BuildAtomFeed)
+}
+
+// handleRSSFeed serves recent alerts and reports as an RSS feed
+func (h *httpHandlers) handleRSSFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveFeed(w, r, "application/rss+xml; charset=utf-8", services.BuildRSSFeed)
+}
+
+// serveFeed loads recent events and renders them with the given feed builder
+func (h *httpHandlers) serveFeed(w http.ResponseWriter, r *http.Request, contentType string, build func(services.Feed, []models.Event) ([]byte, error)) {
+	events, err := h.db.GetRecentEvents(feedEntryLimit)
+	if err != nil {
+		slog.Error("Error loading events for feed", "error", err)
+		http.Error(w, "could not load events", http.StatusInternalServerError)
+		return
+	}
+
+	feed := services.Feed{
+		Title:   appName + " alerts and reports",
+		Link:    h.publicURL(r) + r.URL.Path,
+		Updated: time.Now(),
+	}
+	if len(events) > 0 {
+		feed.Updated = events[0].Timestamp
+	}
+
+	body, err := build(feed, events)
+	if err != nil {
+		slog.Error("Error building feed", "error", err)
+		http.Error(w, "could not build feed", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Write(body)
+}
+
+// publicURL returns the configured public base URL, or one derived from the request
+func (h *httpHandlers) publicURL(r *http.Request) string {
+	if h.config.PublicURL != "" {
+		return h.config.PublicURL
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,70 @@
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Language is a language bot messages can be written in
+type Language string
+
+// Supported languages
+const (
+	English Language = "en"
+	Korean  Language = "ko"
+)
+
+// catalogs maps each translated language to its messages, keyed by the English text or format string
+var catalogs = map[Language]map[string]string{
+	Korean: korean,
+}
+
+// ParseLanguage validates a configured language, accepting codes such as "ko" or "ko_KR" and names such as "korean";
+// it defaults to English
+func ParseLanguage(value string) (Language, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if code, _, found := strings.Cut(strings.ReplaceAll(value, "-", "_"), "_"); found {
+		value = code
+	}
+	switch value {
+	case "", "en", "english":
+		return English, nil
+	case "ko", "kr", "korean", "한국어":
+		return Korean, nil
+	default:
+		return English, fmt.Errorf("unknown language %q (use %s or %s)", value, English, Korean)
+	}
+}
+
+// Printer writes messages in one language, falling back to English for messages without a translation
+type Printer struct {
+	language Language
+	catalog  map[string]string
+}
+
+// NewPrinter returns a printer for a language
+func NewPrinter(language Language) *Printer {
+	return &Printer{language: language, catalog: catalogs[language]}
+}
+
+// Language returns the language the printer writes in
+func (p *Printer) Language() Language {
+	return p.language
+}
+
+// Text returns the translation of an English message
+func (p *Printer) Text(message string) string {
+	if translated, ok := p.catalog[message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Sprintf formats the translation of an English format string; translations may reorder the arguments with
+// explicit indexes such as %[2]s
+func (p *Printer) Sprintf(format string, args ...any) string {
+	if len(args) == 0 {
+		return p.Text(format)
+	}
+	return fmt.Sprintf(p.Text(format), args...)
+}
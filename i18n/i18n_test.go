@@ -0,0 +1,115 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Language
+		wantErr bool
+	}{
+		{"", English, false},
+		{"en", English, false},
+		{"English", English, false},
+		{"en_US", English, false},
+		{"ko", Korean, false},
+		{"ko-KR", Korean, false},
+		{"ko_KR.UTF-8", Korean, false},
+		{" Korean ", Korean, false},
+		{"한국어", Korean, false},
+		{"ja", English, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLanguage(tt.value)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseLanguage(%q) = %q, %v, want %q, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestPrinter(t *testing.T) {
+	tests := []struct {
+		name     string
+		language Language
+		format   string
+		args     []any
+		want     string
+	}{
+		{"English text", English, "Date", nil, "Date"},
+		{"translated text", Korean, "Date", nil, "날짜"},
+		{"untranslated text falls back to English", Korean, "No such message", nil, "No such message"},
+		{"translated format", Korean, "Invalid symbol: %s", []any{"XYZ"}, "잘못된 종목입니다: XYZ"},
+		{"English format", English, "Invalid symbol: %s", []any{"XYZ"}, "Invalid symbol: XYZ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewPrinter(tt.language).Sprintf(tt.format, tt.args...); got != tt.want {
+				t.Errorf("Sprintf = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// formatVerb matches a format verb with its optional explicit argument index
+var formatVerb = regexp.MustCompile(`%(?:\[(\d+)\])?[-+# 0]*\d*(?:\.\d+)?([a-zA-Z%])`)
+
+// verbsByArgument lists the verb applied to each argument of a format string
+func verbsByArgument(format string) []string {
+	var verbs []string
+	next := 0
+	for _, match := range formatVerb.FindAllStringSubmatch(format, -1) {
+		if match[2] == "%" {
+			continue
+		}
+		if match[1] != "" {
+			index, _ := strconv.Atoi(match[1])
+			next = index - 1
+		}
+		for len(verbs) <= next {
+			verbs = append(verbs, "")
+		}
+		verbs[next] = match[2]
+		next++
+	}
+	return verbs
+}
+
+func TestCatalogVerbsMatch(t *testing.T) {
+	for language, catalog := range catalogs {
+		for english, translated := range catalog {
+			if want, got := verbsByArgument(english), verbsByArgument(translated); !slices.Equal(want, got) {
+				t.Errorf("%s translation of %q uses verbs %v, want %v", language, english, got, want)
+			}
+		}
+	}
+}
+
+func TestPadding(t *testing.T) {
+	tests := []struct {
+		text      string
+		width     int
+		wantRight string
+		wantLeft  string
+	}{
+		{"Date", 6, "Date  ", "  Date"},
+		{"날짜", 6, "날짜  ", "  날짜"},
+		{"변동률", 8, "변동률  ", "  변동률"},
+		{"too long", 4, "too long", "too long"},
+	}
+
+	for _, tt := range tests {
+		if got := PadRight(tt.text, tt.width); got != tt.wantRight {
+			t.Errorf("PadRight(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.wantRight)
+		}
+		if got := PadLeft(tt.text, tt.width); got != tt.wantLeft {
+			t.Errorf("PadLeft(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.wantLeft)
+		}
+	}
+}
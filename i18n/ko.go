@@ -0,0 +1,230 @@
+package i18n
+
+// korean holds the Korean translations of chat command replies and of scheduled alerts, reports and message
+// formats other than the Telegram and Line templates; arguments are reordered with explicit indexes
+// where Korean word order differs
+var korean = map[string]string{
+	// Usage and validation
+	"Usage: /price SYMBOL":                                                          "사용법: /price 종목",
+	"Usage: /chart SYMBOL [1w|1m|3m|1y]":                                            "사용법: /chart 종목 [1w|1m|3m|1y]",
+	"Usage: /history SYMBOL [days, max %d]":                                         "사용법: /history 종목 [일수, 최대 %d]",
+	"Usage: /search NAME, e.g. /search nvidia":                                      "사용법: /search 회사명, 예: /search nvidia",
+	"Usage: /notify TYPE on|off":                                                    "사용법: /notify 종류 on|off",
+	"Usage: /notify TYPE on|off (types: %s)":                                        "사용법: /notify 종류 on|off (종류: %s)",
+	"Usage: /alertstyle compact|standard|verbose":                                   "사용법: /alertstyle compact|standard|verbose",
+	"Usage: /mute SYMBOL [duration, e.g. 2h, 3d, 1w, or today]":                     "사용법: /mute 종목 [기간, 예: 2h, 3d, 1w 또는 today]",
+	"Usage: /unmute SYMBOL":                                                         "사용법: /unmute 종목",
+	"Usage: /setthreshold [SYMBOL] PCT":                                             "사용법: /setthreshold [종목] 퍼센트",
+	"Usage: /alert SYMBOL above|below PRICE [repeat]":                               "사용법: /alert 종목 above|below 가격 [repeat]",
+	"Usage: /alerts [delete N|all]":                                                 "사용법: /alerts [delete 번호|all]",
+	"Usage: /buy SYMBOL SHARES PRICE":                                               "사용법: /buy 종목 수량 가격",
+	"Usage: /sell SYMBOL SHARES":                                                    "사용법: /sell 종목 수량",
+	"Usage: /add SYMBOL":                                                            "사용법: /add 종목",
+	"Usage: /remove SYMBOL":                                                         "사용법: /remove 종목",
+	"Usage: /grant USER_ID admin|subscriber":                                        "사용법: /grant 사용자ID admin|subscriber",
+	"Usage: /resume [SYMBOL]":                                                       "사용법: /resume [종목]",
+	"Usage: /announce MESSAGE":                                                      "사용법: /announce 메시지",
+	"Invalid symbol: %s":                                                            "잘못된 종목입니다: %s",
+	"Period must be one of 1w, 1m, 3m, 1y":                                          "기간은 1w, 1m, 3m, 1y 중 하나여야 합니다",
+	"Days must be a number between 1 and %d":                                        "일수는 1에서 %d 사이의 숫자여야 합니다",
+	"Please use on or off":                                                          "on 또는 off로 입력해 주세요",
+	"Duration must look like 30m, 2h, 3d or 1w":                                     "기간은 30m, 2h, 3d, 1w 형식으로 입력해 주세요",
+	"Direction must be above or below":                                              "방향은 above 또는 below여야 합니다",
+	"Price must be a positive number":                                               "가격은 양수여야 합니다",
+	"Shares must be a positive number":                                              "수량은 양수여야 합니다",
+	"Role must be admin or subscriber":                                              "역할은 admin 또는 subscriber여야 합니다",
+	"Choose a target between 1 and %d":                                              "1에서 %d 사이의 목표 번호를 선택해 주세요",
+	"Please send a percentage between 0 and 100, e.g. 5":                            "0에서 100 사이의 퍼센트를 보내 주세요. 예: 5",
+	"Please send an hour between 0 and 23, e.g. 7":                                  "0에서 23 사이의 시각을 보내 주세요. 예: 7",
+	"⛔ This command is only available to admins.":                                   "⛔ 관리자만 사용할 수 있는 명령어입니다.",
+	"I couldn't read these symbols: %s. Please try again.":                          "다음 종목을 인식하지 못했습니다: %s. 다시 시도해 주세요.",
+	"Threshold must be a percentage between 0 and 100, e.g. /setthreshold NVDA 3.5": "기준은 0에서 100 사이의 퍼센트여야 합니다. 예: /setthreshold NVDA 3.5",
+
+	// Line chats
+	"Line chats receive every report and alert of the channel, so this command is only available on Telegram.": "Line 채팅은 채널의 모든 리포트와 알림을 받으므로 이 명령어는 Telegram에서만 사용할 수 있습니다.",
+
+	// Quotes, history and search
+	"Change: %+.2f (%+.2f%%)": "변동: %+.2f (%+.2f%%)",
+	"Volume: %s":              "거래량: %s",
+	"As of %s":                "%s 기준",
+	"*%s* closing prices":     "*%s* 종가",
+	"Date":                    "날짜",
+	"Close":                   "종가",
+	"Change":                  "변동률",
+	"No closing prices stored for %s yet. Try /chart %s to backfill.": "%s의 저장된 종가가 아직 없습니다. /chart %s 로 과거 데이터를 불러와 보세요.",
+	"🔎 Results for %q":        "🔎 %q 검색 결과",
+	"No symbols found for %q": "%q에 해당하는 종목이 없습니다",
+
+	// Notifications, alert style and muting
+	"Notification settings:":           "알림 설정:",
+	"on":                               "켜짐",
+	"off":                              "꺼짐",
+	"✅ %s notifications turned %s":     "✅ %s 알림: %s",
+	"channel default":                  "채널 기본값",
+	"✅ Alerts in this chat are now %s": "✅ 이 채팅의 알림 형식이 %s(으)로 변경되었습니다",
+	"Alert style: %s\n\nUsage: /alertstyle compact|standard|verbose": "알림 형식: %s\n\n사용법: /alertstyle compact|standard|verbose",
+	"🔇 Alerts for %s muted until you send /unmute %s":                "🔇 /unmute %[2]s 를 보낼 때까지 %[1]s 알림을 끕니다",
+	"🔇 Alerts for %s muted until %s":                                 "🔇 %s 알림을 %s까지 끕니다",
+	"%s is not muted":                                                "%s 알림은 꺼져 있지 않습니다",
+	"🔔 Alerts for %s unmuted":                                        "🔔 %s 알림을 다시 켰습니다",
+
+	// Thresholds
+	"Global alert threshold: %.2f%%":         "전체 알림 기준: %.2f%%",
+	"✅ Global alert threshold set to %.2f%%": "✅ 전체 알림 기준을 %.2f%%로 설정했습니다",
+	"✅ Alert threshold for %s set to %.2f%%": "✅ %s 알림 기준을 %.2f%%로 설정했습니다",
+
+	// Price targets
+	"above":                           "이상",
+	"below":                           "이하",
+	"once":                            "한 번만",
+	"every time it crosses":           "도달할 때마다",
+	"🎯 Alerting when %s is %s %s, %s": "🎯 %[1]s 가격이 %[3]s %[2]s일 때 알립니다 (%[4]s)",
+	"You already have %d price targets; remove one with /alerts delete N": "이미 목표가가 %d개 있습니다. /alerts delete 번호 로 하나를 삭제해 주세요",
+	"No price targets. Add one with /alert SYMBOL above|below PRICE":      "목표가가 없습니다. /alert 종목 above|below 가격 으로 추가해 주세요",
+	"🎯 Price targets":             "🎯 목표가",
+	"(repeat)":                    "(반복)",
+	"(repeat, waiting to re-arm)": "(반복, 재설정 대기 중)",
+	"(waiting to re-arm)":         "(재설정 대기 중)",
+	"%s is already there at %s, so the alert waits for the price to cross back first": "%s 가격이 이미 %s로 도달해 있어, 가격이 반대편으로 돌아간 뒤 다시 도달할 때 알립니다",
+	"Delete one with /alerts delete N":                                                "삭제하려면 /alerts delete 번호",
+	"🗑 Deleted %d price targets":                                                      "🗑 목표가 %d개를 삭제했습니다",
+	"🗑 Deleted %s %s %s":                                                              "🗑 %[1]s %[3]s %[2]s 목표가를 삭제했습니다",
+
+	// Portfolio
+	"Your portfolio is empty. Add a position with /buy SYMBOL SHARES PRICE": "포트폴리오가 비어 있습니다. /buy 종목 수량 가격 으로 추가해 주세요",
+	"💼 %s: %s shares at an average cost of %s":                              "💼 %s: %s주, 평균 단가 %s",
+	"You hold %s shares of %s":                                              "%[2]s 보유 수량은 %[1]s주입니다",
+	"💼 Closed your %s position":                                             "💼 %s 포지션을 정리했습니다",
+	"💼 %s: %s shares left":                                                  "💼 %s: %s주 남음",
+	"💼 Portfolio":                                                           "💼 포트폴리오",
+	"%s: %s shares, price unavailable":                                      "%s: %s주, 가격 정보 없음",
+	"Day %s, P&L %s%s":                                                      "당일 %s, 평가손익 %s%s",
+	"Total %s: %s":                                                          "합계 %s: %s",
+
+	// Watchlist and monitored symbols
+	"%s is already on the watchlist.":                                         "%s은(는) 이미 관심 목록에 있습니다.",
+	"✅ Added %s to the watchlist; it is checked from the next scheduled run.": "✅ %s을(를) 관심 목록에 추가했습니다. 다음 예약 실행부터 확인합니다.",
+	"%s is not on the watchlist.":                                             "%s은(는) 관심 목록에 없습니다.",
+	"🗑 Removed %s from the watchlist from the next scheduled run.":            "🗑 다음 예약 실행부터 %s을(를) 관심 목록에서 제외합니다.",
+	"📋 Monitored symbols (%d)":                                                "📋 모니터링 종목 (%d)",
+	"(%s priority)":                                                           "(우선순위 %s)",
+	"paused":                                                                  "일시 중지됨",
+	"⭐ Your watchlist: %s":                                                    "⭐ 내 관심 목록: %s",
+
+	// Administration
+	"✅ User %s is now %s": "✅ 사용자 %s의 역할이 %s(으)로 변경되었습니다",
+	"⏸ Scheduled reports and alerts are paused. Send /resume to continue.": "⏸ 예약된 리포트와 알림을 일시 중지했습니다. 다시 시작하려면 /resume 을 보내 주세요.",
+	"%s is not paused.":                        "%s은(는) 일시 중지 상태가 아닙니다.",
+	"▶️ Fetching %s again.":                    "▶️ %s 조회를 다시 시작합니다.",
+	"▶️ Scheduled reports and alerts resumed.": "▶️ 예약된 리포트와 알림을 다시 시작했습니다.",
+	"📣 Preview:\n\n%s\n\nSend to %d chat(s)? Reply /confirm to send or /cancel to discard.": "📣 미리보기:\n\n%s\n\n%d개 채팅으로 보낼까요? 보내려면 /confirm, 취소하려면 /cancel 을 보내 주세요.",
+	"No announcement waiting for confirmation. Use /announce MESSAGE first.":                "확인을 기다리는 공지가 없습니다. 먼저 /announce 메시지 를 사용해 주세요.",
+	"✅ Announcement delivered to %d chat(s), %d failed.":                                    "✅ 공지를 %d개 채팅에 보냈습니다. 실패 %d건.",
+
+	// Onboarding
+	"default": "기본",
+	"👋 Welcome to %s! Let's set things up in three quick steps (send /cancel to stop).":                              "👋 %s에 오신 것을 환영합니다! 세 단계로 간단히 설정해 볼게요 (중단하려면 /cancel).",
+	"👋 Welcome to %s! This chat receives every report and alert of the channel; send /help for the commands.":        "👋 %s에 오신 것을 환영합니다! 이 채팅은 채널의 모든 리포트와 알림을 받습니다. 명령어는 /help 로 확인하세요.",
+	"1️⃣ Which tickers would you like to follow? Send symbols separated by spaces or commas, or \"default\" for %s.": "1️⃣ 어떤 종목을 받아 보시겠어요? 공백이나 쉼표로 구분해 보내 주세요. \"기본\"을 보내면 %s 입니다.",
+	"2️⃣ What percent move should trigger an alert? Send a number, or \"default\" for %.0f%%.":                       "2️⃣ 몇 퍼센트 변동 시 알림을 받으시겠어요? 숫자를 보내거나 \"기본\"을 보내면 %.0f%%입니다.",
+	"3️⃣ At what hour (0-23, %s) should I send the daily report? Send a number, or \"default\" for %d.":              "3️⃣ 일일 리포트를 몇 시에 보내 드릴까요 (0-23, %s)? 숫자를 보내거나 \"기본\"을 보내면 %d시입니다.",
+	"✅ All set!\nWatching: %s\nAlert threshold: %.2f%%\nDaily report: %02d:00 (%s)":                                  "✅ 설정 완료!\n관심 종목: %s\n알림 기준: %.2f%%\n일일 리포트: %02d:00 (%s)",
+	"Setup cancelled. Send /start to begin again.":                                                                   "설정을 취소했습니다. 다시 시작하려면 /start 를 보내 주세요.",
+	"Announcement discarded.": "공지를 취소했습니다.",
+	"Nothing to cancel.":      "취소할 작업이 없습니다.",
+
+	// Help
+	"🤖 Commands":       "🤖 명령어",
+	"🔑 Admin commands": "🔑 관리자 명령어",
+	"You can also ask in plain words, e.g. \"how is nvidia doing\".": "\"엔비디아 어때\"처럼 일상적인 말로 물어볼 수도 있습니다.",
+	"Live quote with day change and volume":                          "실시간 시세, 당일 변동과 거래량",
+	"Price chart":                                                    "가격 차트",
+	"Recent daily closes":                                            "최근 일별 종가",
+	"Find a ticker by company name":                                  "회사명으로 종목 코드 찾기",
+	"Monitored symbols":                                              "모니터링 종목",
+	"Choose which messages you receive":                              "받을 메시지 종류 선택",
+	"Choose how much detail alerts carry":                            "알림 상세 수준 선택",
+	"Silence alerts for a symbol":                                    "종목 알림 끄기",
+	"Receive alerts for a symbol again":                              "종목 알림 다시 켜기",
+	"Alert when a price level is reached":                            "목표 가격 도달 시 알림",
+	"List or delete your price targets":                              "목표가 목록 보기 또는 삭제",
+	"Value, daily P&L and gains of your holdings":                    "보유 종목의 평가액, 당일 손익과 수익률",
+	"Add shares to your portfolio":                                   "포트폴리오에 수량 추가",
+	"Remove shares from your portfolio":                              "포트폴리오에서 수량 제외",
+	"Set up your watchlist and preferences":                          "관심 목록과 환경 설정",
+	"Stop the setup conversation":                                    "설정 대화 중단",
+	"Show this list":                                                 "이 목록 보기",
+	"Monitor a symbol from the next scheduled run":                   "다음 예약 실행부터 종목 모니터링",
+	"Stop monitoring a symbol":                                       "종목 모니터링 중단",
+	"Alert threshold, default or per symbol":                         "알림 기준 (전체 또는 종목별)",
+	"Assign a role":                                                  "역할 지정",
+	"Pause scheduled reports and alerts":                             "예약된 리포트와 알림 일시 중지",
+	"Resume scheduled work or a paused symbol":                       "예약 작업 또는 일시 중지된 종목 재개",
+	"Broadcast a message to all subscribers":                         "모든 구독자에게 메시지 전송",
+	"Send the pending announcement":                                  "대기 중인 공지 전송",
+
+	// Alert buttons
+	"🔕 Mute %s today": "🔕 오늘 %s 알림 끄기",
+	"📈 %s chart":      "📈 %s 차트",
+	"📜 %s 30 days":    "📜 %s 30일 종가",
+
+	// Scheduled alerts and reports
+	"📏 Streak Alert":                        "📏 연속 등락 알림",
+	"📏 Streaks":                             "📏 연속 등락",
+	"🟢 %s: %d up days in a row (%+.2f%%)":   "🟢 %s: %d일 연속 상승 (%+.2f%%)",
+	"🔴 %s: %d down days in a row (%+.2f%%)": "🔴 %s: %d일 연속 하락 (%+.2f%%)",
+	"🗓 Weekly Summary":                      "🗓 주간 요약",
+	"🗓 Monthly Summary, %s":                 "🗓 %s 월간 요약",
+	"Week-over-Week":                        "전주 대비",
+	"Month-over-Month":                      "전월 대비",
+	"📊 %s Changes":                          "📊 %s 등락률",
+	"🏆 Best Performers":                     "🏆 상승률 상위",
+	"🥀 Worst Performers":                    "🥀 상승률 하위",
+	"🏔 New 30-Day Highs":                    "🏔 30일 신고가",
+	"🕳 New 30-Day Lows":                     "🕳 30일 신저가",
+	"📉 Short Interest Changes":              "📉 공매도 잔고 변화",
+	"%s: %s shares short (%+.1f%%), %.1f days to cover (as of %s)": "%s: 공매도 잔고 %s주 (%+.1f%%), 상환 소요 %.1f일 (%s 기준)",
+	"💰 Upcoming Ex-Dividend Dates":                                 "💰 다가오는 배당락일",
+	"💰 Ex-Dividend Next Session":                                   "💰 다음 거래일 배당락",
+	"Shares bought from the ex-date on don't receive the payment":  "배당락일부터 매수한 주식은 배당을 받지 못합니다",
+	"%s %s a share":      "%s 주당 %s",
+	" (%.2f%% of %s)":    " (%[2]s의 %[1].2f%%)",
+	", paid %s":          ", %s 지급",
+	"📐 52-Week Extremes": "📐 52주 신고가·신저가",
+	"🚀 %s new 52-week high at %s, %.2f%% above the prior high of %s (%s)": "🚀 %s 52주 신고가 %s, 이전 고점 %[4]s(%[5]s)보다 %[3].2f%% 높음",
+	"🕳 %s new 52-week low at %s, %.2f%% below the prior low of %s (%s)":   "🕳 %s 52주 신저가 %s, 이전 저점 %[4]s(%[5]s)보다 %[3].2f%% 낮음",
+	"📊 Unusual Volume":                             "📊 거래량 급증",
+	"%s %s shares, %.1f× the %d-day average of %s": "%s %s주, %[4]d일 평균 %[5]s의 %[3].1f배",
+	"🔔 US market is open, watching %d symbols":     "🔔 미국 시장이 열렸습니다. %d개 종목을 모니터링합니다",
+	"⏰ Early close today at %s ET":                 "⏰ 오늘은 미 동부 시간 %s에 조기 마감합니다",
+	"🏁 US market closed, summary:":                 "🏁 미국 시장 마감 요약:",
+	"%d price alerts sent this session":            "이번 세션에 가격 알림 %d건을 보냈습니다",
+	"Jan 2":                                        "1월 2일",
+	"Mon Jan 2":                                    "1월 2일",
+	"Jan 2, 2006":                                  "2006년 1월 2일",
+	"January 2006":                                 "2006년 1월",
+
+	// Slack, email and plain-text formats
+	"📊 Daily Stock Report":                         "📊 일일 주식 리포트",
+	"⚠️ Significant Price Changes Detected":        "⚠️ 큰 가격 변동 감지",
+	"🟢 Increased":                                  "🟢 상승",
+	"🔴 Decreased":                                  "🔴 하락",
+	"*%s*: %s by *%.2f%%*\n%s: %s → Current: %s\n": "*%s*: %s *%.2f%%*\n%s: %s → 현재: %s\n",
+	"Previous":              "전일 종가",
+	"Open":                  "시가",
+	"%.0fm ago":             "%.0f분 전",
+	"Daily Stock Report %s": "일일 주식 리포트 %s",
+	"Price alert: %s":       "가격 알림: %s",
+	"Symbol":                "종목",
+	"Price":                 "가격",
+	"Current":               "현재",
+	"DAILY STOCK REPORT %s": "일일 주식 리포트 %s",
+	"PRICE ALERTS":          "가격 알림",
+	"SYMBOL":                "종목",
+	"PRICE":                 "가격",
+	"CHG%":                  "변동%",
+	"CCY":                   "통화",
+	"PREV":                  "이전",
+	"NOW":                   "현재",
+	"VOL":                   "거래량",
+}
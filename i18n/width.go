@@ -0,0 +1,42 @@
+package i18n
+
+import "strings"
+
+// DisplayWidth returns how many columns text takes in a monospace font, where Hangul, CJK and fullwidth
+// characters take two
+func DisplayWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		if isWide(r) {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
+
+// PadRight left-aligns text in a column of the given width
+func PadRight(text string, width int) string {
+	return text + strings.Repeat(" ", max(width-DisplayWidth(text), 0))
+}
+
+// PadLeft right-aligns text in a column of the given width
+func PadLeft(text string, width int) string {
+	return strings.Repeat(" ", max(width-DisplayWidth(text), 0)) + text
+}
+
+// isWide reports whether a character is drawn two columns wide
+func isWide(r rune) bool {
+	switch {
+	case r >= 0x1100 && r <= 0x115F, // Hangul Jamo initial consonants
+		r >= 0x2E80 && r <= 0xA4CF, // CJK radicals through Yi
+		r >= 0xAC00 && r <= 0xD7A3, // Hangul syllables
+		r >= 0xF900 && r <= 0xFAFF, // CJK compatibility ideographs
+		r >= 0xFE30 && r <= 0xFE4F, // CJK compatibility forms
+		r >= 0xFF00 && r <= 0xFF60, // Fullwidth forms
+		r >= 0xFFE0 && r <= 0xFFE6:
+		return true
+	}
+	return false
+}
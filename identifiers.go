@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"stock-bot/models"
+)
+
+// resolveSymbol normalizes a user supplied ticker, looks up the market of a bare Korean stock code, or resolves an
+// ISIN or CUSIP to the provider's ticker
+func (h *commandHandlers) resolveSymbol(ctx context.Context, value string) (string, bool) {
+	identifier := strings.ToUpper(strings.TrimSpace(value))
+	if models.IsKRXCode(identifier) {
+		return h.resolveKRXCode(ctx, identifier)
+	}
+	if !models.IsISIN(identifier) && !models.IsCUSIP(identifier) {
+		return models.NormalizeSymbol(identifier)
+	}
+
+	if instrument, err := h.db.FindInstrument(identifier); err == nil {
+		return instrument.Symbol, true
+	}
+
+	resolveCtx, cancel := context.WithTimeout(ctx, commandFetchTimeout)
+	defer cancel()
+
+	instrument, err := h.search.ResolveIdentifier(resolveCtx, identifier)
+	if err != nil {
+		slog.Warn("Could not resolve identifier", "identifier", identifier, "error", err)
+		return "", false
+	}
+	slog.Info("Resolved identifier", "identifier", identifier, "symbol", instrument.Symbol, "name", instrument.Name)
+
+	if err := h.db.SaveInstrument(instrument); err != nil {
+		slog.Error("Error saving instrument", "symbol", instrument.Symbol, "error", err)
+	}
+	return instrument.Symbol, true
+}
+
+// resolveKRXCode finds whether a Korean stock code is listed on KOSPI or KOSDAQ, assuming KOSPI when the search fails
+func (h *commandHandlers) resolveKRXCode(ctx context.Context, code string) (string, bool) {
+	resolveCtx, cancel := context.WithTimeout(ctx, commandFetchTimeout)
+	defer cancel()
+
+	symbol, err := h.search.ResolveKRXCode(resolveCtx, code)
+	if err != nil {
+		slog.Warn("Could not look up Korean stock code, assuming KOSPI", "code", code, "error", err)
+		return models.NormalizeSymbol(code)
+	}
+	return symbol, true
+}
+
+// parseSymbolList splits a space or comma separated list into valid and invalid symbols
+func (h *commandHandlers) parseSymbolList(ctx context.Context, text string) ([]string, []string) {
+	var symbols, invalid []string
+	seen := make(map[string]bool)
+
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' }) {
+		symbol, ok := h.resolveSymbol(ctx, field)
+		if !ok {
+			invalid = append(invalid, field)
+			continue
+		}
+		if !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	return symbols, invalid
+}
@@ -0,0 +1,106 @@
+// Package indicators computes technical indicators from daily closing prices
+package indicators
+
+// SMA returns the simple moving average of the last period values, or false if there are too few
+func SMA(values []float64, period int) (float64, bool) {
+	if period <= 0 || len(values) < period {
+		return 0, false
+	}
+
+	sum := 0.0
+	for _, v := range values[len(values)-period:] {
+		sum += v
+	}
+	return sum / float64(period), true
+}
+
+// RSI returns the Wilder relative strength index of the closes, or false if there are too few
+func RSI(closes []float64, period int) (float64, bool) {
+	if period <= 0 || len(closes) <= period {
+		return 0, false
+	}
+
+	// Seed with simple averages, then apply Wilder's smoothing
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		change := closes[i] - closes[i-1]
+		if change > 0 {
+			gain += change
+		} else {
+			loss -= change
+		}
+	}
+	gain /= float64(period)
+	loss /= float64(period)
+
+	for i := period + 1; i < len(closes); i++ {
+		change := closes[i] - closes[i-1]
+		up, down := 0.0, 0.0
+		if change > 0 {
+			up = change
+		} else {
+			down = -change
+		}
+		gain = (gain*float64(period-1) + up) / float64(period)
+		loss = (loss*float64(period-1) + down) / float64(period)
+	}
+
+	if loss == 0 {
+		return 100, true
+	}
+	return 100 - 100/(1+gain/loss), true
+}
+
+// MovingAverage computes an average of the last period values, or false if there are too few
+type MovingAverage func(values []float64, period int) (float64, bool)
+
+// EMA returns the exponential moving average of the values, seeded with the simple average of the
+// first period values, or false if there are too few
+func EMA(values []float64, period int) (float64, bool) {
+	if period <= 0 || len(values) < period {
+		return 0, false
+	}
+	seed, _ := SMA(values[:period], period)
+
+	k := 2 / float64(period+1)
+	ema := seed
+	for _, v := range values[period:] {
+		ema = v*k + ema*(1-k)
+	}
+	return ema, true
+}
+
+// Cross is a change in the order of a fast and a slow moving average
+type Cross int
+
+// Crossover kinds
+const (
+	CrossNone   Cross = iota
+	CrossGolden       // The fast average rose above the slow one
+	CrossDeath        // The fast average fell below the slow one
+)
+
+// Crossover reports whether the fast average crossed the slow one on the latest value,
+// comparing the averages with and without it
+func Crossover(values []float64, fast, slow int, average MovingAverage) Cross {
+	if len(values) < 2 {
+		return CrossNone
+	}
+
+	previous := values[:len(values)-1]
+	fastBefore, ok1 := average(previous, fast)
+	slowBefore, ok2 := average(previous, slow)
+	fastNow, ok3 := average(values, fast)
+	slowNow, ok4 := average(values, slow)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return CrossNone
+	}
+
+	switch {
+	case fastBefore <= slowBefore && fastNow > slowNow:
+		return CrossGolden
+	case fastBefore >= slowBefore && fastNow < slowNow:
+		return CrossDeath
+	}
+	return CrossNone
+}
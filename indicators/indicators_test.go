@@ -0,0 +1,91 @@
+package indicators
+
+import (
+	"math"
+	"testing"
+)
+
+// Closes of the RSI and EMA examples on StockCharts, with expected values recomputed at full precision
+var (
+	rsiCloses = []float64{44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08, 45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64}
+	emaCloses = []float64{22.27, 22.19, 22.08, 22.17, 22.18, 22.13, 22.23, 22.43, 22.24, 22.29, 22.15, 22.39, 22.38, 22.61, 23.36}
+)
+
+// indicatorCase is a single expectation for an indicator
+type indicatorCase struct {
+	name   string
+	values []float64
+	period int
+	want   float64
+	wantOK bool
+}
+
+// checkIndicator runs the cases against an indicator, comparing to two decimal places
+func checkIndicator(t *testing.T, indicator func([]float64, int) (float64, bool), tests []indicatorCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := indicator(tt.values, tt.period)
+			if ok != tt.wantOK || math.Abs(got-tt.want) > 0.005 {
+				t.Errorf("got %.4f, %v, want %.2f, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSMA(t *testing.T) {
+	checkIndicator(t, SMA, []indicatorCase{
+		{"last values only", []float64{100, 1, 2, 3}, 3, 2, true},
+		{"exactly period values", emaCloses[:10], 10, 22.22, true},
+		{"too few values", []float64{1, 2}, 3, 0, false},
+		{"zero period", []float64{1, 2}, 0, 0, false},
+		{"negative period", []float64{1, 2}, -1, 0, false},
+	})
+}
+
+func TestRSI(t *testing.T) {
+	checkIndicator(t, RSI, []indicatorCase{
+		{"first value", rsiCloses[:15], 14, 70.46, true},
+		{"smoothed", rsiCloses, 14, 57.92, true},
+		{"only gains", []float64{1, 2, 3, 4}, 3, 100, true},
+		{"only losses", []float64{4, 3, 2, 1}, 3, 0, true},
+		{"period values without a change before them", rsiCloses[:14], 14, 0, false},
+		{"zero period", rsiCloses, 0, 0, false},
+	})
+}
+
+func TestEMA(t *testing.T) {
+	checkIndicator(t, EMA, []indicatorCase{
+		{"seeded with the SMA", emaCloses[:10], 10, 22.22, true},
+		{"smoothed", emaCloses[:11], 10, 22.21, true},
+		{"latest", emaCloses, 10, 22.52, true},
+		{"too few values", emaCloses[:9], 10, 0, false},
+		{"zero period", emaCloses, 0, 0, false},
+		{"negative period", emaCloses, -1, 0, false},
+	})
+}
+
+func TestCrossover(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []float64
+		average MovingAverage
+		want    Cross
+	}{
+		{"golden", []float64{3, 2, 1, 4}, SMA, CrossGolden},
+		{"death", []float64{1, 2, 3, 0}, SMA, CrossDeath},
+		{"fast stays above", []float64{1, 2, 3, 4}, SMA, CrossNone},
+		{"fast stays below", []float64{4, 3, 2, 1}, SMA, CrossNone},
+		{"too few values before the latest", []float64{3, 2, 4}, SMA, CrossNone},
+		{"single value", []float64{1}, SMA, CrossNone},
+		{"golden with EMA", []float64{3, 2, 1, 4}, EMA, CrossGolden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Crossover(tt.values, 2, 3, tt.average); got != tt.want {
+				t.Errorf("Crossover(%v, 2, 3) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
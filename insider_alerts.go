@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// Insider transaction alert constants
+const (
+	insiderAlertMinValue = 1_000_000.0        // Minimum transaction value in dollars worth alerting
+	insiderAlertMaxAge   = 7 * 24 * time.Hour // Older filings are stored without alerting
+)
+
+// checkInsiderTrades stores new Form 4 filings for watched symbols and alerts on significant purchases and sales
+func checkInsiderTrades(ctx context.Context, db *services.Database, delivery *services.Delivery, fetcher *services.FMPClient) {
+	var significant []models.InsiderTrade
+	for _, symbol := range models.Tickers() {
+		// The first import only seeds history so old filings don't trigger alerts
+		stored, err := db.CountInsiderTrades(symbol)
+		if err != nil {
+			slog.Error("Error counting insider trades", "symbol", symbol, "error", err)
+			continue
+		}
+
+		trades, err := fetcher.FetchInsiderTrades(ctx, symbol)
+		if err != nil {
+			slog.Error("Error fetching insider trades", "symbol", symbol, "error", err)
+			continue
+		}
+
+		inserted, err := db.SaveInsiderTrades(trades)
+		if err != nil {
+			slog.Error("Error saving insider trades", "symbol", symbol, "error", err)
+			continue
+		}
+		if stored == 0 {
+			slog.Info("Seeded insider trades", "count", len(inserted), "symbol", symbol)
+			continue
+		}
+
+		for _, trade := range inserted {
+			if trade.Value() >= insiderAlertMinValue && time.Since(trade.FilingDate) <= insiderAlertMaxAge {
+				significant = append(significant, trade)
+			}
+		}
+	}
+
+	if len(significant) == 0 {
+		return
+	}
+
+	slog.Info("Sending insider transaction alerts", "count", len(significant))
+	if _, err := delivery.Deliver(models.KindInsider, func(m services.Messenger, user models.User) error {
+		message := formatInsiderAlerts(significant, user)
+		if message == "" {
+			return nil
+		}
+		return m.SendText(message, nil)
+	}); err != nil {
+		slog.Error("Error sending insider transaction alerts", "error", err)
+	}
+}
+
+// formatInsiderAlerts renders insider trades for a recipient, skipping symbols they don't watch or muted
+func formatInsiderAlerts(trades []models.InsiderTrade, user models.User) string {
+	var message strings.Builder
+	now := time.Now()
+
+	for _, trade := range trades {
+		if !user.Watches(trade.Symbol) || user.IsMuted(trade.Symbol, now) {
+			continue
+		}
+
+		action, icon := "sold", "🔻"
+		if trade.TransactionType == models.InsiderPurchase {
+			action, icon = "bought", "🔺"
+		}
+
+		message.WriteString(fmt.Sprintf("%s %s: %s (%s) %s %s shares at %s (%s total) on %s\n",
+			icon,
+			trade.Symbol,
+			trade.Insider,
+			trade.Position,
+			action,
+			formatVolume(int64(trade.Shares)),
+			models.FormatPrice(trade.Symbol, trade.Price),
+			models.FormatPrice(trade.Symbol, trade.Value()),
+			trade.TransactionDate.Format("Jan 2"),
+		))
+		if trade.FilingURL != "" {
+			message.WriteString(trade.FilingURL + "\n")
+		}
+		message.WriteString("\n")
+	}
+
+	if message.Len() == 0 {
+		return ""
+	}
+	return "🧾 Insider Transactions (Form 4)\n\n" + strings.TrimSpace(message.String())
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// Outbound integrations that receive quotes, alerts and reports
+var publishers services.Publishers
+
+// Connections to close on shutdown
+var publisherClosers []func()
+
+// MQTT publisher that announces watched symbols to Home Assistant, nil when discovery is off
+var discoveryPublisher atomic.Pointer[services.MQTTPublisher]
+
+// setupPublishers creates a publisher for every configured integration
+func setupPublishers(db *services.Database, config models.Config) services.Publishers {
+	var configured services.Publishers
+
+	// Alerts and reports are logged for the feeds of the embedded HTTP server
+	if config.HTTPAddr != "" {
+		configured = append(configured, services.NewEventLog(db))
+	}
+
+	if config.IFTTTWebhookKey != "" {
+		if publisher, err := services.NewIFTTTPublisher(config.IFTTTWebhookKey); err == nil {
+			configured = append(configured, publisher)
+		} else {
+			slog.Warn("IFTTT webhooks disabled", "error", err)
+		}
+	}
+	if config.ZapierHookURL != "" {
+		if publisher, err := services.NewZapierPublisher(config.ZapierHookURL); err == nil {
+			configured = append(configured, publisher)
+		} else {
+			slog.Warn("Zapier webhooks disabled", "error", err)
+		}
+	}
+
+	if config.GrafanaURL != "" {
+		if publisher, err := services.NewGrafanaPublisher(config.GrafanaURL, config.GrafanaAPIKey, config.GrafanaDashboardUID); err == nil {
+			configured = append(configured, publisher)
+		} else {
+			slog.Warn("Grafana annotations disabled", "error", err)
+		}
+	}
+
+	if config.MQTT.BrokerURL != "" {
+		publisher, err := services.NewMQTTPublisher(config.MQTT)
+		if err != nil {
+			slog.Warn("MQTT publishing disabled", "error", err)
+		} else {
+			publisherClosers = append(publisherClosers, publisher.Close)
+			configured = append(configured, publisher)
+
+			// Announce every watched symbol as a Home Assistant sensor
+			if config.MQTT.HomeAssistantDiscovery {
+				discoveryPublisher.Store(publisher)
+				publishDiscovery()
+			}
+		}
+	}
+
+	if config.NATSURL != "" {
+		publisher, err := services.NewNATSPublisher(config.NATSURL, config.EventTopicPrefix)
+		if err != nil {
+			slog.Warn("NATS publishing disabled", "error", err)
+		} else {
+			publisherClosers = append(publisherClosers, publisher.Close)
+			configured = append(configured, publisher)
+		}
+	}
+
+	if len(config.KafkaBrokers) > 0 {
+		publisher, err := services.NewKafkaPublisher(config.KafkaBrokers, config.EventTopicPrefix)
+		if err != nil {
+			slog.Warn("Kafka publishing disabled", "error", err)
+		} else {
+			publisherClosers = append(publisherClosers, publisher.Close)
+			configured = append(configured, publisher)
+		}
+	}
+
+	for _, publisher := range configured {
+		slog.Info("Publishing events", "publisher", publisher.Name())
+	}
+	return configured
+}
+
+// publishDiscovery announces the watched symbols to Home Assistant, so symbols added since startup become sensors too.
+// Discovery configs are retained and keyed by symbol, so announcing a symbol again is harmless
+func publishDiscovery() {
+	publisher := discoveryPublisher.Load()
+	if publisher == nil {
+		return
+	}
+	if err := publisher.PublishDiscovery(models.Tickers()); err != nil {
+		slog.Error("Error publishing Home Assistant discovery", "error", err)
+	}
+}
+
+// closePublishers disconnects every integration that holds a connection
+func closePublishers() {
+	for _, closePublisher := range publisherClosers {
+		closePublisher()
+	}
+}
+
+// publishQuotes forwards each fetched price as its own event, with its change since the last close
+func publishQuotes(ctx context.Context, db *services.Database, quotes map[string]models.Quote) {
+	if len(publishers) == 0 {
+		return
+	}
+
+	closes, err := db.GetLatestClosingPrices(slices.Collect(maps.Keys(quotes)))
+	if err != nil {
+		slog.Error("Error retrieving previous closes for quote events", "error", err)
+	}
+
+	now := time.Now()
+	for symbol, quote := range quotes {
+		if err := publishers.Publish(ctx, models.NewQuoteEvent(symbol, quote.Price, closes[symbol], now)); err != nil {
+			slog.Error("Error publishing quote", "symbol", symbol, "error", err)
+		}
+	}
+}
+
+// publishAlerts forwards each price alert as its own event
+func publishAlerts(ctx context.Context, alerts []models.PriceAlert) {
+	for _, alert := range alerts {
+		if err := publishers.Publish(ctx, models.NewAlertEvent(alert)); err != nil {
+			slog.Error("Error publishing alert", "symbol", alert.Symbol, "error", err)
+		}
+	}
+}
+
+// publishReport forwards the daily report prices
+func publishReport(ctx context.Context, quotes map[string]models.Quote) {
+	if err := publishers.Publish(ctx, models.NewReportEvent(quotes, time.Now())); err != nil {
+		slog.Error("Error publishing daily report", "error", err)
+	}
+}
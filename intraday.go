@@ -0,0 +1,125 @@
+package main
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+)
+
+// intradaySample is a price seen by a realtime check
+type intradaySample struct {
+	at    time.Time
+	price float64
+}
+
+// intradayTracker keeps each symbol's first price of the session and its recent prices,
+// so sudden moves are alerted even when the change from the previous close is small
+type intradayTracker struct {
+	mu     sync.Mutex
+	open   map[string]float64
+	recent map[string][]intradaySample
+}
+
+// Intraday prices seen by the realtime checks
+var intraday = &intradayTracker{
+	open:   make(map[string]float64),
+	recent: make(map[string][]intradaySample),
+}
+
+// intradayThresholds are the changes from the session open and within the move window that raise an alert;
+// a zero threshold disables that check
+type intradayThresholds struct {
+	open   float64
+	move   float64
+	window time.Duration
+}
+
+// intradayThresholdsFor returns the configured intraday thresholds of a symbol, lowered to the smallest threshold
+// of the chats watching it. Chats only change thresholds that are enabled, so the alerts stay opt-in
+func intradayThresholdsFor(symbol string, config models.Config, users []models.User) intradayThresholds {
+	thresholds := intradayThresholds{window: config.MoveAlertWindow}
+	if config.OpenAlertThreshold > 0 {
+		thresholds.open = lowestThreshold(symbol, config.OpenAlertThreshold, users)
+	}
+	if config.MoveAlertThreshold > 0 {
+		thresholds.move = lowestThreshold(symbol, config.MoveAlertThreshold, users)
+	}
+	return thresholds
+}
+
+// intradayThreshold returns the configured threshold of an intraday alert basis
+func intradayThreshold(basis models.AlertBasis, config models.Config) float64 {
+	if basis == models.BasisOpen {
+		return config.OpenAlertThreshold
+	}
+	return config.MoveAlertThreshold
+}
+
+// check records a quote and returns alerts for changes from the session open and from the start of the move window
+// that reach their thresholds. The open is the one the source reports for the current session, else the first
+// price seen since the session started
+func (t *intradayTracker) check(quote models.Quote, now time.Time, thresholds intradayThresholds) []models.PriceAlert {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	symbol, price := quote.Symbol, quote.Price
+	var alerts []models.PriceAlert
+	newAlert := func(reference float64, basis models.AlertBasis, threshold float64) {
+		if threshold <= 0 || reference == 0 {
+			return
+		}
+		percentChange := (price - reference) / reference * 100
+		if math.Abs(percentChange) < threshold {
+			return
+		}
+		alert := models.PriceAlert{
+			Symbol:        symbol,
+			PreviousPrice: reference,
+			CurrentPrice:  price,
+			PercentChange: percentChange,
+			Timestamp:     now,
+			Basis:         basis,
+		}
+		if basis == models.BasisWindow {
+			alert.Window = thresholds.window
+		}
+		alerts = append(alerts, alert)
+	}
+
+	// A reported open from an earlier session, as before the bell, is ignored
+	if quote.Open > 0 && models.TradingDate(symbol, quote.Timestamp) == models.TradingDate(symbol, now) {
+		newAlert(quote.Open, models.BasisOpen, thresholds.open)
+		t.open[symbol] = quote.Open
+	} else if open, ok := t.open[symbol]; ok {
+		newAlert(open, models.BasisOpen, thresholds.open)
+	} else {
+		t.open[symbol] = price
+	}
+
+	// Keep the prices within the window, allowing for ticker drift, and compare with the oldest one
+	cutoff := now.Add(-thresholds.window - time.Minute)
+	samples := t.recent[symbol]
+	for len(samples) > 0 && samples[0].at.Before(cutoff) {
+		samples = samples[1:]
+	}
+	if len(samples) > 0 {
+		newAlert(samples[0].price, models.BasisWindow, thresholds.move)
+	}
+	t.recent[symbol] = append(samples, intradaySample{at: now, price: price})
+
+	return alerts
+}
+
+// resetOpens starts a new session for the symbols matching the filter
+func (t *intradayTracker) resetOpens(matches func(symbol string) bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for symbol := range t.open {
+		if matches(symbol) {
+			delete(t.open, symbol)
+		}
+	}
+}
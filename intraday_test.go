@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"stock-bot/models"
+)
+
+func TestIntradayTrackerCheck(t *testing.T) {
+	start := time.Date(2025, time.March, 4, 15, 0, 0, 0, time.UTC) // 10:00 in New York
+	thresholds := intradayThresholds{open: 4, move: 3, window: time.Hour}
+
+	type tick struct {
+		after time.Duration
+		price float64
+		open  float64
+		stale bool // Quote of the previous session, as before the bell
+	}
+	tests := []struct {
+		name       string
+		thresholds intradayThresholds
+		ticks      []tick
+		want       []models.AlertBasis // Alerts of the last tick
+	}{
+		{"first price is the open", thresholds, []tick{{0, 100, 0, false}, {2 * time.Hour, 104.5, 0, false}}, []models.AlertBasis{models.BasisOpen}},
+		{"reported open is used", thresholds, []tick{{0, 104.5, 100, false}}, []models.AlertBasis{models.BasisOpen}},
+		{"reported open replaces the first price", thresholds, []tick{{0, 100, 0, false}, {2 * time.Hour, 101, 97, false}}, []models.AlertBasis{models.BasisOpen}},
+		{"open of an earlier session is ignored", thresholds, []tick{{0, 100, 90, true}, {2 * time.Hour, 101, 90, true}}, nil},
+		{"move within the window", thresholds, []tick{{0, 100, 100, false}, {30 * time.Minute, 96.5, 0, false}}, []models.AlertBasis{models.BasisWindow}},
+		{"move spread beyond the window", thresholds, []tick{{0, 100, 100, false}, {90 * time.Minute, 97, 0, false}, {150 * time.Minute, 96.5, 0, false}}, nil},
+		{"both reached", thresholds, []tick{{0, 100, 100, false}, {30 * time.Minute, 105, 0, false}}, []models.AlertBasis{models.BasisOpen, models.BasisWindow}},
+		{"disabled", intradayThresholds{window: time.Hour}, []tick{{0, 100, 100, false}, {30 * time.Minute, 120, 0, false}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := &intradayTracker{open: make(map[string]float64), recent: make(map[string][]intradaySample)}
+			var alerts []models.PriceAlert
+			for _, tick := range tt.ticks {
+				now := start.Add(tick.after)
+				quote := models.Quote{Symbol: "AAPL", Price: tick.price, Open: tick.open, Timestamp: now}
+				if tick.stale {
+					quote.Timestamp = now.AddDate(0, 0, -1)
+				}
+				alerts = tracker.check(quote, now, tt.thresholds)
+			}
+
+			var got []models.AlertBasis
+			for _, alert := range alerts {
+				got = append(got, alert.Basis)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("alerts = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("alerts = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestIntradayThresholdsFor(t *testing.T) {
+	config := models.Config{OpenAlertThreshold: 4, MoveAlertThreshold: 3, MoveAlertWindow: time.Hour}
+	users := []models.User{
+		{ChatID: "1", Onboarded: true, Threshold: 2, Watchlist: []string{"AAPL"}},
+		{ChatID: "2", Onboarded: true, Threshold: 10},
+	}
+
+	tests := []struct {
+		name   string
+		symbol string
+		config models.Config
+		want   intradayThresholds
+	}{
+		{"lowered by a watching chat", "AAPL", config, intradayThresholds{open: 2, move: 2, window: time.Hour}},
+		{"chats that don't watch it", "MSFT", config, intradayThresholds{open: 4, move: 3, window: time.Hour}},
+		{"off by default", "AAPL", models.DefaultConfig(), intradayThresholds{window: time.Hour}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := intradayThresholdsFor(tt.symbol, tt.config, users); got != tt.want {
+				t.Errorf("intradayThresholdsFor(%s) = %+v, want %+v", tt.symbol, got, tt.want)
+			}
+		})
+	}
+}
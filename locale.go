@@ -0,0 +1,7 @@
+package main
+
+import "stock-bot/i18n"
+
+// locale writes chat command replies and scheduled alerts and reports in the configured BOT_LANGUAGE; main replaces
+// it once the configuration is loaded
+var locale = i18n.NewPrinter(i18n.English)
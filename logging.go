@@ -0,0 +1,36 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"stock-bot/models"
+)
+
+// logLevel is the minimum level logged, adjusted when the config file changes
+var logLevel slog.LevelVar
+
+// setupLogging sends structured logs to stderr as text or JSON at the configured level
+func setupLogging(config models.Config) {
+	setLogLevel(config.LogLevel)
+
+	options := &slog.HandlerOptions{Level: &logLevel}
+	var handler slog.Handler
+	switch config.LogFormat {
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, options)
+	case "text", "":
+		handler = slog.NewTextHandler(os.Stderr, options)
+	default:
+		handler = slog.NewTextHandler(os.Stderr, options)
+		defer slog.Warn("Unknown log format, using text", "format", config.LogFormat)
+	}
+	slog.SetDefault(slog.New(handler))
+}
+
+// setLogLevel changes the minimum level logged; an unknown level keeps the current one
+func setLogLevel(level string) {
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		slog.Warn("Unknown log level, keeping the current level", "level", level)
+	}
+}
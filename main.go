@@ -2,56 +2,85 @@ package main
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
 	"math"
 	"os"
-	"os/signal"
-	"strconv"
+	"slices"
 	"sync"
-	"syscall"
+	"sync/atomic"
 	"time"
 
+	"stock-bot/calendar"
+	"stock-bot/config"
+	"stock-bot/i18n"
 	"stock-bot/models"
 	"stock-bot/services"
-
-	"github.com/joho/godotenv"
 )
 
 // Application constants
 const (
-	appName              = "Stock Price Bot"
-	version              = "1.0.0"
-	alertThreshold       = 5.0 // Alert threshold for price changes over 5%
-	maxConcurrency       = 5   // Maximum number of concurrent requests
-	checkInterval        = 15  // Scheduler check interval in minutes
-	defaultCheckHour     = 7   // Default time for daily report (7AM)
-	realtimeCheckMinutes = 30  // Interval for realtime price checks in minutes
-)
-
-// Environment variable keys
-const (
-	envMongoURI       = "MONGODB_URI"
-	envTelegramToken  = "TELEGRAM_BOT_TOKEN"
-	envTelegramChatID = "TELEGRAM_CHAT_ID"
-	envLineToken      = "LINE_CHANNEL_ACCESS_TOKEN"
-	envTimezone       = "TIMEZONE"
-	envCheckHour      = "CHECK_HOUR"
+	appName        = "Stock Price Bot"
+	version        = "1.0.0"
+	maxConcurrency = 5  // Maximum number of concurrent requests
+	checkInterval  = 15 // Scheduler check interval in minutes
 )
 
-// Global variable to track the last processed date
+// Global variable to track the last processed date, restored from MongoDB on startup
 var lastProcessedDate string
 
-// Map to track the last alert time for each stock
-var lastAlertSentMap = make(map[string]time.Time)
+// The last hour daily reports went to chats that chose their own report hour, restored from MongoDB on startup
+var lastChatReportHour string
+
+// Map to track the last alert time for each stock, restored from MongoDB on startup
+var lastAlertSentMap = make(map[string]models.SentAlert)
 var alertMapMutex sync.RWMutex
 
+// assetSchedule controls when realtime checks run for an asset class
+type assetSchedule struct {
+	interval   time.Duration
+	alwaysOpen bool // Traded around the clock, including weekends
+}
+
+// scheduleFor returns the realtime check schedule of an asset class; crypto and FX trade around the clock
+func scheduleFor(config models.Config, class models.AssetClass) assetSchedule {
+	return assetSchedule{
+		interval:   config.RealtimeIntervals[class],
+		alwaysOpen: class == models.AssetCrypto || class == models.AssetFX,
+	}
+}
+
+// intervalFor returns the realtime interval of a priority tier: halved for high, doubled for low
+func (s assetSchedule) intervalFor(priority models.Priority) time.Duration {
+	switch priority {
+	case models.PriorityHigh:
+		return s.interval / 2
+	case models.PriorityLow:
+		return s.interval * 2
+	default:
+		return s.interval
+	}
+}
+
+// realtimeGroup identifies symbols that share a realtime check schedule
+type realtimeGroup struct {
+	class    models.AssetClass
+	exchange string
+	priority models.Priority
+}
+
+// Time of the last realtime check per asset class, exchange and priority tier
+var lastRealtimeCheck = make(map[realtimeGroup]time.Time)
+
+// Set by admins via /pause and /resume to suspend scheduled work
+var schedulerPaused atomic.Bool
+
 // Global price fetcher instance
 var priceFetcher *services.PriceFetcher
 
 func main() {
-	log.Printf("Starting %s v%s", appName, version)
+	slog.Info("Starting "+appName, "version", version)
 
 	// Initialize the price fetcher
 	priceFetcher = services.NewPriceFetcher()
@@ -61,314 +90,798 @@ func main() {
 	setupSignalHandler(cancel)
 
 	// Load environment variables
-	config, err := loadConfig()
+	config, err := config.Load()
 	if err != nil {
-		log.Fatal("Configuration error: ", err)
+		slog.Error("Configuration error", "error", err)
+		os.Exit(1)
 	}
-
-	// Connect to database
-	db, err := services.NewDatabase(config.MongoURI)
+	setCurrentConfig(config)
+	setupLogging(config)
+	locale = i18n.NewPrinter(i18n.Language(config.Language))
+	priceFetcher.SetBrowserLimits(config.Browser)
+	priceFetcher.SetScrapeProfiles(config.ScrapeProfiles)
+
+	// Try the configured price sources in order, scraping last
+	priceSource = buildPriceSource(config)
+
+	// Price history and the watchlist go to the configured storage driver, which creates its tables on the first start;
+	// with PostgreSQL or SQLite, MongoDB is optional and only keeps chat settings and bot state
+	db, err := services.OpenStore(config)
 	if err != nil {
-		log.Fatal("Database connection error: ", err)
+		slog.Error("Database connection error", "driver", config.StorageDriver, "error", err)
+		os.Exit(1)
 	}
-	defer func() {
-		if err := db.Close(); err != nil {
-			log.Printf("Error closing database connection: %v", err)
+	slog.Info("Connected to database", "driver", config.StorageDriver, "mongodb", db.UsesMongo())
+	priceSource.SetPreviousCloses(db)
+
+	if config.StorageDriver == models.StorageMongoDB {
+		// Price history lives in a time-series collection, created on the first start
+		if timeSeries, err := db.EnsurePriceCollection(); err != nil {
+			slog.Error("Error preparing price collection", "error", err)
+		} else if !timeSeries {
+			slog.Warn("Price history is in a regular collection from an earlier version; see the README to move it to a time-series collection")
+		}
+		if err := db.EnsureIndexes(); err != nil {
+			slog.Error("Error creating price indexes", "error", err)
 		}
-	}()
-	log.Printf("Connected to database")
+	}
 
-	// Initialize messenger
-	messenger, err := initializeMessenger(config)
-	if err != nil {
-		log.Fatal("Messenger initialization error: ", err)
+	// Symbols from TICKERS replace the built-in defaults
+	if len(config.Tickers) > 0 {
+		models.DefaultTickers = config.Tickers
 	}
 
-	fetchAllPrices(ctx, config)
+	// Don't repeat today's report or alerts after a restart
+	loadSchedulerState(db)
+	loadYearRanges(db)
 
-	// Start scheduler
-	runScheduler(ctx, db, messenger, config)
-}
+	// Monitor the stored watchlist, seeded with the default tickers on first start
+	refreshWatchlist(db)
 
-// 시그널 핸들러 함수 추가
-func setupSignalHandler(cancel context.CancelFunc) {
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		log.Println("Received termination signal")
-		cancel()
-
-		// Clean up Chrome browser resources
-		if priceFetcher != nil {
-			log.Println("Cleaning up browser resources")
-			priceFetcher.Cleanup()
-		}
-
-		// 정상적으로 종료될 때까지 잠시 대기
-		time.Sleep(2 * time.Second)
-		log.Println("Gracefully shutting down")
-		os.Exit(0)
-	}()
-}
-
-// loadConfig loads application settings from environment variables
-func loadConfig() (models.Config, error) {
-	// Load .env file
-	if err := godotenv.Load(); err != nil {
-		log.Printf("Warning: .env file not found, using environment variables")
+	// Merge TICKERS into a stored watchlist in the background, once the price source confirms they exist
+	if len(config.Tickers) > 0 {
+		go mergeConfiguredTickers(ctx, db, config.Tickers)
 	}
 
-	config := models.DefaultConfig()
+	// Apply edits to the config file without a restart
+	watchConfig(ctx, db)
 
-	// MongoDB URI
-	config.MongoURI = os.Getenv(envMongoURI)
-	if config.MongoURI == "" {
-		return config, fmt.Errorf("required environment variable %s not set", envMongoURI)
+	// Initialize messenger
+	messenger, err := initializeMessenger(ctx, db, config)
+	if err != nil {
+		slog.Error("Messenger initialization error", "error", err)
+		os.Exit(1)
 	}
 
-	// Telegram settings
-	config.TelegramBotToken = os.Getenv(envTelegramToken)
-	config.TelegramChatID = os.Getenv(envTelegramChatID)
+	// Deliver scheduled messages per recipient according to their preferences
+	delivery := services.NewDelivery(messenger, db, config.TelegramChatID)
 
-	// Line settings
-	config.LineChannelToken = os.Getenv(envLineToken)
+	// Claim an idempotency key per message and recipient so racing instances or retries send once: reports and texts
+	// once a day, and alerts once per cooldown and re-alert step
+	if dedup, err := services.NewMongoDedupStore(db); err != nil {
+		slog.Warn("Message deduplication disabled", "error", err)
+	} else {
+		loc, err := time.LoadLocation(config.TimeZone)
+		if err != nil {
+			loc = time.Local
+		}
+		delivery.SetDedupStore(dedup, func() services.DedupPolicy {
+			config := currentConfig()
+			return services.DedupPolicy{Location: loc, AlertCooldown: config.AlertCooldown, AlertStep: config.AlertStepPercent}
+		})
+	}
 
-	// Ensure at least one messaging service is configured
-	if config.TelegramBotToken == "" && config.LineChannelToken == "" {
-		return config, fmt.Errorf("at least one messaging service (Telegram or Line) must be configured")
+	// Persist outgoing messages so sends that fail, e.g. while a messaging service is down, are retried later
+	if outbox, err := services.NewMongoOutbox(db); err != nil {
+		slog.Warn("Message outbox disabled", "error", err)
+	} else {
+		delivery.SetOutbox(outbox)
 	}
 
-	// Timezone settings
-	if tz := os.Getenv(envTimezone); tz != "" {
-		config.TimeZone = tz
+	// Export closes and alerts to a shared spreadsheet when configured
+	if config.GoogleSheetID != "" {
+		exporter, err := services.NewSheetsExporter(ctx, config.GoogleSheetsCredentials, config.GoogleSheetID)
+		if err != nil {
+			slog.Warn("Google Sheets export disabled", "error", err)
+		} else {
+			sheetsExporter = exporter
+		}
 	}
 
-	// Check hour settings
-	if hourStr := os.Getenv(envCheckHour); hourStr != "" {
-		if hour, err := strconv.Atoi(hourStr); err == nil && hour >= 0 && hour < 24 {
-			config.CheckHour = hour
+	// Forward alerts and reports to no-code automation services
+	publishers = setupPublishers(db, config)
+
+	// Pause symbols that repeatedly fail to resolve
+	symbolHealth = newSymbolHealthTracker(db, delivery)
+	fetchFailures = newFetchFailureMonitor(delivery)
+
+	// Answer Line chats through a webhook, which needs the HTTP server and the channel secret
+	var lineBot *services.LineBot
+	if config.LineChannelSecret != "" {
+		if config.HTTPAddr == "" || config.LineChannelToken == "" {
+			slog.Warn("Line channel secret set without HTTP_ADDR and a channel access token, Line commands disabled")
+		} else if bot, err := startLineBot(ctx, db, delivery, config); err != nil {
+			slog.Error("Error starting Line command bot", "error", err)
 		} else {
-			log.Printf("Warning: invalid %s value, using default: %d", envCheckHour, defaultCheckHour)
+			lineBot = bot
 		}
-	} else {
-		// Set default value
-		config.CheckHour = defaultCheckHour
 	}
 
-	return config, nil
+	// Serve feeds over HTTP when an address is configured
+	if config.HTTPAddr != "" {
+		startHTTPServer(ctx, db, config, lineBot)
+	}
+
+	// Start interactive chat commands when Telegram is configured
+	if config.TelegramBotToken != "" {
+		if err := startCommandBot(ctx, db, delivery, config); err != nil {
+			slog.Error("Error starting Telegram command bot", "error", err)
+		}
+	}
+
+	// Restart the headless browser when it crashes or hangs
+	go priceFetcher.Browser.Watch(ctx)
+
+	fetchAllPrices(ctx, db, config)
+
+	// Follow live equity trades instead of polling when streaming is enabled
+	if config.RealtimeStreaming {
+		startRealtimeStream(ctx, db, delivery, config)
+	}
+
+	// Start scheduler; it returns once a termination signal cancels the context
+	runScheduler(ctx, db, delivery, config)
+
+	shutdown(db)
 }
 
-// initializeMessenger initializes the messaging service
-func initializeMessenger(config models.Config) (services.Messenger, error) {
-	// Use Telegram messenger with priority
+// initializeMessenger sets up every configured messaging service; several are combined so each message reaches all of them
+func initializeMessenger(ctx context.Context, db *services.Database, config models.Config) (services.Messenger, error) {
+	composite := services.NewCompositeMessenger()
+
+	// Telegram messenger
 	if config.TelegramBotToken != "" && config.TelegramChatID != "" {
-		return services.NewTelegramMessenger(config.TelegramBotToken, config.TelegramChatID)
+		messenger, err := services.NewTelegramMessenger(config.TelegramBotToken, config.TelegramChatID)
+		if err != nil {
+			return nil, err
+		}
+		format, err := services.ParseMessageFormat(config.TelegramFormat)
+		if err != nil {
+			return nil, err
+		}
+		if format == services.FormatFlex {
+			return nil, fmt.Errorf("%s format is only supported by Line", format)
+		}
+		messenger.SetFormat(format)
+		style, err := models.ParseAlertStyle(config.TelegramAlertStyle)
+		if err != nil {
+			return nil, err
+		}
+		messenger.SetAlertStyle(style)
+		templates, err := services.LoadMessageTemplates("telegram", config.MessageTemplateDir, i18n.Language(config.Language))
+		if err != nil {
+			return nil, err
+		}
+		messenger.SetTemplates(templates)
+		composite.Add("telegram", services.NewRetryingMessenger(ctx, "telegram", messenger))
 	}
 
-	// Use Line messenger
+	// Line messenger
 	if config.LineChannelToken != "" {
-		return services.NewLineMessenger(config.LineChannelToken)
+		messenger, err := services.NewLineMessenger(config.LineChannelToken)
+		if err != nil {
+			return nil, err
+		}
+		format, err := services.ParseMessageFormat(config.LineFormat)
+		if err != nil {
+			return nil, err
+		}
+		messenger.SetFormat(format)
+		style, err := models.ParseAlertStyle(config.LineAlertStyle)
+		if err != nil {
+			return nil, err
+		}
+		messenger.SetAlertStyle(style)
+		templates, err := services.LoadMessageTemplates("line", config.MessageTemplateDir, i18n.Language(config.Language))
+		if err != nil {
+			return nil, err
+		}
+		messenger.SetTemplates(templates)
+		composite.Add("line", services.NewRetryingMessenger(ctx, "line", messenger))
+	}
+
+	// Slack messenger
+	if config.SlackWebhookURL != "" || config.SlackBotToken != "" {
+		messenger, err := services.NewSlackMessenger(config.SlackWebhookURL, config.SlackBotToken, config.SlackChannel)
+		if err != nil {
+			return nil, err
+		}
+		style, err := models.ParseAlertStyle(config.SlackAlertStyle)
+		if err != nil {
+			return nil, err
+		}
+		messenger.SetAlertStyle(style)
+		messenger.SetLanguage(i18n.Language(config.Language))
+		composite.Add("slack", services.NewRetryingMessenger(ctx, "slack", messenger))
+	}
+
+	// Email messenger
+	if config.SMTP.Host != "" {
+		messenger, err := services.NewEmailMessenger(config.SMTP.Host, config.SMTP.Port, config.SMTP.Username, config.SMTP.Password, config.SMTP.From, config.SMTP.To)
+		if err != nil {
+			return nil, err
+		}
+		messenger.SetLanguage(i18n.Language(config.Language))
+		composite.Add("email", services.NewRetryingMessenger(ctx, "email", messenger))
+	}
+
+	// Generic JSON webhook messengers, one channel per URL so a failing URL is retried on its own; the first
+	// keeps the plain name its outbox messages were saved under
+	for i, url := range config.WebhookURLs {
+		messenger, err := services.NewWebhookMessenger(url, config.WebhookSecret)
+		if err != nil {
+			return nil, err
+		}
+		name := "webhook"
+		if i > 0 {
+			name = fmt.Sprintf("webhook-%d", i+1)
+		}
+		composite.Add(name, services.NewRetryingMessenger(ctx, name, messenger))
+	}
+
+	// Push notification messengers
+	if config.NtfyURL != "" {
+		messenger, err := services.NewNtfyMessenger(config.NtfyURL, config.NtfyToken, config.PushHighPriorityPercent)
+		if err != nil {
+			return nil, err
+		}
+		composite.Add("ntfy", services.NewRetryingMessenger(ctx, "ntfy", messenger))
+	}
+	if config.PushoverToken != "" {
+		messenger, err := services.NewPushoverMessenger(config.PushoverToken, config.PushoverUser, config.PushHighPriorityPercent)
+		if err != nil {
+			return nil, err
+		}
+		composite.Add("pushover", services.NewRetryingMessenger(ctx, "pushover", messenger))
 	}
 
-	return nil, fmt.Errorf("no valid messenger configuration found")
+	// SMS for critical alerts; texts cost money, so failed sends aren't retried right away
+	if config.Twilio.AccountSID != "" {
+		loc, err := time.LoadLocation(config.TimeZone)
+		if err != nil {
+			loc = time.Local
+		}
+		messenger, err := services.NewTwilioMessenger(config.Twilio, loc, db)
+		if err != nil {
+			return nil, err
+		}
+		composite.Add("sms", messenger)
+	}
+
+	switch composite.Len() {
+	case 0:
+		return nil, fmt.Errorf("no valid messenger configuration found")
+	case 1:
+		// A single service is used directly, keeping its per-chat delivery and dedup keys unchanged
+		return composite.Only(), nil
+	default:
+		slog.Info("Delivering messages through several messaging services", "count", composite.Len())
+		return composite, nil
+	}
 }
 
 // runScheduler executes the scheduling logic
-func runScheduler(ctx context.Context, db *services.Database, messenger services.Messenger, config models.Config) {
+func runScheduler(ctx context.Context, db *services.Database, delivery *services.Delivery, config models.Config) {
 	// Set timezone
 	loc, err := time.LoadLocation(config.TimeZone)
 	if err != nil {
-		log.Printf("Warning: could not load timezone %s, using local timezone", config.TimeZone)
+		slog.Warn("Could not load timezone, using local timezone", "timezone", config.TimeZone)
 		loc = time.Local
 	}
-	log.Printf("Scheduler using timezone: %s", loc.String())
+	slog.Info("Scheduler using timezone", "timezone", loc.String())
 
 	// Start scheduler
-	log.Printf("Starting scheduler with check interval of %d minutes", checkInterval)
-	log.Printf("Will perform daily price reports at %d:00 (timezone: %s)", config.CheckHour, config.TimeZone)
-	log.Printf("Will check for significant price changes every %d minutes", realtimeCheckMinutes)
+	slog.Info("Starting scheduler", "check_interval", time.Duration(checkInterval)*time.Minute)
+	slog.Info("Will perform daily price reports", "hour", config.CheckHour, "timezone", config.TimeZone)
+	slog.Info("Will check for significant price changes",
+		"equity", config.RealtimeIntervals[models.AssetEquity], "crypto", config.RealtimeIntervals[models.AssetCrypto], "fx", config.RealtimeIntervals[models.AssetFX])
 
 	ticker := time.NewTicker(time.Duration(checkInterval) * time.Minute)
 	defer ticker.Stop()
 
 	// Check current time at initial run
-	checkAndProcess(ctx, db, messenger, config, loc)
+	checkAndProcess(ctx, db, delivery, config, loc)
 
 	// Periodic execution
 	for {
 		select {
 		case <-ticker.C:
-			checkAndProcess(ctx, db, messenger, config, loc)
+			// Settings changed in the config file apply from the next run
+			checkAndProcess(ctx, db, delivery, currentConfig(), loc)
+		case run := <-reportRequests:
+			now := time.Now().In(loc)
+			slog.Info("Starting daily price report on demand", "run", run)
+			// A run of its own, so the report isn't dropped as a copy of the one sent earlier today
+			sendDailyReport(ctx, db, delivery.ForRun(run), currentConfig())
+			botStatus.recordReport(now, now.Format("2006-01-02"))
 		case <-ctx.Done():
-			log.Println("Scheduler stopped")
+			slog.Info("Scheduler stopped")
 			return
 		}
 	}
 }
 
 // checkAndProcess checks the current time and runs the price collection process if needed
-func checkAndProcess(ctx context.Context, db *services.Database, messenger services.Messenger, config models.Config, loc *time.Location) {
+func checkAndProcess(ctx context.Context, db *services.Database, delivery *services.Delivery, config models.Config, loc *time.Location) {
+	if ctx.Err() != nil {
+		return
+	}
+
 	now := time.Now().In(loc)
 	currentDate := now.Format("2006-01-02")
 
-	log.Printf("Checking time: %s", now.Format("2006-01-02 15:04:05"))
+	slog.Debug("Checking time", "time", now.Format("2006-01-02 15:04:05"))
+	botStatus.recordRun(now)
+
+	if schedulerPaused.Load() {
+		slog.Info("Scheduler is paused, skipping scheduled work")
+		return
+	}
+
+	// Resend messages whose delivery failed earlier, including before a restart
+	delivery.RetryOutbox()
+
+	// Pick up symbols added or removed with /add and /remove
+	refreshWatchlist(db)
+
+	// Download the history of symbols watched for the first time in the background
+	startBackfill(ctx, db, config)
+
+	// Refresh the economic and earnings calendars once a day ahead of the briefing;
+	// without an FMP key, earnings dates come from Finnhub. The calendars are kept in MongoDB
+	if (config.FMPAPIKey != "" || config.FinnhubAPIKey != "") && db.UsesMongo() && lastEconomicIngestDate != currentDate {
+		if fmp, err := services.NewFMPClient(config.FMPAPIKey); err == nil {
+			ingestEconomicCalendar(ctx, db, fmp, now)
+			ingestCorporateCalendar(ctx, db, fmp, now)
+		} else if finnhub, err := services.NewFinnhubSource(config.FinnhubAPIKey); err == nil {
+			ingestEarningsCalendar(ctx, db, finnhub, now)
+		}
+		lastEconomicIngestDate = currentDate
+	}
 
 	// 1. Run daily report at specified time (7AM) if not already run today
 	if now.Hour() == config.CheckHour && now.Minute() < checkInterval && lastProcessedDate != currentDate {
-		log.Printf("Starting daily price report at scheduled time")
-		sendDailyReport(ctx, db, messenger, config)
+		slog.Info("Starting daily price report at scheduled time")
+		// Chats that chose another hour during onboarding get their report then
+		sendDailyReport(ctx, db, delivery.ForReportHour(now.Hour(), config.CheckHour), config)
+		closes := loadDailyCloses(ctx, db)
+		checkStreaks(delivery, config, closes)
+		sendMorningBriefing(ctx, db, delivery, config, now)
+		sendEarningsReminders(db, delivery, now)
+		sendDividendReminders(ctx, db, delivery, now)
+		sendSignalsReport(ctx, db, delivery, config, closes)
 
 		// Record today's date
 		lastProcessedDate = currentDate
-		log.Printf("Daily report processed for date: %s", lastProcessedDate)
+		recordJobRun(db, jobDailyReport, currentDate)
+		botStatus.recordReport(now, currentDate)
+		slog.Info("Daily report processed", "date", lastProcessedDate)
+
+		// Forget alerts whose cooldown has passed, and start a new day for assets traded around the clock
+		pruneAlertMap(db)
+		intraday.resetOpens(func(symbol string) bool { return scheduleFor(config, models.AssetClassOf(symbol)).alwaysOpen })
+
+		// Roll the 52-week ranges forward with the latest closes
+		refreshYearRanges(ctx, db)
+
+		// Pick up newly published biweekly short interest data, compared with the stored release
+		if db.UsesMongo() {
+			ingestShortInterest(ctx, db)
+		}
+
+		// Drop intraday prices past their retention
+		pruneIntradayPrices(db, config, now)
+
+		// Weekly summary after the Friday US close
+		if now.Weekday() == weeklyReportDay && lastWeeklyReportDate != currentDate {
+			sendWeeklyReport(ctx, db, delivery)
+			lastWeeklyReportDate = currentDate
+			recordJobRun(db, jobWeeklyReport, currentDate)
+		}
 
-		// Reset alert map at the start of a new day
-		resetAlertMap()
+		// Monthly summary after the month's last close
+		if currentMonth := now.Format("2006-01"); now.Day() == monthlyReportDay && lastMonthlyReportMonth != currentMonth {
+			sendMonthlyReport(db, delivery, now)
+			lastMonthlyReportMonth = currentMonth
+			recordJobRun(db, jobMonthlyReport, currentMonth)
+		}
 	}
 
-	// 2. Periodic realtime price check (only during market hours)
-	// Skip if market is closed
-	if !isMarketOpen(now) {
-		return
+	// Daily reports of chats that chose another hour than CHECK_HOUR, at the start of that hour
+	if reportHour := now.Format("2006-01-02T15"); now.Hour() != config.CheckHour && now.Minute() < checkInterval && lastChatReportHour != reportHour {
+		if reports := delivery.ForReportHour(now.Hour(), config.CheckHour); reports.RecipientCount(models.KindDailyReport) > 0 {
+			slog.Info("Starting daily price report for chats that chose this hour", "hour", now.Hour())
+			sendReportMessages(ctx, db, reports, config)
+		}
+		lastChatReportHour = reportHour
+		recordJobRun(db, jobChatReports, reportHour)
 	}
 
-	// Check at specified realtime intervals
-	if now.Minute()%realtimeCheckMinutes == 0 {
-		log.Printf("Checking for realtime price changes")
-		checkRealtimePriceChanges(ctx, db, messenger, config)
+	// Analyst rating changes and insider filings are published throughout the day; new ones are found
+	// against those stored in MongoDB
+	if config.FMPAPIKey != "" && db.UsesMongo() && time.Since(lastFMPCheck) >= fmpCheckInterval {
+		if fmp, err := services.NewFMPClient(config.FMPAPIKey); err == nil {
+			checkAnalystRatings(ctx, db, delivery, fmp)
+			checkInsiderTrades(ctx, db, delivery, fmp)
+		}
+		lastFMPCheck = time.Now()
 	}
-}
 
-// isMarketOpen checks if the current time is during stock market hours
-// US market hours: Mon-Fri, 9:30AM-4:00PM ET (Korean time 23:30-7:00)
-func isMarketOpen(now time.Time) bool {
-	// Exclude weekends
-	if now.Weekday() == time.Saturday || now.Weekday() == time.Sunday {
-		return false
+	// Optional reminders shortly before high-impact macro releases
+	if config.FMPAPIKey != "" && config.EconomicAlertLead > 0 && db.UsesMongo() {
+		checkEconomicReminders(db, delivery, config.EconomicAlertLead, now)
 	}
 
-	// Time zone conversion may be needed (simplified implementation for now)
-	hour := now.Hour()
+	// Market open and close bookends around the alerting session
+	checkMarketSession(ctx, db, delivery, config, now)
+
+	// Save each exchange's final prices as the closes the next session is compared with
+	captureClosingPrices(ctx, db, delivery, config, now)
+
+	// 2. Periodic realtime price check; equities only during their exchange's hours,
+	// crypto and FX around the clock, each asset class and priority tier at its own interval
+	var due []string
+	for class, classSymbols := range models.GroupByAssetClass(models.Tickers()) {
+		schedule := scheduleFor(config, class)
+		for exchange, exchangeSymbols := range groupByExchange(classSymbols) {
+			if !schedule.alwaysOpen && !isExchangeOpen(exchange, now) {
+				continue
+			}
+			// Streamed US equities are evaluated as trades arrive
+			if exchange == models.ExchangeUS && equityStreaming {
+				continue
+			}
+			for priority, symbols := range models.GroupByPriority(exchangeSymbols) {
+				group := realtimeGroup{class: class, exchange: exchange, priority: priority}
+				// Allow for ticker drift so a check isn't pushed back a whole scheduler interval
+				if now.Sub(lastRealtimeCheck[group]) < schedule.intervalFor(priority)-time.Minute {
+					continue
+				}
+				lastRealtimeCheck[group] = now
+
+				slog.Info("Checking for realtime price changes", "count", len(symbols), "class", class, "exchange", exchange, "priority", priority)
+				due = append(due, symbols...)
+			}
+		}
+	}
+	if len(due) > 0 {
+		botStatus.recordRealtimeRun(now)
+		checkRealtimePriceChanges(ctx, db, delivery, config, due)
+	}
+}
+
+// isMarketOpen checks if the current time is during stock market hours, converted to New York time
+// US market hours: Mon-Fri, 9:30AM-4:00PM ET except NYSE holidays, closing at 1:00PM on early-close days,
+// extended by the configured pre-market and after-hours windows
+func isMarketOpen(now time.Time) bool {
+	config := currentConfig()
+	return calendar.IsOpenExtended(now, config.PreMarketWindow, config.PostMarketWindow)
+}
 
-	// Example: Assuming 23:30-07:00 Korean time as market hours
-	return (hour >= 21 && hour <= 23) || (hour >= 0 && hour <= 7)
+// isExchangeOpen checks if an exchange's equities are trading: US listings during the US market hours, and every
+// other exchange during its own session, e.g. KRX from 9:00AM to 3:30PM KST
+func isExchangeOpen(exchange string, now time.Time) bool {
+	switch exchange {
+	case models.ExchangeUS:
+		return isMarketOpen(now)
+	case models.ExchangeKRX:
+		return calendar.IsKRXOpen(now)
+	default:
+		return calendar.IsExchangeOpen(exchange, now)
+	}
 }
 
-// resetAlertMap resets the alert tracking map at the start of a new day
-func resetAlertMap() {
+// pruneAlertMap drops alerts from the tracking map once their cooldown has passed
+func pruneAlertMap(db *services.Database) {
+	cutoff := time.Now().Add(-currentConfig().AlertCooldown)
+
 	alertMapMutex.Lock()
 	defer alertMapMutex.Unlock()
 
-	lastAlertSentMap = make(map[string]time.Time)
-	log.Printf("Alert tracking map has been reset for new day")
+	maps.DeleteFunc(lastAlertSentMap, func(_ string, alert models.SentAlert) bool {
+		return alert.SentAt.Before(cutoff)
+	})
+	if err := db.DeleteSentAlertsBefore(cutoff); err != nil {
+		slog.Error("Error pruning saved alerts", "error", err)
+	}
 }
 
-// canSendAlert checks if an alert has already been sent today for a specific stock
-func canSendAlert(symbol string) bool {
-	alertMapMutex.RLock()
-	defer alertMapMutex.RUnlock()
+// alertKey identifies a symbol's alerts of one basis to one chat in the alert tracking map
+func alertKey(chatID, symbol string, basis models.AlertBasis) string {
+	if basis != models.BasisPreviousClose {
+		symbol += "@" + string(basis)
+	}
+	return chatID + "/" + symbol
+}
 
-	lastSent, exists := lastAlertSentMap[symbol]
+// canSendAlert checks if a change of a stock may be alerted to a chat: once the cooldown since its last alert
+// has passed, or right away when the move extends the last alerted change by the re-alert step
+func canSendAlert(chatID string, alert models.PriceAlert) bool {
+	alertMapMutex.RLock()
+	last, exists := lastAlertSentMap[alertKey(chatID, alert.Symbol, alert.Basis)]
+	alertMapMutex.RUnlock()
 	if !exists {
 		return true
 	}
 
-	// Check if the last alert was sent on a different date
-	now := time.Now()
-	return lastSent.Day() != now.Day() || lastSent.Month() != now.Month() || lastSent.Year() != now.Year()
+	config := currentConfig()
+	if time.Since(last.SentAt) >= config.AlertCooldown {
+		return true
+	}
+
+	// A continuation in the same direction, e.g. 5% then 10%, is alerted again; a reversal waits for the cooldown
+	return config.AlertStepPercent > 0 &&
+		math.Signbit(alert.PercentChange) == math.Signbit(last.PercentChange) &&
+		math.Abs(alert.PercentChange) >= math.Abs(last.PercentChange)+config.AlertStepPercent
 }
 
-// markAlertSent records that an alert has been sent for a specific stock
-func markAlertSent(symbol string) {
+// markAlertSent records that a change of a stock has been alerted to a chat, so a restart doesn't send it again
+func markAlertSent(db *services.Database, chatID string, alert models.PriceAlert) {
+	sent := models.SentAlert{ChatID: chatID, Symbol: alert.Symbol, SentAt: time.Now(), PercentChange: alert.PercentChange, Basis: alert.Basis}
 	alertMapMutex.Lock()
-	defer alertMapMutex.Unlock()
+	lastAlertSentMap[alertKey(chatID, alert.Symbol, alert.Basis)] = sent
+	alertMapMutex.Unlock()
+
+	if err := db.SaveSentAlert(sent); err != nil {
+		slog.Error("Error saving sent alert", "symbol", alert.Symbol, "error", err)
+	}
+}
+
+// alertsSentSince counts the alerts sent to each chat since the given time
+func alertsSentSince(since time.Time) int {
+	alertMapMutex.RLock()
+	defer alertMapMutex.RUnlock()
+
+	count := 0
+	for _, alert := range lastAlertSentMap {
+		if !alert.SentAt.Before(since) {
+			count++
+		}
+	}
+	return count
+}
 
-	lastAlertSentMap[symbol] = time.Now()
+// sendDailyReport sends a daily price report for all stocks, then exports and publishes the prices
+func sendDailyReport(ctx context.Context, db *services.Database, delivery *services.Delivery, config models.Config) {
+	quotes, ok := sendReportMessages(ctx, db, delivery, config)
+	if !ok {
+		return
+	}
+
+	exportDailyCloses(ctx, quotes)
+	publishReport(ctx, quotes)
 }
 
-// sendDailyReport sends a daily price report for all stocks
-func sendDailyReport(ctx context.Context, db *services.Database, messenger services.Messenger, config models.Config) {
-	log.Printf("Fetching stock prices for daily report")
+// sendReportMessages fetches every price and sends the messages making up the daily report: the market overview,
+// the prices, exchange rates, portfolios and the symbols that couldn't be fetched
+func sendReportMessages(ctx context.Context, db *services.Database, delivery *services.Delivery, config models.Config) (map[string]models.Quote, bool) {
+	slog.Info("Fetching stock prices for daily report")
+	start := time.Now()
 
 	// Fetch prices
-	prices, err := fetchAllPrices(ctx, config)
+	quotes, err := fetchAllPrices(ctx, db, config)
 	if err != nil {
-		log.Printf("Error during price fetching for daily report: %v", err)
-		return
+		slog.Error("Error during price fetching for daily report", "error", err)
+		return nil, false
 	}
 
+	// Indices open the report in their own overview, ahead of the symbol prices
+	indices, symbolQuotes := splitIndices(quotes)
+	sendMarketOverview(delivery, indices)
+
 	// Send daily report
-	if err := messenger.SendMessage(prices, nil); err != nil {
-		log.Printf("Error sending daily price report: %v", err)
+	if err := delivery.SendMessage(symbolQuotes, nil); err != nil {
+		slog.Error("Error sending daily price report", "error", err)
 	} else {
-		log.Printf("Daily price report sent successfully")
+		slog.Info("Daily price report sent", "duration", time.Since(start))
 	}
+	sendExchangeRates(ctx, delivery, config)
+	sendPortfolioReports(ctx, db, delivery)
+	sendFetchFailures(delivery)
+	return quotes, true
 }
 
 // checkRealtimePriceChanges checks for significant price changes in real-time and sends alerts
-func checkRealtimePriceChanges(ctx context.Context, db *services.Database, messenger services.Messenger, config models.Config) {
+func checkRealtimePriceChanges(ctx context.Context, db *services.Database, delivery *services.Delivery, config models.Config, symbols []string) {
 	// Fetch prices
-	prices, err := fetchAllPrices(ctx, config)
+	quotes, err := fetchPrices(ctx, db, symbols)
 	if err != nil {
-		log.Printf("Error during price fetching for realtime check: %v", err)
+		slog.Error("Error during price fetching for realtime check", "error", err)
 		return
 	}
 
-	// Check for changes in each stock
-	var alertsToSend []models.PriceAlert
+	evaluateRealtimePrices(ctx, db, delivery, quotes)
+}
+
+// evaluateRealtimePrices compares current prices with the previous closes and sends alerts for significant changes
+func evaluateRealtimePrices(ctx context.Context, db *services.Database, delivery *services.Delivery, quotes map[string]models.Quote) {
+	// A reference rate from an earlier day says nothing about a move happening now
+	quotes = liveQuotes(quotes)
+	if len(quotes) == 0 {
+		return
+	}
 
-	for symbol, priceStr := range prices {
-		// Skip if an alert has already been sent today
-		if !canSendAlert(symbol) {
-			continue
+	// Load thresholds once per cycle so runtime changes apply on the next check
+	thresholds, err := loadAlertThresholds(db)
+	if err != nil {
+		slog.Error("Error loading alert thresholds, using default", "default", alertThreshold(), "error", err)
+	}
+
+	// Load every previous close in a single query instead of one round trip per symbol
+	// Without them only intraday moves are checked
+	previousCloses, err := db.GetLatestClosingPrices(slices.Collect(maps.Keys(quotes)))
+	if err != nil {
+		slog.Error("Error retrieving previous closing prices", "error", err)
+	}
+
+	// Chats may set their own threshold, so changes are checked against the lowest one that applies
+	users, err := db.ListUsers()
+	if err != nil {
+		slog.Error("Error loading subscriber thresholds, using symbol thresholds only", "error", err)
+	}
+
+	// Absolute price levels set by chats with /alert, and new 52-week highs and lows
+	checkPriceTargets(db, delivery, quotes)
+	checkYearExtremes(db, delivery, quotes)
+	checkUnusualVolume(db, delivery, quotes, previousCloses)
+
+	// Check for changes in each stock from the previous close, and from the session open and the recent past
+	config := currentConfig()
+	now := time.Now()
+	var candidates []models.PriceAlert
+	var significant []models.MongoDTO
+	for symbol, quote := range quotes {
+		quote.Symbol = symbol
+		for _, alert := range intraday.check(quote, now, intradayThresholdsFor(symbol, config, users)) {
+			candidates = append(candidates, alert)
+			slog.Info("Intraday price move detected", "symbol", symbol, "basis", alert.Basis, "percent_change", alert.PercentChange)
 		}
 
-		// Check for significant changes
-		alert, hasSignificantChange := checkPriceChange(db, symbol, priceStr)
+		threshold := lowestThreshold(symbol, thresholds.For(symbol), users)
+		alert, hasSignificantChange := checkPriceChange(quote, previousCloses[symbol], threshold)
 		if !hasSignificantChange {
 			continue
 		}
+		candidates = append(candidates, alert)
+		significant = append(significant, models.NewPriceRecord(quote, false, now))
+		slog.Info("Significant price change detected", "symbol", symbol, "percent_change", alert.PercentChange)
+	}
 
-		// Add alert
-		alertsToSend = append(alertsToSend, alert)
+	// Prices behind a significant change are kept, saved in one call
+	if err := db.SavePrices(significant); err != nil {
+		slog.Error("Error saving current price data", "error", err)
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	// Volume and headlines only show in verbose alerts, so their lookups are skipped when nobody reads them
+	if needsAlertDetails(config, users, candidates) {
+		enrichAlerts(ctx, candidates)
+	}
 
-		// Record that an alert has been sent
-		markAlertSent(symbol)
-		log.Printf("Significant price change detected for %s (%.2f%%)", symbol, alert.PercentChange)
+	// Each chat is alerted per watched symbol when the change reaches its own threshold, subject to the cooldown.
+	// Sends are recorded after delivery so broadcast channels sharing a recipient don't skip each other
+	var mu sync.Mutex
+	type sentAlert struct {
+		chatID string
+		alert  models.PriceAlert
 	}
+	var sent []sentAlert
+	delivered := make(map[string]models.PriceAlert)
+	_, err = delivery.Deliver(models.KindAlert, func(m services.Messenger, user models.User) error {
+		now := time.Now()
+		var due []models.PriceAlert
+		for _, alert := range candidates {
+			// A chat's own threshold replaces the symbol's and the intraday ones
+			reachesThreshold := math.Abs(alert.PercentChange) >= user.ThresholdOr(thresholds.For(alert.Symbol))
+			if alert.Basis != models.BasisPreviousClose {
+				reachesThreshold = math.Abs(alert.PercentChange) >= user.ThresholdOr(intradayThreshold(alert.Basis, config))
+			}
+			if user.Watches(alert.Symbol) && !user.IsMuted(alert.Symbol, now) && reachesThreshold &&
+				canSendAlert(user.ChatID, alert) {
+				due = append(due, alert)
+			}
+		}
+		if len(due) == 0 {
+			return nil
+		}
+		if err := m.SendAlerts(due, nil); err != nil {
+			return err
+		}
 
-	// Send alerts only if there are any
-	if len(alertsToSend) > 0 {
-		log.Printf("Sending realtime alerts for %d stocks with significant changes", len(alertsToSend))
+		mu.Lock()
+		defer mu.Unlock()
+		for _, alert := range due {
+			sent = append(sent, sentAlert{chatID: user.ChatID, alert: alert})
+			delivered[alertKey("", alert.Symbol, alert.Basis)] = alert
+		}
+		return nil
+	})
+	if err != nil {
+		slog.Error("Error sending realtime price alerts", "error", err)
+	}
 
-		if err := messenger.SendAlerts(alertsToSend, nil); err != nil {
-			log.Printf("Error sending realtime price alerts: %v", err)
-		} else {
-			log.Printf("Realtime price alerts sent successfully")
+	for _, s := range sent {
+		markAlertSent(db, s.chatID, s.alert)
+	}
+	if len(delivered) == 0 {
+		return
+	}
+
+	slog.Info("Realtime price alerts sent", "count", len(delivered))
+	alertsSent := slices.Collect(maps.Values(delivered))
+	exportAlerts(ctx, alertsSent)
+	publishAlerts(ctx, alertsSent)
+}
+
+// lowestThreshold returns the smallest alert threshold of a symbol among its own and those of the chats watching it
+func lowestThreshold(symbol string, symbolThreshold float64, users []models.User) float64 {
+	lowest := symbolThreshold
+	for _, user := range users {
+		if user.Onboarded && user.Watches(symbol) {
+			lowest = min(lowest, user.ThresholdOr(symbolThreshold))
+		}
+	}
+	return lowest
+}
+
+// liveQuotes leaves out the stale quotes, such as a fallback reference rate of an earlier day
+func liveQuotes(quotes map[string]models.Quote) map[string]models.Quote {
+	live := make(map[string]models.Quote, len(quotes))
+	for symbol, quote := range quotes {
+		if quote.Stale {
+			slog.Debug("Skipping alerts on stale quote", "symbol", symbol, "source", quote.Source, "as_of", quote.Timestamp)
+			continue
 		}
+		live[symbol] = quote
 	}
+	return live
 }
 
 // fetchAllPrices fetches prices for all stocks
-func fetchAllPrices(ctx context.Context, config models.Config) (map[string]string, error) {
+func fetchAllPrices(ctx context.Context, db *services.Database, config models.Config) (map[string]models.Quote, error) {
+	return fetchPrices(ctx, db, models.Tickers())
+}
+
+// fetchPrices fetches prices for the given symbols
+func fetchPrices(ctx context.Context, db *services.Database, symbols []string) (map[string]models.Quote, error) {
+	// Skip symbols paused as possibly delisted and fetch high-priority symbols first
+	requested := symbols
+	symbols = models.SortByPriority(symbolHealth.active(symbols))
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no active symbols to fetch")
+	}
+
 	// Fetch price information
-	priceResults, err := priceFetcher.FetchPriceConcurrent(ctx, models.Tickers, maxConcurrency)
+	start := time.Now()
+	priceResults, err := priceSource.FetchConcurrent(ctx, symbols, maxConcurrency)
 	if err != nil {
 		return nil, fmt.Errorf("error during price fetching: %w", err)
 	}
+	symbolHealth.record(priceResults)
+	fetchFailures.record(requested, priceResults)
 
 	// Process results
-	prices := make(map[string]string)
+	quotes := make(map[string]models.Quote)
 	var successCount int
 
 	for symbol, result := range priceResults {
 		if result.Error != nil {
-			log.Printf("Error fetching price for %s: %v", symbol, result.Error)
+			slog.Error("Error fetching price", "symbol", symbol, "error", result.Error)
 			continue
 		}
 
-		prices[symbol] = result.Price
+		quotes[symbol] = result.Quote
 		successCount++
 	}
 
@@ -377,51 +890,31 @@ func fetchAllPrices(ctx context.Context, config models.Config) (map[string]strin
 		return nil, fmt.Errorf("failed to fetch any stock prices")
 	}
 
-	log.Printf("Successfully fetched %d/%d stock prices", successCount, len(models.Tickers))
-	return prices, nil
+	slog.Info("Fetched stock prices", "fetched", successCount, "requested", len(symbols), "duration", time.Since(start))
+	botStatus.recordPrices(quotes)
+	publishQuotes(ctx, db, quotes)
+	return quotes, nil
 }
 
-// checkPriceChange checks for significant changes in stock prices
-func checkPriceChange(db *services.Database, symbol, currentPriceStr string) (models.PriceAlert, bool) {
-	// Parse current price
-	currentPrice, err := strconv.ParseFloat(currentPriceStr, 64)
-	if err != nil {
-		log.Printf("Error parsing current price for %s: %v", symbol, err)
-		return models.PriceAlert{}, false
-	}
-
-	// Get previous closing price
-	previousPrice, err := db.GetLatestClosingPrice(symbol)
-	if err != nil {
-		if !errors.Is(err, services.ErrNoClosingPriceFound) {
-			log.Printf("Error retrieving previous closing price for %s: %v", symbol, err)
-		}
-		return models.PriceAlert{}, false
-	}
-
-	// Skip if this is the first data point (no previous price)
+// checkPriceChange checks for a change from the previous close at or beyond the given percent threshold
+func checkPriceChange(quote models.Quote, previousPrice, threshold float64) (models.PriceAlert, bool) {
+	// Skip if there is no previous close yet
 	if previousPrice == 0 {
 		return models.PriceAlert{}, false
 	}
 
 	// Calculate percentage change
-	percentChange := ((currentPrice - previousPrice) / previousPrice) * 100
+	percentChange := ((quote.Price - previousPrice) / previousPrice) * 100
 
 	// Create alert if change exceeds threshold
-	if math.Abs(percentChange) >= alertThreshold {
+	if math.Abs(percentChange) >= threshold {
 		alert := models.PriceAlert{
-			Symbol:        symbol,
+			Symbol:        quote.Symbol,
 			PreviousPrice: previousPrice,
-			CurrentPrice:  currentPrice,
+			CurrentPrice:  quote.Price,
 			PercentChange: percentChange,
 			Timestamp:     time.Now(),
 		}
-
-		// Save current price to DB
-		if err := db.SavePrice(symbol, currentPriceStr, false, nil); err != nil {
-			log.Printf("Error saving current price data for %s: %v", symbol, err)
-		}
-
 		return alert, true
 	}
 
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// overviewMove is an index level and its change over the last session
+type overviewMove struct {
+	symbol        string
+	level         float64
+	changePercent float64
+	hasChange     bool
+}
+
+// splitIndices separates watched indices from the rest of the report prices
+func splitIndices(quotes map[string]models.Quote) (indices, others map[string]models.Quote) {
+	indices = make(map[string]models.Quote)
+	others = make(map[string]models.Quote, len(quotes))
+	for symbol, quote := range quotes {
+		if models.IsIndex(symbol) {
+			indices[symbol] = quote
+		} else {
+			others[symbol] = quote
+		}
+	}
+	return indices, others
+}
+
+// sendMarketOverview opens the daily report with the levels and daily changes of the watched indices
+func sendMarketOverview(delivery *services.Delivery, indices map[string]models.Quote) {
+	if len(indices) == 0 {
+		return
+	}
+
+	// The quote carries the change from the previous session, which the saved closes can't give before the open
+	var moves []overviewMove
+	for _, symbol := range slices.Sorted(maps.Keys(indices)) {
+		quote := indices[symbol]
+		moves = append(moves, overviewMove{
+			symbol:        symbol,
+			level:         quote.Price,
+			changePercent: quote.ChangePercent,
+			hasChange:     quote.ChangePercent != 0,
+		})
+	}
+
+	if _, err := delivery.Deliver(models.KindDailyReport, func(m services.Messenger, user models.User) error {
+		message := formatMarketOverview(moves, user)
+		if message == "" {
+			return nil
+		}
+		return m.SendText(message, nil)
+	}); err != nil {
+		slog.Error("Error sending market overview", "error", err)
+	}
+}
+
+// formatMarketOverview renders the indices a recipient watches, or "" if they watch none
+func formatMarketOverview(moves []overviewMove, user models.User) string {
+	var message strings.Builder
+	for _, move := range moves {
+		if !user.Watches(move.symbol) {
+			continue
+		}
+		message.WriteString(fmt.Sprintf("%s: %s", models.IndexName(move.symbol), models.FormatPrice(move.symbol, move.level)))
+		if move.hasChange {
+			icon := "🔺"
+			if move.changePercent < 0 {
+				icon = "🔻"
+			}
+			message.WriteString(fmt.Sprintf(" %s %+.2f%%", icon, move.changePercent))
+		}
+		message.WriteString("\n")
+	}
+	if message.Len() == 0 {
+		return ""
+	}
+	return "🌐 Market Overview\n\n" + message.String()
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"stock-bot/calendar"
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// Market session state used to detect the open and close transitions
+var (
+	marketSessionKnown bool
+	marketSessionOpen  bool
+	marketSessionStart time.Time
+)
+
+// sessionMove is a symbol's change over the trading session
+type sessionMove struct {
+	symbol        string
+	price         float64
+	percentChange float64
+}
+
+// checkMarketSession sends the open and close bookends when the market state changes
+func checkMarketSession(ctx context.Context, db *services.Database, delivery *services.Delivery, config models.Config, now time.Time) {
+	open := isMarketOpen(now)
+
+	// The state at startup is only recorded, so a restart mid-session doesn't announce an open
+	if !marketSessionKnown {
+		marketSessionKnown = true
+		marketSessionOpen = open
+		if open {
+			marketSessionStart = now
+		}
+		return
+	}
+	if open == marketSessionOpen {
+		return
+	}
+	marketSessionOpen = open
+
+	if open {
+		marketSessionStart = now
+		intraday.resetOpens(func(symbol string) bool { return models.ExchangeOf(symbol) == models.ExchangeUS })
+		sendMarketOpen(delivery, now)
+	} else {
+		sendMarketClose(ctx, db, delivery, config)
+	}
+}
+
+// sendMarketOpen announces the start of the alerting session, and an early close
+func sendMarketOpen(delivery *services.Delivery, now time.Time) {
+	message := locale.Sprintf("🔔 US market is open, watching %d symbols", len(models.Tickers()))
+	if session, ok := calendar.SessionOn(now); ok && session.EarlyClose {
+		message += "\n" + locale.Sprintf("⏰ Early close today at %s ET", session.Close.Format("15:04"))
+	}
+	if _, err := delivery.Deliver(models.KindMarketOpen, func(m services.Messenger, _ models.User) error {
+		return m.SendText(message, nil)
+	}); err != nil {
+		slog.Error("Error sending market open message", "error", err)
+	}
+}
+
+// sendMarketClose summarizes the session's moves and alerts once the market closes
+func sendMarketClose(ctx context.Context, db *services.Database, delivery *services.Delivery, config models.Config) {
+	quotes, err := fetchAllPrices(ctx, db, config)
+	if err != nil {
+		slog.Error("Error during price fetching for market close summary", "error", err)
+		return
+	}
+
+	closes, err := db.GetLatestClosingPrices(slices.Collect(maps.Keys(quotes)))
+	if err != nil {
+		slog.Error("Error retrieving previous closes for market close summary", "error", err)
+	}
+
+	var moves []sessionMove
+	for symbol, quote := range quotes {
+		previous := closes[symbol]
+		if previous == 0 {
+			continue
+		}
+		moves = append(moves, sessionMove{symbol: symbol, price: quote.Price, percentChange: (quote.Price - previous) / previous * 100})
+	}
+	slices.SortFunc(moves, func(a, b sessionMove) int {
+		return strings.Compare(a.symbol, b.symbol)
+	})
+
+	alertCount := alertsSentSince(marketSessionStart)
+
+	if _, err := delivery.Deliver(models.KindMarketClose, func(m services.Messenger, user models.User) error {
+		return m.SendText(formatMarketClose(moves, alertCount, user), nil)
+	}); err != nil {
+		slog.Error("Error sending market close summary", "error", err)
+	}
+}
+
+// formatMarketClose renders the close summary for a recipient, skipping symbols they muted
+func formatMarketClose(moves []sessionMove, alertCount int, user models.User) string {
+	var message strings.Builder
+	message.WriteString(locale.Text("🏁 US market closed, summary:") + "\n\n")
+
+	now := time.Now()
+	for _, move := range moves {
+		if user.IsMuted(move.symbol, now) {
+			continue
+		}
+		icon := "🔺"
+		if move.percentChange < 0 {
+			icon = "🔻"
+		}
+		message.WriteString(fmt.Sprintf("%s %s: %s (%+.2f%%)\n", icon, move.symbol, models.FormatPrice(move.symbol, move.price), move.percentChange))
+	}
+
+	message.WriteString("\n" + locale.Sprintf("%d price alerts sent this session", alertCount))
+	return message.String()
+}
@@ -0,0 +1,42 @@
+package models
+
+import "strings"
+
+// AssetClass groups symbols that share trading hours
+type AssetClass string
+
+// Supported asset classes
+const (
+	AssetEquity AssetClass = "equity"
+	AssetCrypto AssetClass = "crypto"
+	AssetFX     AssetClass = "fx"
+)
+
+// cryptoQuoteSuffixes are the Yahoo suffixes of crypto pairs such as BTC-USD
+var cryptoQuoteSuffixes = []string{"-USD", "-USDT", "-KRW", "-EUR", "-BTC", "-ETH"}
+
+// AssetClassOf returns the asset class of a Yahoo symbol
+func AssetClassOf(symbol string) AssetClass {
+	// Currency pairs look like EURUSD=X or KRW=X
+	if strings.HasSuffix(symbol, "=X") {
+		return AssetFX
+	}
+
+	for _, suffix := range cryptoQuoteSuffixes {
+		if strings.HasSuffix(symbol, suffix) {
+			return AssetCrypto
+		}
+	}
+
+	return AssetEquity
+}
+
+// GroupByAssetClass splits symbols by their asset class, keeping their order
+func GroupByAssetClass(symbols []string) map[AssetClass][]string {
+	groups := make(map[AssetClass][]string)
+	for _, symbol := range symbols {
+		class := AssetClassOf(symbol)
+		groups[class] = append(groups[class], symbol)
+	}
+	return groups
+}
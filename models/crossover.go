@@ -0,0 +1,50 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Moving average kinds for crossover alerts
+const (
+	AverageSMA = "sma"
+	AverageEMA = "ema"
+)
+
+// CrossoverAll enables crossover alerts for every monitored symbol
+const CrossoverAll = "ALL"
+
+// CrossoverConfig selects the symbols alerted on moving average crossovers and the averages compared
+type CrossoverConfig struct {
+	Symbols []string `json:"symbols"`
+	Fast    int      `json:"fast"`
+	Slow    int      `json:"slow"`
+	Average string   `json:"average"`
+}
+
+// Enabled reports whether crossover alerts are on for a symbol
+func (c CrossoverConfig) Enabled(symbol string) bool {
+	return slices.Contains(c.Symbols, CrossoverAll) || slices.Contains(c.Symbols, symbol)
+}
+
+// ParseCrossoverPeriods parses a "FAST/SLOW" pair of moving average periods such as 20/50
+func ParseCrossoverPeriods(value string) (int, int, error) {
+	fastStr, slowStr, found := strings.Cut(value, "/")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid crossover periods %q, expected FAST/SLOW", value)
+	}
+	fast, err := strconv.Atoi(strings.TrimSpace(fastStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid fast period %q: %w", fastStr, err)
+	}
+	slow, err := strconv.Atoi(strings.TrimSpace(slowStr))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid slow period %q: %w", slowStr, err)
+	}
+	if fast <= 0 || slow <= fast {
+		return 0, 0, fmt.Errorf("crossover periods %q must be positive with the fast one shorter", value)
+	}
+	return fast, slow, nil
+}
@@ -0,0 +1,49 @@
+package models
+
+import "testing"
+
+func TestParseCrossoverPeriods(t *testing.T) {
+	tests := []struct {
+		value   string
+		fast    int
+		slow    int
+		wantErr bool
+	}{
+		{"20/50", 20, 50, false},
+		{" 50 / 200 ", 50, 200, false},
+		{"50", 0, 0, true},
+		{"a/50", 0, 0, true},
+		{"20/b", 0, 0, true},
+		{"50/20", 0, 0, true},
+		{"20/20", 0, 0, true},
+		{"0/20", 0, 0, true},
+		{"-5/20", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			fast, slow, err := ParseCrossoverPeriods(tt.value)
+			if (err != nil) != tt.wantErr || fast != tt.fast || slow != tt.slow {
+				t.Errorf("ParseCrossoverPeriods(%q) = %d, %d, %v, want %d, %d, error %v", tt.value, fast, slow, err, tt.fast, tt.slow, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCrossoverConfigEnabled(t *testing.T) {
+	tests := []struct {
+		symbols []string
+		want    bool
+	}{
+		{[]string{"AAPL", "MSFT"}, true},
+		{[]string{CrossoverAll}, true},
+		{[]string{"MSFT"}, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := (CrossoverConfig{Symbols: tt.symbols}).Enabled("AAPL"); got != tt.want {
+			t.Errorf("Enabled(AAPL) with %v = %v, want %v", tt.symbols, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Currency codes
+const (
+	CurrencyUSD = "USD"
+	CurrencyKRW = "KRW"
+	CurrencyEUR = "EUR"
+	CurrencyJPY = "JPY"
+	CurrencyGBP = "GBP"
+)
+
+// SymbolCurrencies maps symbols to their native currency, overriding suffix-based detection
+var SymbolCurrencies = map[string]string{}
+
+// currencyPrefixes maps currency codes to their display prefix
+var currencyPrefixes = map[string]string{
+	CurrencyUSD: "$",
+	CurrencyKRW: "₩",
+	CurrencyEUR: "€",
+	CurrencyJPY: "¥",
+	CurrencyGBP: "£",
+}
+
+// exchangeSuffixCurrencies maps Yahoo exchange suffixes to the currency they trade in
+var exchangeSuffixCurrencies = map[string]string{
+	".KS": CurrencyKRW,
+	".KQ": CurrencyKRW,
+	".T":  CurrencyJPY,
+	".L":  CurrencyGBP,
+	".DE": CurrencyEUR,
+	".PA": CurrencyEUR,
+	".AS": CurrencyEUR,
+}
+
+// CurrencyFor returns the native currency of a symbol
+func CurrencyFor(symbol string) string {
+	if currency, ok := SymbolCurrencies[symbol]; ok {
+		return currency
+	}
+
+	// Currency pairs are priced in their quote currency
+	if _, quote, ok := ParseFXPair(symbol); ok {
+		return quote
+	}
+
+	// Crypto pairs are quoted in the currency after the dash, with Tether shown as dollars
+	if AssetClassOf(symbol) == AssetCrypto {
+		_, quote, _ := strings.Cut(symbol, "-")
+		if quote == "USDT" {
+			return CurrencyUSD
+		}
+		return quote
+	}
+
+	for suffix, currency := range exchangeSuffixCurrencies {
+		if strings.HasSuffix(symbol, suffix) {
+			return currency
+		}
+	}
+
+	return CurrencyUSD
+}
+
+// CurrencyPrefix returns the display prefix for a symbol's currency (e.g. "$", "₩"); index levels are points and have none
+func CurrencyPrefix(symbol string) string {
+	if IsIndex(symbol) {
+		return ""
+	}
+	currency := CurrencyFor(symbol)
+	if prefix, ok := currencyPrefixes[currency]; ok {
+		return prefix
+	}
+	return currency + " "
+}
+
+// FormatPrice formats a price in the symbol's native currency
+func FormatPrice(symbol string, price float64) string {
+	// Coins priced below one unit keep four significant digits, e.g. $0.1234 or 0.00001234 BTC
+	if AssetClassOf(symbol) == AssetCrypto && price > 0 && price < 1 {
+		decimals := min(3-int(math.Floor(math.Log10(price))), 10)
+		return fmt.Sprintf("%s%.*f", CurrencyPrefix(symbol), decimals, price)
+	}
+
+	// Exchange rates move in small fractions, so they keep two more decimals
+	if AssetClassOf(symbol) == AssetFX {
+		decimals := 4
+		if currency := CurrencyFor(symbol); currency == CurrencyKRW || currency == CurrencyJPY {
+			decimals = 2
+		}
+		return fmt.Sprintf("%s%.*f", CurrencyPrefix(symbol), decimals, price)
+	}
+
+	// Currencies without minor units are shown as whole numbers
+	switch CurrencyFor(symbol) {
+	case CurrencyKRW, CurrencyJPY:
+		return fmt.Sprintf("%s%.0f", CurrencyPrefix(symbol), price)
+	default:
+		return fmt.Sprintf("%s%.2f", CurrencyPrefix(symbol), price)
+	}
+}
+
+// FormatQuote formats a quote's price with its change from the previous close, e.g. "$210.00 (+1.20%)";
+// the change is left out when the source didn't report one
+func FormatQuote(quote Quote) string {
+	if quote.ChangePercent == 0 {
+		return FormatPrice(quote.Symbol, quote.Price)
+	}
+	return fmt.Sprintf("%s (%+.2f%%)", FormatPrice(quote.Symbol, quote.Price), quote.ChangePercent)
+}
+
+// FormatAmount formats a money amount in a currency, signed when signed is set, e.g. "+$12.50" or "-₩3000"
+func FormatAmount(currency string, amount float64, signed bool) string {
+	prefix, ok := currencyPrefixes[currency]
+	if !ok {
+		prefix = currency + " "
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	} else if signed {
+		sign = "+"
+	}
+
+	switch currency {
+	case CurrencyKRW, CurrencyJPY:
+		return fmt.Sprintf("%s%s%.0f", sign, prefix, math.Abs(amount))
+	default:
+		return fmt.Sprintf("%s%s%.2f", sign, prefix, math.Abs(amount))
+	}
+}
+
+// ParseSymbolCurrencies parses a "SYMBOL:CODE,SYMBOL:CODE" list into a currency map
+func ParseSymbolCurrencies(value string) (map[string]string, error) {
+	currencies := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		symbol, currency, found := strings.Cut(entry, ":")
+		if !found || strings.TrimSpace(symbol) == "" || strings.TrimSpace(currency) == "" {
+			return nil, fmt.Errorf("invalid symbol currency entry %q", entry)
+		}
+
+		currencies[strings.ToUpper(strings.TrimSpace(symbol))] = strings.ToUpper(strings.TrimSpace(currency))
+	}
+	return currencies, nil
+}
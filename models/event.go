@@ -0,0 +1,121 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// EventType identifies an event forwarded to outbound integrations
+type EventType string
+
+// Supported event types
+const (
+	EventAlert  EventType = "alert"
+	EventReport EventType = "report"
+	EventQuote  EventType = "quote"
+)
+
+// eventNames are the dotted names used as event bus subjects and topics
+var eventNames = map[EventType]string{
+	EventQuote:  "quote.fetched",
+	EventAlert:  "alert.fired",
+	EventReport: "report.sent",
+}
+
+// Name returns the event bus name of the event type, e.g. "alert.fired"
+func (t EventType) Name() string {
+	if name, ok := eventNames[t]; ok {
+		return name
+	}
+	return string(t)
+}
+
+// Event is the JSON document sent to outbound integrations
+type Event struct {
+	Type          EventType          `bson:"event" json:"event"`
+	Timestamp     time.Time          `bson:"timestamp" json:"timestamp"`
+	Symbol        string             `bson:"symbol,omitempty" json:"symbol,omitempty"`
+	PreviousPrice float64            `bson:"previousPrice,omitempty" json:"previousPrice,omitempty"`
+	CurrentPrice  float64            `bson:"currentPrice,omitempty" json:"currentPrice,omitempty"`
+	PercentChange float64            `bson:"percentChange,omitempty" json:"percentChange,omitempty"`
+	Direction     string             `bson:"direction,omitempty" json:"direction,omitempty"` // "up" or "down" for alerts
+	Prices        map[string]float64 `bson:"prices,omitempty" json:"prices,omitempty"`       // Report prices by symbol
+}
+
+// Title returns a one-line summary of the event for feeds and calendars
+func (e Event) Title() string {
+	switch e.Type {
+	case EventAlert:
+		return fmt.Sprintf("%s %+.2f%%: %s → %s", e.Symbol, e.PercentChange, FormatPrice(e.Symbol, e.PreviousPrice), FormatPrice(e.Symbol, e.CurrentPrice))
+	case EventReport:
+		return "Daily stock report " + e.Timestamp.Format("2006-01-02")
+	default:
+		return fmt.Sprintf("%s %s", e.Symbol, FormatPrice(e.Symbol, e.CurrentPrice))
+	}
+}
+
+// Summary returns the event details as plain text lines
+func (e Event) Summary() string {
+	if e.Type != EventReport {
+		return e.Title()
+	}
+
+	symbols := make([]string, 0, len(e.Prices))
+	for symbol := range e.Prices {
+		symbols = append(symbols, symbol)
+	}
+	slices.Sort(symbols)
+
+	lines := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		lines = append(lines, fmt.Sprintf("%s: %s", symbol, FormatPrice(symbol, e.Prices[symbol])))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// NewAlertEvent creates the event for a price alert
+func NewAlertEvent(alert PriceAlert) Event {
+	direction := "up"
+	if alert.PercentChange < 0 {
+		direction = "down"
+	}
+	return Event{
+		Type:          EventAlert,
+		Timestamp:     alert.Timestamp,
+		Symbol:        alert.Symbol,
+		PreviousPrice: alert.PreviousPrice,
+		CurrentPrice:  alert.CurrentPrice,
+		PercentChange: alert.PercentChange,
+		Direction:     direction,
+	}
+}
+
+// NewQuoteEvent creates the event for a fetched price; the change is left out when the previous close is unknown
+func NewQuoteEvent(symbol string, price, previousClose float64, at time.Time) Event {
+	event := Event{
+		Type:         EventQuote,
+		Timestamp:    at,
+		Symbol:       symbol,
+		CurrentPrice: price,
+	}
+	if previousClose > 0 {
+		event.PreviousPrice = previousClose
+		event.PercentChange = (price - previousClose) / previousClose * 100
+	}
+	return event
+}
+
+// NewReportEvent creates the event for a daily report
+func NewReportEvent(quotes map[string]Quote, at time.Time) Event {
+	prices := make(map[string]float64, len(quotes))
+	for symbol, quote := range quotes {
+		prices[symbol] = quote.Price
+	}
+	return Event{
+		Type:      EventReport,
+		Timestamp: at,
+		Prices:    prices,
+	}
+}
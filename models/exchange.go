@@ -0,0 +1,148 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Exchanges whose closing prices are captured; crypto and FX trade around the clock and close at midnight UTC
+const (
+	ExchangeUS       = "US"
+	ExchangeKRX      = "KRX"
+	ExchangeTSE      = "TSE"
+	ExchangeLSE      = "LSE"
+	ExchangeXetra    = "XETRA"
+	ExchangeEuronext = "EURONEXT"
+	Exchange24H      = "24H"
+)
+
+// exchangeSuffixes maps Yahoo exchange suffixes to the exchange they trade on
+var exchangeSuffixes = map[string]string{
+	".KS": ExchangeKRX,
+	".KQ": ExchangeKRX,
+	".T":  ExchangeTSE,
+	".L":  ExchangeLSE,
+	".DE": ExchangeXetra,
+	".PA": ExchangeEuronext,
+	".AS": ExchangeEuronext,
+}
+
+// ExchangeTimeZones are the time zones closing times are given in
+var ExchangeTimeZones = map[string]string{
+	ExchangeUS:       "America/New_York",
+	ExchangeKRX:      "Asia/Seoul",
+	ExchangeTSE:      "Asia/Tokyo",
+	ExchangeLSE:      "Europe/London",
+	ExchangeXetra:    "Europe/Berlin",
+	ExchangeEuronext: "Europe/Paris",
+	Exchange24H:      "UTC",
+}
+
+// ClockTime is a time of day in minutes after midnight
+type ClockTime int
+
+// ParseClockTime parses a "HH:MM" time of day
+func ParseClockTime(value string) (ClockTime, error) {
+	hourStr, minuteStr, found := strings.Cut(strings.TrimSpace(value), ":")
+	hour, hourErr := strconv.Atoi(hourStr)
+	minute, minuteErr := strconv.Atoi(minuteStr)
+	if !found || hourErr != nil || minuteErr != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", value)
+	}
+	return ClockTime(hour*60 + minute), nil
+}
+
+// String formats the time as "HH:MM"
+func (t ClockTime) String() string {
+	return fmt.Sprintf("%02d:%02d", int(t)/60, int(t)%60)
+}
+
+// DefaultClosingTimes are when closing prices are captured, shortly after each exchange's close in its own time zone
+func DefaultClosingTimes() map[string]ClockTime {
+	return map[string]ClockTime{
+		ExchangeUS:       16*60 + 10,
+		ExchangeKRX:      15*60 + 40,
+		ExchangeTSE:      15*60 + 40,
+		ExchangeLSE:      16*60 + 40,
+		ExchangeXetra:    17*60 + 40,
+		ExchangeEuronext: 17*60 + 40,
+		Exchange24H:      0,
+	}
+}
+
+// ExchangeOf returns the exchange a Yahoo symbol trades on
+func ExchangeOf(symbol string) string {
+	if AssetClassOf(symbol) != AssetEquity {
+		return Exchange24H
+	}
+	if exchange, ok := indexExchanges[symbol]; ok {
+		return exchange
+	}
+	for suffix, exchange := range exchangeSuffixes {
+		if strings.HasSuffix(symbol, suffix) {
+			return exchange
+		}
+	}
+	return ExchangeUS
+}
+
+// ExchangeLocation returns the time zone of an exchange, UTC when it can't be loaded
+func ExchangeLocation(exchange string) *time.Location {
+	loc, err := time.LoadLocation(ExchangeTimeZones[exchange])
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// TradingDate returns the exchange-local date a symbol's daily close at t belongs to. Daily rows that carry only a
+// date, from CSV files and stooq, are stored at midnight UTC and keep that date
+func TradingDate(symbol string, t time.Time) string {
+	utc := t.UTC()
+	if utc.Equal(utc.Truncate(24 * time.Hour)) {
+		return utc.Format("2006-01-02")
+	}
+	return t.In(ExchangeLocation(ExchangeOf(symbol))).Format("2006-01-02")
+}
+
+// CompletedCloses drops the daily bars of the symbol's current trading day: until its close is captured, the latest
+// bar a history source returns holds an intraday price
+func CompletedCloses(symbol string, points []PricePoint, now time.Time) []PricePoint {
+	today := now.In(ExchangeLocation(ExchangeOf(symbol))).Format("2006-01-02")
+	completed := make([]PricePoint, 0, len(points))
+	for _, point := range points {
+		if TradingDate(symbol, point.Timestamp) < today {
+			completed = append(completed, point)
+		}
+	}
+	return completed
+}
+
+// ParseClosingTimes parses a "EXCHANGE:HH:MM,EXCHANGE:HH:MM" list of closing price capture times
+func ParseClosingTimes(value string) (map[string]ClockTime, error) {
+	times := make(map[string]ClockTime)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		exchange, timeStr, found := strings.Cut(entry, ":")
+		exchange = strings.ToUpper(strings.TrimSpace(exchange))
+		if !found {
+			return nil, fmt.Errorf("invalid closing time entry %q", entry)
+		}
+		if _, known := ExchangeTimeZones[exchange]; !known {
+			return nil, fmt.Errorf("unknown exchange %q", exchange)
+		}
+
+		clock, err := ParseClockTime(timeStr)
+		if err != nil {
+			return nil, err
+		}
+		times[exchange] = clock
+	}
+	return times, nil
+}
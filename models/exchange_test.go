@@ -0,0 +1,51 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTradingDate(t *testing.T) {
+	tests := []struct {
+		name   string
+		symbol string
+		at     time.Time
+		want   string
+	}{
+		{"US capture after the close", "AAPL", time.Date(2025, 3, 14, 20, 10, 0, 0, time.UTC), "2025-03-14"},
+		{"US Yahoo bar at the open", "AAPL", time.Date(2025, 3, 14, 13, 30, 0, 0, time.UTC), "2025-03-14"},
+		{"US CSV row at midnight UTC", "AAPL", time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC), "2025-03-14"},
+		{"US evening in UTC is the next day", "AAPL", time.Date(2025, 3, 15, 1, 0, 0, 0, time.UTC), "2025-03-14"},
+		{"KRX capture after the close", "005930.KS", time.Date(2025, 3, 14, 6, 40, 0, 0, time.UTC), "2025-03-14"},
+		{"KRX morning is the previous UTC day", "005930.KS", time.Date(2025, 3, 13, 23, 30, 0, 0, time.UTC), "2025-03-14"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TradingDate(tt.symbol, tt.at); got != tt.want {
+				t.Errorf("TradingDate(%q, %v) = %q, want %q", tt.symbol, tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompletedCloses(t *testing.T) {
+	// 11:00 in New York on a Friday
+	now := time.Date(2025, 3, 14, 15, 0, 0, 0, time.UTC)
+	points := []PricePoint{
+		{Timestamp: time.Date(2025, 3, 12, 13, 30, 0, 0, time.UTC), Close: 1},
+		{Timestamp: time.Date(2025, 3, 13, 13, 30, 0, 0, time.UTC), Close: 2},
+		{Timestamp: time.Date(2025, 3, 14, 13, 30, 0, 0, time.UTC), Close: 3},
+	}
+
+	got := CompletedCloses("AAPL", points, now)
+	if len(got) != 2 || got[1].Close != 2 {
+		t.Errorf("CompletedCloses kept %v, want the two finished days", got)
+	}
+
+	// Crypto days end at midnight UTC, so the bar opened at 00:00 is still running
+	crypto := []PricePoint{{Timestamp: time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC), Close: 4}}
+	if got := CompletedCloses("BTC-USD", crypto, now); len(got) != 0 {
+		t.Errorf("CompletedCloses kept today's crypto bar: %v", got)
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import "strings"
+
+// FXSymbol returns the Yahoo symbol of a currency pair, e.g. EURUSD=X for EUR/USD
+func FXSymbol(base, quote string) string {
+	return strings.ToUpper(base+quote) + "=X"
+}
+
+// ParseFXPair returns the base and quote currencies of a Yahoo currency pair symbol;
+// the short form KRW=X is the dollar rate, USD/KRW
+func ParseFXPair(symbol string) (base, quote string, ok bool) {
+	code, found := strings.CutSuffix(symbol, "=X")
+	if !found {
+		return "", "", false
+	}
+	switch len(code) {
+	case 3:
+		return CurrencyUSD, code, true
+	case 6:
+		return code[:3], code[3:], true
+	}
+	return "", "", false
+}
+
+// FXPairName returns the BASE/QUOTE name of a currency pair symbol, or the symbol itself if it isn't one
+func FXPairName(symbol string) string {
+	base, quote, ok := ParseFXPair(symbol)
+	if !ok {
+		return symbol
+	}
+	return base + "/" + quote
+}
+
+// parseSlashPair converts a BASE/QUOTE currency pair such as USD/KRW into its Yahoo symbol
+func parseSlashPair(symbol string) (string, bool) {
+	base, quote, found := strings.Cut(symbol, "/")
+	if !found || len(base) != 3 || len(quote) != 3 {
+		return "", false
+	}
+	for _, r := range base + quote {
+		if r < 'A' || r > 'Z' {
+			return "", false
+		}
+	}
+	return FXSymbol(base, quote), true
+}
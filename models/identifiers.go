@@ -0,0 +1,114 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Instrument links a provider ticker to its ISIN and CUSIP identifiers
+type Instrument struct {
+	Symbol     string    `bson:"symbol" json:"symbol"`
+	ISIN       string    `bson:"isin,omitempty" json:"isin,omitempty"`
+	CUSIP      string    `bson:"cusip,omitempty" json:"cusip,omitempty"`
+	Name       string    `bson:"name" json:"name"`
+	Exchange   string    `bson:"exchange" json:"exchange"`
+	ResolvedAt time.Time `bson:"resolvedAt" json:"resolvedAt"`
+}
+
+// SymbolMatch is a single result of a provider symbol search
+type SymbolMatch struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Exchange string `json:"exchange"`
+	Type     string `json:"type"`
+}
+
+// IsISIN reports whether a value is a well-formed ISIN with a valid check digit
+func IsISIN(value string) bool {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) != 12 || !isUpperLetter(rune(value[0])) || !isUpperLetter(rune(value[1])) {
+		return false
+	}
+
+	// Letters expand to two digits (A=10 … Z=35) before the Luhn check
+	var digits []int
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = append(digits, int(r-'0'))
+		case isUpperLetter(r):
+			n := int(r-'A') + 10
+			digits = append(digits, n/10, n%10)
+		default:
+			return false
+		}
+	}
+
+	sum := 0
+	for i := range digits {
+		d := digits[len(digits)-1-i]
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// IsCUSIP reports whether a value is a well-formed CUSIP with a valid check digit
+func IsCUSIP(value string) bool {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if len(value) != 9 || value[8] < '0' || value[8] > '9' {
+		return false
+	}
+
+	check, ok := cusipCheckDigit(value[:8])
+	return ok && check == int(value[8]-'0')
+}
+
+// CUSIPToISIN converts a US CUSIP into the equivalent ISIN
+func CUSIPToISIN(cusip string) string {
+	cusip = strings.ToUpper(strings.TrimSpace(cusip))
+	for check := 0; check <= 9; check++ {
+		isin := "US" + cusip + string(rune('0'+check))
+		if IsISIN(isin) {
+			return isin
+		}
+	}
+	return ""
+}
+
+// cusipCheckDigit computes the check digit of the first eight CUSIP characters
+func cusipCheckDigit(base string) (int, bool) {
+	sum := 0
+	for i, r := range base {
+		var v int
+		switch {
+		case r >= '0' && r <= '9':
+			v = int(r - '0')
+		case isUpperLetter(r):
+			v = int(r-'A') + 10
+		case r == '*':
+			v = 36
+		case r == '@':
+			v = 37
+		case r == '#':
+			v = 38
+		default:
+			return 0, false
+		}
+		if i%2 == 1 {
+			v *= 2
+		}
+		sum += v/10 + v%10
+	}
+	return (10 - sum%10) % 10, true
+}
+
+// isUpperLetter reports whether r is an ASCII upper-case letter
+func isUpperLetter(r rune) bool {
+	return r >= 'A' && r <= 'Z'
+}
@@ -0,0 +1,71 @@
+package models
+
+import "testing"
+
+func TestIsISIN(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"US0378331005", true}, // Apple
+		{"us0378331005 ", true},
+		{"DE0007164600", true}, // SAP
+		{"KR7005930003", true}, // Samsung Electronics
+		{"GB0002634946", true}, // BAE Systems
+		{"US0378331006", false},
+		{"US037833100", false},
+		{"1S0378331005", false},
+		{"US03783310-5", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := IsISIN(tt.value); got != tt.want {
+				t.Errorf("IsISIN(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsCUSIP(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"037833100", true}, // Apple
+		{"594918104", true}, // Microsoft
+		{"38259P508", true}, // Google, with a letter
+		{"38259p508", true},
+		{"037833101", false},
+		{"03783310", false},
+		{"03783310X", false},
+		{"0378-3100", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := IsCUSIP(tt.value); got != tt.want {
+				t.Errorf("IsCUSIP(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCUSIPToISIN(t *testing.T) {
+	tests := []struct {
+		cusip string
+		want  string
+	}{
+		{"037833100", "US0378331005"},
+		{"594918104", "US5949181045"},
+		{"38259P508", "US38259P5089"},
+	}
+
+	for _, tt := range tests {
+		if got := CUSIPToISIN(tt.cusip); got != tt.want {
+			t.Errorf("CUSIPToISIN(%q) = %q, want %q", tt.cusip, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import "strings"
+
+// indexExchanges maps non-US Yahoo index symbols to the exchange whose session they follow
+var indexExchanges = map[string]string{
+	"^KS11":  ExchangeKRX,
+	"^KQ11":  ExchangeKRX,
+	"^N225":  ExchangeTSE,
+	"^FTSE":  ExchangeLSE,
+	"^GDAXI": ExchangeXetra,
+	"^FCHI":  ExchangeEuronext,
+}
+
+// indexNames are the display names of common indices
+var indexNames = map[string]string{
+	"^GSPC":  "S&P 500",
+	"^DJI":   "Dow Jones",
+	"^IXIC":  "Nasdaq",
+	"^RUT":   "Russell 2000",
+	"^VIX":   "VIX",
+	"^KS11":  "KOSPI",
+	"^KQ11":  "KOSDAQ",
+	"^N225":  "Nikkei 225",
+	"^FTSE":  "FTSE 100",
+	"^GDAXI": "DAX",
+	"^FCHI":  "CAC 40",
+}
+
+// IsIndex reports whether a Yahoo symbol is a market index such as ^GSPC
+func IsIndex(symbol string) bool {
+	return strings.HasPrefix(symbol, "^")
+}
+
+// IndexName returns the display name of an index, or the symbol itself for unknown ones
+func IndexName(symbol string) string {
+	if name, ok := indexNames[symbol]; ok {
+		return name
+	}
+	return symbol
+}
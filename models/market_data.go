@@ -0,0 +1,105 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// ShortInterest is a short interest snapshot for a settlement date
+type ShortInterest struct {
+	Symbol         string    `bson:"symbol" json:"symbol"`
+	SettlementDate time.Time `bson:"settlementDate" json:"settlementDate"`
+	ShortInterest  int64     `bson:"shortInterest" json:"shortInterest"`
+	AvgDailyVolume int64     `bson:"avgDailyVolume" json:"avgDailyVolume"`
+	DaysToCover    float64   `bson:"daysToCover" json:"daysToCover"`
+	FetchedAt      time.Time `bson:"fetchedAt" json:"fetchedAt"`
+}
+
+// ShortInterestSymbol returns the symbol FINRA publishes a Yahoo symbol's short interest under, such as BRK.B for
+// BRK-B, or false for symbols without it: indices, currencies, crypto and listings outside the US
+func ShortInterestSymbol(symbol string) (string, bool) {
+	if IsIndex(symbol) || AssetClassOf(symbol) != AssetEquity || ExchangeOf(symbol) != ExchangeUS {
+		return "", false
+	}
+	// Yahoo suffixes of exchanges without their own session, such as .TO or .HK
+	if strings.Contains(symbol, ".") {
+		return "", false
+	}
+	return strings.ReplaceAll(symbol, "-", "."), true
+}
+
+// Analyst rating actions
+const (
+	RatingUpgrade   = "upgrade"
+	RatingDowngrade = "downgrade"
+)
+
+// AnalystRating is an analyst rating or price target change published for a symbol
+type AnalystRating struct {
+	Symbol      string    `bson:"symbol" json:"symbol"`
+	Firm        string    `bson:"firm" json:"firm"`
+	Action      string    `bson:"action" json:"action"`
+	FromGrade   string    `bson:"fromGrade" json:"fromGrade"`
+	ToGrade     string    `bson:"toGrade" json:"toGrade"`
+	PriceTarget float64   `bson:"priceTarget,omitempty" json:"priceTarget,omitempty"`
+	URL         string    `bson:"url,omitempty" json:"url,omitempty"`
+	PublishedAt time.Time `bson:"publishedAt" json:"publishedAt"`
+}
+
+// Insider transaction types from SEC Form 4 transaction codes
+const (
+	InsiderPurchase = "P"
+	InsiderSale     = "S"
+)
+
+// InsiderTrade is an insider purchase or sale reported on SEC Form 4
+type InsiderTrade struct {
+	Symbol          string    `bson:"symbol" json:"symbol"`
+	Insider         string    `bson:"insider" json:"insider"`
+	Position        string    `bson:"position" json:"position"`
+	TransactionType string    `bson:"transactionType" json:"transactionType"`
+	Shares          float64   `bson:"shares" json:"shares"`
+	Price           float64   `bson:"price" json:"price"`
+	TransactionDate time.Time `bson:"transactionDate" json:"transactionDate"`
+	FilingDate      time.Time `bson:"filingDate" json:"filingDate"`
+	FilingURL       string    `bson:"filingUrl" json:"filingUrl"`
+}
+
+// Value returns the dollar value of the transaction
+func (t InsiderTrade) Value() float64 {
+	return t.Shares * t.Price
+}
+
+// ImpactHigh marks economic events that typically move the market
+const ImpactHigh = "High"
+
+// EconomicEvent is a scheduled macro release such as CPI, an FOMC decision or non-farm payrolls
+type EconomicEvent struct {
+	Country   string    `bson:"country" json:"country"`
+	Event     string    `bson:"event" json:"event"`
+	Impact    string    `bson:"impact" json:"impact"`
+	Time      time.Time `bson:"time" json:"time"`
+	Previous  *float64  `bson:"previous,omitempty" json:"previous,omitempty"`
+	Estimate  *float64  `bson:"estimate,omitempty" json:"estimate,omitempty"`
+	Actual    *float64  `bson:"actual,omitempty" json:"actual,omitempty"`
+	Reminded  bool      `bson:"reminded" json:"reminded"`
+	FetchedAt time.Time `bson:"fetchedAt" json:"fetchedAt"`
+}
+
+// Corporate event types
+const (
+	CorporateEarnings   = "earnings"
+	CorporateExDividend = "exdividend"
+)
+
+// CorporateEvent is a scheduled earnings release or ex-dividend date of a symbol
+type CorporateEvent struct {
+	Symbol      string    `bson:"symbol" json:"symbol"`
+	Type        string    `bson:"type" json:"type"`
+	Date        time.Time `bson:"date" json:"date"`
+	Timing      string    `bson:"timing,omitempty" json:"timing,omitempty"` // "bmo" before open or "amc" after close, for earnings
+	EPSEstimate *float64  `bson:"epsEstimate,omitempty" json:"epsEstimate,omitempty"`
+	Dividend    float64   `bson:"dividend,omitempty" json:"dividend,omitempty"`
+	PaymentDate time.Time `bson:"paymentDate,omitempty" json:"paymentDate,omitempty"`
+	FetchedAt   time.Time `bson:"fetchedAt" json:"fetchedAt"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "testing"
+
+func TestShortInterestSymbol(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   string
+		ok     bool
+	}{
+		{"AAPL", "AAPL", true},
+		{"BRK-B", "BRK.B", true},
+		{"BF-B", "BF.B", true},
+		{"005930.KS", "", false},
+		{"247540.KQ", "", false},
+		{"VOD.L", "", false},
+		{"SHOP.TO", "", false},
+		{"^GSPC", "", false},
+		{"EURUSD=X", "", false},
+		{"BTC-USD", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			got, ok := ShortInterestSymbol(tt.symbol)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ShortInterestSymbol(%s) = %q, %v, want %q, %v", tt.symbol, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
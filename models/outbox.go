@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Outbox message methods, matching the Messenger method that sends them
+const (
+	OutboxReport = "report"
+	OutboxAlerts = "alerts"
+	OutboxText   = "text"
+)
+
+// OutboxMessage is an outgoing message persisted before it is sent, so a failed send can be retried later.
+// Payload holds the JSON of the quotes, alerts or text; ChatID is empty for broadcast-only channels
+type OutboxMessage struct {
+	ID          string     `bson:"_id" json:"id"`
+	Channel     string     `bson:"channel,omitempty" json:"channel,omitempty"`
+	ChatID      string     `bson:"chatId,omitempty" json:"chatId,omitempty"`
+	AlertStyle  AlertStyle `bson:"alertStyle,omitempty" json:"alertStyle,omitempty"`
+	Method      string     `bson:"method" json:"method"`
+	Payload     string     `bson:"payload" json:"payload"`
+	CreatedAt   time.Time  `bson:"createdAt" json:"createdAt"`
+	Attempts    int        `bson:"attempts" json:"attempts"`
+	LastError   string     `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	LockedUntil time.Time  `bson:"lockedUntil" json:"lockedUntil"`
+	DeliveredAt *time.Time `bson:"deliveredAt,omitempty" json:"deliveredAt,omitempty"`
+	// Set when the message won't be retried: the service rejected it, or it is an alert gone stale
+	DeadAt *time.Time `bson:"deadAt,omitempty" json:"deadAt,omitempty"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// rangeDays is the lookback of the highs and lows in period summaries
+const rangeDays = 30
+
+// PeriodMove is a symbol's change over a summary period, from the last close before it to the latest close
+type PeriodMove struct {
+	Symbol        string
+	Start         float64
+	End           float64
+	PercentChange float64
+	RangeHigh     bool // The latest close is the highest of the last 30 days
+	RangeLow      bool // The latest close is the lowest of the last 30 days
+}
+
+// MeasurePeriod returns a symbol's move since from, given its chronologically sorted closes,
+// or false when there is no close before the period or none in it
+func MeasurePeriod(symbol string, points []PricePoint, from time.Time) (PeriodMove, bool) {
+	start := -1
+	for i, point := range points {
+		if point.Timestamp.After(from) {
+			break
+		}
+		start = i
+	}
+	last := len(points) - 1
+	if start < 0 || start == last || points[start].Close == 0 {
+		return PeriodMove{}, false
+	}
+
+	move := PeriodMove{
+		Symbol: symbol,
+		Start:  points[start].Close,
+		End:    points[last].Close,
+	}
+	move.PercentChange = (move.End - move.Start) / move.Start * 100
+
+	// Only a full range of earlier closes makes a new high or low meaningful
+	rangeStart := points[last].Timestamp.AddDate(0, 0, -rangeDays)
+	if points[0].Timestamp.After(rangeStart) {
+		return move, true
+	}
+	move.RangeHigh, move.RangeLow = true, true
+	for _, point := range points[:last] {
+		if point.Timestamp.Before(rangeStart) {
+			continue
+		}
+		if point.Close >= move.End {
+			move.RangeHigh = false
+		}
+		if point.Close <= move.End {
+			move.RangeLow = false
+		}
+	}
+	return move, true
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Holding is a position in a symbol, with the average price paid per share in the symbol's currency
+type Holding struct {
+	Symbol    string  `bson:"symbol" json:"symbol"`
+	Shares    float64 `bson:"shares" json:"shares"`
+	CostBasis float64 `bson:"costBasis" json:"costBasis"`
+}
+
+// Portfolio is the set of holdings a chat tracks
+type Portfolio struct {
+	ChatID    string    `bson:"chatId" json:"chatId"`
+	Holdings  []Holding `bson:"holdings" json:"holdings"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Holding returns the position in a symbol, if the portfolio has one
+func (p Portfolio) Holding(symbol string) (Holding, bool) {
+	i := slices.IndexFunc(p.Holdings, func(h Holding) bool { return h.Symbol == symbol })
+	if i < 0 {
+		return Holding{}, false
+	}
+	return p.Holdings[i], true
+}
+
+// Buy adds shares bought at a price, averaging the cost basis with the shares already held
+func (p *Portfolio) Buy(symbol string, shares, price float64) Holding {
+	i := slices.IndexFunc(p.Holdings, func(h Holding) bool { return h.Symbol == symbol })
+	if i < 0 {
+		holding := Holding{Symbol: symbol, Shares: shares, CostBasis: price}
+		p.Holdings = append(p.Holdings, holding)
+		p.sort()
+		return holding
+	}
+
+	h := &p.Holdings[i]
+	h.CostBasis = (h.Shares*h.CostBasis + shares*price) / (h.Shares + shares)
+	h.Shares += shares
+	return *h
+}
+
+// shareEpsilon absorbs the floating-point residue of fractional share arithmetic, so selling 0.1 shares three
+// times out of 0.3 closes the position
+const shareEpsilon = 1e-9
+
+// Sell removes shares from a position, dropping it once none are left, and reports whether the
+// position held that many shares
+func (p *Portfolio) Sell(symbol string, shares float64) (Holding, bool) {
+	i := slices.IndexFunc(p.Holdings, func(h Holding) bool { return h.Symbol == symbol })
+	if i < 0 || p.Holdings[i].Shares < shares-shareEpsilon {
+		return Holding{}, false
+	}
+
+	h := &p.Holdings[i]
+	h.Shares -= shares
+	if h.Shares < shareEpsilon {
+		h.Shares = 0
+	}
+	sold := *h
+	if h.Shares == 0 {
+		p.Holdings = slices.Delete(p.Holdings, i, i+1)
+	}
+	return sold, true
+}
+
+// Symbols lists the held symbols
+func (p Portfolio) Symbols() []string {
+	symbols := make([]string, 0, len(p.Holdings))
+	for _, h := range p.Holdings {
+		symbols = append(symbols, h.Symbol)
+	}
+	return symbols
+}
+
+// sort keeps holdings in symbol order
+func (p *Portfolio) sort() {
+	slices.SortFunc(p.Holdings, func(a, b Holding) int { return strings.Compare(a.Symbol, b.Symbol) })
+}
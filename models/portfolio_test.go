@@ -0,0 +1,41 @@
+package models
+
+import "testing"
+
+func TestPortfolioSell(t *testing.T) {
+	tests := []struct {
+		name   string
+		held   float64
+		sales  []float64
+		ok     bool
+		left   float64
+		closed bool
+	}{
+		{"partial sale", 10, []float64{4}, true, 6, false},
+		{"whole position", 10, []float64{10}, true, 0, true},
+		{"more than held", 10, []float64{10.5}, false, 10, false},
+		{"fractional sales leave no residue", 0.3, []float64{0.1, 0.1, 0.1}, true, 0, true},
+		{"fractional sale of the rest", 0.7, []float64{0.1, 0.6}, true, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Portfolio{Holdings: []Holding{{Symbol: "AAPL", Shares: tt.held, CostBasis: 100}}}
+			ok := true
+			for _, shares := range tt.sales {
+				_, sold := p.Sell("AAPL", shares)
+				ok = ok && sold
+			}
+			if ok != tt.ok {
+				t.Fatalf("Sell ok = %v, want %v", ok, tt.ok)
+			}
+			holding, held := p.Holding("AAPL")
+			if held == tt.closed {
+				t.Fatalf("position kept = %v with %v shares, want closed = %v", held, holding.Shares, tt.closed)
+			}
+			if held && holding.Shares != tt.left {
+				t.Errorf("shares left = %v, want %v", holding.Shares, tt.left)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// TargetDirection is the side of a price level a target fires on
+type TargetDirection string
+
+// Target directions
+const (
+	TargetAbove TargetDirection = "above"
+	TargetBelow TargetDirection = "below"
+)
+
+// PriceTarget is a chat's alert on a symbol's price crossing an absolute level. One-shot targets are
+// removed once they fire; repeating ones re-arm when the price moves back across the level
+type PriceTarget struct {
+	ID          string          `bson:"_id" json:"id"`
+	ChatID      string          `bson:"chatId" json:"chatId"`
+	Symbol      string          `bson:"symbol" json:"symbol"`
+	Direction   TargetDirection `bson:"direction" json:"direction"`
+	Level       float64         `bson:"level" json:"level"`
+	Repeat      bool            `bson:"repeat" json:"repeat"`
+	Armed       bool            `bson:"armed" json:"armed"`
+	StartPrice  float64         `bson:"startPrice,omitempty" json:"startPrice,omitempty"` // Price when the target was set, zero if unknown
+	CreatedAt   time.Time       `bson:"createdAt" json:"createdAt"`
+	TriggeredAt *time.Time      `bson:"triggeredAt,omitempty" json:"triggeredAt,omitempty"`
+}
+
+// Arm sets a new target armed unless its start price is already at or beyond the level, so it fires
+// only once the price crosses the level rather than on the first check
+func (t *PriceTarget) Arm() {
+	t.Armed = t.StartPrice == 0 || !t.Reached(t.StartPrice)
+}
+
+// Reached reports whether a price is at or beyond the target level
+func (t PriceTarget) Reached(price float64) bool {
+	if t.Direction == TargetBelow {
+		return price <= t.Level
+	}
+	return price >= t.Level
+}
@@ -0,0 +1,29 @@
+package models
+
+import "testing"
+
+func TestPriceTargetArm(t *testing.T) {
+	tests := []struct {
+		name       string
+		direction  TargetDirection
+		startPrice float64
+		armed      bool
+	}{
+		{"below an above target", TargetAbove, 190, true},
+		{"already above an above target", TargetAbove, 210, false},
+		{"at the level", TargetAbove, 200, false},
+		{"above a below target", TargetBelow, 210, true},
+		{"already below a below target", TargetBelow, 190, false},
+		{"unknown start price", TargetAbove, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := PriceTarget{Direction: tt.direction, Level: 200, StartPrice: tt.startPrice}
+			target.Arm()
+			if target.Armed != tt.armed {
+				t.Errorf("Armed = %v, want %v", target.Armed, tt.armed)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Priority controls how early and how often a symbol is fetched
+type Priority string
+
+// Supported priority tiers
+const (
+	PriorityHigh   Priority = "high"
+	PriorityNormal Priority = "normal"
+	PriorityLow    Priority = "low"
+)
+
+// SymbolPriorities maps symbols to a non-default priority tier
+var SymbolPriorities = map[string]Priority{}
+
+// priorityRanks orders tiers from first to last fetched
+var priorityRanks = map[Priority]int{
+	PriorityHigh:   0,
+	PriorityNormal: 1,
+	PriorityLow:    2,
+}
+
+// PriorityOf returns the priority tier of a symbol
+func PriorityOf(symbol string) Priority {
+	if priority, ok := SymbolPriorities[symbol]; ok {
+		return priority
+	}
+	return PriorityNormal
+}
+
+// SortByPriority returns the symbols with high-priority ones first, keeping their order within a tier
+func SortByPriority(symbols []string) []string {
+	sorted := slices.Clone(symbols)
+	slices.SortStableFunc(sorted, func(a, b string) int {
+		return priorityRanks[PriorityOf(a)] - priorityRanks[PriorityOf(b)]
+	})
+	return sorted
+}
+
+// GroupByPriority splits symbols by their priority tier, keeping their order
+func GroupByPriority(symbols []string) map[Priority][]string {
+	groups := make(map[Priority][]string)
+	for _, symbol := range symbols {
+		priority := PriorityOf(symbol)
+		groups[priority] = append(groups[priority], symbol)
+	}
+	return groups
+}
+
+// ParseSymbolPriorities parses a "SYMBOL:high,SYMBOL:low" list into a priority map
+func ParseSymbolPriorities(value string) (map[string]Priority, error) {
+	priorities := make(map[string]Priority)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		symbol, tier, found := strings.Cut(entry, ":")
+		priority := Priority(strings.ToLower(strings.TrimSpace(tier)))
+		if _, known := priorityRanks[priority]; !found || strings.TrimSpace(symbol) == "" || !known {
+			return nil, fmt.Errorf("invalid symbol priority entry %q", entry)
+		}
+
+		priorities[strings.ToUpper(strings.TrimSpace(symbol))] = priority
+	}
+	return priorities, nil
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Wait strategies of a scrape profile; any other value is a Go duration to pause after the page loads
+const (
+	WaitVisible = "visible" // Wait until an element matching a price selector is shown
+	WaitLoad    = "load"    // Wait until the page body is loaded
+)
+
+// ScrapeProfile describes how to read quotes from one site's pages: the page URL, the CSS selectors of each field,
+// tried in order until one matches, and how to tell the page is ready
+type ScrapeProfile struct {
+	Name          string   `json:"name"`
+	URL           string   `json:"url"` // Page URL with {symbol} in place of the Yahoo symbol or {google} of the Google Finance one
+	Price         []string `json:"price"`
+	Change        []string `json:"change"`
+	ChangePercent []string `json:"changePercent"`
+	Volume        []string `json:"volume"`
+	Wait          string   `json:"wait"`
+}
+
+// Validate checks that a profile has a name, a URL template for the symbol, a price selector and a known wait
+func (p ScrapeProfile) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("scrape profile without a name")
+	}
+	if !strings.Contains(p.URL, "{symbol}") && !strings.Contains(p.URL, "{google}") {
+		return fmt.Errorf("scrape profile %s: url must contain {symbol} or {google}", p.Name)
+	}
+	if len(p.Price) == 0 {
+		return fmt.Errorf("scrape profile %s: no price selector", p.Name)
+	}
+	if _, err := p.WaitDelay(); err != nil {
+		return fmt.Errorf("scrape profile %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// WaitDelay returns the pause after loading the page when the wait strategy is a duration, and 0 otherwise
+func (p ScrapeProfile) WaitDelay() (time.Duration, error) {
+	switch p.Wait {
+	case "", WaitVisible, WaitLoad:
+		return 0, nil
+	}
+	delay, err := time.ParseDuration(p.Wait)
+	if err != nil || delay <= 0 {
+		return 0, fmt.Errorf("invalid wait %q, expected %s, %s or a duration such as 3s", p.Wait, WaitVisible, WaitLoad)
+	}
+	return delay, nil
+}
+
+// DefaultScrapeProfiles read Yahoo Finance quote pages, first with the current layout's selectors and then with the
+// older fin-streamer ones, and fall back to Google Finance. Google shows the day change without its sign, so only the
+// price is read from it
+func DefaultScrapeProfiles() []ScrapeProfile {
+	return []ScrapeProfile{{
+		Name: "yahoo",
+		URL:  "https://finance.yahoo.com/quote/{symbol}/",
+		Price: []string{
+			`span[data-testid="qsp-price"]`,
+			`fin-streamer[data-field="regularMarketPrice"]`,
+		},
+		Change: []string{
+			`span[data-testid="qsp-price-change"]`,
+			`fin-streamer[data-field="regularMarketChange"]`,
+		},
+		ChangePercent: []string{
+			`span[data-testid="qsp-price-change-percent"]`,
+			`fin-streamer[data-field="regularMarketChangePercent"]`,
+		},
+		Volume: []string{
+			`fin-streamer[data-field="regularMarketVolume"]`,
+		},
+		Wait: WaitVisible,
+	}, {
+		Name:  "google",
+		URL:   "https://www.google.com/finance/quote/{google}",
+		Price: []string{`div.YMlKec.fxKbKc`},
+		// A ticker listed on another exchange loads a page without a price, so don't wait for one to show
+		Wait: WaitLoad,
+	}}
+}
+
+// googleExchanges maps Yahoo exchange suffixes to Google Finance exchange codes
+var googleExchanges = map[string]string{
+	".KS": "KRX",
+	".KQ": "KOSDAQ",
+	".T":  "TYO",
+	".L":  "LON",
+	".DE": "ETR",
+	".PA": "EPA",
+	".AS": "AMS",
+}
+
+// googleIndices maps Yahoo index symbols to their Google Finance tickers
+var googleIndices = map[string]string{
+	"^GSPC": ".INX:INDEXSP",
+	"^DJI":  ".DJI:INDEXDJX",
+	"^IXIC": ".IXIC:INDEXNASDAQ",
+	"^KS11": "KOSPI:KRX",
+	"^N225": "NI225:INDEXNIKKEI",
+	"^FTSE": "UKX:INDEXFTSE",
+}
+
+// GoogleFinanceTickers returns the Google Finance tickers a Yahoo symbol may be listed under, most likely first.
+// Yahoo leaves out the exchange of US equities, so they get one ticker per US exchange; unknown indices get none
+func GoogleFinanceTickers(symbol string) []string {
+	if IsIndex(symbol) {
+		if ticker, ok := googleIndices[symbol]; ok {
+			return []string{ticker}
+		}
+		return nil
+	}
+	switch AssetClassOf(symbol) {
+	case AssetFX:
+		if base, quote, ok := ParseFXPair(symbol); ok {
+			return []string{base + "-" + quote}
+		}
+		return nil
+	case AssetCrypto:
+		return []string{symbol}
+	}
+
+	for suffix, exchange := range googleExchanges {
+		if code, found := strings.CutSuffix(symbol, suffix); found {
+			return []string{code + ":" + exchange}
+		}
+	}
+	code := strings.ReplaceAll(symbol, "-", ".") // Class shares: BRK-B on Yahoo is BRK.B on Google
+	return []string{code + ":NASDAQ", code + ":NYSE", code + ":NYSEARCA"}
+}
+
+// MergeScrapeProfiles puts the configured profiles first and keeps the defaults they don't replace by name after them
+func MergeScrapeProfiles(configured, defaults []ScrapeProfile) []ScrapeProfile {
+	merged := append([]ScrapeProfile(nil), configured...)
+	for _, profile := range defaults {
+		replaced := false
+		for _, custom := range configured {
+			if strings.EqualFold(custom.Name, profile.Name) {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, profile)
+		}
+	}
+	return merged
+}
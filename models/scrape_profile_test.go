@@ -0,0 +1,67 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGoogleFinanceTickers(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   []string
+	}{
+		{"AAPL", []string{"AAPL:NASDAQ", "AAPL:NYSE", "AAPL:NYSEARCA"}},
+		{"BRK-B", []string{"BRK.B:NASDAQ", "BRK.B:NYSE", "BRK.B:NYSEARCA"}},
+		{"005930.KS", []string{"005930:KRX"}},
+		{"247540.KQ", []string{"247540:KOSDAQ"}},
+		{"VOD.L", []string{"VOD:LON"}},
+		{"7203.T", []string{"7203:TYO"}},
+		{"^GSPC", []string{".INX:INDEXSP"}},
+		{"^KS11", []string{"KOSPI:KRX"}},
+		{"^VIX", nil},
+		{"EURUSD=X", []string{"EUR-USD"}},
+		{"BTC-USD", []string{"BTC-USD"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			if got := GoogleFinanceTickers(tt.symbol); !slices.Equal(got, tt.want) {
+				t.Errorf("GoogleFinanceTickers(%s) = %v, want %v", tt.symbol, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeScrapeProfiles(t *testing.T) {
+	defaults := DefaultScrapeProfiles()
+	custom := ScrapeProfile{Name: "marketwatch", URL: "https://www.marketwatch.com/investing/stock/{symbol}"}
+	yahoo := ScrapeProfile{Name: "Yahoo", URL: "https://finance.yahoo.com/quote/{symbol}/history"}
+
+	tests := []struct {
+		name       string
+		configured []ScrapeProfile
+		want       []string // Profile names in order
+		wantURL    string   // URL of the first profile
+	}{
+		{"nothing configured", nil, []string{"yahoo", "google"}, defaults[0].URL},
+		{"custom profile goes first", []ScrapeProfile{custom}, []string{"marketwatch", "yahoo", "google"}, custom.URL},
+		{"built-in replaced by name, ignoring case", []ScrapeProfile{yahoo}, []string{"Yahoo", "google"}, yahoo.URL},
+		{"configured order kept", []ScrapeProfile{yahoo, custom}, []string{"Yahoo", "marketwatch", "google"}, yahoo.URL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := MergeScrapeProfiles(tt.configured, defaults)
+			var names []string
+			for _, profile := range merged {
+				names = append(names, profile.Name)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("profiles %v, want %v", names, tt.want)
+			}
+			if merged[0].URL != tt.wantURL {
+				t.Errorf("first profile URL = %s, want %s", merged[0].URL, tt.wantURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignalAction is the recommendation of a composite trading signal
+type SignalAction string
+
+// Signal actions
+const (
+	SignalBuy   SignalAction = "buy"
+	SignalWatch SignalAction = "watch"
+	SignalSell  SignalAction = "sell"
+)
+
+// Signal is a composite score built from several indicators, persisted for later accuracy review
+type Signal struct {
+	Symbol string       `bson:"symbol" json:"symbol"`
+	Date   string       `bson:"date" json:"date"` // Trading day the signal was evaluated for (YYYY-MM-DD)
+	Action SignalAction `bson:"action" json:"action"`
+	// Weighted score between -1 (strong sell) and 1 (strong buy)
+	Score float64 `bson:"score" json:"score"`
+	// Individual indicator scores between -1 and 1, keyed by component name
+	Components map[string]float64 `bson:"components" json:"components"`
+	Price      float64            `bson:"price" json:"price"`
+	RSI        float64            `bson:"rsi,omitempty" json:"rsi,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// Signal components
+const (
+	SignalTrend     = "trend"
+	SignalRSI       = "rsi"
+	SignalVolume    = "volume"
+	SignalSentiment = "sentiment"
+)
+
+// SignalWeights holds the relative weight of each signal component
+type SignalWeights map[string]float64
+
+// DefaultSignalWeights returns the default component weights
+func DefaultSignalWeights() SignalWeights {
+	return SignalWeights{
+		SignalTrend:     0.35,
+		SignalRSI:       0.25,
+		SignalVolume:    0.15,
+		SignalSentiment: 0.25,
+	}
+}
+
+// ParseSignalWeights parses a "trend:0.4,rsi:0.3" list, keeping the defaults for components not listed
+func ParseSignalWeights(value string) (SignalWeights, error) {
+	weights := DefaultSignalWeights()
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, weightStr, found := strings.Cut(entry, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, known := weights[name]; !found || !known {
+			return nil, fmt.Errorf("invalid signal weight entry %q", entry)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid signal weight entry %q", entry)
+		}
+		weights[name] = weight
+	}
+	return weights, nil
+}
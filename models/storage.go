@@ -0,0 +1,8 @@
+package models
+
+// Storage drivers for price history and the watchlist
+const (
+	StorageMongoDB  = "mongodb"
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite"
+)
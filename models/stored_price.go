@@ -0,0 +1,51 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// StoredPrice is a price field of a stored document; prices are written as numbers, while records
+// written before prices were typed hold them as strings, which are still read
+type StoredPrice float64
+
+// UnmarshalBSONValue decodes a price stored as a double, an integer or a legacy numeric string
+func (p *StoredPrice) UnmarshalBSONValue(typ byte, data []byte) error {
+	raw := bson.RawValue{Type: bson.Type(typ), Value: data}
+	switch raw.Type {
+	case bson.TypeDouble:
+		value, ok := raw.DoubleOK()
+		if !ok {
+			return fmt.Errorf("invalid stored price: malformed double")
+		}
+		*p = StoredPrice(value)
+	case bson.TypeInt32:
+		value, ok := raw.Int32OK()
+		if !ok {
+			return fmt.Errorf("invalid stored price: malformed int32")
+		}
+		*p = StoredPrice(value)
+	case bson.TypeInt64:
+		value, ok := raw.Int64OK()
+		if !ok {
+			return fmt.Errorf("invalid stored price: malformed int64")
+		}
+		*p = StoredPrice(value)
+	case bson.TypeString:
+		text, ok := raw.StringValueOK()
+		if !ok {
+			return fmt.Errorf("invalid stored price: malformed string")
+		}
+		value, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(text), ",", ""), 64)
+		if err != nil {
+			return fmt.Errorf("invalid stored price %q: %w", text, err)
+		}
+		*p = StoredPrice(value)
+	default:
+		return fmt.Errorf("invalid stored price: BSON type %s", raw.Type)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestStoredPriceUnmarshalBSONValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    StoredPrice
+		wantErr bool
+	}{
+		{"double", 187.5, 187.5, false},
+		{"int32", int32(72000), 72000, false},
+		{"int64", int64(1_250_000), 1_250_000, false},
+		{"legacy string", "1,234.56", 1234.56, false},
+		{"legacy string with spaces", " 42 ", 42, false},
+		{"non-numeric string", "n/a", 0, true},
+		{"boolean", true, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := bson.Marshal(bson.D{{Key: "price", Value: tt.value}})
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+
+			var doc struct {
+				Price StoredPrice `bson:"price"`
+			}
+			err = bson.Unmarshal(data, &doc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && doc.Price != tt.want {
+				t.Errorf("Price = %v, want %v", doc.Price, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoredPriceMalformedValue(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  bson.Type
+		data []byte
+	}{
+		{"short double", bson.TypeDouble, []byte{1, 2, 3}},
+		{"short int32", bson.TypeInt32, []byte{1}},
+		{"short int64", bson.TypeInt64, []byte{1, 2, 3, 4}},
+		{"short string", bson.TypeString, []byte{9, 0, 0, 0, 'a'}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p StoredPrice
+			if err := p.UnmarshalBSONValue(byte(tt.typ), tt.data); err == nil {
+				t.Errorf("UnmarshalBSONValue accepted %v, want an error", tt.data)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Streak is a run of consecutive closes moving in the same direction
+type Streak struct {
+	Symbol        string
+	Days          int       // Number of consecutive up or down closes
+	Up            bool      // Direction of the streak
+	Start         time.Time // Close the streak started from
+	PercentChange float64   // Change from the start close to the latest close
+}
+
+// CurrentStreak returns the streak ending at the latest of the chronologically sorted closes;
+// unchanged closes end a streak
+func CurrentStreak(symbol string, points []PricePoint) Streak {
+	streak := Streak{Symbol: symbol}
+	if len(points) < 2 {
+		return streak
+	}
+
+	last := len(points) - 1
+	i := last
+	for ; i > 0; i-- {
+		diff := points[i].Close - points[i-1].Close
+		if diff == 0 || (streak.Days > 0 && (diff > 0) != streak.Up) {
+			break
+		}
+		streak.Up = diff > 0
+		streak.Days++
+	}
+
+	if streak.Days == 0 {
+		return streak
+	}
+	streak.Start = points[i].Timestamp
+	streak.PercentChange = (points[last].Close - points[i].Close) / points[i].Close * 100
+	return streak
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCurrentStreak(t *testing.T) {
+	tests := []struct {
+		name    string
+		closes  []float64
+		days    int
+		up      bool
+		percent float64
+	}{
+		{"no points", nil, 0, false, 0},
+		{"single point", []float64{100}, 0, false, 0},
+		{"one up day", []float64{100, 110}, 1, true, 10},
+		{"three down days", []float64{100, 90, 80, 72}, 3, false, -28},
+		{"flat close ends the streak", []float64{100, 110, 110, 121}, 1, true, 10},
+		{"flat last close", []float64{100, 110, 110}, 0, false, 0},
+		{"direction flip", []float64{100, 90, 99, 108.9}, 2, true, 21},
+	}
+
+	start := time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var points []PricePoint
+			for i, close := range tt.closes {
+				points = append(points, PricePoint{Timestamp: start.AddDate(0, 0, i), Close: close})
+			}
+
+			streak := CurrentStreak("AAPL", points)
+			if streak.Days != tt.days || streak.Up != tt.up {
+				t.Fatalf("CurrentStreak = %d days, up %v, want %d days, up %v", streak.Days, streak.Up, tt.days, tt.up)
+			}
+			if diff := streak.PercentChange - tt.percent; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("PercentChange = %v, want %v", streak.PercentChange, tt.percent)
+			}
+			if tt.days > 0 && !streak.Start.Equal(points[len(points)-1-tt.days].Timestamp) {
+				t.Errorf("Start = %v, want the close before the streak", streak.Start)
+			}
+		})
+	}
+}
@@ -1,22 +1,62 @@
 package models
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
-// PriceResult contains stock symbol and price information
+// PriceResult is the outcome of fetching a symbol: its quote, or the error that prevented it
 type PriceResult struct {
 	Symbol string `json:"symbol"`
-	Price  string `json:"price"`
+	Quote  Quote  `json:"quote"`
 	Error  error  `json:"-"` // Used when an error occurs
 }
 
+// Quote is a detailed snapshot of a symbol's market data
+type Quote struct {
+	Symbol        string    `json:"symbol"`
+	Price         float64   `json:"price"`
+	Change        float64   `json:"change"`
+	ChangePercent float64   `json:"changePercent"`
+	Open          float64   `json:"open,omitempty"`  // Session open reported by the source, zero when it has none
+	Stale         bool      `json:"stale,omitempty"` // Reference rate of an earlier day rather than a live price
+	Volume        int64     `json:"volume"`
+	Currency      string    `json:"currency"`
+	Source        string    `json:"source,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// PricePoint is a single daily closing price used for history and charts
+type PricePoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Close     float64   `json:"close"`
+	Volume    int64     `json:"volume"`
+}
+
 // MongoDTO is a structure for price information to be stored in MongoDB
 type MongoDTO struct {
-	Symbol    string    `bson:"symbol"`
-	Price     string    `bson:"price"`
-	Timestamp time.Time `bson:"timestamp"`
-	IsClosing bool      `bson:"isClosing"`
+	Symbol    string      `bson:"symbol"`
+	Price     StoredPrice `bson:"price"`
+	Source    string      `bson:"source,omitempty"` // Price source that provided the price
+	Volume    int64       `bson:"volume,omitempty"` // Session volume at the time of the price
+	Timestamp time.Time   `bson:"timestamp"`
+	IsClosing bool        `bson:"isClosing"`
+}
+
+// NewPriceRecord builds the stored record of a quote fetched at the given time
+func NewPriceRecord(quote Quote, isClosing bool, at time.Time) MongoDTO {
+	return MongoDTO{
+		Symbol:    quote.Symbol,
+		Price:     StoredPrice(quote.Price),
+		Source:    quote.Source,
+		Volume:    quote.Volume,
+		Timestamp: at,
+		IsClosing: isClosing,
+	}
 }
 
 // PriceAlert is a structure for price change notifications
@@ -26,6 +66,103 @@ type PriceAlert struct {
 	CurrentPrice  float64   `json:"currentPrice"`
 	PercentChange float64   `json:"percentChange"`
 	Timestamp     time.Time `json:"timestamp"`
+	Volume        int64     `json:"volume,omitempty"`
+	Headline      string    `json:"headline,omitempty"`
+	// What the change is measured against; the previous close unless set
+	Basis  AlertBasis    `json:"basis,omitempty"`
+	Window time.Duration `json:"window,omitempty"` // Lookback of a BasisWindow alert
+}
+
+// AlertBasis is the reference price a price alert's change is measured against
+type AlertBasis string
+
+// Alert bases
+const (
+	BasisPreviousClose AlertBasis = ""
+	BasisOpen          AlertBasis = "open"
+	BasisWindow        AlertBasis = "window"
+)
+
+// ReferenceLabel names the reference price of an alert, e.g. "Previous", "Open" or "60m ago"
+func (a PriceAlert) ReferenceLabel() string {
+	switch a.Basis {
+	case BasisOpen:
+		return "Open"
+	case BasisWindow:
+		return fmt.Sprintf("%.0fm ago", a.Window.Minutes())
+	default:
+		return "Previous"
+	}
+}
+
+// PausedSymbol is a symbol whose fetching was suspended after repeated resolution failures
+type PausedSymbol struct {
+	Symbol    string    `bson:"symbol" json:"symbol"`
+	Failures  int       `bson:"failures" json:"failures"`
+	LastError string    `bson:"lastError" json:"lastError"`
+	PausedAt  time.Time `bson:"pausedAt" json:"pausedAt"`
+}
+
+// SentAlert records when a price alert for a symbol was last sent to a chat
+type SentAlert struct {
+	ChatID string    `bson:"chatId" json:"chatId"`
+	Symbol string    `bson:"symbol" json:"symbol"`
+	SentAt time.Time `bson:"sentAt" json:"sentAt"`
+	// Change that was alerted, in percent, and what it was measured against
+	PercentChange float64    `bson:"percentChange" json:"percentChange"`
+	Basis         AlertBasis `bson:"basis,omitempty" json:"basis,omitempty"`
+}
+
+// GlobalThresholdKey is the symbol under which the global alert threshold is stored
+const GlobalThresholdKey = "*"
+
+// AlertThreshold is a persisted alert threshold override for a symbol or the global default
+type AlertThreshold struct {
+	Symbol    string    `bson:"symbol"`
+	Percent   float64   `bson:"percent"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+// AlertThresholds holds the effective global, per-asset-class and per-symbol alert thresholds
+type AlertThresholds struct {
+	Global  float64
+	Classes map[AssetClass]float64
+	Symbols map[string]float64
+}
+
+// For returns the alert threshold that applies to a symbol: its own, its asset class's, or the global one
+func (t AlertThresholds) For(symbol string) float64 {
+	if percent, ok := t.Symbols[symbol]; ok {
+		return percent
+	}
+	if percent, ok := t.Classes[AssetClassOf(symbol)]; ok {
+		return percent
+	}
+	return t.Global
+}
+
+// ParseSymbolThresholds parses a "SYMBOL:PCT,SYMBOL:PCT" list into per-symbol alert thresholds
+func ParseSymbolThresholds(value string) (map[string]float64, error) {
+	thresholds := make(map[string]float64)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		symbol, percentStr, found := strings.Cut(entry, ":")
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if !found || symbol == "" {
+			return nil, fmt.Errorf("invalid symbol threshold entry %q", entry)
+		}
+
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(percentStr), "%"), 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid threshold in entry %q", entry)
+		}
+		thresholds[symbol] = percent
+	}
+	return thresholds, nil
 }
 
 // Ticker constants
@@ -40,8 +177,15 @@ const (
 	META  = "META"
 )
 
-// Tickers is a list of stock symbols to monitor
-var Tickers = []string{
+// WatchlistEntry is a monitored symbol stored in MongoDB
+type WatchlistEntry struct {
+	Symbol  string    `bson:"symbol" json:"symbol"`
+	AddedBy string    `bson:"addedBy" json:"addedBy"`
+	AddedAt time.Time `bson:"addedAt" json:"addedAt"`
+}
+
+// DefaultTickers seeds the watchlist the first time the bot starts
+var DefaultTickers = []string{
 	AAPL,
 	GOOGL,
 	AMZN,
@@ -52,28 +196,229 @@ var Tickers = []string{
 	META,
 }
 
+// tickers is the list of stock symbols to monitor, loaded from the stored watchlist. The scheduler, command handlers
+// and background jobs read it while a watchlist refresh replaces it, so the list is swapped whole
+var tickers = func() *atomic.Pointer[[]string] {
+	var list atomic.Pointer[[]string]
+	initial := slices.Clone(DefaultTickers)
+	list.Store(&initial)
+	return &list
+}()
+
+// Tickers returns a copy of the monitored symbols
+func Tickers() []string {
+	return slices.Clone(*tickers.Load())
+}
+
+// SetTickers replaces the monitored symbols
+func SetTickers(symbols []string) {
+	symbols = slices.Clone(symbols)
+	tickers.Store(&symbols)
+}
+
+// NormalizeSymbol upper-cases a user supplied symbol and reports whether it looks like a valid ticker.
+// A bare six-digit Korean stock code is taken as a KOSPI listing, as telling it from a KOSDAQ one takes a lookup
+// (chat commands do one, see IsKRXCode); a currency pair written as USD/KRW becomes USDKRW=X
+func NormalizeSymbol(symbol string) (string, bool) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" || len(symbol) > 20 {
+		return "", false
+	}
+	if pair, ok := parseSlashPair(symbol); ok {
+		return pair, true
+	}
+
+	for _, r := range symbol {
+		isLetter := r >= 'A' && r <= 'Z'
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !isDigit && !strings.ContainsRune(".-^=", r) {
+			return "", false
+		}
+	}
+
+	if IsKRXCode(symbol) {
+		symbol += ".KS"
+	}
+	return symbol, true
+}
+
+// IsKRXCode reports whether a symbol is a bare six-digit Korea Exchange stock code such as 005930
+func IsKRXCode(symbol string) bool {
+	if len(symbol) != 6 {
+		return false
+	}
+	for _, r := range symbol {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // Config manages application settings
 type Config struct {
-	MongoURI            string        `json:"mongoUri"`
-	TelegramBotToken    string        `json:"telegramBotToken"`
-	TelegramChatID      string        `json:"telegramChatId"`
-	LineChannelToken    string        `json:"lineChannelToken"`
-	CheckInterval       time.Duration `json:"checkInterval"`
-	FetchTimeout        time.Duration `json:"fetchTimeout"`
-	MaxConcurrency      int           `json:"maxConcurrency"`
-	PriceAlertThreshold float64       `json:"priceAlertThreshold"`
-	TimeZone            string        `json:"timeZone"`
-	CheckHour           int           `json:"checkHour"`
+	MongoURI                 string                       `json:"mongoUri"`
+	StorageDriver            string                       `json:"storageDriver"`
+	PostgresURL              string                       `json:"postgresUrl"`
+	SQLitePath               string                       `json:"sqlitePath"`
+	RedisURL                 string                       `json:"redisUrl"`
+	QuoteCacheTTL            time.Duration                `json:"quoteCacheTtl"`
+	TelegramBotToken         string                       `json:"telegramBotToken"`
+	TelegramChatID           string                       `json:"telegramChatId"`
+	LineChannelToken         string                       `json:"lineChannelToken"`
+	LineChannelSecret        string                       `json:"lineChannelSecret"`
+	TelegramFormat           string                       `json:"telegramFormat"`
+	LineFormat               string                       `json:"lineFormat"`
+	TelegramAlertStyle       string                       `json:"telegramAlertStyle"`
+	LineAlertStyle           string                       `json:"lineAlertStyle"`
+	MessageTemplateDir       string                       `json:"messageTemplateDir"`
+	Language                 string                       `json:"language"`
+	SlackWebhookURL          string                       `json:"slackWebhookUrl"`
+	SlackBotToken            string                       `json:"slackBotToken"`
+	SlackChannel             string                       `json:"slackChannel"`
+	SlackAlertStyle          string                       `json:"slackAlertStyle"`
+	SMTP                     SMTPConfig                   `json:"smtp"`
+	WebhookURLs              []string                     `json:"webhookUrls"`
+	WebhookSecret            string                       `json:"webhookSecret"`
+	NtfyURL                  string                       `json:"ntfyUrl"`
+	NtfyToken                string                       `json:"ntfyToken"`
+	PushoverToken            string                       `json:"pushoverToken"`
+	PushoverUser             string                       `json:"pushoverUser"`
+	PushHighPriorityPercent  float64                      `json:"pushHighPriorityPercent"`
+	Twilio                   TwilioConfig                 `json:"twilio"`
+	Browser                  BrowserConfig                `json:"browser"`
+	ScrapeProfiles           []ScrapeProfile              `json:"scrapeProfiles"`
+	CheckInterval            time.Duration                `json:"checkInterval"`
+	FetchTimeout             time.Duration                `json:"fetchTimeout"`
+	MaxConcurrency           int                          `json:"maxConcurrency"`
+	PriceAlertThreshold      float64                      `json:"priceAlertThreshold"`
+	CryptoAlertThreshold     float64                      `json:"cryptoAlertThreshold"`
+	FXAlertThreshold         float64                      `json:"fxAlertThreshold"`
+	AlertCooldown            time.Duration                `json:"alertCooldown"`
+	AlertStepPercent         float64                      `json:"alertStepPercent"`
+	OpenAlertThreshold       float64                      `json:"openAlertThreshold"`
+	MoveAlertThreshold       float64                      `json:"moveAlertThreshold"`
+	MoveAlertWindow          time.Duration                `json:"moveAlertWindow"`
+	VolumeAlertMultiple      float64                      `json:"volumeAlertMultiple"`
+	SymbolThresholds         map[string]float64           `json:"symbolThresholds"`
+	TimeZone                 string                       `json:"timeZone"`
+	CheckHour                int                          `json:"checkHour"`
+	ClosingTimes             map[string]ClockTime         `json:"closingTimes"`
+	PreMarketWindow          time.Duration                `json:"preMarketWindow"`
+	PostMarketWindow         time.Duration                `json:"postMarketWindow"`
+	SymbolCurrencies         map[string]string            `json:"symbolCurrencies"`
+	SymbolPriorities         map[string]Priority          `json:"symbolPriorities"`
+	AdminUserIDs             []string                     `json:"adminUserIds"`
+	FMPAPIKey                string                       `json:"fmpApiKey"`
+	AlphaVantageAPIKey       string                       `json:"alphaVantageApiKey"`
+	FinnhubAPIKey            string                       `json:"finnhubApiKey"`
+	RealtimeStreaming        bool                         `json:"realtimeStreaming"`
+	PriceSources             []string                     `json:"priceSources"`
+	Tickers                  []string                     `json:"tickers"`
+	ReportFXRates            []string                     `json:"reportFxRates"`
+	EconomicAlertLead        time.Duration                `json:"economicAlertLead"`
+	RealtimeIntervals        map[AssetClass]time.Duration `json:"realtimeIntervals"`
+	DelistFailureLimit       int                          `json:"delistFailureLimit"`
+	FetchFailureAlertPercent float64                      `json:"fetchFailureAlertPercent"`
+	StreakAlertDays          int                          `json:"streakAlertDays"`
+	IntradayRetentionDays    int                          `json:"intradayRetentionDays"`
+	BackfillDays             int                          `json:"backfillDays"`
+	BackfillSources          []string                     `json:"backfillSources"`
+	Crossover                CrossoverConfig              `json:"crossover"`
+	SignalWeights            SignalWeights                `json:"signalWeights"`
+	GoogleSheetsCredentials  string                       `json:"googleSheetsCredentials"`
+	GoogleSheetID            string                       `json:"googleSheetId"`
+	IFTTTWebhookKey          string                       `json:"iftttWebhookKey"`
+	ZapierHookURL            string                       `json:"zapierHookUrl"`
+	MQTT                     MQTTConfig                   `json:"mqtt"`
+	NATSURL                  string                       `json:"natsUrl"`
+	KafkaBrokers             []string                     `json:"kafkaBrokers"`
+	EventTopicPrefix         string                       `json:"eventTopicPrefix"`
+	GrafanaURL               string                       `json:"grafanaUrl"`
+	GrafanaAPIKey            string                       `json:"grafanaApiKey"`
+	GrafanaDashboardUID      string                       `json:"grafanaDashboardUid"`
+	HTTPAddr                 string                       `json:"httpAddr"`
+	PublicURL                string                       `json:"publicUrl"`
+	AdminAPIToken            string                       `json:"adminApiToken"`
+	LogLevel                 string                       `json:"logLevel"`
+	LogFormat                string                       `json:"logFormat"`
+}
+
+// MQTTConfig holds the MQTT broker connection and topic settings
+type MQTTConfig struct {
+	BrokerURL              string `json:"brokerUrl"`
+	Username               string `json:"username"`
+	Password               string `json:"password"`
+	QoS                    byte   `json:"qos"`
+	TopicPrefix            string `json:"topicPrefix"`
+	HomeAssistantDiscovery bool   `json:"homeAssistantDiscovery"`
+	DiscoveryPrefix        string `json:"discoveryPrefix"`
+}
+
+// SMTPConfig holds the mail server and recipients for email delivery
+type SMTPConfig struct {
+	Host     string   `json:"host"`
+	Port     string   `json:"port"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// TwilioConfig holds the Twilio account and phone numbers for texting critical alerts
+type TwilioConfig struct {
+	AccountSID      string   `json:"accountSid"`
+	AuthToken       string   `json:"authToken"`
+	From            string   `json:"from"`
+	To              []string `json:"to"`
+	CriticalPercent float64  `json:"criticalPercent"` // Smallest move in percent that is texted
+	DailyCap        int      `json:"dailyCap"`        // Most texts sent a day, over all numbers
+}
+
+// BrowserConfig bounds the tabs of the headless browser and how long one browser process is kept before it is
+// restarted to release the memory Chrome accumulates
+type BrowserConfig struct {
+	MaxTabs        int           `json:"maxTabs"`        // Tabs open at the same time; further fetches wait for a free tab
+	MaxNavigations int           `json:"maxNavigations"` // Page loads after which the browser is restarted; 0 never restarts on count
+	MaxAge         time.Duration `json:"maxAge"`         // Age after which the browser is restarted; 0 never restarts on age
 }
 
 // DefaultConfig returns default configuration values
 func DefaultConfig() Config {
 	return Config{
-		CheckInterval:       15 * time.Minute,
-		FetchTimeout:        2 * time.Minute,
-		MaxConcurrency:      5,
-		PriceAlertThreshold: 5.0,
-		TimeZone:            "Asia/Seoul",
-		CheckHour:           7,
+		SQLitePath:           "stock-bot.db",
+		QuoteCacheTTL:        2 * time.Minute,
+		CheckInterval:        15 * time.Minute,
+		FetchTimeout:         2 * time.Minute,
+		MaxConcurrency:       5,
+		PriceAlertThreshold:  5.0,
+		CryptoAlertThreshold: 8,
+		FXAlertThreshold:     1,
+		AlertCooldown:        2 * time.Hour,
+		AlertStepPercent:     5,
+		MoveAlertWindow:      time.Hour, // Intraday alerts stay off until their thresholds are set
+		VolumeAlertMultiple:  3,
+		TimeZone:             "Asia/Seoul",
+		CheckHour:            7,
+		ClosingTimes:         DefaultClosingTimes(),
+		RealtimeIntervals: map[AssetClass]time.Duration{
+			AssetEquity: 30 * time.Minute,
+			AssetCrypto: 15 * time.Minute,
+			AssetFX:     30 * time.Minute,
+		},
+		DelistFailureLimit:       5,
+		FetchFailureAlertPercent: 25,
+		StreakAlertDays:          5,
+		PushHighPriorityPercent:  10,
+		Twilio:                   TwilioConfig{CriticalPercent: 10, DailyCap: 10},
+		Browser:                  BrowserConfig{MaxTabs: 4, MaxNavigations: 500, MaxAge: 6 * time.Hour},
+		ScrapeProfiles:           DefaultScrapeProfiles(),
+		BackfillDays:             730,
+		Crossover:                CrossoverConfig{Fast: 20, Slow: 50, Average: AverageSMA},
+		SignalWeights:            DefaultSignalWeights(),
+		MQTT:                     MQTTConfig{TopicPrefix: "stockbot", DiscoveryPrefix: "homeassistant"},
+		EventTopicPrefix:         "stockbot",
+		LogLevel:                 "info",
+		LogFormat:                "text",
 	}
 }
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestNormalizeSymbol(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   string
+		wantOK bool
+	}{
+		{"aapl", "AAPL", true},
+		{" brk-b ", "BRK-B", true},
+		{"005930", "005930.KS", true},
+		{"247540.kq", "247540.KQ", true},
+		{"usd/krw", "USDKRW=X", true},
+		{"^gspc", "^GSPC", true},
+		{"", "", false},
+		{"AAPL;DROP", "", false},
+		{"ABCDEFGHIJKLMNOPQRSTU", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := NormalizeSymbol(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("NormalizeSymbol(%q) = %q, %v, want %q, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestIsKRXCode(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   bool
+	}{
+		{"005930", true},
+		{"247540", true},
+		{"05930", false},
+		{"005930.KS", false},
+		{"AAPL12", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsKRXCode(tt.symbol); got != tt.want {
+			t.Errorf("IsKRXCode(%q) = %v, want %v", tt.symbol, got, tt.want)
+		}
+	}
+}
+
+func TestParseSymbolThresholds(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    map[string]float64
+		wantErr bool
+	}{
+		{"AAPL:2,TSLA:8", map[string]float64{"AAPL": 2, "TSLA": 8}, false},
+		{" aapl : 2.5% , ,btc-usd:10", map[string]float64{"AAPL": 2.5, "BTC-USD": 10}, false},
+		{"", map[string]float64{}, false},
+		{"AAPL", nil, true},
+		{":2", nil, true},
+		{"AAPL:abc", nil, true},
+		{"AAPL:0", nil, true},
+		{"AAPL:101", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSymbolThresholds(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSymbolThresholds(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !maps.Equal(got, tt.want) {
+			t.Errorf("ParseSymbolThresholds(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
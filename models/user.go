@@ -0,0 +1,150 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// User roles
+const (
+	RoleAdmin      = "admin"
+	RoleSubscriber = "subscriber"
+)
+
+// UserRole is the role granted to a Telegram user, wherever they write to the bot from
+type UserRole struct {
+	UserID    string    `bson:"userId" json:"userId"`
+	Role      string    `bson:"role" json:"role"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// MessageKind identifies a category of scheduled message that users can opt out of
+type MessageKind string
+
+// Message kinds
+const (
+	KindDailyReport   MessageKind = "report"
+	KindWeeklyReport  MessageKind = "weekly"
+	KindMonthlyReport MessageKind = "monthly"
+	KindAlert         MessageKind = "alerts"
+	KindEarnings      MessageKind = "earnings"
+	KindDividends     MessageKind = "dividends"
+	KindAnalyst       MessageKind = "analyst"
+	KindInsider       MessageKind = "insider"
+	KindEconomic      MessageKind = "events"
+	KindMarketOpen    MessageKind = "open"
+	KindMarketClose   MessageKind = "close"
+	KindSignals       MessageKind = "signals"
+
+	// Announcements from admins are delivered to everyone and cannot be toggled
+	KindAnnouncement MessageKind = "announcement"
+)
+
+// MessageKinds lists every message kind users can toggle
+var MessageKinds = []MessageKind{
+	KindDailyReport,
+	KindWeeklyReport,
+	KindMonthlyReport,
+	KindAlert,
+	KindEarnings,
+	KindDividends,
+	KindAnalyst,
+	KindInsider,
+	KindEconomic,
+	KindMarketOpen,
+	KindMarketClose,
+	KindSignals,
+}
+
+// AlertStyle controls how much detail price alerts carry
+type AlertStyle string
+
+// Supported alert styles
+const (
+	AlertCompact  AlertStyle = "compact"  // One line per symbol, e.g. "NVDA −6.2% $118.40"
+	AlertStandard AlertStyle = "standard" // Direction, change and previous/current price
+	AlertVerbose  AlertStyle = "verbose"  // Standard plus volume and the latest headline
+)
+
+// AlertStyles lists every alert style users can choose
+var AlertStyles = []AlertStyle{AlertCompact, AlertStandard, AlertVerbose}
+
+// ParseAlertStyle validates an alert style, defaulting to standard
+func ParseAlertStyle(value string) (AlertStyle, error) {
+	style := AlertStyle(strings.ToLower(strings.TrimSpace(value)))
+	if style == "" {
+		return AlertStandard, nil
+	}
+	if !slices.Contains(AlertStyles, style) {
+		return AlertStandard, fmt.Errorf("unknown alert style %q (use %s, %s or %s)", value, AlertCompact, AlertStandard, AlertVerbose)
+	}
+	return style, nil
+}
+
+// ChannelLine marks chats that talk to the bot through Line; chats without a channel are Telegram chats
+const ChannelLine = "line"
+
+// User holds the preferences and watchlist of a chat subscribed to the bot
+type User struct {
+	ChatID string `bson:"chatId" json:"chatId"`
+	// Messaging channel of the chat; empty for Telegram
+	Channel    string   `bson:"channel,omitempty" json:"channel,omitempty"`
+	Username   string   `bson:"username,omitempty" json:"username,omitempty"`
+	Watchlist  []string `bson:"watchlist" json:"watchlist"`
+	Threshold  float64  `bson:"threshold" json:"threshold"`
+	ReportHour int      `bson:"reportHour" json:"reportHour"` // Chosen during onboarding; -1 follows CHECK_HOUR
+	Onboarded  bool     `bson:"onboarded" json:"onboarded"`
+	// Message kinds the user opted out of; everything else is delivered
+	DisabledKinds []MessageKind `bson:"disabledKinds,omitempty" json:"disabledKinds,omitempty"`
+	// Alert verbosity chosen for this chat; empty uses the channel default
+	AlertStyle AlertStyle `bson:"alertStyle,omitempty" json:"alertStyle,omitempty"`
+	// Symbols whose alerts are silenced, mapped to the mute expiry (zero means until unmuted)
+	MutedUntil map[string]time.Time `bson:"mutedUntil,omitempty" json:"mutedUntil,omitempty"`
+	CreatedAt  time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Receives reports whether the user wants messages of the given kind
+func (u User) Receives(kind MessageKind) bool {
+	return !slices.Contains(u.DisabledKinds, kind)
+}
+
+// SetReceives enables or disables a message kind for the user
+func (u *User) SetReceives(kind MessageKind, enabled bool) {
+	u.DisabledKinds = slices.DeleteFunc(u.DisabledKinds, func(k MessageKind) bool { return k == kind })
+	if !enabled {
+		u.DisabledKinds = append(u.DisabledKinds, kind)
+	}
+}
+
+// Watches reports whether the user follows a symbol; an empty watchlist follows every monitored symbol
+func (u User) Watches(symbol string) bool {
+	return len(u.Watchlist) == 0 || slices.Contains(u.Watchlist, symbol)
+}
+
+// ThresholdOr returns the user's own alert threshold, or fallback when they have not set one
+func (u User) ThresholdOr(fallback float64) float64 {
+	if u.Threshold > 0 {
+		return u.Threshold
+	}
+	return fallback
+}
+
+// ReportHourOr returns the hour the user gets the daily report, or fallback when they didn't choose one
+func (u User) ReportHourOr(fallback int) int {
+	if !u.Onboarded || u.ReportHour < 0 {
+		return fallback
+	}
+	return u.ReportHour
+}
+
+// IsMuted reports whether alerts for a symbol are silenced at the given time
+func (u User) IsMuted(symbol string, now time.Time) bool {
+	until, ok := u.MutedUntil[symbol]
+	if !ok {
+		return false
+	}
+	return until.IsZero() || now.Before(until)
+}
@@ -0,0 +1,24 @@
+package models
+
+import "testing"
+
+func TestUserReportHourOr(t *testing.T) {
+	tests := []struct {
+		name string
+		user User
+		want int
+	}{
+		{"not onboarded", User{ReportHour: 0}, 7},
+		{"chose midnight", User{Onboarded: true, ReportHour: 0}, 0},
+		{"chose an hour", User{Onboarded: true, ReportHour: 18}, 18},
+		{"chose the default", User{Onboarded: true, ReportHour: -1}, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.user.ReportHourOr(7); got != tt.want {
+				t.Errorf("ReportHourOr(7) = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// YearWeeks is the lookback of the rolling high and low
+const YearWeeks = 52
+
+// YearRange is a symbol's rolling 52-week closing high and low
+type YearRange struct {
+	Symbol string    `bson:"symbol" json:"symbol"`
+	High   float64   `bson:"high" json:"high"`
+	HighAt time.Time `bson:"highAt" json:"highAt"`
+	Low    float64   `bson:"low" json:"low"`
+	LowAt  time.Time `bson:"lowAt" json:"lowAt"`
+	// Dates a new high and low were last alerted, so a symbol making new extremes all day is alerted once
+	HighAlerted string    `bson:"highAlerted,omitempty" json:"highAlerted,omitempty"`
+	LowAlerted  string    `bson:"lowAlerted,omitempty" json:"lowAlerted,omitempty"`
+	UpdatedAt   time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// YearRangeOf returns the highest and lowest of chronologically sorted closes
+func YearRangeOf(symbol string, points []PricePoint) (YearRange, bool) {
+	if len(points) == 0 {
+		return YearRange{}, false
+	}
+
+	r := YearRange{Symbol: symbol, High: points[0].Close, HighAt: points[0].Timestamp, Low: points[0].Close, LowAt: points[0].Timestamp}
+	for _, point := range points[1:] {
+		if point.Close >= r.High {
+			r.High, r.HighAt = point.Close, point.Timestamp
+		}
+		if point.Close <= r.Low {
+			r.Low, r.LowAt = point.Close, point.Timestamp
+		}
+	}
+	return r, true
+}
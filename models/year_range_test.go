@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestYearRangeOf(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, time.March, d, 0, 0, 0, 0, time.UTC) }
+	points := func(closes ...float64) []PricePoint {
+		var list []PricePoint
+		for i, c := range closes {
+			list = append(list, PricePoint{Timestamp: day(i + 1), Close: c})
+		}
+		return list
+	}
+
+	tests := []struct {
+		name   string
+		points []PricePoint
+		want   YearRange
+		wantOK bool
+	}{
+		{"no closes", nil, YearRange{}, false},
+		{"single close", points(100), YearRange{Symbol: "AAPL", High: 100, HighAt: day(1), Low: 100, LowAt: day(1)}, true},
+		{"high and low", points(100, 120, 90, 110), YearRange{Symbol: "AAPL", High: 120, HighAt: day(2), Low: 90, LowAt: day(3)}, true},
+		{"latest of equal extremes", points(100, 120, 90, 120, 90), YearRange{Symbol: "AAPL", High: 120, HighAt: day(4), Low: 90, LowAt: day(5)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := YearRangeOf("AAPL", tt.points)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("YearRangeOf = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
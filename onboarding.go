@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// onboardingStep is a stage of the guided setup conversation
+type onboardingStep int
+
+// Onboarding steps, in order
+const (
+	stepTickers onboardingStep = iota
+	stepThreshold
+	stepReportHour
+)
+
+// onboardingTTL is how long a setup conversation waits for the next reply before it is dropped
+const onboardingTTL = 30 * time.Minute
+
+// onboardingSession tracks a chat that is going through setup
+type onboardingSession struct {
+	step      onboardingStep
+	user      models.User
+	updatedAt time.Time
+}
+
+// onboardingSessions holds in-progress setup conversations keyed by chat ID
+type onboardingSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*onboardingSession
+}
+
+// start begins a setup conversation, replacing any earlier one of the chat, and drops the expired ones
+func (s *onboardingSessions) start(chatID string, user models.User, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, session := range s.sessions {
+		if now.Sub(session.updatedAt) > onboardingTTL {
+			delete(s.sessions, id)
+		}
+	}
+	s.sessions[chatID] = &onboardingSession{step: stepTickers, user: user, updatedAt: now}
+}
+
+// get returns the chat's current session and a copy of it to work on, or nil once it expired
+func (s *onboardingSessions) get(chatID string, now time.Time) (*onboardingSession, onboardingSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, active := s.sessions[chatID]
+	if !active {
+		return nil, onboardingSession{}
+	}
+	if now.Sub(session.updatedAt) > onboardingTTL {
+		delete(s.sessions, chatID)
+		return nil, onboardingSession{}
+	}
+	return session, *session
+}
+
+// advance stores the next state of a session, unless it was cancelled or restarted in the meantime
+func (s *onboardingSessions) advance(chatID string, current *onboardingSession, next onboardingSession, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sessions[chatID] == current {
+		next.updatedAt = now
+		s.sessions[chatID] = &next
+	}
+}
+
+// finish ends a session, unless it was restarted in the meantime
+func (s *onboardingSessions) finish(chatID string, current *onboardingSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sessions[chatID] == current {
+		delete(s.sessions, chatID)
+	}
+}
+
+// cancel ends the chat's session and reports whether one was in progress
+func (s *onboardingSessions) cancel(chatID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, active := s.sessions[chatID]
+	delete(s.sessions, chatID)
+	return active
+}
+
+// handleStart begins the guided setup when the bot joins a chat or receives /start
+func (h *commandHandlers) handleStart(ctx context.Context, cmd services.BotCommand) error {
+	// Line chats receive the channel broadcast, so the per-chat settings wouldn't apply
+	if cmd.Channel == models.ChannelLine {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("👋 Welcome to %s! This chat receives every report and alert of the channel; send /help for the commands.", appName))
+	}
+
+	// Only a chat without saved settings starts from scratch; other errors would overwrite them
+	user, err := h.db.GetUser(cmd.ChatID)
+	if errors.Is(err, services.ErrUserNotFound) {
+		user = models.User{ChatID: cmd.ChatID}
+	} else if err != nil {
+		return fmt.Errorf("could not load your settings: %w", err)
+	}
+	user.Channel = cmd.Channel
+	if user.Username == "" {
+		user.Username = cmd.Username
+	}
+
+	h.onboarding.start(cmd.ChatID, user, time.Now())
+
+	var message strings.Builder
+	message.WriteString(locale.Sprintf("👋 Welcome to %s! Let's set things up in three quick steps (send /cancel to stop).", appName) + "\n\n")
+	message.WriteString(locale.Sprintf("1️⃣ Which tickers would you like to follow? Send symbols separated by spaces or commas, or \"default\" for %s.", strings.Join(models.Tickers(), ", ")))
+	return h.bot.Reply(ctx, cmd.ChatID, message.String())
+}
+
+// handleCancel aborts an in-progress setup conversation or a staged announcement
+func (h *commandHandlers) handleCancel(ctx context.Context, cmd services.BotCommand) error {
+	active := h.onboarding.cancel(cmd.ChatID)
+
+	h.announcementsMu.Lock()
+	_, staged := h.announcements[cmd.ChatID]
+	delete(h.announcements, cmd.ChatID)
+	h.announcementsMu.Unlock()
+
+	switch {
+	case active:
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Setup cancelled. Send /start to begin again."))
+	case staged:
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Announcement discarded."))
+	default:
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Nothing to cancel."))
+	}
+}
+
+// continueOnboarding consumes a reply during setup and reports whether the message belonged to it.
+// The session is worked on as a copy, so symbol lookups, replies and saves don't hold up other chats
+func (h *commandHandlers) continueOnboarding(ctx context.Context, cmd services.BotCommand) (bool, error) {
+	current, session := h.onboarding.get(cmd.ChatID, time.Now())
+	if current == nil {
+		return false, nil
+	}
+
+	answer := strings.TrimSpace(cmd.Text)
+	useDefault := strings.EqualFold(answer, "default") || answer == locale.Text("default")
+
+	switch session.step {
+	case stepTickers:
+		watchlist := models.Tickers()
+		if !useDefault {
+			var invalid []string
+			watchlist, invalid = h.parseSymbolList(ctx, answer)
+			if len(invalid) > 0 || len(watchlist) == 0 {
+				return true, h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("I couldn't read these symbols: %s. Please try again.", strings.Join(invalid, ", ")))
+			}
+		}
+		session.user.Watchlist = watchlist
+		session.step = stepThreshold
+		h.onboarding.advance(cmd.ChatID, current, session, time.Now())
+		return true, h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("2️⃣ What percent move should trigger an alert? Send a number, or \"default\" for %.0f%%.", alertThreshold()))
+
+	case stepThreshold:
+		// Without a threshold of its own the chat keeps the symbol, asset class and intraday thresholds
+		threshold := 0.0
+		if !useDefault {
+			parsed, err := strconv.ParseFloat(strings.TrimSuffix(answer, "%"), 64)
+			if err != nil || parsed <= 0 || parsed > 100 {
+				return true, h.bot.Reply(ctx, cmd.ChatID, locale.Text("Please send a percentage between 0 and 100, e.g. 5"))
+			}
+			threshold = parsed
+		}
+		session.user.Threshold = threshold
+		session.step = stepReportHour
+		h.onboarding.advance(cmd.ChatID, current, session, time.Now())
+		return true, h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("3️⃣ At what hour (0-23, %s) should I send the daily report? Send a number, or \"default\" for %d.", h.config.TimeZone, currentConfig().CheckHour))
+
+	default:
+		// Without an hour of its own the chat follows CHECK_HOUR, also when it changes
+		reportHour := -1
+		if !useDefault {
+			parsed, err := strconv.Atoi(strings.TrimSuffix(answer, "h"))
+			if err != nil || parsed < 0 || parsed > 23 {
+				return true, h.bot.Reply(ctx, cmd.ChatID, locale.Text("Please send an hour between 0 and 23, e.g. 7"))
+			}
+			reportHour = parsed
+		}
+		session.user.ReportHour = reportHour
+		session.user.Onboarded = true
+
+		if err := h.db.SaveUser(session.user); err != nil {
+			return true, fmt.Errorf("could not save your preferences: %w", err)
+		}
+		h.onboarding.finish(cmd.ChatID, current)
+
+		return true, h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("✅ All set!\nWatching: %s\nAlert threshold: %.2f%%\nDaily report: %02d:00 (%s)",
+			strings.Join(session.user.Watchlist, ", "),
+			session.user.ThresholdOr(alertThreshold()),
+			session.user.ReportHourOr(currentConfig().CheckHour),
+			h.config.TimeZone,
+		))
+	}
+}
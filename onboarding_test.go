@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"stock-bot/models"
+)
+
+func TestOnboardingSessions(t *testing.T) {
+	start := time.Date(2025, time.March, 4, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		run     func(s *onboardingSessions)
+		after   time.Duration
+		active  bool
+		advance bool // Whether the last step's state was kept
+	}{
+		{"reply within the TTL", func(s *onboardingSessions) {}, 10 * time.Minute, true, true},
+		{"expired session", func(s *onboardingSessions) {}, onboardingTTL + time.Minute, false, false},
+		{"cancelled while answering", func(s *onboardingSessions) { s.cancel("1") }, 0, false, false},
+		{"restarted while answering", func(s *onboardingSessions) { s.start("1", models.User{ChatID: "1"}, start) }, 0, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &onboardingSessions{sessions: make(map[string]*onboardingSession)}
+			s.start("1", models.User{ChatID: "1"}, start)
+
+			now := start.Add(tt.after)
+			current, session := s.get("1", now)
+			if current == nil {
+				if tt.active {
+					t.Fatal("session expired early")
+				}
+				return
+			}
+			session.step = stepThreshold
+			tt.run(s)
+			s.advance("1", current, session, now)
+
+			current, session = s.get("1", now)
+			if (current != nil) != tt.active {
+				t.Fatalf("active = %v, want %v", current != nil, tt.active)
+			}
+			if current != nil && (session.step == stepThreshold) != tt.advance {
+				t.Errorf("step = %v, want the answer kept = %v", session.step, tt.advance)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// portfolioTotals sums the value and gains of the positions held in one currency
+type portfolioTotals struct {
+	value      float64
+	cost       float64
+	dailyPL    float64
+	unrealized float64
+}
+
+// fetchPortfolioQuotes fetches a quote for every symbol held in the portfolios, once per symbol
+func fetchPortfolioQuotes(ctx context.Context, portfolios ...models.Portfolio) map[string]models.Quote {
+	quotes := make(map[string]models.Quote)
+	for _, portfolio := range portfolios {
+		for _, symbol := range portfolio.Symbols() {
+			if _, ok := quotes[symbol]; ok {
+				continue
+			}
+			quote, err := priceSource.Fetch(ctx, symbol)
+			if err != nil {
+				slog.Error("Error fetching quote for portfolio", "symbol", symbol, "error", err)
+				continue
+			}
+			quotes[symbol] = quote
+		}
+	}
+	return quotes
+}
+
+// loadPreviousCloses returns each quoted symbol's stored close of the session before its quote's
+func loadPreviousCloses(db *services.Database, quotes map[string]models.Quote) map[string]float64 {
+	closes := make(map[string]float64, len(quotes))
+	for symbol, quote := range quotes {
+		previousClose, err := db.PreviousClose(symbol, quote.Timestamp)
+		if err != nil {
+			if !errors.Is(err, services.ErrNoClosingPriceFound) {
+				slog.Warn("Error loading previous close for portfolio", "symbol", symbol, "error", err)
+			}
+			continue
+		}
+		closes[symbol] = previousClose
+	}
+	return closes
+}
+
+// formatPortfolio renders each position's value, daily P&L and unrealized gain, with totals per currency
+// as positions in different currencies can't be added up. The daily P&L is measured from the stored previous close,
+// falling back to the quote's change for symbols without one
+func formatPortfolio(portfolio models.Portfolio, quotes map[string]models.Quote, previousCloses map[string]float64) string {
+	var message strings.Builder
+	message.WriteString(locale.Text("💼 Portfolio") + "\n\n")
+
+	totals := make(map[string]*portfolioTotals)
+	for _, h := range portfolio.Holdings {
+		currency := models.CurrencyFor(h.Symbol)
+		quote, ok := quotes[h.Symbol]
+		if !ok {
+			message.WriteString(locale.Sprintf("%s: %s shares, price unavailable", h.Symbol, formatShares(h.Shares)) + "\n")
+			continue
+		}
+
+		value := h.Shares * quote.Price
+		cost := h.Shares * h.CostBasis
+		dailyPL := h.Shares * quote.Change
+		if previousClose, ok := previousCloses[h.Symbol]; ok {
+			dailyPL = h.Shares * (quote.Price - previousClose)
+		}
+		unrealized := value - cost
+
+		message.WriteString(fmt.Sprintf("%s: %s × %s = %s\n", h.Symbol, formatShares(h.Shares), models.FormatPrice(h.Symbol, quote.Price), models.FormatAmount(currency, value, false)))
+		message.WriteString("  " + locale.Sprintf("Day %s, P&L %s%s", models.FormatAmount(currency, dailyPL, true), models.FormatAmount(currency, unrealized, true), formatGainPercent(unrealized, cost)) + "\n")
+
+		t, ok := totals[currency]
+		if !ok {
+			t = &portfolioTotals{}
+			totals[currency] = t
+		}
+		t.value += value
+		t.cost += cost
+		t.dailyPL += dailyPL
+		t.unrealized += unrealized
+	}
+
+	for _, currency := range slices.Sorted(maps.Keys(totals)) {
+		t := totals[currency]
+		message.WriteString("\n" + locale.Sprintf("Total %s: %s", currency, models.FormatAmount(currency, t.value, false)) + "\n")
+		message.WriteString("  " + locale.Sprintf("Day %s, P&L %s%s", models.FormatAmount(currency, t.dailyPL, true), models.FormatAmount(currency, t.unrealized, true), formatGainPercent(t.unrealized, t.cost)) + "\n")
+	}
+	return message.String()
+}
+
+// formatGainPercent renders a gain relative to its cost, e.g. " (+8.57%)", or "" without a cost
+func formatGainPercent(gain, cost float64) string {
+	if cost == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%+.2f%%)", gain/cost*100)
+}
+
+// formatShares renders a share count without trailing zeros, so fractional shares and coins read naturally
+func formatShares(shares float64) string {
+	return strconv.FormatFloat(shares, 'f', -1, 64)
+}
+
+// sendPortfolioReports follows the daily report with each chat's portfolio valuation
+func sendPortfolioReports(ctx context.Context, db *services.Database, delivery *services.Delivery) {
+	portfolios, err := db.ListPortfolios()
+	if err != nil {
+		slog.Error("Error loading portfolios for daily report", "error", err)
+		return
+	}
+	if len(portfolios) == 0 {
+		return
+	}
+
+	quotes := fetchPortfolioQuotes(ctx, slices.Collect(maps.Values(portfolios))...)
+	previousCloses := loadPreviousCloses(db, quotes)
+
+	if _, err := delivery.Deliver(models.KindDailyReport, func(m services.Messenger, user models.User) error {
+		portfolio, ok := portfolios[user.ChatID]
+		if !ok {
+			return nil
+		}
+		return m.SendText(formatPortfolio(portfolio, quotes, previousCloses), nil)
+	}); err != nil {
+		slog.Error("Error sending portfolio reports", "error", err)
+	}
+}
+
+// handlePortfolio replies with the chat's portfolio valuation
+func (h *commandHandlers) handlePortfolio(ctx context.Context, cmd services.BotCommand) error {
+	portfolio, err := h.db.GetPortfolio(cmd.ChatID)
+	if err != nil {
+		return fmt.Errorf("could not load portfolio: %w", err)
+	}
+	if len(portfolio.Holdings) == 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Your portfolio is empty. Add a position with /buy SYMBOL SHARES PRICE"))
+	}
+
+	quotes := make(map[string]models.Quote)
+	for _, symbol := range portfolio.Symbols() {
+		quote, err := h.getQuote(ctx, symbol)
+		if err != nil {
+			slog.Warn("Error fetching quote for portfolio", "symbol", symbol, "error", err)
+			continue
+		}
+		quotes[symbol] = quote
+	}
+
+	return h.bot.Reply(ctx, cmd.ChatID, formatPortfolio(portfolio, quotes, loadPreviousCloses(h.db, quotes)))
+}
+
+// handleBuy adds shares bought at a price to the chat's portfolio
+func (h *commandHandlers) handleBuy(ctx context.Context, cmd services.BotCommand) error {
+	if len(cmd.Args) < 3 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /buy SYMBOL SHARES PRICE"))
+	}
+
+	symbol, ok := h.resolveSymbol(ctx, cmd.Args[0])
+	if !ok {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Invalid symbol: %s", cmd.Args[0]))
+	}
+	shares, err := strconv.ParseFloat(cmd.Args[1], 64)
+	if err != nil || shares <= 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Shares must be a positive number"))
+	}
+	price, err := services.ParsePrice(cmd.Args[2])
+	if err != nil || price <= 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Price must be a positive number"))
+	}
+
+	portfolio, err := h.db.GetPortfolio(cmd.ChatID)
+	if err != nil {
+		return fmt.Errorf("could not load portfolio: %w", err)
+	}
+	holding := portfolio.Buy(symbol, shares, price)
+	if err := h.db.SavePortfolio(portfolio); err != nil {
+		return fmt.Errorf("could not save portfolio: %w", err)
+	}
+
+	return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("💼 %s: %s shares at an average cost of %s", symbol, formatShares(holding.Shares), models.FormatPrice(symbol, holding.CostBasis)))
+}
+
+// handleSell removes shares from the chat's portfolio
+func (h *commandHandlers) handleSell(ctx context.Context, cmd services.BotCommand) error {
+	if len(cmd.Args) < 2 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /sell SYMBOL SHARES"))
+	}
+
+	symbol, ok := h.resolveSymbol(ctx, cmd.Args[0])
+	if !ok {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Invalid symbol: %s", cmd.Args[0]))
+	}
+	shares, err := strconv.ParseFloat(cmd.Args[1], 64)
+	if err != nil || shares <= 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Shares must be a positive number"))
+	}
+
+	portfolio, err := h.db.GetPortfolio(cmd.ChatID)
+	if err != nil {
+		return fmt.Errorf("could not load portfolio: %w", err)
+	}
+	holding, ok := portfolio.Sell(symbol, shares)
+	if !ok {
+		held, _ := portfolio.Holding(symbol)
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("You hold %s shares of %s", formatShares(held.Shares), symbol))
+	}
+	if err := h.db.SavePortfolio(portfolio); err != nil {
+		return fmt.Errorf("could not save portfolio: %w", err)
+	}
+
+	if holding.Shares <= 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("💼 Closed your %s position", symbol))
+	}
+	return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("💼 %s: %s shares left", symbol, formatShares(holding.Shares)))
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"log/slog"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// Price source chain shared by reports, alerts and commands
+var priceSource *services.MultiSource
+
+// Redis cache in front of the price sources, nil unless REDIS_URL is set
+var redisQuoteCache *services.RedisQuoteCache
+
+// defaultPriceSources is the fallback chain used unless PRICE_SOURCES is set
+func defaultPriceSources(config models.Config) []string {
+	// CoinGecko only answers for crypto pairs and needs no key, so it goes first
+	names := []string{"coingecko", "yahoo-api"}
+	// Alpha Vantage's free tier allows only a few requests a minute, so it backs up Yahoo for US listings
+	if config.AlphaVantageAPIKey != "" {
+		names = append(names, "alphavantage")
+	}
+	// The daily reference rates of Frankfurter back up Yahoo's live ones for currency pairs
+	return append(names, "frankfurter", "chromedp")
+}
+
+// buildPriceSource chains the configured price sources, skipping unknown ones and those missing an API key
+func buildPriceSource(config models.Config) *services.MultiSource {
+	names := config.PriceSources
+	if len(names) == 0 {
+		names = defaultPriceSources(config)
+	}
+
+	var sources []services.PriceSource
+	for _, name := range names {
+		var source services.PriceSource
+		var err error
+		switch name {
+		case "yahoo-api":
+			source = services.NewYahooAPISource()
+		case "alphavantage":
+			source, err = services.NewAlphaVantageSource(config.AlphaVantageAPIKey)
+		case "coingecko":
+			source = services.NewCoinGeckoSource()
+		case "frankfurter":
+			source = services.NewFrankfurterSource()
+		case "finnhub":
+			source, err = services.NewFinnhubSource(config.FinnhubAPIKey)
+		case "chromedp":
+			source = priceFetcher
+		default:
+			slog.Warn("Unknown price source, skipping", "source", name)
+			continue
+		}
+		if err != nil {
+			slog.Warn("Price source disabled", "source", name, "error", err)
+			continue
+		}
+		sources = append(sources, source)
+	}
+
+	chain := services.NewMultiSource(sources...)
+	slog.Info("Fetching prices", "source", chain.Name())
+
+	// Every fetched quote is cached, but only chat commands are answered from the cache; closing prices and
+	// alerts are always fetched fresh
+	if config.RedisURL != "" {
+		cache, err := services.NewRedisQuoteCache(config.RedisURL, config.QuoteCacheTTL)
+		if err != nil {
+			slog.Warn("Quote cache disabled", "error", err)
+		} else {
+			redisQuoteCache = cache
+			chain.SetCache(cache)
+			slog.Info("Caching quotes in Redis", "ttl", config.QuoteCacheTTL)
+		}
+	}
+	return chain
+}
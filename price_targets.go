@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// maxPriceTargets is the number of price targets a chat may keep
+const maxPriceTargets = 20
+
+// checkPriceTargets fires the armed price targets whose level the latest prices reached, and
+// re-arms repeating targets once the price is back on the other side of their level
+func checkPriceTargets(db *services.Database, delivery *services.Delivery, quotes map[string]models.Quote) {
+	targets, err := db.ListPriceTargets("")
+	if err != nil {
+		slog.Error("Error loading price targets", "error", err)
+		return
+	}
+
+	fired := make(map[string][]models.PriceTarget)
+	current := make(map[string]float64)
+	for _, target := range targets {
+		quote, ok := quotes[target.Symbol]
+		if !ok {
+			continue
+		}
+		current[target.Symbol] = quote.Price
+
+		reached := target.Reached(quote.Price)
+		switch {
+		case target.Armed && reached:
+			fired[target.ChatID] = append(fired[target.ChatID], target)
+		case !target.Armed && !reached:
+			target.Armed = true
+			if err := db.UpdatePriceTarget(target); err != nil {
+				slog.Error("Error re-arming price target", "symbol", target.Symbol, "error", err)
+			}
+		}
+	}
+	if len(fired) == 0 {
+		return
+	}
+
+	// Targets belong to a chat, so broadcast channels without one are skipped
+	var mu sync.Mutex
+	delivered := make(map[string]bool)
+	if _, err := delivery.Deliver(models.KindAlert, func(m services.Messenger, user models.User) error {
+		due := fired[user.ChatID]
+		if len(due) == 0 {
+			return nil
+		}
+		if err := m.SendText(formatPriceTargets(due, current), nil); err != nil {
+			return err
+		}
+		mu.Lock()
+		delivered[user.ChatID] = true
+		mu.Unlock()
+		return nil
+	}); err != nil {
+		slog.Error("Error sending price target alerts", "error", err)
+	}
+
+	// Targets of chats that weren't reached stay armed for the next check
+	now := time.Now()
+	for chatID, due := range fired {
+		if !delivered[chatID] {
+			continue
+		}
+		for _, target := range due {
+			slog.Info("Price target reached", "chat", chatID, "symbol", target.Symbol, "direction", target.Direction, "level", target.Level)
+			if !target.Repeat {
+				if err := db.DeletePriceTarget(target.ID); err != nil {
+					slog.Error("Error removing fired price target", "symbol", target.Symbol, "error", err)
+				}
+				continue
+			}
+			target.Armed = false
+			target.TriggeredAt = &now
+			if err := db.UpdatePriceTarget(target); err != nil {
+				slog.Error("Error disarming fired price target", "symbol", target.Symbol, "error", err)
+			}
+		}
+	}
+}
+
+// formatPriceTargets renders the targets a chat's symbols reached
+func formatPriceTargets(targets []models.PriceTarget, prices map[string]float64) string {
+	var message strings.Builder
+	message.WriteString("🎯 Price Targets Reached\n\n")
+	for _, target := range targets {
+		icon := "⬆️"
+		if target.Direction == models.TargetBelow {
+			icon = "⬇️"
+		}
+		message.WriteString(fmt.Sprintf("%s %s at %s (%s %s)\n", icon, target.Symbol,
+			models.FormatPrice(target.Symbol, prices[target.Symbol]), target.Direction, models.FormatPrice(target.Symbol, target.Level)))
+	}
+	return message.String()
+}
+
+// handleAlert adds a price target for the chat, e.g. /alert AAPL above 200 repeat
+func (h *commandHandlers) handleAlert(ctx context.Context, cmd services.BotCommand) error {
+	if len(cmd.Args) < 3 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /alert SYMBOL above|below PRICE [repeat]"))
+	}
+
+	symbol, ok := h.resolveSymbol(ctx, cmd.Args[0])
+	if !ok {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Invalid symbol: %s", cmd.Args[0]))
+	}
+	direction := models.TargetDirection(strings.ToLower(cmd.Args[1]))
+	if direction != models.TargetAbove && direction != models.TargetBelow {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Direction must be above or below"))
+	}
+	level, err := services.ParsePrice(cmd.Args[2])
+	if err != nil || level <= 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Price must be a positive number"))
+	}
+	repeat := len(cmd.Args) > 3 && strings.EqualFold(cmd.Args[3], "repeat")
+
+	existing, err := h.db.ListPriceTargets(cmd.ChatID)
+	if err != nil {
+		return fmt.Errorf("could not load price targets: %w", err)
+	}
+	if len(existing) >= maxPriceTargets {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("You already have %d price targets; remove one with /alerts delete N", len(existing)))
+	}
+
+	// The side of the level the price starts on decides whether the first check may fire
+	var startPrice float64
+	if quote, err := h.getQuote(ctx, symbol); err == nil {
+		startPrice = quote.Price
+	} else {
+		slog.Warn("Error fetching price for new price target, arming it", "symbol", symbol, "error", err)
+	}
+
+	target, err := h.db.AddPriceTarget(models.PriceTarget{
+		ChatID:     cmd.ChatID,
+		Symbol:     symbol,
+		Direction:  direction,
+		Level:      level,
+		Repeat:     repeat,
+		StartPrice: startPrice,
+	})
+	if err != nil {
+		return fmt.Errorf("could not save price target: %w", err)
+	}
+
+	// Targets are checked against the monitored prices, so their symbols join the watchlist
+	refreshWatchlist(h.db)
+
+	mode := "once"
+	if target.Repeat {
+		mode = "every time it crosses"
+	}
+	message := locale.Sprintf("🎯 Alerting when %s is %s %s, %s", symbol, locale.Text(string(direction)), models.FormatPrice(symbol, level), locale.Text(mode))
+	if !target.Armed {
+		message += "\n" + locale.Sprintf("%s is already there at %s, so the alert waits for the price to cross back first", symbol, models.FormatPrice(symbol, startPrice))
+	}
+	return h.bot.Reply(ctx, cmd.ChatID, message)
+}
+
+// handleAlerts lists the chat's price targets, or deletes one by its number or all of them
+func (h *commandHandlers) handleAlerts(ctx context.Context, cmd services.BotCommand) error {
+	targets, err := h.db.ListPriceTargets(cmd.ChatID)
+	if err != nil {
+		return fmt.Errorf("could not load price targets: %w", err)
+	}
+
+	if len(cmd.Args) > 0 {
+		if !strings.EqualFold(cmd.Args[0], "delete") || len(cmd.Args) < 2 {
+			return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /alerts [delete N|all]"))
+		}
+		return h.deletePriceTargets(ctx, cmd.ChatID, targets, cmd.Args[1])
+	}
+
+	if len(targets) == 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("No price targets. Add one with /alert SYMBOL above|below PRICE"))
+	}
+
+	var message strings.Builder
+	message.WriteString(locale.Text("🎯 Price targets") + "\n\n")
+	for i, target := range targets {
+		line := fmt.Sprintf("%d. %s %s %s", i+1, target.Symbol, locale.Text(string(target.Direction)), models.FormatPrice(target.Symbol, target.Level))
+		switch {
+		case target.Repeat && target.Armed:
+			line += " " + locale.Text("(repeat)")
+		case target.Repeat:
+			line += " " + locale.Text("(repeat, waiting to re-arm)")
+		case !target.Armed:
+			line += " " + locale.Text("(waiting to re-arm)")
+		}
+		message.WriteString(line + "\n")
+	}
+	message.WriteString("\n" + locale.Text("Delete one with /alerts delete N"))
+	return h.bot.Reply(ctx, cmd.ChatID, message.String())
+}
+
+// deletePriceTargets removes the numbered target shown by /alerts, or every target for "all"
+func (h *commandHandlers) deletePriceTargets(ctx context.Context, chatID string, targets []models.PriceTarget, which string) error {
+	if strings.EqualFold(which, "all") {
+		for _, target := range targets {
+			if err := h.db.DeletePriceTarget(target.ID); err != nil {
+				return fmt.Errorf("could not delete price target: %w", err)
+			}
+		}
+		refreshWatchlist(h.db)
+		return h.bot.Reply(ctx, chatID, locale.Sprintf("🗑 Deleted %d price targets", len(targets)))
+	}
+
+	n, err := strconv.Atoi(which)
+	if err != nil || n < 1 || n > len(targets) {
+		return h.bot.Reply(ctx, chatID, locale.Sprintf("Choose a target between 1 and %d", len(targets)))
+	}
+	target := targets[n-1]
+	if err := h.db.DeletePriceTarget(target.ID); err != nil {
+		return fmt.Errorf("could not delete price target: %w", err)
+	}
+
+	// The symbol leaves the monitored prices unless something else still needs it
+	refreshWatchlist(h.db)
+	return h.bot.Reply(ctx, chatID, locale.Sprintf("🗑 Deleted %s %s %s", target.Symbol, locale.Text(string(target.Direction)), models.FormatPrice(target.Symbol, target.Level)))
+}
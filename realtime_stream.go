@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// streamEvaluateInterval is how often the latest streamed trade prices are checked for alerts
+const streamEvaluateInterval = time.Minute
+
+// Set once equities follow the live trade stream, so the scheduler stops polling them
+var equityStreaming bool
+
+// streamedPrices keeps the latest trade per symbol until the next evaluation
+type streamedPrices struct {
+	mu     sync.Mutex
+	quotes map[string]models.Quote
+}
+
+// record stores a trade, replacing earlier trades of the symbol; its volume is the trade's size
+// rather than the session's, so it is dropped
+func (s *streamedPrices) record(quote models.Quote) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	quote.Volume = 0
+	s.quotes[quote.Symbol] = quote
+}
+
+// drain returns the trades collected since the last call and starts a new collection
+func (s *streamedPrices) drain() map[string]models.Quote {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	quotes := s.quotes
+	s.quotes = make(map[string]models.Quote)
+	return quotes
+}
+
+// streamedSymbols returns the symbols to stream: Finnhub streams US trades only, and other exchanges' equities keep
+// being polled during their own sessions
+func streamedSymbols() []string {
+	return symbolHealth.active(groupByExchange(models.GroupByAssetClass(models.Tickers())[models.AssetEquity])[models.ExchangeUS])
+}
+
+// startRealtimeStream subscribes to live equity trades on Finnhub and feeds them into the realtime alert check
+func startRealtimeStream(ctx context.Context, db *services.Database, delivery *services.Delivery, config models.Config) {
+	source, err := services.NewFinnhubSource(config.FinnhubAPIKey)
+	if err != nil {
+		slog.Warn("Realtime streaming disabled", "error", err)
+		return
+	}
+
+	symbols := streamedSymbols()
+	if len(symbols) == 0 {
+		slog.Warn("Realtime streaming disabled: no US equities to stream")
+		return
+	}
+
+	loc, err := time.LoadLocation(config.TimeZone)
+	if err != nil {
+		loc = time.Local
+	}
+
+	latest := &streamedPrices{quotes: make(map[string]models.Quote)}
+	updates := make(chan []string, 1)
+	go source.Stream(ctx, symbols, updates, latest.record)
+
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		ticker := time.NewTicker(streamEvaluateInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				// Follow symbols added to or removed from the watchlist since the last check
+				if current := streamedSymbols(); !slices.Equal(current, symbols) {
+					symbols = current
+					select {
+					case <-updates:
+					default:
+					}
+					updates <- current
+				}
+
+				quotes := latest.drain()
+				if len(quotes) == 0 || schedulerPaused.Load() || !isMarketOpen(time.Now().In(loc)) {
+					continue
+				}
+				botStatus.recordPrices(quotes)
+				botStatus.recordRealtimeRun(time.Now())
+				evaluateRealtimePrices(ctx, db, delivery, quotes)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	equityStreaming = true
+	slog.Info("Streaming realtime trades", "count", len(symbols), "source", source.Name())
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// pruneIntradayPrices removes intraday prices older than the configured retention, keeping every closing price,
+// and reports how many were removed. Retention is off unless configured, as the removal can't be undone
+func pruneIntradayPrices(db *services.Database, config models.Config, now time.Time) {
+	if config.IntradayRetentionDays <= 0 {
+		return
+	}
+
+	cutoff := now.AddDate(0, 0, -config.IntradayRetentionDays)
+	removed, err := db.DeleteIntradayPricesBefore(cutoff)
+	if err != nil {
+		slog.Error("Error pruning intraday prices", "error", err)
+		return
+	}
+
+	botStatus.recordPrune(now, removed)
+	slog.Info("Pruned intraday prices", "removed", removed, "before", cutoff.Format(time.DateOnly), "retention_days", config.IntradayRetentionDays)
+}
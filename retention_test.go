@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// pruneStore records the cutoffs intraday prices are deleted before; its other methods aren't used
+type pruneStore struct {
+	services.Store
+	cutoffs []time.Time
+}
+
+func (s *pruneStore) DeleteIntradayPricesBefore(cutoff time.Time) (int64, error) {
+	s.cutoffs = append(s.cutoffs, cutoff)
+	return 3, nil
+}
+
+func TestPruneIntradayPrices(t *testing.T) {
+	now := time.Date(2025, time.March, 4, 7, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		days   int
+		cutoff time.Time // Zero when nothing may be deleted
+	}{
+		{"off by default", models.DefaultConfig().IntradayRetentionDays, time.Time{}},
+		{"configured", 30, now.AddDate(0, 0, -30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &pruneStore{}
+			pruneIntradayPrices(services.NewStoreDatabase(store), models.Config{IntradayRetentionDays: tt.days}, now)
+
+			switch {
+			case tt.cutoff.IsZero() && len(store.cutoffs) > 0:
+				t.Errorf("deleted intraday prices before %v, want none deleted", store.cutoffs[0])
+			case !tt.cutoff.IsZero() && (len(store.cutoffs) != 1 || !store.cutoffs[0].Equal(tt.cutoff)):
+				t.Errorf("cutoffs = %v, want %v", store.cutoffs, tt.cutoff)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"log/slog"
+
+	"stock-bot/services"
+)
+
+// Scheduled jobs whose last run date is kept in MongoDB
+const (
+	jobDailyReport   = "daily_report"
+	jobWeeklyReport  = "weekly_report"
+	jobMonthlyReport = "monthly_report"
+	jobChatReports   = "chat_reports" // Last hour, as 2006-01-02T15, reports went to chats that chose their own hour
+)
+
+// loadSchedulerState restores the last report and closing price capture dates and the alerts still in their cooldown, so a restart doesn't send them again
+func loadSchedulerState(db *services.Database) {
+	runs, err := db.GetJobRuns()
+	if err != nil {
+		slog.Error("Error loading scheduler state", "error", err)
+	} else {
+		lastProcessedDate = runs[jobDailyReport]
+		lastWeeklyReportDate = runs[jobWeeklyReport]
+		lastMonthlyReportMonth = runs[jobMonthlyReport]
+		lastChatReportHour = runs[jobChatReports]
+		restoreCloseCaptures(runs)
+	}
+
+	alerts, err := db.GetSentAlerts()
+	if err != nil {
+		slog.Error("Error loading sent alerts", "error", err)
+		return
+	}
+
+	alertMapMutex.Lock()
+	defer alertMapMutex.Unlock()
+	for _, alert := range alerts {
+		lastAlertSentMap[alertKey(alert.ChatID, alert.Symbol, alert.Basis)] = alert
+	}
+	slog.Info("Scheduler state loaded", "last_report", lastProcessedDate, "alerts", len(alerts))
+}
+
+// recordJobRun saves the date a scheduled job ran
+func recordJobRun(db *services.Database, job, date string) {
+	if err := db.SaveJobRun(job, date); err != nil {
+		slog.Error("Error saving scheduler state", "job", job, "error", err)
+	}
+}
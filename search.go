@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// searchResultLimit caps the number of matches returned by a symbol search
+const searchResultLimit = 8
+
+// handleSearch replies with tickers matching a company name or partial symbol
+func (h *commandHandlers) handleSearch(ctx context.Context, cmd services.BotCommand) error {
+	query := strings.TrimSpace(strings.Join(cmd.Args, " "))
+	if query == "" {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /search NAME, e.g. /search nvidia"))
+	}
+
+	searchCtx, cancel := context.WithTimeout(ctx, commandFetchTimeout)
+	defer cancel()
+
+	matches, err := h.search.Search(searchCtx, query, searchResultLimit)
+	if err != nil {
+		return fmt.Errorf("could not search for %q: %w", query, err)
+	}
+	if len(matches) == 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("No symbols found for %q", query))
+	}
+
+	return h.bot.Reply(ctx, cmd.ChatID, formatSearchResults(query, matches))
+}
+
+// formatSearchResults lists search matches with their exchange and name
+func formatSearchResults(query string, matches []models.SymbolMatch) string {
+	var sb strings.Builder
+	sb.WriteString(locale.Sprintf("🔎 Results for %q", query) + "\n")
+	for _, match := range matches {
+		fmt.Fprintf(&sb, "\n%s — %s", match.Symbol, match.Name)
+		if match.Exchange != "" {
+			fmt.Fprintf(&sb, " (%s)", match.Exchange)
+		}
+	}
+	return sb.String()
+}
+
+// handleSearch serves symbol search results as JSON
+func (h *httpHandlers) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		http.Error(w, "missing q parameter", http.StatusBadRequest)
+		return
+	}
+
+	matches, err := h.search.Search(r.Context(), query, searchResultLimit)
+	if err != nil {
+		slog.Error("Error searching symbols", "query", query, "error", err)
+		http.Error(w, "symbol search failed", http.StatusBadGateway)
+		return
+	}
+	if matches == nil {
+		matches = []models.SymbolMatch{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(matches); err != nil {
+		slog.Error("Error writing search results", "error", err)
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"stock-bot/i18n"
+	"stock-bot/models"
+)
+
+// formatCompactAlerts renders one line per alert, e.g. "NVDA −6.2% $118.40"
+func formatCompactAlerts(alerts []models.PriceAlert) string {
+	lines := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		// A true minus sign lines up with the plus sign in proportional fonts
+		sign := "+"
+		if alert.PercentChange < 0 {
+			sign = "−"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s%.1f%% %s",
+			alert.Symbol,
+			sign,
+			math.Abs(alert.PercentChange),
+			models.FormatPrice(alert.Symbol, alert.CurrentPrice),
+		))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeAlertDetails appends the volume and headline lines of a verbose alert
+func writeAlertDetails(message *strings.Builder, alert models.PriceAlert, indent string, printer *i18n.Printer) {
+	if alert.Volume > 0 {
+		message.WriteString(indent + printer.Sprintf("Volume: %s", formatVolume(alert.Volume)) + "\n")
+	}
+	if alert.Headline != "" {
+		message.WriteString(fmt.Sprintf("%s📰 %s\n", indent, alert.Headline))
+	}
+}
+
+// referenceLabel names the price an alert's change is measured from, in the printer's language
+func referenceLabel(alert models.PriceAlert, printer *i18n.Printer) string {
+	switch alert.Basis {
+	case models.BasisOpen:
+		return printer.Text("Open")
+	case models.BasisWindow:
+		return printer.Sprintf("%.0fm ago", alert.Window.Minutes())
+	default:
+		return printer.Text("Previous")
+	}
+}
+
+// formatVolume abbreviates a share volume, e.g. 12345678 as "12.3M"
+func formatVolume(volume int64) string {
+	switch {
+	case volume <= 0:
+		return "-"
+	case volume >= 1_000_000_000:
+		return fmt.Sprintf("%.1fB", float64(volume)/1e9)
+	case volume >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(volume)/1e6)
+	case volume >= 1_000:
+		return fmt.Sprintf("%.1fK", float64(volume)/1e3)
+	default:
+		return fmt.Sprintf("%d", volume)
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+)
+
+// alphaVantageRequestsPerMinute is the request limit of the free Alpha Vantage tier
+const alphaVantageRequestsPerMinute = 5
+
+// Error definitions for Alpha Vantage data
+var (
+	ErrAlphaVantageUnavailable = errors.New("alpha vantage data unavailable")
+	ErrRateLimited             = errors.New("rate limit reached")
+)
+
+// AlphaVantageSource reads quotes and daily closes from the Alpha Vantage API, spacing requests to stay within the rate limit
+type AlphaVantageSource struct {
+	apiKey   string
+	client   *http.Client
+	interval time.Duration // Minimum time between requests
+
+	mu   sync.Mutex
+	next time.Time // Earliest time the next request may be sent
+}
+
+// alphaVantageResponse holds the fields shared by all Alpha Vantage responses and the ones used for quotes and closes
+type alphaVantageResponse struct {
+	Note         string                       `json:"Note"`
+	Information  string                       `json:"Information"`
+	ErrorMessage string                       `json:"Error Message"`
+	GlobalQuote  map[string]string            `json:"Global Quote"`
+	Daily        map[string]map[string]string `json:"Time Series (Daily)"`
+}
+
+// NewAlphaVantageSource creates a new AlphaVantageSource limited to the free tier's request rate
+func NewAlphaVantageSource(apiKey string) (*AlphaVantageSource, error) {
+	if apiKey == "" {
+		return nil, ErrTokenNotSet
+	}
+	return &AlphaVantageSource{
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: 15 * time.Second},
+		interval: time.Minute / alphaVantageRequestsPerMinute,
+	}, nil
+}
+
+// Name identifies the Alpha Vantage source
+func (as *AlphaVantageSource) Name() string {
+	return "alphavantage"
+}
+
+// Supports reports whether a symbol is a US listing; Alpha Vantage's few requests are wasted on indexes and other markets
+func (as *AlphaVantageSource) Supports(symbol string) bool {
+	return models.ExchangeOf(symbol) == models.ExchangeUS && !strings.HasPrefix(symbol, "^")
+}
+
+// Fetch returns the latest quote of a symbol from the GLOBAL_QUOTE endpoint
+func (as *AlphaVantageSource) Fetch(ctx context.Context, symbol string) (models.Quote, error) {
+	query := url.Values{}
+	query.Set("function", "GLOBAL_QUOTE")
+	query.Set("symbol", symbol)
+
+	result, err := as.get(ctx, query)
+	if err != nil {
+		return models.Quote{}, err
+	}
+	if len(result.GlobalQuote) == 0 {
+		return models.Quote{}, fmt.Errorf("%w: %s", ErrElementNotFound, symbol)
+	}
+
+	price, err := ParsePrice(result.GlobalQuote["05. price"])
+	if err != nil {
+		return models.Quote{}, fmt.Errorf("%w: %v", ErrAlphaVantageUnavailable, err)
+	}
+
+	quote := models.Quote{
+		Symbol:    symbol,
+		Price:     price,
+		Currency:  models.CurrencyFor(symbol),
+		Timestamp: time.Now(),
+	}
+
+	// Change and volume are optional; missing values are left at zero
+	if change, err := ParsePrice(result.GlobalQuote["09. change"]); err == nil {
+		quote.Change = change
+	}
+	if changePercent, err := ParsePrice(result.GlobalQuote["10. change percent"]); err == nil {
+		quote.ChangePercent = changePercent
+	}
+	if volume, err := strconv.ParseInt(result.GlobalQuote["06. volume"], 10, 64); err == nil {
+		quote.Volume = volume
+	}
+
+	return quote, nil
+}
+
+// FetchDailyCloses returns daily closing prices for a symbol between from and to from the TIME_SERIES_DAILY endpoint
+func (as *AlphaVantageSource) FetchDailyCloses(ctx context.Context, symbol string, from, to time.Time) ([]models.PricePoint, error) {
+	query := url.Values{}
+	query.Set("function", "TIME_SERIES_DAILY")
+	query.Set("symbol", symbol)
+	// The compact series covers the last 100 trading days; older ranges need the full history
+	if time.Since(from) > 140*24*time.Hour {
+		query.Set("outputsize", "full")
+	}
+
+	result, err := as.get(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Daily) == 0 {
+		return nil, fmt.Errorf("%w: empty response for %s", ErrHistoryUnavailable, symbol)
+	}
+
+	var points []models.PricePoint
+	for day, values := range result.Daily {
+		date, err := time.Parse("2006-01-02", day)
+		if err != nil || date.Before(from) || date.After(to) {
+			continue
+		}
+
+		closePrice, err := strconv.ParseFloat(values["4. close"], 64)
+		if err != nil {
+			continue
+		}
+		point := models.PricePoint{Timestamp: date, Close: closePrice}
+		if volume, err := strconv.ParseInt(values["5. volume"], 10, 64); err == nil {
+			point.Volume = volume
+		}
+		points = append(points, point)
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Timestamp.Before(points[j].Timestamp)
+	})
+	return points, nil
+}
+
+// get waits for a free request slot, calls the query endpoint and decodes the JSON response
+func (as *AlphaVantageSource) get(ctx context.Context, query url.Values) (alphaVantageResponse, error) {
+	if err := as.wait(ctx); err != nil {
+		return alphaVantageResponse{}, fmt.Errorf("%w: %v", ErrAlphaVantageUnavailable, err)
+	}
+
+	query.Set("apikey", as.apiKey)
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://www.alphavantage.co/query?"+query.Encode(), nil)
+	if err != nil {
+		return alphaVantageResponse{}, fmt.Errorf("%w: %v", ErrAlphaVantageUnavailable, err)
+	}
+
+	resp, err := as.client.Do(req)
+	if err != nil {
+		return alphaVantageResponse{}, fmt.Errorf("%w: %v", ErrAlphaVantageUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return alphaVantageResponse{}, fmt.Errorf("%w: received status code %d", ErrAlphaVantageUnavailable, resp.StatusCode)
+	}
+
+	var result alphaVantageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return alphaVantageResponse{}, fmt.Errorf("%w: %v", ErrAlphaVantageUnavailable, err)
+	}
+
+	// Errors and rate limit notices are returned with status 200
+	if result.ErrorMessage != "" {
+		return alphaVantageResponse{}, fmt.Errorf("%w: %s", ErrElementNotFound, result.ErrorMessage)
+	}
+	if notice := result.Note + result.Information; notice != "" {
+		if strings.Contains(strings.ToLower(notice), "rate limit") || strings.Contains(notice, "call frequency") {
+			as.backOff()
+			return alphaVantageResponse{}, fmt.Errorf("%w: %s", ErrRateLimited, notice)
+		}
+		return alphaVantageResponse{}, fmt.Errorf("%w: %s", ErrAlphaVantageUnavailable, notice)
+	}
+
+	return result, nil
+}
+
+// wait blocks until the next request slot, reserving it for the caller; a caller giving up hands its slot back
+// unless a later one was reserved after it
+func (as *AlphaVantageSource) wait(ctx context.Context) error {
+	as.mu.Lock()
+	now := time.Now()
+	slot := as.next
+	if slot.Before(now) {
+		slot = now
+	}
+	as.next = slot.Add(as.interval)
+	as.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		as.mu.Lock()
+		if as.next.Equal(slot.Add(as.interval)) {
+			as.next = slot
+		}
+		as.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// backOff pauses requests for a full minute after the API reports the rate limit was hit
+func (as *AlphaVantageSource) backOff() {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	if resume := time.Now().Add(time.Minute); as.next.Before(resume) {
+		as.next = resume
+	}
+}
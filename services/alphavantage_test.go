@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAlphaVantageSupports(t *testing.T) {
+	as := &AlphaVantageSource{}
+	tests := []struct {
+		symbol string
+		want   bool
+	}{
+		{"AAPL", true},
+		{"BRK-B", true},
+		{"^GSPC", false},
+		{"005930.KS", false},
+		{"VOD.L", false},
+		{"BTC-USD", false},
+		{"EURUSD=X", false},
+	}
+	for _, tt := range tests {
+		if got := as.Supports(tt.symbol); got != tt.want {
+			t.Errorf("Supports(%q) = %v, want %v", tt.symbol, got, tt.want)
+		}
+	}
+}
+
+func TestAlphaVantageWaitReturnsCancelledSlot(t *testing.T) {
+	as := &AlphaVantageSource{interval: time.Hour}
+	if err := as.wait(context.Background()); err != nil {
+		t.Fatalf("first wait: %v", err)
+	}
+	reserved := as.next
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := as.wait(ctx); err == nil {
+		t.Fatal("wait returned before its slot")
+	}
+	if !as.next.Equal(reserved) {
+		t.Errorf("next slot = %v, want the cancelled slot %v back", as.next, reserved)
+	}
+}
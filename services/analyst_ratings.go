@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// fmpGradeChange is an entry of the FMP upgrades-downgrades endpoint
+type fmpGradeChange struct {
+	PublishedDate  string `json:"publishedDate"`
+	NewsURL        string `json:"newsURL"`
+	NewGrade       string `json:"newGrade"`
+	PreviousGrade  string `json:"previousGrade"`
+	GradingCompany string `json:"gradingCompany"`
+	Action         string `json:"action"`
+}
+
+// fmpPriceTarget is an entry of the FMP price-target endpoint
+type fmpPriceTarget struct {
+	PublishedDate  string  `json:"publishedDate"`
+	PriceTarget    float64 `json:"priceTarget"`
+	AnalystCompany string  `json:"analystCompany"`
+}
+
+// FetchAnalystRatings returns recent rating changes for a symbol, each matched with the firm's price target from the same day
+func (fc *FMPClient) FetchAnalystRatings(ctx context.Context, symbol string) ([]models.AnalystRating, error) {
+	var grades []fmpGradeChange
+	if err := fc.get(ctx, "upgrades-downgrades", symbol, &grades); err != nil {
+		return nil, err
+	}
+
+	var targets []fmpPriceTarget
+	if err := fc.get(ctx, "price-target", symbol, &targets); err != nil {
+		return nil, err
+	}
+
+	// Index price targets by firm and day
+	targetsByKey := make(map[string]float64)
+	for _, target := range targets {
+		published, err := time.Parse(time.RFC3339, target.PublishedDate)
+		if err != nil {
+			continue
+		}
+		key := strings.ToLower(target.AnalystCompany) + published.Format("2006-01-02")
+		if _, exists := targetsByKey[key]; !exists {
+			targetsByKey[key] = target.PriceTarget
+		}
+	}
+
+	ratings := make([]models.AnalystRating, 0, len(grades))
+	for _, grade := range grades {
+		published, err := time.Parse(time.RFC3339, grade.PublishedDate)
+		if err != nil {
+			continue
+		}
+
+		ratings = append(ratings, models.AnalystRating{
+			Symbol:      symbol,
+			Firm:        grade.GradingCompany,
+			Action:      strings.ToLower(grade.Action),
+			FromGrade:   grade.PreviousGrade,
+			ToGrade:     grade.NewGrade,
+			PriceTarget: targetsByKey[strings.ToLower(grade.GradingCompany)+published.Format("2006-01-02")],
+			URL:         grade.NewsURL,
+			PublishedAt: published,
+		})
+	}
+
+	return ratings, nil
+}
+
+// SaveAnalystRatings stores rating changes and returns the ones that were not seen before
+func (db *Database) SaveAnalystRatings(ratings []models.AnalystRating) ([]models.AnalystRating, error) {
+	if len(ratings) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("analyst_ratings")
+	if err != nil {
+		return nil, err
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(ratings))
+	for _, rating := range ratings {
+		filter := bson.D{
+			{Key: "symbol", Value: rating.Symbol},
+			{Key: "firm", Value: rating.Firm},
+			{Key: "action", Value: rating.Action},
+			{Key: "publishedAt", Value: rating.PublishedAt},
+		}
+		// Insert only; existing ratings are left untouched
+		update := bson.D{{Key: "$setOnInsert", Value: rating}}
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+	}
+
+	result, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(true))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	inserted := make([]models.AnalystRating, 0, len(result.UpsertedIDs))
+	for index := range result.UpsertedIDs {
+		inserted = append(inserted, ratings[index])
+	}
+	return inserted, nil
+}
+
+// CountAnalystRatings returns how many ratings are stored for a symbol
+func (db *Database) CountAnalystRatings(symbol string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("analyst_ratings")
+	if err != nil {
+		return 0, err
+	}
+	count, err := collection.CountDocuments(ctx, bson.D{{Key: "symbol", Value: symbol}})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,432 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+
+	"stock-bot/models"
+)
+
+// ErrBrowserUnavailable is returned when the headless browser can't be started
+var ErrBrowserUnavailable = errors.New("headless browser unavailable")
+
+// Browser settings
+const (
+	browserWatchInterval = time.Minute      // How often the watchdog pings the browser
+	browserPingTimeout   = 10 * time.Second // How long a ping may take before the browser is considered dead
+	browserStartTimeout  = 30 * time.Second // How long launching Chrome or opening a tab may take
+)
+
+// BrowserPool hands out reusable tabs of a headless browser and replaces the browser once it has loaded enough
+// pages or run long enough, so Chrome's memory doesn't grow over days of fetching
+type BrowserPool struct {
+	opts   []chromedp.ExecAllocatorOption
+	limits models.BrowserConfig
+	slots  chan struct{} // One token per tab that may be open
+
+	// launch starts a browser process; replaced in tests
+	launch func(ctx context.Context) (*pooledBrowser, error)
+
+	// mu guards the fields below and is never held while waiting on Chrome, so a hung browser only stalls the
+	// fetches using it
+	mu           sync.Mutex
+	current      *pooledBrowser
+	starting     chan struct{} // Closed when the browser being started is running or failed to start
+	lastStartErr error
+	startErr     error // Set when the first browser failed to start
+	started      bool
+	closed       bool
+	restarts     int // Browsers replaced on reaching the navigation or age limit
+	recoveries   int // Browsers replaced after they stopped answering
+	lastRecovery time.Time
+}
+
+// BrowserStats describes the running browser and its restarts, for the status endpoint
+type BrowserStats struct {
+	Running      bool      `json:"running"`
+	StartedAt    time.Time `json:"startedAt"`
+	Navigations  int       `json:"navigations"`
+	ActiveTabs   int       `json:"activeTabs"`
+	Restarts     int       `json:"restarts"`
+	Recoveries   int       `json:"recoveries"`
+	LastRecovery time.Time `json:"lastRecovery"`
+}
+
+// pooledBrowser is one browser process with its idle tabs
+type pooledBrowser struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	allocCancel context.CancelFunc
+	startedAt   time.Time
+	navigations int
+	active      int // Tabs handed out and not yet released
+	idle        []*BrowserTab
+	retired     bool // Replaced by a newer browser; closed once its last tab is released
+}
+
+// BrowserTab is a tab lent out by the pool; it must be given back with Release
+type BrowserTab struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	browser *pooledBrowser
+}
+
+// Context returns the tab's chromedp context; derive timeouts from it rather than cancelling it
+func (t *BrowserTab) Context() context.Context {
+	return t.ctx
+}
+
+// NewBrowserPool creates a pool that starts Chrome with the allocator options on first use
+func NewBrowserPool(opts []chromedp.ExecAllocatorOption, limits models.BrowserConfig) *BrowserPool {
+	limits.MaxTabs = max(limits.MaxTabs, 1)
+	bp := &BrowserPool{
+		opts:   opts,
+		limits: limits,
+		slots:  make(chan struct{}, limits.MaxTabs),
+	}
+	bp.launch = bp.startBrowser
+	return bp
+}
+
+// Available starts the browser on first use and reports whether it started
+func (bp *BrowserPool) Available() bool {
+	bp.mu.Lock()
+	if bp.started {
+		defer bp.mu.Unlock()
+		return bp.startErr == nil
+	}
+	bp.started = true
+	bp.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), browserStartTimeout)
+	defer cancel()
+	err := bp.ensureRunning(ctx)
+	if err != nil {
+		slog.Error("Error starting browser, falling back to HTTP scraping", "error", err)
+	}
+
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.startErr = err
+	return err == nil
+}
+
+// ensureRunning starts a browser when none is running. One caller launches it without holding the lock while
+// the others wait for it, each no longer than its ctx allows
+func (bp *BrowserPool) ensureRunning(ctx context.Context) error {
+	bp.mu.Lock()
+	if bp.closed {
+		bp.mu.Unlock()
+		return ErrBrowserUnavailable
+	}
+	if bp.current != nil {
+		bp.mu.Unlock()
+		return nil
+	}
+	if starting := bp.starting; starting != nil {
+		bp.mu.Unlock()
+		select {
+		case <-starting:
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %v", ErrBrowserUnavailable, ctx.Err())
+		}
+
+		bp.mu.Lock()
+		defer bp.mu.Unlock()
+		if bp.current == nil {
+			return bp.lastStartErr
+		}
+		return nil
+	}
+	bp.starting = make(chan struct{})
+	bp.mu.Unlock()
+
+	browser, err := bp.launch(ctx)
+
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	close(bp.starting)
+	bp.starting = nil
+	bp.lastStartErr = err
+	if err != nil {
+		return err
+	}
+	if bp.closed {
+		bp.closeBrowser(browser)
+		return ErrBrowserUnavailable
+	}
+	bp.current = browser
+	return nil
+}
+
+// Acquire waits for a free tab, reusing an idle one when possible, and restarts the browser first when it has
+// reached its navigation or age limit
+func (bp *BrowserPool) Acquire(ctx context.Context) (*BrowserTab, error) {
+	select {
+	case bp.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	tab, err := bp.take(ctx)
+	if err != nil {
+		<-bp.slots
+		return nil, err
+	}
+	return tab, nil
+}
+
+// take hands out a tab of the current browser, counting the navigation it is about to make; a new tab is opened
+// without holding the lock
+func (bp *BrowserPool) take(ctx context.Context) (*BrowserTab, error) {
+	browser, tab, err := bp.reserve(ctx)
+	if err != nil || tab != nil {
+		return tab, err
+	}
+
+	tab, err = bp.openTab(ctx, browser)
+	if err != nil {
+		bp.mu.Lock()
+		browser.active--
+		if browser.retired && browser.active == 0 {
+			bp.closeBrowser(browser)
+		}
+		bp.mu.Unlock()
+		return nil, err
+	}
+	return tab, nil
+}
+
+// reserve counts a navigation on the current browser, starting or restarting it first when needed, and returns
+// an idle tab of it when there is one
+func (bp *BrowserPool) reserve(ctx context.Context) (*pooledBrowser, *BrowserTab, error) {
+	for {
+		if err := bp.ensureRunning(ctx); err != nil {
+			return nil, nil, err
+		}
+
+		bp.mu.Lock()
+		browser := bp.current
+		if browser == nil {
+			// Retired by the watchdog in the meantime
+			bp.mu.Unlock()
+			continue
+		}
+		if bp.expired(browser) {
+			slog.Info("Restarting browser", "navigations", browser.navigations, "age", time.Since(browser.startedAt).Round(time.Minute))
+			bp.retire(browser)
+			bp.current = nil
+			bp.restarts++
+			bp.mu.Unlock()
+			continue
+		}
+
+		browser.navigations++
+		browser.active++
+		var tab *BrowserTab
+		if n := len(browser.idle); n > 0 {
+			tab = browser.idle[n-1]
+			browser.idle = browser.idle[:n-1]
+		}
+		bp.mu.Unlock()
+		return browser, tab, nil
+	}
+}
+
+// openTab opens a new tab in a browser, giving up when ctx ends or the browser doesn't answer in time
+func (bp *BrowserPool) openTab(ctx context.Context, browser *pooledBrowser) (*BrowserTab, error) {
+	// Open the tab under its own context, so a timeout of the first fetch doesn't close it
+	tabCtx, tabCancel := chromedp.NewContext(browser.ctx)
+	openCtx, openCancel := context.WithTimeout(ctx, browserStartTimeout)
+	defer openCancel()
+	stop := context.AfterFunc(openCtx, tabCancel)
+
+	err := chromedp.Run(tabCtx)
+	if !stop() || err != nil {
+		go tabCancel()
+		if err == nil {
+			err = openCtx.Err()
+		}
+		return nil, fmt.Errorf("%w: %v", ErrBrowserUnavailable, err)
+	}
+	return &BrowserTab{ctx: tabCtx, cancel: tabCancel, browser: browser}, nil
+}
+
+// Release gives a tab back; a tab whose fetch failed is closed rather than reused, as it may be stuck mid-load
+func (bp *BrowserPool) Release(tab *BrowserTab, healthy bool) {
+	defer func() { <-bp.slots }()
+
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	browser := tab.browser
+	browser.active--
+	if healthy && !browser.retired && !bp.closed {
+		browser.idle = append(browser.idle, tab)
+		return
+	}
+
+	go tab.cancel()
+	if browser.retired && browser.active == 0 {
+		bp.closeBrowser(browser)
+	}
+}
+
+// Close shuts the browser down; tabs still out are closed when released
+func (bp *BrowserPool) Close() {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	bp.closed = true
+	if bp.current != nil {
+		bp.retire(bp.current)
+		bp.current = nil
+	}
+}
+
+// Stats returns the state of the running browser and how often it was replaced
+func (bp *BrowserPool) Stats() BrowserStats {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	stats := BrowserStats{Restarts: bp.restarts, Recoveries: bp.recoveries, LastRecovery: bp.lastRecovery}
+	if browser := bp.current; browser != nil {
+		stats.Running = true
+		stats.StartedAt = browser.startedAt
+		stats.Navigations = browser.navigations
+		stats.ActiveTabs = browser.active
+	}
+	return stats
+}
+
+// Watch pings the browser every minute until ctx ends and replaces it once it stops answering, so a crashed
+// Chrome doesn't fail every fetch until the bot is restarted
+func (bp *BrowserPool) Watch(ctx context.Context) {
+	ticker := time.NewTicker(browserWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			bp.checkHealth()
+		}
+	}
+}
+
+// checkHealth pings the current browser, restarting it when the ping fails
+func (bp *BrowserPool) checkHealth() {
+	bp.mu.Lock()
+	browser := bp.current
+	bp.mu.Unlock()
+	if browser == nil {
+		return
+	}
+
+	// Ping without the lock, so fetches go on while a hung browser times out
+	err := pingBrowser(browser)
+	if err == nil {
+		return
+	}
+
+	bp.mu.Lock()
+	if bp.closed || bp.current != browser {
+		bp.mu.Unlock()
+		return // Shut down or replaced while pinging
+	}
+	slog.Warn("Browser stopped responding, restarting it", "error", err, "age", time.Since(browser.startedAt).Round(time.Minute))
+	bp.retire(browser)
+	bp.current = nil
+	bp.mu.Unlock()
+
+	// The replacement is launched without the lock; fetches arriving meanwhile wait for it rather than start another
+	ctx, cancel := context.WithTimeout(context.Background(), browserStartTimeout)
+	defer cancel()
+	if err := bp.ensureRunning(ctx); err != nil {
+		// The next fetch tries to start it again
+		slog.Error("Error restarting browser", "error", err)
+		return
+	}
+
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.recoveries++
+	bp.lastRecovery = time.Now()
+	slog.Info("Browser recovered", "recoveries", bp.recoveries)
+}
+
+// pingBrowser lists the browser's targets, which fails once the Chrome process has died or hangs
+func pingBrowser(browser *pooledBrowser) error {
+	if err := browser.ctx.Err(); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(browser.ctx, browserPingTimeout)
+	defer cancel()
+
+	_, err := chromedp.Targets(ctx)
+	return err
+}
+
+// expired reports whether a browser reached its navigation or age limit
+func (bp *BrowserPool) expired(browser *pooledBrowser) bool {
+	if bp.limits.MaxNavigations > 0 && browser.navigations >= bp.limits.MaxNavigations {
+		return true
+	}
+	return bp.limits.MaxAge > 0 && time.Since(browser.startedAt) >= bp.limits.MaxAge
+}
+
+// retire stops handing out a browser's tabs, closing it now if none are in use and otherwise after the last release
+func (bp *BrowserPool) retire(browser *pooledBrowser) {
+	browser.retired = true
+	idle := browser.idle
+	browser.idle = nil
+	go func() {
+		for _, tab := range idle {
+			tab.cancel()
+		}
+	}()
+	if browser.active == 0 {
+		bp.closeBrowser(browser)
+	}
+}
+
+// closeBrowser ends a browser process in the background, as cancelling waits for Chrome to exit
+func (bp *BrowserPool) closeBrowser(browser *pooledBrowser) {
+	go func() {
+		browser.cancel()
+		browser.allocCancel()
+	}()
+}
+
+// startBrowser launches a browser process, giving up when ctx ends or Chrome doesn't answer in time
+func (bp *BrowserPool) startBrowser(ctx context.Context) (*pooledBrowser, error) {
+	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), bp.opts...)
+	browserCtx, browserCancel := chromedp.NewContext(
+		allocCtx,
+		chromedp.WithLogf(func(format string, args ...any) { slog.Debug(fmt.Sprintf(format, args...)) }),
+	)
+
+	startCtx, startCancel := context.WithTimeout(ctx, browserStartTimeout)
+	defer startCancel()
+	stop := context.AfterFunc(startCtx, allocCancel)
+
+	err := chromedp.Run(browserCtx)
+	if !stop() || err != nil {
+		go func() {
+			browserCancel()
+			allocCancel()
+		}()
+		if err == nil {
+			err = startCtx.Err()
+		}
+		return nil, fmt.Errorf("%w: %v", ErrBrowserUnavailable, err)
+	}
+	return &pooledBrowser{ctx: browserCtx, cancel: browserCancel, allocCancel: allocCancel, startedAt: time.Now()}, nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"stock-bot/models"
+)
+
+// fakeBrowser returns a browser without a Chrome process; a dead one has its context cancelled
+func fakeBrowser(alive bool) *pooledBrowser {
+	ctx, cancel := context.WithCancel(context.Background())
+	if !alive {
+		cancel()
+	}
+	return &pooledBrowser{ctx: ctx, cancel: cancel, allocCancel: func() {}, startedAt: time.Now()}
+}
+
+// returnsWithin fails the test when f doesn't return within a second
+func returnsWithin(t *testing.T, name string, f func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		f()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("%s blocked", name)
+	}
+}
+
+func TestCheckHealthReplacesDeadBrowser(t *testing.T) {
+	bp := NewBrowserPool(nil, models.BrowserConfig{MaxTabs: 1})
+	dead, replacement := fakeBrowser(false), fakeBrowser(true)
+	bp.current = dead
+
+	launched := make(chan struct{})
+	release := make(chan struct{})
+	bp.launch = func(ctx context.Context) (*pooledBrowser, error) {
+		close(launched)
+		<-release
+		return replacement, nil
+	}
+
+	checked := make(chan struct{})
+	go func() {
+		bp.checkHealth()
+		close(checked)
+	}()
+	<-launched
+
+	// The pool stays usable while the replacement launches
+	returnsWithin(t, "Stats during a restart", func() { bp.Stats() })
+	returnsWithin(t, "Acquire during a restart", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if _, err := bp.Acquire(ctx); !errors.Is(err, ErrBrowserUnavailable) {
+			t.Errorf("Acquire = %v, want ErrBrowserUnavailable once ctx ends", err)
+		}
+	})
+
+	close(release)
+	<-checked
+
+	if !dead.retired {
+		t.Error("dead browser was not retired")
+	}
+	stats := bp.Stats()
+	if bp.current != replacement || !stats.Running || stats.Recoveries != 1 {
+		t.Errorf("after recovery: current replaced = %v, stats = %+v", bp.current == replacement, stats)
+	}
+}
+
+func TestCheckHealthSkipsClosedPool(t *testing.T) {
+	bp := NewBrowserPool(nil, models.BrowserConfig{MaxTabs: 1})
+	dead := fakeBrowser(false)
+	bp.current = dead
+	bp.closed = true
+	bp.launch = func(ctx context.Context) (*pooledBrowser, error) {
+		t.Error("launched a browser for a closed pool")
+		return nil, ErrBrowserUnavailable
+	}
+
+	bp.checkHealth()
+	if bp.Stats().Recoveries != 0 {
+		t.Error("counted a recovery for a closed pool")
+	}
+}
+
+func TestAcquireGivesUpOnHangingLaunch(t *testing.T) {
+	bp := NewBrowserPool(nil, models.BrowserConfig{MaxTabs: 2})
+	bp.launch = func(ctx context.Context) (*pooledBrowser, error) {
+		<-ctx.Done()
+		return nil, ErrBrowserUnavailable
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	acquired := make(chan error, 1)
+	go func() {
+		_, err := bp.Acquire(ctx)
+		acquired <- err
+	}()
+
+	returnsWithin(t, "Stats during a launch", func() { bp.Stats() })
+	returnsWithin(t, "checkHealth during a launch", bp.checkHealth)
+	select {
+	case err := <-acquired:
+		if !errors.Is(err, ErrBrowserUnavailable) {
+			t.Errorf("Acquire = %v, want ErrBrowserUnavailable", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Acquire outlived its context")
+	}
+}
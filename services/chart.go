@@ -0,0 +1,125 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"stock-bot/models"
+)
+
+// Error definitions for chart rendering
+var (
+	ErrNotEnoughData = errors.New("not enough data points to render a chart")
+)
+
+// Chart layout and colors
+const (
+	chartWidth     = 800
+	chartHeight    = 400
+	chartPadding   = 24
+	chartGridLines = 4
+)
+
+var (
+	chartBackground = color.RGBA{R: 0x1e, G: 0x1e, B: 0x24, A: 0xff}
+	chartGrid       = color.RGBA{R: 0x3a, G: 0x3a, B: 0x44, A: 0xff}
+	chartUp         = color.RGBA{R: 0x26, G: 0xa6, B: 0x9a, A: 0xff}
+	chartDown       = color.RGBA{R: 0xef, G: 0x53, B: 0x50, A: 0xff}
+)
+
+// RenderPriceChart draws a line chart of closing prices and returns it as a PNG image
+func RenderPriceChart(points []models.PricePoint) ([]byte, error) {
+	if len(points) < 2 {
+		return nil, ErrNotEnoughData
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, chartHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: chartBackground}, image.Point{}, draw.Src)
+
+	// Horizontal grid lines
+	plotHeight := chartHeight - 2*chartPadding
+	plotWidth := chartWidth - 2*chartPadding
+	for i := 0; i <= chartGridLines; i++ {
+		y := chartPadding + i*plotHeight/chartGridLines
+		for x := chartPadding; x < chartWidth-chartPadding; x++ {
+			img.Set(x, y, chartGrid)
+		}
+	}
+
+	minPrice, maxPrice := points[0].Close, points[0].Close
+	for _, point := range points {
+		minPrice = min(minPrice, point.Close)
+		maxPrice = max(maxPrice, point.Close)
+	}
+	priceRange := maxPrice - minPrice
+	if priceRange == 0 {
+		priceRange = 1
+	}
+
+	lineColor := chartUp
+	if points[len(points)-1].Close < points[0].Close {
+		lineColor = chartDown
+	}
+
+	toPixel := func(i int, price float64) (int, int) {
+		x := chartPadding + i*plotWidth/(len(points)-1)
+		y := chartPadding + int(float64(plotHeight)*(maxPrice-price)/priceRange)
+		return x, y
+	}
+
+	for i := 1; i < len(points); i++ {
+		x0, y0 := toPixel(i-1, points[i-1].Close)
+		x1, y1 := toPixel(i, points[i].Close)
+		drawLine(img, x0, y0, x1, y1, lineColor)
+		drawLine(img, x0, y0+1, x1, y1+1, lineColor)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLine draws a straight line using Bresenham's algorithm
+func drawLine(img *image.RGBA, x0, y0, x1, y1 int, c color.Color) {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	errAcc := dx + dy
+	for {
+		img.Set(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * errAcc
+		if e2 >= dy {
+			errAcc += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			errAcc += dx
+			y0 += sy
+		}
+	}
+}
+
+// abs returns the absolute value of an int
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+)
+
+// Error definitions for CoinGecko data
+var (
+	ErrCoinGeckoUnavailable = errors.New("coingecko data unavailable")
+)
+
+// coinGeckoIDs maps the tickers of common coins to their CoinGecko ids, saving a search request
+var coinGeckoIDs = map[string]string{
+	"BTC":  "bitcoin",
+	"ETH":  "ethereum",
+	"SOL":  "solana",
+	"XRP":  "ripple",
+	"BNB":  "binancecoin",
+	"DOGE": "dogecoin",
+	"ADA":  "cardano",
+	"AVAX": "avalanche-2",
+	"DOT":  "polkadot",
+	"LINK": "chainlink",
+	"LTC":  "litecoin",
+	"TRX":  "tron",
+}
+
+// CoinGeckoSource reads crypto quotes from the public CoinGecko API; other asset classes are left to the next source
+type CoinGeckoSource struct {
+	client *http.Client
+
+	mu  sync.Mutex
+	ids map[string]string // Coin ticker to CoinGecko id, including ones found by search
+}
+
+// coinGeckoSearchResponse is the CoinGecko /search response, with coins ranked by market cap
+type coinGeckoSearchResponse struct {
+	Coins []struct {
+		ID     string `json:"id"`
+		Symbol string `json:"symbol"`
+	} `json:"coins"`
+}
+
+// NewCoinGeckoSource creates a new CoinGeckoSource instance
+func NewCoinGeckoSource() *CoinGeckoSource {
+	return &CoinGeckoSource{
+		client: &http.Client{Timeout: 15 * time.Second},
+		ids:    maps.Clone(coinGeckoIDs),
+	}
+}
+
+// Name identifies the CoinGecko source
+func (cs *CoinGeckoSource) Name() string {
+	return "coingecko"
+}
+
+// Supports reports whether a symbol is a crypto pair such as BTC-USD
+func (cs *CoinGeckoSource) Supports(symbol string) bool {
+	return models.AssetClassOf(symbol) == models.AssetCrypto
+}
+
+// Fetch returns the latest quote of a crypto pair, with the change over the last 24 hours
+func (cs *CoinGeckoSource) Fetch(ctx context.Context, symbol string) (models.Quote, error) {
+	coin, quoteCurrency, found := strings.Cut(symbol, "-")
+	if !found || !cs.Supports(symbol) {
+		return models.Quote{}, fmt.Errorf("%w: %s is not a crypto pair", ErrElementNotFound, symbol)
+	}
+
+	id, err := cs.coinID(ctx, coin)
+	if err != nil {
+		return models.Quote{}, err
+	}
+
+	// Tether tracks the dollar, which CoinGecko quotes directly
+	vs := strings.ToLower(quoteCurrency)
+	if vs == "usdt" {
+		vs = "usd"
+	}
+
+	query := url.Values{}
+	query.Set("ids", id)
+	query.Set("vs_currencies", vs)
+	query.Set("include_24hr_change", "true")
+	query.Set("include_24hr_vol", "true")
+	query.Set("include_last_updated_at", "true")
+
+	var result map[string]map[string]float64
+	if err := cs.get(ctx, "/simple/price", query, &result); err != nil {
+		return models.Quote{}, err
+	}
+
+	fields := result[id]
+	price := fields[vs]
+	if price == 0 {
+		return models.Quote{}, fmt.Errorf("%w: no %s price for %s", ErrElementNotFound, vs, symbol)
+	}
+
+	quote := models.Quote{
+		Symbol:        symbol,
+		Price:         price,
+		ChangePercent: fields[vs+"_24h_change"],
+		Volume:        int64(fields[vs+"_24h_vol"]),
+		Currency:      models.CurrencyFor(symbol),
+		Timestamp:     time.Now(),
+	}
+	if quote.ChangePercent != 0 {
+		quote.Change = price - price/(1+quote.ChangePercent/100)
+	}
+	if updated := fields["last_updated_at"]; updated > 0 {
+		quote.Timestamp = time.Unix(int64(updated), 0)
+	}
+	return quote, nil
+}
+
+// coinID returns the CoinGecko id of a coin ticker, searching for tickers not seen before
+func (cs *CoinGeckoSource) coinID(ctx context.Context, coin string) (string, error) {
+	cs.mu.Lock()
+	id, ok := cs.ids[coin]
+	cs.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	query := url.Values{}
+	query.Set("query", coin)
+
+	var result coinGeckoSearchResponse
+	if err := cs.get(ctx, "/search", query, &result); err != nil {
+		return "", err
+	}
+	// Several coins can share a ticker; the search ranks the largest first
+	for _, match := range result.Coins {
+		if strings.EqualFold(match.Symbol, coin) {
+			cs.mu.Lock()
+			cs.ids[coin] = match.ID
+			cs.mu.Unlock()
+			return match.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unknown coin %s", ErrElementNotFound, coin)
+}
+
+// get sends a request to a CoinGecko API endpoint and decodes the JSON response into v
+func (cs *CoinGeckoSource) get(ctx context.Context, path string, query url.Values, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.coingecko.com/api/v3"+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCoinGeckoUnavailable, err)
+	}
+
+	resp, err := cs.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCoinGeckoUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %s", ErrRateLimited, cs.Name())
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%w: received status code %d", ErrCoinGeckoUnavailable, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("%w: %v", ErrCoinGeckoUnavailable, err)
+	}
+	return nil
+}
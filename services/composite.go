@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"stock-bot/models"
+)
+
+// CompositeMessenger delivers every message to all of its messengers concurrently
+type CompositeMessenger struct {
+	names      []string
+	messengers []Messenger
+}
+
+// NewCompositeMessenger creates an empty CompositeMessenger
+func NewCompositeMessenger() *CompositeMessenger {
+	return &CompositeMessenger{}
+}
+
+// Add registers a messenger under a channel name used in errors and dedup keys
+func (cm *CompositeMessenger) Add(name string, m Messenger) {
+	cm.names = append(cm.names, name)
+	cm.messengers = append(cm.messengers, m)
+}
+
+// Len returns how many messengers are registered
+func (cm *CompositeMessenger) Len() int {
+	return len(cm.messengers)
+}
+
+// Only returns the first registered messenger, for when no fan-out is needed
+func (cm *CompositeMessenger) Only() Messenger {
+	if len(cm.messengers) == 0 {
+		return nil
+	}
+	return cm.messengers[0]
+}
+
+// SendMessage sends the daily report through every messenger
+func (cm *CompositeMessenger) SendMessage(quotes map[string]models.Quote, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return cm.each(func(_ string, m Messenger) error {
+		return m.SendMessage(quotes, nil)
+	})
+}
+
+// SendAlerts sends price alerts through every messenger
+func (cm *CompositeMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return cm.each(func(_ string, m Messenger) error {
+		return m.SendAlerts(alerts, nil)
+	})
+}
+
+// SendText sends a text message through every messenger
+func (cm *CompositeMessenger) SendText(text string, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return cm.each(func(_ string, m Messenger) error {
+		return m.SendText(text, nil)
+	})
+}
+
+// each runs send for every messenger concurrently and joins the errors of the channels that failed
+func (cm *CompositeMessenger) each(send func(name string, m Messenger) error) error {
+	errs := make([]error, len(cm.messengers))
+
+	var wg sync.WaitGroup
+	for i, m := range cm.messengers {
+		wg.Add(1)
+		go func(i int, m Messenger) {
+			defer wg.Done()
+			if err := send(cm.names[i], m); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", cm.names[i], err)
+			}
+		}(i, m)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// fmpEarningsEvent is an entry of the FMP earnings calendar endpoint
+type fmpEarningsEvent struct {
+	Symbol      string   `json:"symbol"`
+	Date        string   `json:"date"`
+	Time        string   `json:"time"`
+	EPSEstimate *float64 `json:"epsEstimated"`
+}
+
+// fmpDividendEvent is an entry of the FMP dividend calendar endpoint
+type fmpDividendEvent struct {
+	Symbol      string  `json:"symbol"`
+	Date        string  `json:"date"` // Ex-dividend date
+	Dividend    float64 `json:"dividend"`
+	PaymentDate string  `json:"paymentDate"`
+}
+
+// FetchCorporateCalendar returns upcoming earnings and ex-dividend dates of the given symbols
+func (fc *FMPClient) FetchCorporateCalendar(ctx context.Context, from, to time.Time, symbols []string) ([]models.CorporateEvent, error) {
+	query := url.Values{}
+	query.Set("from", from.Format("2006-01-02"))
+	query.Set("to", to.Format("2006-01-02"))
+
+	var earnings []fmpEarningsEvent
+	if err := fc.fetch(ctx, "v3/earning_calendar", query, &earnings); err != nil {
+		return nil, err
+	}
+
+	var dividends []fmpDividendEvent
+	if err := fc.fetch(ctx, "v3/stock_dividend_calendar", query, &dividends); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var events []models.CorporateEvent
+	for _, entry := range earnings {
+		date, err := time.Parse("2006-01-02", entry.Date)
+		if err != nil || !slices.Contains(symbols, entry.Symbol) {
+			continue
+		}
+		events = append(events, models.CorporateEvent{
+			Symbol:      entry.Symbol,
+			Type:        models.CorporateEarnings,
+			Date:        date,
+			Timing:      entry.Time,
+			EPSEstimate: entry.EPSEstimate,
+			FetchedAt:   now,
+		})
+	}
+	for _, entry := range dividends {
+		date, err := time.Parse("2006-01-02", entry.Date)
+		if err != nil || !slices.Contains(symbols, entry.Symbol) {
+			continue
+		}
+		paymentDate, _ := time.Parse("2006-01-02", entry.PaymentDate)
+		events = append(events, models.CorporateEvent{
+			Symbol:      entry.Symbol,
+			Type:        models.CorporateExDividend,
+			Date:        date,
+			Dividend:    entry.Dividend,
+			PaymentDate: paymentDate,
+			FetchedAt:   now,
+		})
+	}
+
+	return events, nil
+}
+
+// SaveCorporateEvents upserts earnings and ex-dividend dates
+func (db *Database) SaveCorporateEvents(events []models.CorporateEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("corporate_events")
+	if err != nil {
+		return err
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(events))
+	for _, event := range events {
+		filter := bson.D{
+			{Key: "symbol", Value: event.Symbol},
+			{Key: "type", Value: event.Type},
+			{Key: "date", Value: event.Date},
+		}
+		writes = append(writes, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(event).SetUpsert(true))
+	}
+
+	if _, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// GetCorporateEvents returns earnings and ex-dividend dates in [from, to), ordered by date
+func (db *Database) GetCorporateEvents(from, to time.Time) ([]models.CorporateEvent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("corporate_events")
+	if !ok {
+		return nil, nil
+	}
+
+	filter := bson.D{{Key: "date", Value: bson.D{{Key: "$gte", Value: from}, {Key: "$lt", Value: to}}}}
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "symbol", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	defer cursor.Close(ctx)
+
+	var events []models.CorporateEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return events, nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+)
+
+// Error definitions for CSV import
+var (
+	ErrInvalidCSV = errors.New("invalid price history CSV")
+)
+
+// Date layouts used by Yahoo ("2024-01-31") and stooq ("2024-01-31" or "20240131") exports
+var csvDateLayouts = []string{"2006-01-02", "20060102", "01/02/2006"}
+
+// ParseHistoryCSV reads daily closes from a Yahoo or stooq price history export
+func ParseHistoryCSV(r io.Reader) ([]models.PricePoint, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCSV, err)
+	}
+
+	// Columns are matched by name, e.g. "Date"/"<DATE>" and "Close"/"<CLOSE>"
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.Trim(strings.TrimSpace(name), "<>\ufeff"))] = i
+	}
+	dateCol, hasDate := columns["date"]
+	closeCol, hasClose := columns["close"]
+	if !hasDate || !hasClose {
+		return nil, fmt.Errorf("%w: missing date or close column", ErrInvalidCSV)
+	}
+	volumeCol, hasVolume := columns["volume"]
+	if !hasVolume {
+		volumeCol, hasVolume = columns["vol"]
+	}
+
+	var points []models.PricePoint
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCSV, line, err)
+		}
+		if len(record) <= max(dateCol, closeCol) {
+			continue
+		}
+
+		date, err := parseCSVDate(record[dateCol])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidCSV, line, err)
+		}
+
+		// Yahoo writes "null" for days without trading
+		closePrice, err := strconv.ParseFloat(strings.TrimSpace(record[closeCol]), 64)
+		if err != nil || closePrice <= 0 {
+			continue
+		}
+
+		point := models.PricePoint{Timestamp: date, Close: closePrice}
+		if hasVolume && volumeCol < len(record) {
+			if volume, err := strconv.ParseFloat(strings.TrimSpace(record[volumeCol]), 64); err == nil {
+				point.Volume = int64(volume)
+			}
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// SymbolFromCSVPath derives a symbol from an export file name such as "AAPL.csv" or stooq's "aapl.us.txt"
+func SymbolFromCSVPath(path string) (string, bool) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name = strings.TrimSuffix(strings.ToLower(name), ".us")
+	return models.NormalizeSymbol(name)
+}
+
+// parseCSVDate parses a date in any of the supported export layouts
+func parseCSVDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range csvDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", value)
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"stock-bot/models"
+)
+
+func TestParseHistoryCSV(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.January, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		csv     string
+		want    []models.PricePoint
+		wantErr bool
+	}{
+		{
+			"yahoo export",
+			"Date,Open,High,Low,Close,Adj Close,Volume\n2024-01-02,187.15,188.44,183.89,185.64,184.94,82488700\n2024-01-03,184.22,185.88,183.43,184.25,183.56,58414500\n",
+			[]models.PricePoint{{Timestamp: day(2), Close: 185.64, Volume: 82488700}, {Timestamp: day(3), Close: 184.25, Volume: 58414500}},
+			false,
+		},
+		{
+			"stooq export",
+			"<TICKER>,<PER>,<DATE>,<TIME>,<OPEN>,<HIGH>,<LOW>,<CLOSE>,<VOL>,<OPENINT>\nAAPL.US,D,20240102,000000,187.15,188.44,183.89,185.64,8.24887e+07,0\n",
+			[]models.PricePoint{{Timestamp: day(2), Close: 185.64, Volume: 82488700}},
+			false,
+		},
+		{
+			"byte order mark and US dates",
+			"\ufeffDate,Close\n01/02/2024,185.64\n",
+			[]models.PricePoint{{Timestamp: day(2), Close: 185.64}},
+			false,
+		},
+		{
+			"days without trading skipped",
+			"Date,Close,Volume\n2024-01-01,null,null\n2024-01-02,185.64,abc\n2024-01-03,0,100\n2024-01-04\n",
+			[]models.PricePoint{{Timestamp: day(2), Close: 185.64}},
+			false,
+		},
+		{"header only", "Date,Close\n", nil, false},
+		{"missing close column", "Date,Open\n2024-01-02,187.15\n", nil, true},
+		{"empty file", "", nil, true},
+		{"unrecognized date", "Date,Close\nJan 2 2024,185.64\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHistoryCSV(strings.NewReader(tt.csv))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCSV) {
+					t.Fatalf("error = %v, want ErrInvalidCSV", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseHistoryCSV: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d points %+v, want %+v", len(got), got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Timestamp.Equal(tt.want[i].Timestamp) || got[i].Close != tt.want[i].Close || got[i].Volume != tt.want[i].Volume {
+					t.Errorf("point %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSymbolFromCSVPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"AAPL.csv", "AAPL", true},
+		{"/data/exports/msft.csv", "MSFT", true},
+		{"aapl.us.txt", "AAPL", true},
+		{"BRK-B.csv", "BRK-B", true},
+		{"005930.csv", "005930.KS", true},
+		{"^GSPC.csv", "^GSPC", true},
+		{"my prices.csv", "", false},
+		{".csv", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := SymbolFromCSVPath(tt.path)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("SymbolFromCSVPath(%q) = %q, %v, want %q, %v", tt.path, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
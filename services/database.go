@@ -4,8 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"strconv"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -19,16 +18,22 @@ import (
 // Database related error definitions
 var (
 	ErrMongoURINotSet      = errors.New("MONGODB_URI not set")
+	ErrMongoNotConfigured  = errors.New("MongoDB not configured; this needs MONGODB_URI")
 	ErrMongoConnection     = errors.New("failed to connect to MongoDB")
 	ErrMongoQueryFailed    = errors.New("MongoDB query failed")
 	ErrNoClosingPriceFound = errors.New("no closing price found for symbol")
 	ErrInvalidPriceFormat  = errors.New("invalid price format")
 )
 
-// Database handles MongoDB connections and operations
+// Database handles MongoDB connections and operations; price history and the watchlist go through the embedded Store,
+// which is MongoDB unless another backend is set with UseStore. With another backend MongoDB is optional: without
+// it, caches and logs aren't kept, scheduler state lasts until a restart, and everything else kept only in MongoDB
+// reads as empty and fails to change with ErrMongoNotConfigured
 type Database struct {
+	Store
 	client *mongo.Client
 	config models.Config
+	local  localState
 }
 
 // NewDatabase creates a new Database instance
@@ -53,13 +58,110 @@ func NewDatabase(mongoURI string) (*Database, error) {
 	}
 
 	return &Database{
+		Store:  &MongoStore{client: client},
 		client: client,
 		config: models.DefaultConfig(),
 	}, nil
 }
 
-// SavePrice saves stock price information to MongoDB
-func (db *Database) SavePrice(symbol, price string, isClosing bool, wg *sync.WaitGroup) error {
+// NewStoreDatabase creates a Database without MongoDB around another price store
+func NewStoreDatabase(store Store) *Database {
+	return &Database{Store: store, config: models.DefaultConfig()}
+}
+
+// OpenStore connects to the storage driver the config selects, and to MongoDB when its URI is set
+func OpenStore(config models.Config) (*Database, error) {
+	var db *Database
+	if config.MongoURI != "" {
+		var err error
+		if db, err = NewDatabase(config.MongoURI); err != nil {
+			return nil, err
+		}
+	}
+
+	var store Store
+	var err error
+	switch config.StorageDriver {
+	case models.StoragePostgres:
+		store, err = NewPostgresStore(config.PostgresURL)
+	case models.StorageSQLite:
+		store, err = NewSQLiteStore(config.SQLitePath)
+	default:
+		if db == nil {
+			return nil, ErrMongoURINotSet
+		}
+		return db, nil
+	}
+	if err != nil {
+		if db != nil {
+			db.Close()
+		}
+		return nil, err
+	}
+
+	if db == nil {
+		slog.Warn("MONGODB_URI not set: chat settings, thresholds, price targets and portfolios aren't kept, and calendars, analyst, insider and short interest alerts are off; see the README")
+		return NewStoreDatabase(store), nil
+	}
+	db.UseStore(store)
+	return db, nil
+}
+
+// collection returns a collection of the bot's MongoDB database, or ErrMongoNotConfigured without MongoDB
+func (db *Database) collection(name string) (*mongo.Collection, error) {
+	if db.client == nil {
+		return nil, ErrMongoNotConfigured
+	}
+	return db.client.Database("stock_data").Collection(name), nil
+}
+
+// optionalCollection returns a collection of data the bot runs the same without, such as caches, logs and lists that
+// can't be filled without MongoDB, or false without MongoDB; callers then read nothing and store nothing
+func (db *Database) optionalCollection(name string) (*mongo.Collection, bool) {
+	if db.client == nil {
+		return nil, false
+	}
+	return db.client.Database("stock_data").Collection(name), true
+}
+
+// UsesMongo reports whether the bot is connected to MongoDB
+func (db *Database) UsesMongo() bool {
+	return db.client != nil
+}
+
+// UseStore moves price history and the watchlist to another backend
+func (db *Database) UseStore(store Store) {
+	db.Store = store
+}
+
+// UsesMongoStore reports whether price history and the watchlist are kept in MongoDB
+func (db *Database) UsesMongoStore() bool {
+	_, ok := db.Store.(*MongoStore)
+	return ok
+}
+
+// previousCloseDays is how many days of stored closes are searched for a previous close, enough to span a long weekend
+const previousCloseDays = 10
+
+// PreviousClose returns a symbol's stored close of the last session before the one t falls in, or
+// ErrNoClosingPriceFound. Once a day's close is captured it is the latest stored one, so the latest close alone
+// would measure the day's change against itself
+func (db *Database) PreviousClose(symbol string, t time.Time) (float64, error) {
+	history, err := db.GetPriceHistory(symbol, previousCloseDays)
+	if err != nil {
+		return 0, err
+	}
+	session := models.TradingDate(symbol, t)
+	for i := len(history) - 1; i >= 0; i-- {
+		if models.TradingDate(symbol, history[i].Timestamp) < session {
+			return float64(history[i].Price), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrNoClosingPriceFound, symbol)
+}
+
+// SavePrice saves a quote's price, source and session volume to MongoDB
+func (s *MongoStore) SavePrice(quote models.Quote, isClosing bool, wg *sync.WaitGroup) error {
 	if wg != nil {
 		defer wg.Done()
 	}
@@ -67,30 +169,52 @@ func (db *Database) SavePrice(symbol, price string, isClosing bool, wg *sync.Wai
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	collection := db.client.Database("stock_data").Collection("stocks")
-	stockData := models.MongoDTO{
-		Symbol:    symbol,
-		Price:     price,
-		Timestamp: time.Now(),
-		IsClosing: isClosing,
-	}
+	collection := s.client.Database("stock_data").Collection("stocks")
+	stockData := models.NewPriceRecord(quote, isClosing, time.Now())
 
 	_, err := collection.InsertOne(ctx, stockData)
 	if err != nil {
-		log.Printf("Failed to insert stock data: %v", err)
+		slog.Error("Failed to insert stock data", "error", err)
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	slog.Debug("Saved price to MongoDB", "symbol", quote.Symbol, "price", quote.Price, "source", quote.Source, "volume", quote.Volume, "closing", isClosing)
+	return nil
+}
+
+// SavePrices saves several price records in one unordered insert, so one failed record doesn't stop the others
+func (s *MongoStore) SavePrices(records []models.MongoDTO) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := s.client.Database("stock_data").Collection("stocks")
+
+	documents := make([]any, 0, len(records))
+	for _, record := range records {
+		documents = append(documents, record)
+	}
+	if _, err := collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false)); err != nil {
+		var bulkErr mongo.BulkWriteException
+		if errors.As(err, &bulkErr) && len(bulkErr.WriteErrors) > 0 {
+			return fmt.Errorf("%w: %d of %d prices not saved: %v", ErrMongoQueryFailed, len(bulkErr.WriteErrors), len(records), err)
+		}
 		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
 	}
 
-	log.Printf("Saved %s: %s to MongoDB (closing: %v)", symbol, price, isClosing)
+	slog.Debug("Saved prices to MongoDB", "count", len(records))
 	return nil
 }
 
 // GetLatestClosingPrice retrieves the latest closing price for a specific stock
-func (db *Database) GetLatestClosingPrice(symbol string) (float64, error) {
+func (s *MongoStore) GetLatestClosingPrice(symbol string) (float64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	collection := db.client.Database("stock_data").Collection("stocks")
+	collection := s.client.Database("stock_data").Collection("stocks")
 
 	filter := bson.D{{Key: "symbol", Value: symbol}, {Key: "isClosing", Value: true}}
 	opts := options.FindOne().SetSort(bson.D{{Key: "timestamp", Value: -1}})
@@ -104,20 +228,56 @@ func (db *Database) GetLatestClosingPrice(symbol string) (float64, error) {
 		return 0, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
 	}
 
-	price, err := strconv.ParseFloat(result.Price, 64)
+	return float64(result.Price), nil
+}
+
+// GetLatestClosingPrices retrieves the latest closing price of several stocks in one aggregation;
+// symbols without a stored close are left out of the result
+func (s *MongoStore) GetLatestClosingPrices(symbols []string) (map[string]float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := s.client.Database("stock_data").Collection("stocks")
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{
+			{Key: "symbol", Value: bson.D{{Key: "$in", Value: symbols}}},
+			{Key: "isClosing", Value: true},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "symbol", Value: 1}, {Key: "timestamp", Value: -1}}}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$symbol"},
+			{Key: "price", Value: bson.D{{Key: "$first", Value: "$price"}}},
+		}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
-		return 0, fmt.Errorf("%w: %v", ErrInvalidPriceFormat, err)
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Symbol string             `bson:"_id"`
+		Price  models.StoredPrice `bson:"price"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
 	}
 
-	return price, nil
+	closes := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		closes[row.Symbol] = float64(row.Price)
+	}
+	return closes, nil
 }
 
 // GetPriceHistory retrieves price history for a specific stock
-func (db *Database) GetPriceHistory(symbol string, days int) ([]models.MongoDTO, error) {
+func (s *MongoStore) GetPriceHistory(symbol string, days int) ([]models.MongoDTO, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	collection := db.client.Database("stock_data").Collection("stocks")
+	collection := s.client.Database("stock_data").Collection("stocks")
 
 	// Query for data from the specified number of previous days
 	startDate := time.Now().AddDate(0, 0, -days)
@@ -142,10 +302,86 @@ func (db *Database) GetPriceHistory(symbol string, days int) ([]models.MongoDTO,
 	return results, nil
 }
 
-// Close terminates the database connection
+// SaveHistoricalCloses stores the daily closing prices of a symbol that aren't stored yet and returns
+// the number of new records
+func (s *MongoStore) SaveHistoricalCloses(symbol string, points []models.PricePoint) (int, error) {
+	if len(points) == 0 {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	collection := s.client.Database("stock_data").Collection("stocks")
+
+	// Time-series collections don't support upserts, so the days already stored are looked up and skipped
+	from, to := closesSpan(points)
+	filter := bson.D{
+		{Key: "symbol", Value: symbol},
+		{Key: "timestamp", Value: bson.D{{Key: "$gte", Value: from}, {Key: "$lte", Value: to}}},
+		{Key: "isClosing", Value: true},
+	}
+	cursor, err := collection.Find(ctx, filter, options.Find().SetProjection(bson.D{{Key: "timestamp", Value: 1}}))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	var stored []models.MongoDTO
+	if err := cursor.All(ctx, &stored); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	timestamps := make([]time.Time, 0, len(stored))
+	for _, record := range stored {
+		timestamps = append(timestamps, record.Timestamp)
+	}
+
+	var documents []any
+	for _, point := range newCloses(symbol, points, timestamps) {
+		documents = append(documents, models.MongoDTO{
+			Symbol:    symbol,
+			Price:     models.StoredPrice(point.Close),
+			Volume:    point.Volume,
+			Timestamp: point.Timestamp,
+			IsClosing: true,
+		})
+	}
+	if len(documents) == 0 {
+		return 0, nil
+	}
+
+	result, err := collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	slog.Info("Saved historical closes to MongoDB", "symbol", symbol, "count", len(result.InsertedIDs))
+	return len(result.InsertedIDs), nil
+}
+
+// Ping checks that MongoDB, if used, and the price store are reachable
+func (db *Database) Ping(ctx context.Context) error {
+	if db.client == nil {
+		return db.Store.Ping(ctx)
+	}
+	if err := db.client.Ping(ctx, nil); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoConnection, err)
+	}
+	if db.UsesMongoStore() {
+		return nil
+	}
+	return db.Store.Ping(ctx)
+}
+
+// Close terminates the database connections
 func (db *Database) Close() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	return db.client.Disconnect(ctx)
+	storeErr := db.Store.Close()
+	if db.client == nil {
+		return storeErr
+	}
+	if err := db.client.Disconnect(ctx); err != nil {
+		return err
+	}
+	return storeErr
 }
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"stock-bot/models"
+)
+
+func TestDatabaseWithoutMongo(t *testing.T) {
+	db := NewStoreDatabase(nil)
+
+	if _, err := db.GetUser("1"); !errors.Is(err, ErrMongoNotConfigured) {
+		t.Errorf("GetUser = %v, want ErrMongoNotConfigured", err)
+	}
+	if err := db.SetAlertThreshold("AAPL", 2); !errors.Is(err, ErrMongoNotConfigured) {
+		t.Errorf("SetAlertThreshold = %v, want ErrMongoNotConfigured", err)
+	}
+
+	users, err := db.ListUsers()
+	if err != nil || len(users) != 0 {
+		t.Errorf("ListUsers = %v, %v, want no users", users, err)
+	}
+	runs, err := db.GetJobRuns()
+	if err != nil || len(runs) != 0 {
+		t.Errorf("GetJobRuns = %v, %v, want no runs", runs, err)
+	}
+	if err := db.SaveJobRun("daily_report", "2024-03-15"); err != nil {
+		t.Errorf("SaveJobRun = %v", err)
+	}
+	if runs, err := db.GetJobRuns(); err != nil || runs["daily_report"] != "2024-03-15" {
+		t.Errorf("GetJobRuns = %v, %v, want the run kept in memory", runs, err)
+	}
+	for i, want := range []bool{true, true, false} {
+		if claimed, err := db.ClaimSMS("2024-03-15", 2); err != nil || claimed != want {
+			t.Errorf("ClaimSMS #%d = %v, %v, want %v", i+1, claimed, err, want)
+		}
+	}
+	if err := db.ReleaseSMS("2024-03-15"); err != nil {
+		t.Errorf("ReleaseSMS = %v", err)
+	}
+	if claimed, err := db.ClaimSMS("2024-03-15", 2); err != nil || !claimed {
+		t.Errorf("ClaimSMS after release = %v, %v, want a slot", claimed, err)
+	}
+	if _, err := db.SaveAnalystRatings([]models.AnalystRating{{Symbol: "AAPL"}}); !errors.Is(err, ErrMongoNotConfigured) {
+		t.Errorf("SaveAnalystRatings = %v, want ErrMongoNotConfigured", err)
+	}
+	if events, err := db.GetRecentEvents(10); err != nil || len(events) != 0 {
+		t.Errorf("GetRecentEvents = %v, %v, want no events", events, err)
+	}
+	thresholds, err := db.GetAlertThresholds(3)
+	if err != nil || thresholds.Global != 3 {
+		t.Errorf("GetAlertThresholds = %+v, %v, want the default", thresholds, err)
+	}
+	if _, err := NewMongoDedupStore(db); !errors.Is(err, ErrMongoNotConfigured) {
+		t.Errorf("NewMongoDedupStore = %v, want ErrMongoNotConfigured", err)
+	}
+}
+
+func TestOpenStore(t *testing.T) {
+	sqlite := models.DefaultConfig()
+	sqlite.StorageDriver = models.StorageSQLite
+	sqlite.SQLitePath = filepath.Join(t.TempDir(), "prices.db")
+
+	db, err := OpenStore(sqlite)
+	if err != nil {
+		t.Fatalf("OpenStore(sqlite) = %v", err)
+	}
+	defer db.Close()
+	if _, ok := db.Store.(*SQLiteStore); !ok || db.UsesMongo() {
+		t.Errorf("store = %T, uses MongoDB = %v, want SQLite alone", db.Store, db.UsesMongo())
+	}
+
+	mongo := models.DefaultConfig()
+	mongo.StorageDriver = models.StorageMongoDB
+	if _, err := OpenStore(mongo); !errors.Is(err, ErrMongoURINotSet) {
+		t.Errorf("OpenStore(mongodb) = %v, want ErrMongoURINotSet", err)
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// dedupKeyTTL is how long idempotency keys are kept
+const dedupKeyTTL = 48 * time.Hour
+
+// DedupPolicy sets the periods within which copies of a message are one message
+type DedupPolicy struct {
+	Location      *time.Location // Reports and texts go out once per calendar day in this time zone
+	AlertCooldown time.Duration  // The same alert goes out once per cooldown period
+	AlertStep     float64        // Alerts of a symbol this many points further are new alerts; 0 for none
+}
+
+// DedupStore records idempotency keys so each message reaches a recipient at most once
+type DedupStore interface {
+	// Claim records the key and reports whether this caller is the first to claim it
+	Claim(ctx context.Context, key string) (bool, error)
+	// Release removes a claimed key so a failed send can be retried
+	Release(ctx context.Context, key string) error
+}
+
+// MongoDedupStore keeps idempotency keys in a MongoDB collection with a unique index
+type MongoDedupStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoDedupStore creates the dedup collection indexes and returns the store
+func NewMongoDedupStore(db *Database) (*MongoDedupStore, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("sent_messages")
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	return &MongoDedupStore{collection: collection}, nil
+}
+
+// Claim inserts the key; a duplicate key error means another instance or attempt already sent the message
+func (s *MongoDedupStore) Claim(ctx context.Context, key string) (bool, error) {
+	now := time.Now()
+	_, err := s.collection.InsertOne(ctx, bson.D{
+		{Key: "key", Value: key},
+		{Key: "sentAt", Value: now},
+		{Key: "expiresAt", Value: now.Add(dedupKeyTTL)},
+	})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return true, nil
+}
+
+// Release deletes a claimed key
+func (s *MongoDedupStore) Release(ctx context.Context, key string) error {
+	if _, err := s.collection.DeleteOne(ctx, bson.D{{Key: "key", Value: key}}); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// dedupMessenger claims an idempotency key in the shared store before every send
+type dedupMessenger struct {
+	inner     Messenger
+	store     DedupStore
+	recipient string
+	run       string // On-demand run the messages belong to; empty for scheduled sends
+	policy    func() DedupPolicy
+}
+
+// SendMessage sends the daily report once per recipient, day and run, however many instances or retries send it
+func (dm *dedupMessenger) SendMessage(quotes map[string]models.Quote, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return dm.once("report", struct {
+		Day string `json:"day"`
+		Run string `json:"run,omitempty"`
+	}{dm.day(), dm.run}, func() error {
+		return dm.inner.SendMessage(quotes, nil)
+	})
+}
+
+// SendAlerts sends the same alerts once per recipient and cooldown period; a later alert for a symbol, such as a
+// re-alert after the cooldown or a further step, is a new message
+func (dm *dedupMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	if len(alerts) == 0 {
+		return dm.inner.SendAlerts(alerts, nil)
+	}
+
+	policy := dm.policy()
+	identities := make([]alertIdentity, 0, len(alerts))
+	for _, alert := range alerts {
+		identities = append(identities, identifyAlert(alert, policy))
+	}
+	slices.SortFunc(identities, func(a, b alertIdentity) int {
+		return strings.Compare(a.Symbol+string(a.Basis), b.Symbol+string(b.Basis))
+	})
+
+	return dm.once("alerts", identities, func() error {
+		return dm.inner.SendAlerts(alerts, nil)
+	})
+}
+
+// SendText sends the same text once per recipient, day and run, however many instances or retries send it
+func (dm *dedupMessenger) SendText(text string, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return dm.once("text", struct {
+		Day  string `json:"day"`
+		Run  string `json:"run,omitempty"`
+		Text string `json:"text"`
+	}{dm.day(), dm.run, text}, func() error {
+		return dm.inner.SendText(text, nil)
+	})
+}
+
+// day returns today's date in the policy's time zone
+func (dm *dedupMessenger) day() string {
+	loc := dm.policy().Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	return time.Now().In(loc).Format("2006-01-02")
+}
+
+// once runs send only if this is the first claim of the message's idempotency key
+func (dm *dedupMessenger) once(method string, payload any, send func() error) error {
+	key, err := dedupKey(dm.recipient, method, payload)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	claimed, err := dm.store.Claim(ctx, key)
+	if err != nil {
+		// Prefer a possible duplicate over a lost message when the store is unreachable
+		slog.Error("Error claiming dedup key, sending anyway", "error", err)
+		return send()
+	}
+	if !claimed {
+		slog.Debug("Skipping duplicate", "kind", method, "recipient", dm.recipient)
+		return nil
+	}
+
+	if err := send(); err != nil {
+		releaseCtx, releaseCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer releaseCancel()
+		if releaseErr := dm.store.Release(releaseCtx, key); releaseErr != nil {
+			slog.Error("Error releasing dedup key after failed send", "error", releaseErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// alertIdentity is what makes two alerts the same message. Prices differ between instances fetching moments apart,
+// so the change only counts by its direction and re-alert step, and the time by its cooldown period: a re-alert is
+// at least a step further or a cooldown later, so it always lands in another step or period
+type alertIdentity struct {
+	Symbol string            `json:"symbol"`
+	Basis  models.AlertBasis `json:"basis,omitempty"`
+	Window time.Duration     `json:"window,omitempty"`
+	Rising bool              `json:"rising"`
+	Step   int               `json:"step"`
+	Period time.Time         `json:"period"`
+}
+
+// identifyAlert returns the identity of an alert under a dedup policy
+func identifyAlert(alert models.PriceAlert, policy DedupPolicy) alertIdentity {
+	step := 0
+	if policy.AlertStep > 0 {
+		step = int(math.Abs(alert.PercentChange) / policy.AlertStep)
+	}
+	at := alert.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	return alertIdentity{
+		Symbol: alert.Symbol,
+		Basis:  alert.Basis,
+		Window: alert.Window,
+		Rising: alert.PercentChange >= 0,
+		Step:   step,
+		Period: at.Truncate(max(policy.AlertCooldown, time.Minute)).UTC(),
+	}
+}
+
+// dedupKey builds the idempotency key of a message for a recipient
+func dedupKey(recipient, method string, payload any) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%s:%s:%s", recipient, method, hex.EncodeToString(sum[:])), nil
+}
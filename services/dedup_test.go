@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"stock-bot/models"
+)
+
+// memDedupStore keeps idempotency keys in memory
+type memDedupStore struct {
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+func (s *memDedupStore) Claim(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys[key] {
+		return false, nil
+	}
+	s.keys[key] = true
+	return true, nil
+}
+
+func (s *memDedupStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, key)
+	return nil
+}
+
+// countingMessenger counts the messages it is asked to send and fails them while err is set
+type countingMessenger struct {
+	mu      sync.Mutex
+	reports int
+	alerts  int
+	texts   int
+	err     error
+}
+
+func (m *countingMessenger) SendMessage(_ map[string]models.Quote, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reports++
+	return m.err
+}
+
+func (m *countingMessenger) SendAlerts(_ []models.PriceAlert, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.alerts++
+	return m.err
+}
+
+func (m *countingMessenger) SendText(_ string, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.texts++
+	return m.err
+}
+
+// testDedupPolicy is a two-hour cooldown with a 5 point re-alert step, reading days in UTC
+func testDedupPolicy() DedupPolicy {
+	return DedupPolicy{Location: time.UTC, AlertCooldown: 2 * time.Hour, AlertStep: 5}
+}
+
+// newTestDedupMessenger wraps inner in a dedup messenger with an empty store
+func newTestDedupMessenger(inner Messenger) *dedupMessenger {
+	return &dedupMessenger{inner: inner, store: &memDedupStore{keys: make(map[string]bool)}, recipient: "telegram:1", policy: testDedupPolicy}
+}
+
+func TestDedupMessengerAlerts(t *testing.T) {
+	at := time.Date(2026, 3, 2, 14, 31, 0, 0, time.UTC)
+	alert := models.PriceAlert{Symbol: "AAPL", PercentChange: 3.04, Timestamp: at}
+
+	tests := []struct {
+		name  string
+		later models.PriceAlert
+		sent  int
+	}{
+		{"resend of the same alert", alert, 1},
+		{"other instance moments later", models.PriceAlert{Symbol: "AAPL", PercentChange: 3.11, Timestamp: at.Add(20 * time.Second)}, 1},
+		{"other instance a tick later", models.PriceAlert{Symbol: "AAPL", PercentChange: 3.4, Timestamp: at.Add(15 * time.Minute)}, 1},
+		{"re-alert after the cooldown", models.PriceAlert{Symbol: "AAPL", PercentChange: 3.04, Timestamp: at.Add(2 * time.Hour)}, 2},
+		{"further step", models.PriceAlert{Symbol: "AAPL", PercentChange: 8.1, Timestamp: at}, 2},
+		{"reversal", models.PriceAlert{Symbol: "AAPL", PercentChange: -3.04, Timestamp: at}, 2},
+		{"other basis", models.PriceAlert{Symbol: "AAPL", PercentChange: 3.04, Timestamp: at, Basis: models.BasisOpen}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &countingMessenger{}
+			dm := newTestDedupMessenger(inner)
+
+			for _, a := range []models.PriceAlert{alert, tt.later} {
+				if err := dm.SendAlerts([]models.PriceAlert{a}, nil); err != nil {
+					t.Fatalf("SendAlerts: %v", err)
+				}
+			}
+			if inner.alerts != tt.sent {
+				t.Errorf("sent %d alert messages, want %d", inner.alerts, tt.sent)
+			}
+		})
+	}
+}
+
+func TestDedupMessengerReportOncePerDay(t *testing.T) {
+	inner := &countingMessenger{}
+	dm := newTestDedupMessenger(inner)
+
+	// Instances whose ticks fall in different minutes, or that see different prices, send the same report
+	reports := []map[string]models.Quote{
+		{"AAPL": {Price: 190.1}},
+		{"AAPL": {Price: 190.3}, "MSFT": {Price: 410}},
+	}
+	for _, quotes := range reports {
+		if err := dm.SendMessage(quotes, nil); err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+	}
+	if inner.reports != 1 {
+		t.Errorf("sent %d reports, want 1", inner.reports)
+	}
+}
+
+func TestDeliveryReportTriggeredTwice(t *testing.T) {
+	inner := &countingMessenger{}
+	d := NewDelivery(inner, NewStoreDatabase(nil), "1")
+	d.SetDedupStore(&memDedupStore{keys: make(map[string]bool)}, testDedupPolicy)
+	quotes := map[string]models.Quote{"AAPL": {Price: 190.1}}
+
+	// The scheduled report, sent by two instances, then two reports requested on demand the same day, the first
+	// of them retried
+	sends := []*Delivery{d, d, d.ForRun("first"), d.ForRun("first"), d.ForRun("second")}
+	for _, delivery := range sends {
+		if err := delivery.SendMessage(quotes, nil); err != nil {
+			t.Fatalf("SendMessage: %v", err)
+		}
+	}
+	if inner.reports != 3 {
+		t.Errorf("sent %d reports, want 3: the scheduled one and one per requested run", inner.reports)
+	}
+}
+
+func TestDedupMessengerReleasesFailedSend(t *testing.T) {
+	inner := &countingMessenger{err: ErrMessageSending}
+	dm := newTestDedupMessenger(inner)
+
+	if err := dm.SendText("Market closed early", nil); err == nil {
+		t.Fatal("SendText succeeded, want the inner error")
+	}
+	inner.err = nil
+	if err := dm.SendText("Market closed early", nil); err != nil {
+		t.Fatalf("SendText: %v", err)
+	}
+	if err := dm.SendText("Market closed early", nil); err != nil {
+		t.Fatalf("SendText: %v", err)
+	}
+	if inner.texts != 2 {
+		t.Errorf("sent %d texts, want 2: the failed one and its retry", inner.texts)
+	}
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+)
+
+// broadcastRecipient identifies messages sent through broadcast-only channels in dedup keys
+const broadcastRecipient = "broadcast"
+
+// DeliveryResult counts the outcome of delivering one message to its recipients
+type DeliveryResult struct {
+	Delivered int
+	Failed    int
+	Skipped   int
+}
+
+// Delivery fans scheduled messages out to every subscribed chat that wants the message kind
+type Delivery struct {
+	base          Messenger
+	db            *Database
+	defaultChatID string
+	dedup         DedupStore
+	dedupPolicy   func() DedupPolicy
+	run           string
+	reportHour    *reportHour
+	outbox        OutboxStore
+}
+
+// reportHour limits daily report messages to the recipients that get the report at hour; fallback is the hour of
+// those that didn't choose one
+type reportHour struct {
+	hour     int
+	fallback int
+}
+
+// NewDelivery creates a new Delivery around the configured messenger
+func NewDelivery(base Messenger, db *Database, defaultChatID string) *Delivery {
+	return &Delivery{
+		base:          base,
+		db:            db,
+		defaultChatID: defaultChatID,
+	}
+}
+
+// SetDedupStore makes every send claim an idempotency key first, so racing instances or retries deliver once;
+// policy is read on every send, so it can follow configuration changes
+func (d *Delivery) SetDedupStore(store DedupStore, policy func() DedupPolicy) {
+	d.dedup = store
+	d.dedupPolicy = policy
+}
+
+// ForRun returns a Delivery for one on-demand run, such as a report requested through the admin API or a confirmed
+// announcement: its reports and texts are deduplicated within the run only, so retries send once but another run
+// the same day sends again. Scheduled sends have no run, so every instance sending the day's report shares its key
+func (d *Delivery) ForRun(id string) *Delivery {
+	run := *d
+	run.run = id
+	return &run
+}
+
+// ForReportHour returns a Delivery whose daily report messages only reach the recipients that get the report at
+// hour, fallback being the hour of those that didn't choose one; other message kinds reach everyone as usual
+func (d *Delivery) ForReportHour(hour, fallback int) *Delivery {
+	filtered := *d
+	filtered.reportHour = &reportHour{hour: hour, fallback: fallback}
+	return &filtered
+}
+
+// atOtherHour reports whether a recipient gets messages of a kind at another hour than this Delivery's
+func (d *Delivery) atOtherHour(kind models.MessageKind, user models.User) bool {
+	return kind == models.KindDailyReport && d.reportHour != nil && user.ReportHourOr(d.reportHour.fallback) != d.reportHour.hour
+}
+
+// dedupFor wraps a recipient's messenger so sends go through the dedup store, if one is set
+func (d *Delivery) dedupFor(m Messenger, recipient string) Messenger {
+	if d.dedup == nil {
+		return m
+	}
+	return &dedupMessenger{inner: m, store: d.dedup, recipient: recipient, run: d.run, policy: d.dedupPolicy}
+}
+
+// SetOutbox persists every send before it is made, so failed ones can be retried with RetryOutbox
+func (d *Delivery) SetOutbox(store OutboxStore) {
+	d.outbox = store
+}
+
+// outboxFor wraps a recipient's messenger so sends are persisted in the outbox, if one is set and the channel's
+// messenger doesn't skip it; chatID is empty for broadcast-only channels
+func (d *Delivery) outboxFor(m, base Messenger, channel, chatID string, style models.AlertStyle) Messenger {
+	if d.outbox == nil {
+		return m
+	}
+	if skipper, ok := base.(OutboxSkipper); ok && skipper.SkipsOutbox() {
+		return m
+	}
+	return &outboxMessenger{inner: m, store: d.outbox, channel: channel, chatID: chatID, alertStyle: style}
+}
+
+// channel returns the messenger of a named channel, or the only messenger when channel is empty
+func (d *Delivery) channel(name string) Messenger {
+	composite, ok := d.base.(*CompositeMessenger)
+	if !ok {
+		if name == "" {
+			return d.base
+		}
+		return nil
+	}
+	for i, channel := range composite.names {
+		if channel == name {
+			return composite.messengers[i]
+		}
+	}
+	return nil
+}
+
+// styled applies the recipient's alert style to their messenger, if they chose one
+func (d *Delivery) styled(m Messenger, user models.User) Messenger {
+	styler, ok := m.(AlertStyler)
+	if !ok || user.AlertStyle == "" {
+		return m
+	}
+	return styler.WithAlertStyle(user.AlertStyle)
+}
+
+// SendMessage delivers the daily report to every recipient that receives reports, limited to their watchlist
+func (d *Delivery) SendMessage(quotes map[string]models.Quote, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	_, err := d.Deliver(models.KindDailyReport, func(m Messenger, user models.User) error {
+		// Each chat only sees the symbols on its watchlist
+		watched := make(map[string]models.Quote, len(quotes))
+		for symbol, quote := range quotes {
+			if user.Watches(symbol) {
+				watched[symbol] = quote
+			}
+		}
+		if len(watched) == 0 {
+			return nil
+		}
+		return m.SendMessage(watched, nil)
+	})
+	return err
+}
+
+// SendAlerts delivers price alerts to every recipient that receives alerts
+func (d *Delivery) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	_, err := d.Deliver(models.KindAlert, func(m Messenger, user models.User) error {
+		// Drop alerts for symbols the recipient doesn't watch or muted
+		now := time.Now()
+		unmuted := make([]models.PriceAlert, 0, len(alerts))
+		for _, alert := range alerts {
+			if user.Watches(alert.Symbol) && !user.IsMuted(alert.Symbol, now) {
+				unmuted = append(unmuted, alert)
+			}
+		}
+		return m.SendAlerts(unmuted, nil)
+	})
+	return err
+}
+
+// SendText delivers an announcement to every recipient regardless of their preferences
+func (d *Delivery) SendText(text string, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	_, err := d.Deliver(models.KindAnnouncement, func(m Messenger, _ models.User) error {
+		return m.SendText(text, nil)
+	})
+	return err
+}
+
+// NotifyChats sends a text to specific chats, such as the admins, regardless of their preferences
+func (d *Delivery) NotifyChats(chatIDs []string, text string) error {
+	if composite, ok := d.base.(*CompositeMessenger); ok {
+		return composite.each(func(channel string, m Messenger) error {
+			return d.notifyVia(m, channel, chatIDs, text)
+		})
+	}
+	return d.notifyVia(d.base, "", chatIDs, text)
+}
+
+// notifyVia sends a text to specific chats through a single messenger
+func (d *Delivery) notifyVia(m Messenger, channel string, chatIDs []string, text string) error {
+	// Broadcast-only channels cannot address individual chats
+	chats, ok := m.(ChatMessenger)
+	if !ok {
+		return d.outboxFor(d.dedupFor(m, recipientKey(channel, broadcastRecipient)), m, channel, "", "").SendText(text, nil)
+	}
+
+	var errs []error
+	for _, chatID := range chatIDs {
+		if err := d.outboxFor(d.dedupFor(chats.ForChat(chatID), recipientKey(channel, chatID)), m, channel, chatID, "").SendText(text, nil); err != nil {
+			errs = append(errs, fmt.Errorf("chat %s: %w", chatID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RecipientCount returns how many chats would receive a message of the given kind, summed over all channels
+func (d *Delivery) RecipientCount(kind models.MessageKind) int {
+	composite, ok := d.base.(*CompositeMessenger)
+	if !ok {
+		return d.recipientCountVia(d.base, kind)
+	}
+
+	count := 0
+	for _, m := range composite.messengers {
+		count += d.recipientCountVia(m, kind)
+	}
+	return count
+}
+
+// recipientCountVia returns how many chats of a single messenger would receive a message of the given kind
+func (d *Delivery) recipientCountVia(m Messenger, kind models.MessageKind) int {
+	if _, ok := m.(ChatMessenger); !ok {
+		if d.atOtherHour(kind, models.User{}) {
+			return 0
+		}
+		return 1
+	}
+
+	count := 0
+	for _, user := range d.recipients() {
+		if user.Receives(kind) && !d.atOtherHour(kind, user) {
+			count++
+		}
+	}
+	return count
+}
+
+// Deliver calls send once per recipient whose preferences allow the message kind
+func (d *Delivery) Deliver(kind models.MessageKind, send func(m Messenger, user models.User) error) (DeliveryResult, error) {
+	composite, ok := d.base.(*CompositeMessenger)
+	if !ok {
+		return d.deliverVia(d.base, "", kind, send)
+	}
+
+	// Every channel reaches its own recipients concurrently, so a failing channel doesn't hold up the others
+	var mu sync.Mutex
+	var total DeliveryResult
+	err := composite.each(func(channel string, m Messenger) error {
+		result, err := d.deliverVia(m, channel, kind, send)
+		mu.Lock()
+		total.Delivered += result.Delivered
+		total.Failed += result.Failed
+		total.Skipped += result.Skipped
+		mu.Unlock()
+		return err
+	})
+	return total, err
+}
+
+// deliverVia delivers a message through a single messenger; channel names it within a composite messenger
+func (d *Delivery) deliverVia(m Messenger, channel string, kind models.MessageKind, send func(m Messenger, user models.User) error) (DeliveryResult, error) {
+	var result DeliveryResult
+
+	// Broadcast-only channels cannot address individual chats
+	chats, ok := m.(ChatMessenger)
+	if !ok {
+		if d.atOtherHour(kind, models.User{}) {
+			result.Skipped++
+			return result, nil
+		}
+		if err := send(d.outboxFor(d.dedupFor(m, recipientKey(channel, broadcastRecipient)), m, channel, "", ""), models.User{}); err != nil {
+			result.Failed++
+			return result, err
+		}
+		result.Delivered++
+		return result, nil
+	}
+
+	var errs []error
+	for chatID, user := range d.recipients() {
+		if !user.Receives(kind) || d.atOtherHour(kind, user) {
+			result.Skipped++
+			continue
+		}
+		recipient := d.dedupFor(d.styled(chats.ForChat(chatID), user), recipientKey(channel, chatID))
+		if err := send(d.outboxFor(recipient, m, channel, chatID, user.AlertStyle), user); err != nil {
+			errs = append(errs, fmt.Errorf("chat %s: %w", chatID, err))
+			result.Failed++
+			continue
+		}
+		result.Delivered++
+	}
+
+	slog.Info("Delivered message", "kind", kind, "delivered", result.Delivered, "failed", result.Failed, "opted_out", result.Skipped)
+	return result, errors.Join(errs...)
+}
+
+// recipientKey identifies a recipient in dedup keys, prefixed with the channel name within a composite messenger
+func recipientKey(channel, recipient string) string {
+	if channel == "" {
+		return recipient
+	}
+	return channel + ":" + recipient
+}
+
+// recipients returns the configured chat plus every onboarded Telegram user, keyed by chat ID; Line chats
+// receive the Line broadcast instead
+func (d *Delivery) recipients() map[string]models.User {
+	recipients := make(map[string]models.User)
+	if d.defaultChatID != "" {
+		recipients[d.defaultChatID] = models.User{ChatID: d.defaultChatID}
+	}
+
+	users, err := d.db.ListUsers()
+	if err != nil {
+		slog.Error("Error loading recipients, using the default chat only", "error", err)
+		return recipients
+	}
+
+	// The configured chat always receives messages unless it opted out
+	for _, user := range users {
+		if user.Channel != "" {
+			continue
+		}
+		if _, isDefault := recipients[user.ChatID]; user.Onboarded || isDefault {
+			recipients[user.ChatID] = user
+		}
+	}
+	return recipients
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+
+	"stock-bot/models"
+)
+
+func TestDeliveryForReportHour(t *testing.T) {
+	quotes := map[string]models.Quote{"AAPL": {Price: 190.1}}
+
+	tests := []struct {
+		name    string
+		hour    int
+		reports int
+		texts   int
+	}{
+		{"configured hour", 7, 1, 1},
+		{"another hour", 18, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &countingMessenger{}
+			d := NewDelivery(inner, NewStoreDatabase(nil), "").ForReportHour(tt.hour, 7)
+
+			if err := d.SendMessage(quotes, nil); err != nil {
+				t.Fatalf("SendMessage: %v", err)
+			}
+			// Only the daily report follows the report hour
+			if err := d.SendText("Market closed early", nil); err != nil {
+				t.Fatalf("SendText: %v", err)
+			}
+			if inner.reports != tt.reports || inner.texts != tt.texts {
+				t.Errorf("sent %d reports and %d texts, want %d and %d", inner.reports, inner.texts, tt.reports, tt.texts)
+			}
+			if got := d.RecipientCount(models.KindDailyReport); got != tt.reports {
+				t.Errorf("RecipientCount = %d, want %d", got, tt.reports)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// fmpEconomicEvent is an entry of the FMP economic calendar endpoint
+type fmpEconomicEvent struct {
+	Date     string   `json:"date"`
+	Country  string   `json:"country"`
+	Event    string   `json:"event"`
+	Impact   string   `json:"impact"`
+	Previous *float64 `json:"previous"`
+	Estimate *float64 `json:"estimate"`
+	Actual   *float64 `json:"actual"`
+}
+
+// FetchEconomicCalendar returns scheduled macro events for the given countries between two dates
+func (fc *FMPClient) FetchEconomicCalendar(ctx context.Context, from, to time.Time, countries []string) ([]models.EconomicEvent, error) {
+	query := url.Values{}
+	query.Set("from", from.Format("2006-01-02"))
+	query.Set("to", to.Format("2006-01-02"))
+
+	var entries []fmpEconomicEvent
+	if err := fc.fetch(ctx, "v3/economic_calendar", query, &entries); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	events := make([]models.EconomicEvent, 0, len(entries))
+	for _, entry := range entries {
+		if !slices.Contains(countries, entry.Country) {
+			continue
+		}
+
+		// Event times are published in UTC
+		eventTime, err := time.Parse("2006-01-02 15:04:05", entry.Date)
+		if err != nil {
+			continue
+		}
+
+		events = append(events, models.EconomicEvent{
+			Country:   entry.Country,
+			Event:     entry.Event,
+			Impact:    entry.Impact,
+			Time:      eventTime,
+			Previous:  entry.Previous,
+			Estimate:  entry.Estimate,
+			Actual:    entry.Actual,
+			FetchedAt: now,
+		})
+	}
+
+	return events, nil
+}
+
+// SaveEconomicEvents upserts economic events, keeping the reminder state of known events
+func (db *Database) SaveEconomicEvents(events []models.EconomicEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("economic_events")
+	if err != nil {
+		return err
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(events))
+	for _, event := range events {
+		filter := bson.D{
+			{Key: "country", Value: event.Country},
+			{Key: "event", Value: event.Event},
+			{Key: "time", Value: event.Time},
+		}
+		update := bson.D{
+			{Key: "$set", Value: bson.D{
+				{Key: "impact", Value: event.Impact},
+				{Key: "previous", Value: event.Previous},
+				{Key: "estimate", Value: event.Estimate},
+				{Key: "actual", Value: event.Actual},
+				{Key: "fetchedAt", Value: event.FetchedAt},
+			}},
+			{Key: "$setOnInsert", Value: bson.D{{Key: "reminded", Value: false}}},
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+	}
+
+	if _, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// GetEconomicEvents returns events with the given impact scheduled in [from, to), ordered by time
+func (db *Database) GetEconomicEvents(from, to time.Time, impact string) ([]models.EconomicEvent, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("economic_events")
+	if !ok {
+		return nil, nil
+	}
+
+	filter := bson.D{
+		{Key: "time", Value: bson.D{{Key: "$gte", Value: from}, {Key: "$lt", Value: to}}},
+		{Key: "impact", Value: impact},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "time", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	defer cursor.Close(ctx)
+
+	var events []models.EconomicEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return events, nil
+}
+
+// MarkEconomicEventReminded records that a reminder was sent for an event
+func (db *Database) MarkEconomicEventReminded(event models.EconomicEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("economic_events")
+	if err != nil {
+		return err
+	}
+
+	filter := bson.D{
+		{Key: "country", Value: event.Country},
+		{Key: "event", Value: event.Event},
+		{Key: "time", Value: event.Time},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "reminded", Value: true}}}}
+
+	if _, err := collection.UpdateOne(ctx, filter, update); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
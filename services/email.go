@@ -0,0 +1,171 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"mime"
+	"net"
+	"net/smtp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/i18n"
+	"stock-bot/models"
+)
+
+// emailTemplateFuncs are the functions the email templates are parsed with; text is replaced at send time with the
+// messenger's translation of a label
+var emailTemplateFuncs = template.FuncMap{"text": func(message string) string { return message }}
+
+// emailReportTemplate renders the daily report as a two-column table
+var emailReportTemplate = template.Must(template.New("report").Funcs(emailTemplateFuncs).Parse(`<html><body style="font-family: sans-serif">
+<h2>{{text "📊 Daily Stock Report"}}</h2>
+<table cellpadding="6" style="border-collapse: collapse">
+<tr style="background: #f0f0f0"><th align="left">{{text "Symbol"}}</th><th align="right">{{text "Price"}}</th></tr>
+{{range .}}<tr style="border-top: 1px solid #ddd"><td><b>{{.Symbol}}</b></td><td align="right">{{.Price}}</td></tr>
+{{end}}</table>
+</body></html>`))
+
+// emailAlertTemplate renders price alerts as a table with the change colored by direction
+var emailAlertTemplate = template.Must(template.New("alerts").Funcs(emailTemplateFuncs).Parse(`<html><body style="font-family: sans-serif">
+<h2>{{text "⚠️ Significant Price Changes Detected"}}</h2>
+<table cellpadding="6" style="border-collapse: collapse">
+<tr style="background: #f0f0f0"><th align="left">{{text "Symbol"}}</th><th align="right">{{text "Change"}}</th><th align="right">{{text "Previous"}}</th><th align="right">{{text "Current"}}</th></tr>
+{{range .}}<tr style="border-top: 1px solid #ddd"><td><b>{{.Symbol}}</b></td><td align="right" style="color: {{.Color}}">{{.Change}}</td><td align="right">{{.Previous}}</td><td align="right">{{.Current}}</td></tr>
+{{end}}</table>
+</body></html>`))
+
+// emailTextTemplate wraps a plain text message so its line breaks survive
+var emailTextTemplate = template.Must(template.New("text").Parse(`<html><body><pre style="font-family: sans-serif; white-space: pre-wrap">{{.}}</pre></body></html>`))
+
+// EmailMessenger sends reports and alerts as HTML email over SMTP
+type EmailMessenger struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+	to       []string
+	printer  *i18n.Printer
+}
+
+// NewEmailMessenger creates a new EmailMessenger; the sender defaults to the SMTP user
+func NewEmailMessenger(host, port, username, password, from string, to []string) (*EmailMessenger, error) {
+	if host == "" {
+		return nil, ErrTokenNotSet
+	}
+	if len(to) == 0 {
+		return nil, ErrChatIDNotSet
+	}
+	if port == "" {
+		port = "587"
+	}
+	if from == "" {
+		from = username
+	}
+	return &EmailMessenger{host: host, port: port, username: username, password: password, from: from, to: to, printer: i18n.NewPrinter(i18n.English)}, nil
+}
+
+// SetLanguage selects the language of report and alert subjects and labels
+func (em *EmailMessenger) SetLanguage(language i18n.Language) {
+	em.printer = i18n.NewPrinter(language)
+}
+
+// SendMessage emails the daily report as a table of symbols and prices
+func (em *EmailMessenger) SendMessage(quotes map[string]models.Quote, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	type row struct{ Symbol, Price string }
+	rows := make([]row, 0, len(quotes))
+	for symbol, quote := range quotes {
+		rows = append(rows, row{Symbol: symbol, Price: models.FormatQuote(quote)})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Symbol < rows[j].Symbol })
+
+	subject := em.printer.Sprintf("Daily Stock Report %s", time.Now().Format("2006-01-02"))
+	return em.send(subject, emailReportTemplate, rows)
+}
+
+// SendAlerts emails price alerts as a table, rises in green and drops in red
+func (em *EmailMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	type row struct{ Symbol, Change, Color, Previous, Current string }
+	rows := make([]row, 0, len(alerts))
+	symbols := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		color := "#c0392b"
+		if alert.PercentChange > 0 {
+			color = "#27ae60"
+		}
+		rows = append(rows, row{
+			Symbol:   alert.Symbol,
+			Change:   fmt.Sprintf("%+.2f%%", alert.PercentChange),
+			Color:    color,
+			Previous: models.FormatPrice(alert.Symbol, alert.PreviousPrice),
+			Current:  models.FormatPrice(alert.Symbol, alert.CurrentPrice),
+		})
+		symbols = append(symbols, alert.Symbol)
+	}
+
+	subject := em.printer.Sprintf("Price alert: %s", strings.Join(symbols, ", "))
+	return em.send(subject, emailAlertTemplate, rows)
+}
+
+// SendText emails an arbitrary text message, using its first line as the subject
+func (em *EmailMessenger) SendText(text string, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	subject, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return em.send(subject, emailTextTemplate, text)
+}
+
+// send renders the HTML body with labels in the messenger's language and delivers it to all recipients
+func (em *EmailMessenger) send(subject string, body *template.Template, data interface{}) error {
+	body, err := body.Clone()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+	body.Funcs(template.FuncMap{"text": em.printer.Text})
+
+	var html bytes.Buffer
+	if err := body.Execute(&html, data); err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+
+	var message bytes.Buffer
+	message.WriteString(fmt.Sprintf("From: %s\r\n", em.from))
+	message.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(em.to, ", ")))
+	message.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject)))
+	message.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n")
+	message.Write(html.Bytes())
+
+	// Servers without authentication (e.g. a local relay) are used as is
+	var auth smtp.Auth
+	if em.username != "" {
+		auth = smtp.PlainAuth("", em.username, em.password, em.host)
+	}
+
+	if err := smtp.SendMail(net.JoinHostPort(em.host, em.port), auth, em.from, em.to, message.Bytes()); err != nil {
+		return fmt.Errorf("%w: %v", ErrMessageSending, err)
+	}
+
+	slog.Info("Email sent", "recipients", len(em.to), "subject", subject)
+	return nil
+}
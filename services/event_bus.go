@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"stock-bot/models"
+
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+)
+
+// Error definitions for the event bus
+var (
+	ErrEventBusConnection = errors.New("failed to connect to event bus")
+)
+
+// NATSPublisher emits events to NATS subjects such as stockbot.alert.fired
+type NATSPublisher struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+// NewNATSPublisher connects to a NATS server and creates a new NATSPublisher
+func NewNATSPublisher(url, prefix string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("stock-bot"), nats.Timeout(10*time.Second), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEventBusConnection, err)
+	}
+	return &NATSPublisher{conn: conn, prefix: prefix}, nil
+}
+
+// Name returns the integration name
+func (np *NATSPublisher) Name() string {
+	return "nats"
+}
+
+// Publish sends the event JSON to the subject of its type
+func (np *NATSPublisher) Publish(ctx context.Context, event models.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err)
+	}
+	if err := np.conn.Publish(np.prefix+"."+event.Type.Name(), payload); err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err)
+	}
+	return nil
+}
+
+// Close flushes pending messages and closes the connection
+func (np *NATSPublisher) Close() {
+	if err := np.conn.Drain(); err != nil {
+		np.conn.Close()
+	}
+}
+
+// KafkaPublisher emits events to Kafka topics such as stockbot.alert.fired
+type KafkaPublisher struct {
+	brokers []string
+	prefix  string
+	writers map[models.EventType]*kafka.Writer
+}
+
+// NewKafkaPublisher creates a new KafkaPublisher with one writer per event topic
+func NewKafkaPublisher(brokers []string, prefix string) (*KafkaPublisher, error) {
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("%w: no brokers configured", ErrEventBusConnection)
+	}
+
+	writers := make(map[models.EventType]*kafka.Writer)
+	for _, eventType := range []models.EventType{models.EventQuote, models.EventAlert, models.EventReport} {
+		writers[eventType] = kafka.NewWriter(kafka.WriterConfig{
+			Brokers:      brokers,
+			Topic:        prefix + "." + eventType.Name(),
+			Balancer:     &kafka.Hash{},
+			BatchTimeout: 100 * time.Millisecond,
+			WriteTimeout: 10 * time.Second,
+		})
+	}
+
+	return &KafkaPublisher{brokers: brokers, prefix: prefix, writers: writers}, nil
+}
+
+// Name returns the integration name
+func (kp *KafkaPublisher) Name() string {
+	return "kafka"
+}
+
+// Publish writes the event JSON to the topic of its type, keyed by symbol so a symbol's events stay ordered
+func (kp *KafkaPublisher) Publish(ctx context.Context, event models.Event) error {
+	writer, ok := kp.writers[event.Type]
+	if !ok {
+		return fmt.Errorf("%w: no topic for event %s", ErrPublishFailed, event.Type)
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err)
+	}
+
+	message := kafka.Message{
+		Key:     []byte(event.Symbol),
+		Value:   payload,
+		Headers: []kafka.Header{{Key: "event", Value: []byte(event.Type.Name())}},
+	}
+	if err := writer.WriteMessages(ctx, message); err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err)
+	}
+	return nil
+}
+
+// Close flushes and closes every topic writer
+func (kp *KafkaPublisher) Close() {
+	for _, writer := range kp.writers {
+		writer.Close()
+	}
+}
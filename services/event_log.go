@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// EventLog stores alert and report events so they can be served as feeds
+type EventLog struct {
+	db *Database
+}
+
+// NewEventLog creates a new EventLog backed by the database
+func NewEventLog(db *Database) *EventLog {
+	return &EventLog{db: db}
+}
+
+// Name returns the integration name
+func (el *EventLog) Name() string {
+	return "event log"
+}
+
+// Publish stores alert and report events; quotes are too frequent to keep
+func (el *EventLog) Publish(ctx context.Context, event models.Event) error {
+	if event.Type == models.EventQuote {
+		return nil
+	}
+	return el.db.SaveEvent(ctx, event)
+}
+
+// SaveEvent stores an event in the event log
+func (db *Database) SaveEvent(ctx context.Context, event models.Event) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("events")
+	if !ok {
+		return nil
+	}
+	if _, err := collection.InsertOne(ctx, event); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// GetRecentEvents returns the latest logged events, newest first
+func (db *Database) GetRecentEvents(limit int) ([]models.Event, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("events")
+	if !ok {
+		return nil, nil
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}}).SetLimit(int64(limit))
+
+	cursor, err := collection.Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	defer cursor.Close(ctx)
+
+	var events []models.Event
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return events, nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+)
+
+// Feed describes the channel an event feed is published under
+type Feed struct {
+	Title   string
+	Link    string // Public URL of the feed itself
+	Updated time.Time
+}
+
+// atomFeed is an Atom 1.0 document
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Content atomContent `xml:"content"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// rssFeed is an RSS 2.0 document
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Description string  `xml:"description"`
+	PubDate     string  `xml:"pubDate"`
+	GUID        rssGUID `xml:"guid"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	ID          string `xml:",chardata"`
+}
+
+// BuildAtomFeed renders events as an Atom feed
+func BuildAtomFeed(feed Feed, events []models.Event) ([]byte, error) {
+	doc := atomFeed{
+		ID:      feed.Link,
+		Title:   feed.Title,
+		Updated: feed.Updated.UTC().Format(time.RFC3339),
+		Link:    atomLink{Href: feed.Link, Rel: "self"},
+	}
+	for _, event := range events {
+		doc.Entries = append(doc.Entries, atomEntry{
+			ID:      eventID(event),
+			Title:   event.Title(),
+			Updated: event.Timestamp.UTC().Format(time.RFC3339),
+			Author:  atomAuthor{Name: "Stock Bot"},
+			Content: atomContent{Type: "text", Body: event.Summary()},
+		})
+	}
+	return marshalFeed(doc)
+}
+
+// BuildRSSFeed renders events as an RSS 2.0 feed
+func BuildRSSFeed(feed Feed, events []models.Event) ([]byte, error) {
+	doc := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       feed.Title,
+			Link:        feed.Link,
+			Description: "Price alerts and daily reports",
+		},
+	}
+	for _, event := range events {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       event.Title(),
+			Description: event.Summary(),
+			PubDate:     event.Timestamp.UTC().Format(time.RFC1123Z),
+			GUID:        rssGUID{ID: eventID(event)},
+		})
+	}
+	return marshalFeed(doc)
+}
+
+// eventID returns a stable tag URI for an event
+func eventID(event models.Event) string {
+	parts := []string{string(event.Type), event.Timestamp.UTC().Format("20060102T150405.000Z")}
+	if event.Symbol != "" {
+		parts = append(parts, event.Symbol)
+	}
+	return "tag:stock-bot," + event.Timestamp.UTC().Format("2006-01-02") + ":" + strings.Join(parts, "/")
+}
+
+// marshalFeed encodes a feed document with the XML declaration
+func marshalFeed(doc interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
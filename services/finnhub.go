@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"stock-bot/models"
+
+	"github.com/gorilla/websocket"
+)
+
+// Reconnect backoff and stall detection for the Finnhub trade stream
+const (
+	finnhubMinBackoff  = time.Second
+	finnhubMaxBackoff  = time.Minute
+	finnhubReadTimeout = 2 * time.Minute // Finnhub pings idle connections well within this
+)
+
+// Error definitions for Finnhub data
+var (
+	ErrFinnhubUnavailable = errors.New("finnhub data unavailable")
+)
+
+// FinnhubSource reads quotes from the Finnhub REST API and streams live trades over its WebSocket API
+type FinnhubSource struct {
+	apiKey string
+	client *http.Client
+}
+
+// finnhubQuoteResponse is the Finnhub /quote response
+type finnhubQuoteResponse struct {
+	Current       float64 `json:"c"`
+	Change        float64 `json:"d"`
+	ChangePercent float64 `json:"dp"`
+	PreviousClose float64 `json:"pc"`
+	Timestamp     int64   `json:"t"`
+}
+
+// finnhubEarningsResponse is the Finnhub /calendar/earnings response
+type finnhubEarningsResponse struct {
+	EarningsCalendar []struct {
+		Symbol      string   `json:"symbol"`
+		Date        string   `json:"date"`
+		Hour        string   `json:"hour"` // "bmo", "amc" or "dmh" during market hours
+		EPSEstimate *float64 `json:"epsEstimate"`
+	} `json:"earningsCalendar"`
+}
+
+// finnhubStreamMessage is a message received on the Finnhub trade stream
+type finnhubStreamMessage struct {
+	Type string `json:"type"`
+	Msg  string `json:"msg"`
+	Data []struct {
+		Symbol    string  `json:"s"`
+		Price     float64 `json:"p"`
+		Volume    float64 `json:"v"`
+		Timestamp int64   `json:"t"` // Milliseconds since the epoch
+	} `json:"data"`
+}
+
+// NewFinnhubSource creates a new FinnhubSource instance
+func NewFinnhubSource(apiKey string) (*FinnhubSource, error) {
+	if apiKey == "" {
+		return nil, ErrTokenNotSet
+	}
+	return &FinnhubSource{
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// Name identifies the Finnhub source
+func (fs *FinnhubSource) Name() string {
+	return "finnhub"
+}
+
+// Fetch returns the latest quote of a symbol from the Finnhub REST API
+func (fs *FinnhubSource) Fetch(ctx context.Context, symbol string) (models.Quote, error) {
+	query := url.Values{}
+	query.Set("symbol", symbol)
+	query.Set("token", fs.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://finnhub.io/api/v1/quote?"+query.Encode(), nil)
+	if err != nil {
+		return models.Quote{}, fmt.Errorf("%w: %v", ErrFinnhubUnavailable, err)
+	}
+
+	resp, err := fs.client.Do(req)
+	if err != nil {
+		return models.Quote{}, fmt.Errorf("%w: %v", ErrFinnhubUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return models.Quote{}, fmt.Errorf("%w: %s", ErrRateLimited, fs.Name())
+	}
+	if resp.StatusCode >= 400 {
+		return models.Quote{}, fmt.Errorf("%w: received status code %d", ErrFinnhubUnavailable, resp.StatusCode)
+	}
+
+	var result finnhubQuoteResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return models.Quote{}, fmt.Errorf("%w: %v", ErrFinnhubUnavailable, err)
+	}
+
+	// Unknown symbols are answered with an all-zero quote
+	if result.Current == 0 {
+		return models.Quote{}, fmt.Errorf("%w: %s", ErrElementNotFound, symbol)
+	}
+
+	quote := models.Quote{
+		Symbol:        symbol,
+		Price:         result.Current,
+		Change:        result.Change,
+		ChangePercent: result.ChangePercent,
+		Currency:      models.CurrencyFor(symbol),
+		Timestamp:     time.Now(),
+	}
+	if result.Timestamp > 0 {
+		quote.Timestamp = time.Unix(result.Timestamp, 0)
+	}
+	return quote, nil
+}
+
+// FetchEarningsCalendar returns the earnings dates of the given symbols between from and to
+func (fs *FinnhubSource) FetchEarningsCalendar(ctx context.Context, from, to time.Time, symbols []string) ([]models.CorporateEvent, error) {
+	query := url.Values{}
+	query.Set("from", from.Format("2006-01-02"))
+	query.Set("to", to.Format("2006-01-02"))
+	query.Set("token", fs.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://finnhub.io/api/v1/calendar/earnings?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFinnhubUnavailable, err)
+	}
+
+	resp, err := fs.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFinnhubUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: %s", ErrRateLimited, fs.Name())
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("%w: received status code %d", ErrFinnhubUnavailable, resp.StatusCode)
+	}
+
+	var result finnhubEarningsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFinnhubUnavailable, err)
+	}
+
+	now := time.Now()
+	var events []models.CorporateEvent
+	for _, entry := range result.EarningsCalendar {
+		date, err := time.Parse("2006-01-02", entry.Date)
+		if err != nil || !slices.Contains(symbols, entry.Symbol) {
+			continue
+		}
+		events = append(events, models.CorporateEvent{
+			Symbol:      entry.Symbol,
+			Type:        models.CorporateEarnings,
+			Date:        date,
+			Timing:      entry.Hour,
+			EPSEstimate: entry.EPSEstimate,
+			FetchedAt:   now,
+		})
+	}
+	return events, nil
+}
+
+// Stream subscribes to live trades of the symbols and passes each one to handle until ctx is cancelled,
+// reconnecting with exponential backoff when the connection drops. Each list received from updates replaces the
+// subscribed symbols
+func (fs *FinnhubSource) Stream(ctx context.Context, symbols []string, updates <-chan []string, handle func(models.Quote)) {
+	backoff := finnhubMinBackoff
+	for {
+		connected, err := fs.streamOnce(ctx, &symbols, updates, handle)
+		if ctx.Err() != nil {
+			slog.Info("Finnhub trade stream stopped")
+			return
+		}
+
+		// A connection that worked starts the next one without a long wait
+		if connected {
+			backoff = finnhubMinBackoff
+		}
+		slog.Warn("Finnhub trade stream disconnected, reconnecting", "backoff", backoff, "error", err)
+
+		wait := time.After(backoff)
+	waiting:
+		for {
+			select {
+			case <-wait:
+				break waiting
+			case symbols = <-updates:
+			case <-ctx.Done():
+				return
+			}
+		}
+		backoff = min(backoff*2, finnhubMaxBackoff)
+	}
+}
+
+// streamOnce runs a single stream connection, reporting whether the subscription was established; symbols follows
+// the updates, so the next connection subscribes to the latest list
+func (fs *FinnhubSource) streamOnce(ctx context.Context, symbols *[]string, updates <-chan []string, handle func(models.Quote)) (bool, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, "wss://ws.finnhub.io?token="+url.QueryEscape(fs.apiKey), nil)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrFinnhubUnavailable, err)
+	}
+	defer conn.Close()
+
+	// Unblock the read loop as soon as the caller gives up
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := finnhubSubscribe(conn, "subscribe", *symbols); err != nil {
+		return false, err
+	}
+	slog.Info("Subscribed to Finnhub trades", "count", len(*symbols))
+
+	// Messages are read on their own goroutine, so subscriptions can change while waiting for trades; only this
+	// goroutine writes to the connection
+	messages := make(chan finnhubStreamMessage)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			if err := conn.SetReadDeadline(time.Now().Add(finnhubReadTimeout)); err != nil {
+				readErr <- err
+				return
+			}
+			var message finnhubStreamMessage
+			if err := conn.ReadJSON(&message); err != nil {
+				readErr <- fmt.Errorf("%w: %v", ErrFinnhubUnavailable, err)
+				return
+			}
+			select {
+			case messages <- message:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for {
+		var message finnhubStreamMessage
+		select {
+		case err := <-readErr:
+			return true, err
+		case updated := <-updates:
+			added, removed := symbolChanges(*symbols, updated)
+			*symbols = updated
+			if err := finnhubSubscribe(conn, "unsubscribe", removed); err != nil {
+				return true, err
+			}
+			if err := finnhubSubscribe(conn, "subscribe", added); err != nil {
+				return true, err
+			}
+			slog.Info("Updated Finnhub trade subscriptions", "added", len(added), "removed", len(removed))
+			continue
+		case message = <-messages:
+		}
+
+		switch message.Type {
+		case "trade":
+			for _, trade := range message.Data {
+				handle(models.Quote{
+					Symbol:    trade.Symbol,
+					Price:     trade.Price,
+					Volume:    int64(trade.Volume),
+					Currency:  models.CurrencyFor(trade.Symbol),
+					Source:    fs.Name(),
+					Timestamp: time.UnixMilli(trade.Timestamp),
+				})
+			}
+		case "error":
+			return true, fmt.Errorf("%w: %s", ErrFinnhubUnavailable, message.Msg)
+		}
+	}
+}
+
+// finnhubSubscribe sends a subscribe or unsubscribe message for each symbol
+func finnhubSubscribe(conn *websocket.Conn, action string, symbols []string) error {
+	for _, symbol := range symbols {
+		if err := conn.WriteJSON(map[string]string{"type": action, "symbol": symbol}); err != nil {
+			return fmt.Errorf("%w: %v", ErrFinnhubUnavailable, err)
+		}
+	}
+	return nil
+}
+
+// symbolChanges returns the symbols of updated that previous lacks and those of previous that updated lacks
+func symbolChanges(previous, updated []string) (added, removed []string) {
+	for _, symbol := range updated {
+		if !slices.Contains(previous, symbol) {
+			added = append(added, symbol)
+		}
+	}
+	for _, symbol := range previous {
+		if !slices.Contains(updated, symbol) {
+			removed = append(removed, symbol)
+		}
+	}
+	return added, removed
+}
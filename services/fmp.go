@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Error definitions for Financial Modeling Prep data
+var (
+	ErrFMPUnavailable = errors.New("financial modeling prep data unavailable")
+)
+
+// FMPClient reads analyst ratings, insider filings and the economic calendar from the Financial Modeling Prep API
+type FMPClient struct {
+	apiKey string
+	client *http.Client
+}
+
+// NewFMPClient creates a new FMPClient instance
+func NewFMPClient(apiKey string) (*FMPClient, error) {
+	if apiKey == "" {
+		return nil, ErrTokenNotSet
+	}
+	return &FMPClient{
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// get calls an FMP v4 endpoint for a symbol and decodes the JSON response
+func (fc *FMPClient) get(ctx context.Context, endpoint, symbol string, result interface{}) error {
+	query := url.Values{}
+	query.Set("symbol", symbol)
+	return fc.fetch(ctx, "v4/"+endpoint, query, result)
+}
+
+// fetch calls an FMP API path with the given query and decodes the JSON response
+func (fc *FMPClient) fetch(ctx context.Context, path string, query url.Values, result interface{}) error {
+	query.Set("apikey", fc.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://financialmodelingprep.com/api/%s?%s", path, query.Encode()), nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFMPUnavailable, err)
+	}
+
+	resp, err := fc.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrFMPUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%w: %s received status code %d", ErrFMPUnavailable, path, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("%w: %v", ErrFMPUnavailable, err)
+	}
+	return nil
+}
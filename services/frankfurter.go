@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"stock-bot/models"
+)
+
+// Error definitions for exchange rate data
+var (
+	ErrFXUnavailable = errors.New("exchange rate data unavailable")
+)
+
+// FrankfurterSource reads currency pair rates from the Frankfurter API, which publishes the
+// European Central Bank reference rates once per working day and needs no API key
+type FrankfurterSource struct {
+	client *http.Client
+}
+
+// frankfurterResponse is the Frankfurter /latest response
+type frankfurterResponse struct {
+	Base  string             `json:"base"`
+	Date  string             `json:"date"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// NewFrankfurterSource creates a new FrankfurterSource instance
+func NewFrankfurterSource() *FrankfurterSource {
+	return &FrankfurterSource{
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Name identifies the Frankfurter source
+func (fs *FrankfurterSource) Name() string {
+	return "frankfurter"
+}
+
+// Supports reports whether a symbol is a currency pair such as EURUSD=X
+func (fs *FrankfurterSource) Supports(symbol string) bool {
+	_, _, ok := models.ParseFXPair(symbol)
+	return ok
+}
+
+// Fetch returns the latest reference rate of a currency pair, marked stale: the ECB publishes it once per working
+// day, so it is usually the previous day's rate
+func (fs *FrankfurterSource) Fetch(ctx context.Context, symbol string) (models.Quote, error) {
+	base, quoteCurrency, ok := models.ParseFXPair(symbol)
+	if !ok {
+		return models.Quote{}, fmt.Errorf("%w: %s is not a currency pair", ErrElementNotFound, symbol)
+	}
+
+	query := url.Values{}
+	query.Set("from", base)
+	query.Set("to", quoteCurrency)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.frankfurter.app/latest?"+query.Encode(), nil)
+	if err != nil {
+		return models.Quote{}, fmt.Errorf("%w: %v", ErrFXUnavailable, err)
+	}
+
+	resp, err := fs.client.Do(req)
+	if err != nil {
+		return models.Quote{}, fmt.Errorf("%w: %v", ErrFXUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	// Unsupported currencies are answered with 404 or 422
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity {
+		return models.Quote{}, fmt.Errorf("%w: %s", ErrElementNotFound, symbol)
+	}
+	if resp.StatusCode >= 400 {
+		return models.Quote{}, fmt.Errorf("%w: received status code %d", ErrFXUnavailable, resp.StatusCode)
+	}
+
+	var result frankfurterResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return models.Quote{}, fmt.Errorf("%w: %v", ErrFXUnavailable, err)
+	}
+
+	rate := result.Rates[quoteCurrency]
+	if rate == 0 {
+		return models.Quote{}, fmt.Errorf("%w: no %s rate for %s", ErrElementNotFound, quoteCurrency, base)
+	}
+
+	quote := models.Quote{
+		Symbol:    symbol,
+		Price:     rate,
+		Currency:  quoteCurrency,
+		Stale:     true,
+		Timestamp: time.Now(),
+	}
+	if date, err := time.Parse("2006-01-02", result.Date); err == nil {
+		quote.Timestamp = date
+	}
+	return quote, nil
+}
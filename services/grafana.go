@@ -0,0 +1,85 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+)
+
+// GrafanaPublisher writes fired alerts as Grafana annotations
+type GrafanaPublisher struct {
+	baseURL      string
+	apiKey       string
+	dashboardUID string
+	client       *http.Client
+}
+
+// grafanaAnnotation is the body of the Grafana annotations API
+type grafanaAnnotation struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	Time         int64    `json:"time"`
+	Tags         []string `json:"tags"`
+	Text         string   `json:"text"`
+}
+
+// NewGrafanaPublisher creates a new GrafanaPublisher; without a dashboard UID annotations are organization-wide
+func NewGrafanaPublisher(baseURL, apiKey, dashboardUID string) (*GrafanaPublisher, error) {
+	if baseURL == "" || apiKey == "" {
+		return nil, ErrTokenNotSet
+	}
+	return &GrafanaPublisher{
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+		apiKey:       apiKey,
+		dashboardUID: dashboardUID,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name returns the integration name
+func (gp *GrafanaPublisher) Name() string {
+	return "grafana"
+}
+
+// Publish creates an annotation for alert events, tagged with the symbol so panels can filter on it
+func (gp *GrafanaPublisher) Publish(ctx context.Context, event models.Event) error {
+	if event.Type != models.EventAlert {
+		return nil
+	}
+
+	annotation := grafanaAnnotation{
+		DashboardUID: gp.dashboardUID,
+		Time:         event.Timestamp.UnixMilli(),
+		Tags:         []string{"stockbot", "alert", event.Symbol},
+		Text: fmt.Sprintf("%s %+.2f%%: %s → %s", event.Symbol, event.PercentChange,
+			models.FormatPrice(event.Symbol, event.PreviousPrice), models.FormatPrice(event.Symbol, event.CurrentPrice)),
+	}
+
+	body, err := json.Marshal(annotation)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", gp.baseURL+"/api/annotations", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+gp.apiKey)
+
+	resp, err := gp.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%w: received status code %d", ErrPublishFailed, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"stock-bot/models"
+)
+
+// Error definitions for historical price lookups
+var (
+	ErrHistoryUnavailable = errors.New("price history unavailable")
+)
+
+// DailyHistorySource downloads the daily closes of a symbol over a date range
+type DailyHistorySource interface {
+	// Name identifies the source in logs and configuration
+	Name() string
+	// FetchDailyCloses returns daily closing prices for a symbol between from and to, oldest first
+	FetchDailyCloses(ctx context.Context, symbol string, from, to time.Time) ([]models.PricePoint, error)
+}
+
+// HistoryFetcher downloads daily price history from the Yahoo Finance chart API
+type HistoryFetcher struct {
+	client *http.Client
+}
+
+// yahooChartResponse is the subset of the Yahoo chart API response used for daily history and quotes
+type yahooChartResponse struct {
+	Chart struct {
+		Result []struct {
+			Meta struct {
+				Currency            string  `json:"currency"`
+				RegularMarketPrice  float64 `json:"regularMarketPrice"`
+				RegularMarketOpen   float64 `json:"regularMarketOpen"`
+				RegularMarketVolume int64   `json:"regularMarketVolume"`
+				RegularMarketTime   int64   `json:"regularMarketTime"`
+				ChartPreviousClose  float64 `json:"chartPreviousClose"`
+			} `json:"meta"`
+			Timestamp  []int64 `json:"timestamp"`
+			Indicators struct {
+				Quote []struct {
+					Open   []*float64 `json:"open"`
+					Close  []*float64 `json:"close"`
+					Volume []*int64   `json:"volume"`
+				} `json:"quote"`
+			} `json:"indicators"`
+		} `json:"result"`
+		Error *struct {
+			Code        string `json:"code"`
+			Description string `json:"description"`
+		} `json:"error"`
+	} `json:"chart"`
+}
+
+// NewHistoryFetcher creates a new HistoryFetcher instance
+func NewHistoryFetcher() *HistoryFetcher {
+	return &HistoryFetcher{
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Name identifies the Yahoo chart API history source
+func (hf *HistoryFetcher) Name() string {
+	return "yahoo"
+}
+
+// FetchDailyCloses returns daily closing prices for a symbol between from and to
+func (hf *HistoryFetcher) FetchDailyCloses(ctx context.Context, symbol string, from, to time.Time) ([]models.PricePoint, error) {
+	query := url.Values{}
+	query.Set("period1", fmt.Sprintf("%d", from.Unix()))
+	query.Set("period2", fmt.Sprintf("%d", to.Unix()))
+	query.Set("interval", "1d")
+	endpoint := fmt.Sprintf("https://query1.finance.yahoo.com/v8/finance/chart/%s?%s", url.PathEscape(symbol), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHistoryUnavailable, err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; stock-bot)")
+
+	resp, err := hf.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHistoryUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("%w: received status code %d", ErrHistoryUnavailable, resp.StatusCode)
+	}
+
+	var chart yahooChartResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chart); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHistoryUnavailable, err)
+	}
+
+	if chart.Chart.Error != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHistoryUnavailable, chart.Chart.Error.Description)
+	}
+	if len(chart.Chart.Result) == 0 || len(chart.Chart.Result[0].Indicators.Quote) == 0 {
+		return nil, fmt.Errorf("%w: empty response for %s", ErrHistoryUnavailable, symbol)
+	}
+
+	result := chart.Chart.Result[0]
+	quote := result.Indicators.Quote[0]
+
+	var points []models.PricePoint
+	for i, ts := range result.Timestamp {
+		// Days without trading have null closes
+		if i >= len(quote.Close) || quote.Close[i] == nil {
+			continue
+		}
+
+		point := models.PricePoint{
+			Timestamp: time.Unix(ts, 0),
+			Close:     *quote.Close[i],
+		}
+		if i < len(quote.Volume) && quote.Volume[i] != nil {
+			point.Volume = *quote.Volume[i]
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
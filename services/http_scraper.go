@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// HTTPScraper reads quote pages over plain HTTP with the scrape profiles' selectors, for environments without Chrome
+type HTTPScraper struct {
+	client    *http.Client
+	userAgent string
+	profiles  *scrapeProfiles
+}
+
+// NewHTTPScraper creates a new HTTPScraper instance
+func NewHTTPScraper() *HTTPScraper {
+	return &HTTPScraper{
+		client:    &http.Client{Timeout: 20 * time.Second},
+		userAgent: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36",
+		profiles:  newScrapeProfiles(),
+	}
+}
+
+// SetScrapeProfiles replaces the sites and selectors quotes are read with
+func (hs *HTTPScraper) SetScrapeProfiles(profiles []models.ScrapeProfile) {
+	hs.profiles.set(profiles)
+}
+
+// FetchPrice extracts the price text from a quote page URL
+func (hs *HTTPScraper) FetchPrice(ctx context.Context, url string) (string, error) {
+	doc, err := hs.fetchDocument(ctx, url)
+	if err != nil {
+		return "", err
+	}
+
+	price := selectText(doc, hs.profiles.priceSelectors())
+	if price == "" {
+		return "", ErrElementNotFound
+	}
+	return price, nil
+}
+
+// FetchQuote extracts a detailed quote for a symbol, trying each scrape profile until one reads a price, healthier
+// profiles first
+func (hs *HTTPScraper) FetchQuote(ctx context.Context, symbol string) (models.Quote, error) {
+	return hs.profiles.fetchQuote(ctx, symbol, func(profile models.ScrapeProfile) (models.Quote, error) {
+		return hs.profiles.fetchFirstURL(profile, symbol, func(page string) (models.Quote, error) {
+			return hs.fetchPageQuote(ctx, symbol, profile, page)
+		})
+	})
+}
+
+// fetchPageQuote reads a symbol's quote from one of a profile's pages
+func (hs *HTTPScraper) fetchPageQuote(ctx context.Context, symbol string, profile models.ScrapeProfile, url string) (models.Quote, error) {
+	doc, err := hs.fetchDocument(ctx, url)
+	if err != nil {
+		return models.Quote{}, err
+	}
+
+	fields := quoteFields{
+		Price:         selectText(doc, profile.Price),
+		Change:        selectText(doc, profile.Change),
+		ChangePercent: selectText(doc, profile.ChangePercent),
+		Volume:        selectText(doc, profile.Volume),
+	}
+	if fields.Price == "" {
+		return models.Quote{}, ErrElementNotFound
+	}
+
+	return parseQuoteFields(symbol, fields)
+}
+
+// fetchDocument downloads and parses an HTML page
+func (hs *HTTPScraper) fetchDocument(ctx context.Context, url string) (*goquery.Document, error) {
+	slog.Debug("Fetching over HTTP", "url", url)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPriceFetchFailed, err)
+	}
+	req.Header.Set("User-Agent", hs.userAgent)
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+
+	resp, err := hs.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPriceFetchFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("%w: received status code %d", ErrPriceFetchFailed, resp.StatusCode)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPriceFetchFailed, err)
+	}
+	return doc, nil
+}
+
+// selectText returns the text of the first selector that matches a non-empty element
+func selectText(doc *goquery.Document, selectors []string) string {
+	for _, selector := range selectors {
+		if text := strings.TrimSpace(doc.Find(selector).First().Text()); text != "" {
+			return text
+		}
+	}
+	return ""
+}
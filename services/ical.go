@@ -0,0 +1,94 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+)
+
+// icalEscaper escapes text values as required by RFC 5545
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// earningsTimings describes FMP earnings release timings
+var earningsTimings = map[string]string{
+	"bmo": "before open",
+	"amc": "after close",
+}
+
+// BuildICalendar renders earnings and ex-dividend dates as an iCalendar document of all-day events
+func BuildICalendar(name string, events []models.CorporateEvent, now time.Time) []byte {
+	var cal strings.Builder
+	writeLine := func(line string) {
+		cal.WriteString(foldICalLine(line))
+	}
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//stock-bot//corporate events//EN")
+	writeLine("CALSCALE:GREGORIAN")
+	writeLine("X-WR-CALNAME:" + icalEscaper.Replace(name))
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, event := range events {
+		summary, description := describeCorporateEvent(event)
+
+		writeLine("BEGIN:VEVENT")
+		writeLine(fmt.Sprintf("UID:%s-%s-%s@stock-bot", event.Symbol, event.Type, event.Date.Format("20060102")))
+		writeLine("DTSTAMP:" + stamp)
+		writeLine("DTSTART;VALUE=DATE:" + event.Date.Format("20060102"))
+		writeLine("DTEND;VALUE=DATE:" + event.Date.AddDate(0, 0, 1).Format("20060102"))
+		writeLine("SUMMARY:" + icalEscaper.Replace(summary))
+		if description != "" {
+			writeLine("DESCRIPTION:" + icalEscaper.Replace(description))
+		}
+		writeLine("TRANSP:TRANSPARENT")
+		writeLine("END:VEVENT")
+	}
+
+	writeLine("END:VCALENDAR")
+	return []byte(cal.String())
+}
+
+// describeCorporateEvent returns the summary and description of a calendar event
+func describeCorporateEvent(event models.CorporateEvent) (string, string) {
+	switch event.Type {
+	case models.CorporateEarnings:
+		summary := event.Symbol + " earnings"
+		if timing, ok := earningsTimings[event.Timing]; ok {
+			summary += " (" + timing + ")"
+		}
+		description := ""
+		if event.EPSEstimate != nil {
+			description = fmt.Sprintf("EPS estimate: %.2f", *event.EPSEstimate)
+		}
+		return summary, description
+	default:
+		summary := fmt.Sprintf("%s ex-dividend %s", event.Symbol, models.FormatPrice(event.Symbol, event.Dividend))
+		description := ""
+		if !event.PaymentDate.IsZero() {
+			description = "Payment date: " + event.PaymentDate.Format("2006-01-02")
+		}
+		return summary, description
+	}
+}
+
+// foldICalLine terminates a content line with CRLF, folding it at 75 octets
+func foldICalLine(line string) string {
+	var folded strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		// Don't split a multi-byte character
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		folded.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space
+		limit = 74
+	}
+	folded.WriteString(line + "\r\n")
+	return folded.String()
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// priceIndexes serve the per-symbol price queries: the latest closing price and closing history
+// filter on isClosing, while intraday lookups and range queries only filter on symbol and time
+var priceIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "symbol", Value: 1}, {Key: "isClosing", Value: 1}, {Key: "timestamp", Value: -1}}},
+	{Keys: bson.D{{Key: "symbol", Value: 1}, {Key: "timestamp", Value: -1}}},
+}
+
+// EnsureIndexes creates the indexes the price queries rely on; existing indexes are left as they are,
+// so it is safe to run on every start
+func (db *Database) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("stocks")
+	if err != nil {
+		return err
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, priceIndexes); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
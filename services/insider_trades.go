@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// fmpInsiderTrade is an entry of the FMP insider-trading endpoint
+type fmpInsiderTrade struct {
+	FilingDate           string  `json:"filingDate"`
+	TransactionDate      string  `json:"transactionDate"`
+	TransactionType      string  `json:"transactionType"`
+	ReportingName        string  `json:"reportingName"`
+	TypeOfOwner          string  `json:"typeOfOwner"`
+	SecuritiesTransacted float64 `json:"securitiesTransacted"`
+	Price                float64 `json:"price"`
+	FormType             string  `json:"formType"`
+	Link                 string  `json:"link"`
+}
+
+// FetchInsiderTrades returns recent open-market insider purchases and sales filed on Form 4
+func (fc *FMPClient) FetchInsiderTrades(ctx context.Context, symbol string) ([]models.InsiderTrade, error) {
+	var entries []fmpInsiderTrade
+	if err := fc.get(ctx, "insider-trading", symbol, &entries); err != nil {
+		return nil, err
+	}
+
+	trades := make([]models.InsiderTrade, 0, len(entries))
+	for _, entry := range entries {
+		// Transaction types look like "P-Purchase" or "S-Sale"; grants and exercises are ignored
+		code, _, _ := strings.Cut(entry.TransactionType, "-")
+		if entry.FormType != "4" || (code != models.InsiderPurchase && code != models.InsiderSale) {
+			continue
+		}
+
+		filed, err := time.Parse("2006-01-02 15:04:05", entry.FilingDate)
+		if err != nil {
+			continue
+		}
+		transacted, _ := time.Parse("2006-01-02", entry.TransactionDate)
+
+		trades = append(trades, models.InsiderTrade{
+			Symbol:          symbol,
+			Insider:         entry.ReportingName,
+			Position:        entry.TypeOfOwner,
+			TransactionType: code,
+			Shares:          entry.SecuritiesTransacted,
+			Price:           entry.Price,
+			TransactionDate: transacted,
+			FilingDate:      filed,
+			FilingURL:       entry.Link,
+		})
+	}
+
+	return trades, nil
+}
+
+// SaveInsiderTrades stores insider trades and returns the ones that were not seen before
+func (db *Database) SaveInsiderTrades(trades []models.InsiderTrade) ([]models.InsiderTrade, error) {
+	if len(trades) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("insider_trades")
+	if err != nil {
+		return nil, err
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(trades))
+	for _, trade := range trades {
+		filter := bson.D{
+			{Key: "symbol", Value: trade.Symbol},
+			{Key: "insider", Value: trade.Insider},
+			{Key: "filingUrl", Value: trade.FilingURL},
+			{Key: "transactionDate", Value: trade.TransactionDate},
+			{Key: "shares", Value: trade.Shares},
+		}
+		update := bson.D{{Key: "$setOnInsert", Value: trade}}
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update).SetUpsert(true))
+	}
+
+	result, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(true))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	inserted := make([]models.InsiderTrade, 0, len(result.UpsertedIDs))
+	for index := range result.UpsertedIDs {
+		inserted = append(inserted, trades[index])
+	}
+	return inserted, nil
+}
+
+// CountInsiderTrades returns how many insider trades are stored for a symbol
+func (db *Database) CountInsiderTrades(symbol string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("insider_trades")
+	if err != nil {
+		return 0, err
+	}
+	count, err := collection.CountDocuments(ctx, bson.D{{Key: "symbol", Value: symbol}})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return count, nil
+}
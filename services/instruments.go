@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// SaveInstrument stores the identifiers resolved for a ticker
+func (db *Database) SaveInstrument(instrument models.Instrument) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("instruments")
+	if !ok {
+		return nil
+	}
+
+	// Merge identifiers so resolving by CUSIP does not drop an ISIN stored earlier, and vice versa
+	set := bson.D{
+		{Key: "name", Value: instrument.Name},
+		{Key: "exchange", Value: instrument.Exchange},
+		{Key: "resolvedAt", Value: instrument.ResolvedAt},
+	}
+	if instrument.ISIN != "" {
+		set = append(set, bson.E{Key: "isin", Value: instrument.ISIN})
+	}
+	if instrument.CUSIP != "" {
+		set = append(set, bson.E{Key: "cusip", Value: instrument.CUSIP})
+	}
+
+	filter := bson.D{{Key: "symbol", Value: instrument.Symbol}}
+	update := bson.D{{Key: "$set", Value: set}}
+	if _, err := collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true)); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// FindInstrument looks up a previously resolved instrument by ISIN or CUSIP
+func (db *Database) FindInstrument(identifier string) (models.Instrument, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("instruments")
+	if err != nil {
+		return models.Instrument{}, err
+	}
+
+	filter := bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: "isin", Value: identifier}},
+		bson.D{{Key: "cusip", Value: identifier}},
+	}}}
+
+	var instrument models.Instrument
+	if err := collection.FindOne(ctx, filter).Decode(&instrument); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.Instrument{}, fmt.Errorf("%w: %s", ErrSymbolNotFound, identifier)
+		}
+		return models.Instrument{}, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return instrument, nil
+}
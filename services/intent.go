@@ -0,0 +1,176 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"stock-bot/models"
+)
+
+// IntentKind identifies what a free-form chat message is asking for
+type IntentKind string
+
+// Supported intents
+const (
+	IntentPrice   IntentKind = "price"
+	IntentHistory IntentKind = "history"
+	IntentChart   IntentKind = "chart"
+)
+
+// Intent is a chat message mapped onto a bot command
+type Intent struct {
+	Kind   IntentKind
+	Symbol string
+	Period string // Chart period such as "1w"; empty for the default
+}
+
+// CompanyAliases maps lower-case company names and nicknames to ticker symbols
+var CompanyAliases = map[string]string{
+	"apple":     models.AAPL,
+	"애플":        models.AAPL,
+	"google":    models.GOOGL,
+	"alphabet":  models.GOOGL,
+	"구글":        models.GOOGL,
+	"amazon":    models.AMZN,
+	"아마존":       models.AMZN,
+	"microsoft": models.MSFT,
+	"마이크로소프트":   models.MSFT,
+	"tesla":     models.TSLA,
+	"테슬라":       models.TSLA,
+	"nvidia":    models.NVDA,
+	"엔비디아":      models.NVDA,
+	"netflix":   models.NFLX,
+	"넷플릭스":      models.NFLX,
+	"meta":      models.META,
+	"facebook":  models.META,
+	"메타":        models.META,
+	"samsung":   "005930.KS",
+	"삼성전자":      "005930.KS",
+}
+
+// Keywords that select an intent or a chart period
+var (
+	chartKeywords   = []string{"chart", "graph", "차트", "그래프"}
+	historyKeywords = []string{"history", "closes", "closing", "recent", "past", "기록", "종가"}
+	quoteKeywords   = []string{"price", "prices", "quote", "quotes", "trading", "doing", "show", "가격", "시세", "주가", "얼마", "어때"}
+	periodKeywords  = []struct {
+		words  []string
+		period string
+	}{
+		{[]string{"year", "1y", "12 months", "일년", "1년"}, "1y"},
+		{[]string{"quarter", "3 months", "three months", "3m", "3개월"}, "3m"},
+		{[]string{"month", "1m", "한달", "이번달"}, "1m"},
+		{[]string{"week", "1w", "7 days", "이번주", "일주일"}, "1w"},
+	}
+)
+
+// ParseIntent maps a free-form message such as "how is nvidia doing" onto a command intent
+func ParseIntent(text string) (Intent, bool) {
+	symbol, ok := findSymbol(text)
+	if !ok {
+		return Intent{}, false
+	}
+
+	lower := strings.ToLower(text)
+	period := ""
+	for _, candidate := range periodKeywords {
+		if containsAny(lower, candidate.words) {
+			period = candidate.period
+			break
+		}
+	}
+
+	switch {
+	case containsAny(lower, chartKeywords):
+		return Intent{Kind: IntentChart, Symbol: symbol, Period: period}, true
+	case containsAny(lower, historyKeywords):
+		return Intent{Kind: IntentHistory, Symbol: symbol}, true
+	case period != "":
+		// "show me apple this week" asks for a trend rather than a single price
+		return Intent{Kind: IntentChart, Symbol: symbol, Period: period}, true
+	default:
+		return Intent{Kind: IntentPrice, Symbol: symbol}, true
+	}
+}
+
+// AsksForQuote reports whether a message reads as a request for market data rather than a passing mention of a
+// company: it asks a question or uses a word such as "price", "chart" or "history"
+func AsksForQuote(text string) bool {
+	if strings.ContainsAny(text, "?？") {
+		return true
+	}
+	lower := strings.ToLower(text)
+	return containsAny(lower, quoteKeywords) || containsAny(lower, chartKeywords) || containsAny(lower, historyKeywords)
+}
+
+// findSymbol looks for a known company name or a watched ticker in the message
+func findSymbol(text string) (string, bool) {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '^' && r != '-'
+	})
+
+	for _, word := range words {
+		trimmed := strings.Trim(word, ".-")
+		if symbol, ok := CompanyAliases[strings.ToLower(trimmed)]; ok {
+			return symbol, true
+		}
+
+		// Korean particles are attached to the noun, e.g. "테슬라는"
+		for alias, symbol := range CompanyAliases {
+			if !isASCII(alias) && strings.HasPrefix(trimmed, alias) {
+				return symbol, true
+			}
+		}
+	}
+
+	// Only accept raw tickers that are watched, to avoid matching ordinary words
+	for _, word := range words {
+		candidate := strings.ToUpper(strings.Trim(word, ".-"))
+		for _, ticker := range models.Tickers() {
+			if candidate == ticker {
+				return ticker, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// containsAny reports whether text contains any of the given words as whole words, so "past" doesn't match "pasta".
+// Korean words only need to start a word, since particles attach to their end as in "차트를"
+func containsAny(text string, words []string) bool {
+	for _, word := range words {
+		for offset := 0; offset < len(text); {
+			i := strings.Index(text[offset:], word)
+			if i < 0 {
+				break
+			}
+			start, end := offset+i, offset+i+len(word)
+			before, _ := utf8.DecodeLastRuneInString(text[:start])
+			after, _ := utf8.DecodeRuneInString(text[end:])
+			if start == 0 || !isWordRune(before) {
+				if end == len(text) || !isWordRune(after) || !isASCII(word) {
+					return true
+				}
+			}
+			offset = start + 1
+		}
+	}
+	return false
+}
+
+// isWordRune reports whether r is part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isASCII reports whether s only contains ASCII characters
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
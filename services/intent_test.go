@@ -0,0 +1,88 @@
+package services
+
+import (
+	"testing"
+
+	"stock-bot/models"
+)
+
+func TestContainsAny(t *testing.T) {
+	tests := []struct {
+		text  string
+		words []string
+		want  bool
+	}{
+		{"apple chart", chartKeywords, true},
+		{"chart: apple", chartKeywords, true},
+		{"charts of apple", chartKeywords, false},
+		{"apple past week", historyKeywords, true},
+		{"apple pasta recipe", historyKeywords, false},
+		{"recently apple", historyKeywords, false},
+		{"the monthly apple", []string{"month"}, false},
+		{"apple over 12 months", []string{"12 months"}, true},
+		{"apple over 112 months", []string{"12 months"}, false},
+		{"애플 차트를 보여줘", chartKeywords, true},
+		{"애플차트", chartKeywords, false},
+		{"", chartKeywords, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := containsAny(tt.text, tt.words); got != tt.want {
+				t.Errorf("containsAny(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIntent(t *testing.T) {
+	tests := []struct {
+		text string
+		want Intent
+		ok   bool
+	}{
+		{"how is nvidia doing", Intent{Kind: IntentPrice, Symbol: models.NVDA}, true},
+		{"show me the apple chart", Intent{Kind: IntentChart, Symbol: models.AAPL}, true},
+		{"tesla graph for the year", Intent{Kind: IntentChart, Symbol: models.TSLA, Period: "1y"}, true},
+		{"apple this week", Intent{Kind: IntentChart, Symbol: models.AAPL, Period: "1w"}, true},
+		{"recent closes of microsoft", Intent{Kind: IntentHistory, Symbol: models.MSFT}, true},
+		{"tesla biweekly meeting", Intent{Kind: IntentPrice, Symbol: models.TSLA}, true},
+		{"테슬라는 어때", Intent{Kind: IntentPrice, Symbol: models.TSLA}, true},
+		{"삼성전자 종가", Intent{Kind: IntentHistory, Symbol: "005930.KS"}, true},
+		{"anyone for pasta", Intent{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, ok := ParseIntent(tt.text)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ParseIntent(%q) = %+v, %v, want %+v, %v", tt.text, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAsksForQuote(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"how is nvidia doing", true},
+		{"apple?", true},
+		{"테슬라 주가", true},
+		{"apple price", true},
+		{"show me the apple chart", true},
+		{"recent closes of microsoft", true},
+		{"I had an apple for lunch", false},
+		{"IT is down again", false},
+		{"meta is hiring", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := AsksForQuote(tt.text); got != tt.want {
+				t.Errorf("AsksForQuote(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
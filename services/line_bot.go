@@ -0,0 +1,392 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+
+	"github.com/google/uuid"
+)
+
+// Error definitions related to the Line webhook
+var (
+	ErrLineSignature = errors.New("invalid Line signature")
+	ErrLineAPI       = errors.New("line API error")
+)
+
+// Line webhook settings
+const (
+	lineReplyTokenTTL = 50 * time.Second // Reply tokens expire a minute after the event; older ones are pushed instead
+	lineImageTTL      = time.Hour        // How long chart images stay available to Line clients
+	lineMaxImages     = 100              // Chart images kept at most; the oldest is dropped for a new one
+	lineMaxBodyBytes  = 1 << 20          // Largest webhook request accepted
+	lineMaxTextLength = 5000             // Longest text message Line accepts
+)
+
+// LineBot receives commands from Line chats through the webhook and replies to them
+type LineBot struct {
+	token     string
+	secret    string
+	publicURL string
+	client    *http.Client
+	mu        sync.RWMutex
+	handlers  map[string]CommandHandler
+	fallback  CommandHandler
+	running   sync.WaitGroup
+
+	// Reply tokens of the latest event per chat; a token answers one reply, later ones are pushed
+	tokensMu    sync.Mutex
+	replyTokens map[string]lineReplyToken
+
+	// Chart images served to Line clients, which only load images by URL
+	imagesMu sync.Mutex
+	images   map[string]lineImage
+}
+
+// lineReplyToken is the reply token of a webhook event and when it was received
+type lineReplyToken struct {
+	token    string
+	received time.Time
+}
+
+// lineImage is a PNG image served to Line clients until it expires
+type lineImage struct {
+	data    []byte
+	expires time.Time
+}
+
+// lineWebhook is the subset of the Line webhook request body used by the bot
+type lineWebhook struct {
+	Events []lineEvent `json:"events"`
+}
+
+// lineEvent is a webhook event: a message, or the bot being followed or added to a group
+type lineEvent struct {
+	Type       string `json:"type"`
+	ReplyToken string `json:"replyToken"`
+	Source     struct {
+		Type    string `json:"type"`
+		UserID  string `json:"userId"`
+		GroupID string `json:"groupId"`
+		RoomID  string `json:"roomId"`
+	} `json:"source"`
+	Message *struct {
+		Type    string `json:"type"`
+		Text    string `json:"text"`
+		Mention *struct {
+			Mentionees []struct {
+				IsSelf bool `json:"isSelf"`
+			} `json:"mentionees"`
+		} `json:"mention"`
+	} `json:"message"`
+}
+
+// chatID returns the chat an event came from: the group or room, or the user for one-to-one chats
+func (e lineEvent) chatID() string {
+	switch {
+	case e.Source.GroupID != "":
+		return e.Source.GroupID
+	case e.Source.RoomID != "":
+		return e.Source.RoomID
+	default:
+		return e.Source.UserID
+	}
+}
+
+// mentionsBot reports whether a text message mentions the bot
+func (e lineEvent) mentionsBot() bool {
+	if e.Message == nil || e.Message.Mention == nil {
+		return false
+	}
+	for _, mentionee := range e.Message.Mention.Mentionees {
+		if mentionee.IsSelf {
+			return true
+		}
+	}
+	return false
+}
+
+// NewLineBot creates a Line bot that verifies webhook requests with the channel secret; chart images are linked
+// under publicURL, and left out when it is empty
+func NewLineBot(token, secret, publicURL string) (*LineBot, error) {
+	if token == "" || secret == "" {
+		return nil, ErrTokenNotSet
+	}
+	return &LineBot{
+		token:       token,
+		secret:      secret,
+		publicURL:   strings.TrimSuffix(publicURL, "/"),
+		client:      &http.Client{Timeout: 10 * time.Second},
+		handlers:    make(map[string]CommandHandler),
+		replyTokens: make(map[string]lineReplyToken),
+		images:      make(map[string]lineImage),
+	}, nil
+}
+
+// Handle registers a handler for a command name (without the leading slash)
+func (lb *LineBot) Handle(name string, handler CommandHandler) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	lb.handlers[strings.ToLower(name)] = handler
+}
+
+// HandleText registers a handler for plain (non-command) messages
+func (lb *LineBot) HandleText(handler CommandHandler) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	lb.fallback = handler
+}
+
+// Wait blocks until the command handlers still running have finished
+func (lb *LineBot) Wait() {
+	lb.running.Wait()
+}
+
+// ServeHTTP handles a webhook request: the signature is checked, the events are dispatched in the background
+// and Line gets its response right away
+func (lb *LineBot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, lineMaxBodyBytes))
+	if err != nil {
+		http.Error(w, "could not read body", http.StatusBadRequest)
+		return
+	}
+	if err := lb.verify(body, r.Header.Get("X-Line-Signature")); err != nil {
+		slog.Warn("Rejected Line webhook request", "remote", r.RemoteAddr, "error", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var webhook lineWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		http.Error(w, "invalid body", http.StatusBadRequest)
+		return
+	}
+
+	for _, event := range webhook.Events {
+		lb.dispatch(r.Context(), event)
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// verify checks the base64 HMAC-SHA256 of the body, keyed with the channel secret, against the signature header
+func (lb *LineBot) verify(body []byte, signature string) error {
+	expected, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || signature == "" {
+		return ErrLineSignature
+	}
+	mac := hmac.New(sha256.New, []byte(lb.secret))
+	mac.Write(body)
+	if !hmac.Equal(mac.Sum(nil), expected) {
+		return ErrLineSignature
+	}
+	return nil
+}
+
+// dispatch routes a text message to its command handler, or to the text handler for plain messages;
+// following the bot or adding it to a group runs /start
+func (lb *LineBot) dispatch(ctx context.Context, event lineEvent) {
+	cmd := BotCommand{Channel: models.ChannelLine, ChatID: event.chatID(), UserID: event.Source.UserID}
+	if cmd.ChatID == "" {
+		return
+	}
+
+	switch event.Type {
+	case "follow", "join":
+		cmd.Name = startCommand
+	case "message":
+		if event.Message == nil || event.Message.Type != "text" || strings.TrimSpace(event.Message.Text) == "" {
+			return
+		}
+		cmd.Text = event.Message.Text
+		cmd.Group = event.Source.Type != "user"
+		cmd.Mention = event.mentionsBot()
+		if strings.HasPrefix(event.Message.Text, "/") {
+			parsed := parseBotCommand(event.Message.Text)
+			cmd.Name, cmd.Args = parsed.Name, parsed.Args
+		}
+	default:
+		return
+	}
+
+	lb.mu.RLock()
+	handler, ok := lb.handlers[cmd.Name]
+	if cmd.Name == "" {
+		handler, ok = lb.fallback, lb.fallback != nil
+	}
+	lb.mu.RUnlock()
+
+	if !ok {
+		if cmd.Name != "" {
+			slog.Info("Ignoring unknown command", "channel", models.ChannelLine, "command", cmd.Name, "chat", cmd.ChatID)
+		}
+		return
+	}
+
+	if event.ReplyToken != "" {
+		lb.tokensMu.Lock()
+		lb.replyTokens[cmd.ChatID] = lineReplyToken{token: event.ReplyToken, received: time.Now()}
+		lb.tokensMu.Unlock()
+	}
+
+	if cmd.Name != "" {
+		slog.Info("Handling command", "channel", models.ChannelLine, "command", cmd.Name, "chat", cmd.ChatID)
+	}
+	lb.run(ctx, handler, cmd)
+}
+
+// run executes a handler in the background and reports its error back to the chat.
+// Handlers outlive the webhook request so the reply is sent after Line got its response
+func (lb *LineBot) run(ctx context.Context, handler CommandHandler, cmd BotCommand) {
+	ctx = context.WithoutCancel(ctx)
+	lb.running.Add(1)
+	go func() {
+		defer lb.running.Done()
+		if err := handler(ctx, cmd); err != nil {
+			slog.Error("Error handling command", "channel", models.ChannelLine, "command", cmd.Name, "error", err)
+			if replyErr := lb.Reply(ctx, cmd.ChatID, fmt.Sprintf("⚠️ %v", err)); replyErr != nil {
+				slog.Error("Error sending command error reply", "error", replyErr)
+			}
+		}
+	}()
+}
+
+// Reply sends a text message to a chat
+func (lb *LineBot) Reply(ctx context.Context, chatID, text string) error {
+	return lb.send(ctx, chatID, []map[string]string{lineTextMessage(text)})
+}
+
+// ReplyMarkdown sends a MarkdownV2 formatted message to a chat as plain text, as Line has no markup
+func (lb *LineBot) ReplyMarkdown(ctx context.Context, chatID, text string) error {
+	return lb.Reply(ctx, chatID, stripMarkdownV2(text))
+}
+
+// ReplyPhoto sends a PNG image with a caption to a chat; without a public URL to serve the image from,
+// only the caption is sent
+func (lb *LineBot) ReplyPhoto(ctx context.Context, chatID string, photo []byte, caption string) error {
+	if lb.publicURL == "" {
+		return lb.Reply(ctx, chatID, caption)
+	}
+
+	url := lb.publicURL + "/line/images/" + lb.storeImage(photo) + ".png"
+	image := map[string]string{
+		"type":               "image",
+		"originalContentUrl": url,
+		"previewImageUrl":    url,
+	}
+	return lb.send(ctx, chatID, []map[string]string{image, lineTextMessage(caption)})
+}
+
+// ServeImage serves a chart image linked from a reply
+func (lb *LineBot) ServeImage(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSuffix(r.PathValue("id"), ".png")
+
+	lb.imagesMu.Lock()
+	image, ok := lb.images[id]
+	lb.imagesMu.Unlock()
+	if !ok || time.Now().After(image.expires) {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(image.data)
+}
+
+// storeImage keeps an image for Line clients to load and returns its ID, dropping expired images and, when the
+// limit is reached, the oldest one
+func (lb *LineBot) storeImage(data []byte) string {
+	now := time.Now()
+	id := uuid.NewString()
+
+	lb.imagesMu.Lock()
+	defer lb.imagesMu.Unlock()
+	oldest := ""
+	for key, image := range lb.images {
+		if now.After(image.expires) {
+			delete(lb.images, key)
+		} else if oldest == "" || image.expires.Before(lb.images[oldest].expires) {
+			oldest = key
+		}
+	}
+	if len(lb.images) >= lineMaxImages {
+		delete(lb.images, oldest)
+	}
+	lb.images[id] = lineImage{data: data, expires: now.Add(lineImageTTL)}
+	return id
+}
+
+// lineTextMessage builds a text message, truncated to the length Line accepts
+func lineTextMessage(text string) map[string]string {
+	if runes := []rune(text); len(runes) > lineMaxTextLength {
+		text = string(runes[:lineMaxTextLength-1]) + "…"
+	}
+	return map[string]string{"type": "text", "text": text}
+}
+
+// send answers the chat's latest event with its reply token while it is fresh, and pushes the messages otherwise
+func (lb *LineBot) send(ctx context.Context, chatID string, messages []map[string]string) error {
+	lb.tokensMu.Lock()
+	token, ok := lb.replyTokens[chatID]
+	delete(lb.replyTokens, chatID)
+	lb.tokensMu.Unlock()
+
+	if ok && time.Since(token.received) < lineReplyTokenTTL {
+		payload := map[string]interface{}{"replyToken": token.token, "messages": messages}
+		err := lb.call(ctx, "reply", payload)
+		if err == nil {
+			return nil
+		}
+		slog.Warn("Line reply failed, pushing the message instead", "chat", chatID, "error", err)
+	}
+
+	payload := map[string]interface{}{"to": chatID, "messages": messages}
+	return lb.call(ctx, "push", payload)
+}
+
+// call invokes a Line Messaging API message endpoint with a JSON payload
+func (lb *LineBot) call(ctx context.Context, endpoint string, payload interface{}) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+
+	url := "https://api.line.me/v2/bot/message/" + endpoint
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+lb.token)
+
+	resp, err := lb.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessageSending, err)
+	}
+	defer resp.Body.Close()
+
+	slog.Debug("LINE Bot response", "kind", endpoint, "status", resp.Status)
+
+	if resp.StatusCode >= 400 {
+		var body struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return fmt.Errorf("%w: %s: %w: %s", ErrLineAPI, endpoint, newStatusError(resp), body.Message)
+	}
+	return nil
+}
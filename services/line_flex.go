@@ -0,0 +1,182 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"stock-bot/models"
+)
+
+// ErrFlexTooLarge is returned when a report or alert list doesn't fit in one Flex Message
+var ErrFlexTooLarge = errors.New("too many entries for a Flex Message")
+
+// Line Flex Message limits and colors
+const (
+	flexMaxBubbles     = 12  // Bubbles a carousel may hold
+	flexRowsPerBubble  = 20  // Report rows per bubble before the report continues in the next one
+	flexMaxAltText     = 400 // Longest alternative text, shown in notifications and on clients without Flex support
+	flexColorUp        = "#1DB446"
+	flexColorDown      = "#E0314B"
+	flexColorUnchanged = "#888888"
+	flexColorMuted     = "#AAAAAA"
+)
+
+// flexComponent is a Flex Message container or component; only the properties the bot uses are included
+type flexComponent struct {
+	Type     string          `json:"type"`
+	Layout   string          `json:"layout,omitempty"`
+	Header   *flexComponent  `json:"header,omitempty"`
+	Body     *flexComponent  `json:"body,omitempty"`
+	Contents []flexComponent `json:"contents,omitempty"`
+	Text     string          `json:"text,omitempty"`
+	Size     string          `json:"size,omitempty"`
+	Weight   string          `json:"weight,omitempty"`
+	Color    string          `json:"color,omitempty"`
+	Align    string          `json:"align,omitempty"`
+	Margin   string          `json:"margin,omitempty"`
+	Spacing  string          `json:"spacing,omitempty"`
+	Wrap     bool            `json:"wrap,omitempty"`
+}
+
+// flexMessage wraps a bubble or carousel into a message; altText is what notifications show
+func flexMessage(altText string, contents flexComponent) map[string]any {
+	if runes := []rune(altText); len(runes) > flexMaxAltText {
+		altText = string(runes[:flexMaxAltText-1]) + "…"
+	}
+	return map[string]any{"type": "flex", "altText": altText, "contents": contents}
+}
+
+// buildFlexReport lays the daily report out as bubbles of symbol, price and colored change rows, titled with the
+// first line of the text report so custom and translated templates carry over
+func buildFlexReport(title string, quotes map[string]models.Quote) (flexComponent, error) {
+	symbols := slices.Sorted(maps.Keys(quotes))
+	if len(symbols) == 0 {
+		return flexComponent{}, fmt.Errorf("%w: empty report", ErrMessagePreparation)
+	}
+	if len(symbols) > flexMaxBubbles*flexRowsPerBubble {
+		return flexComponent{}, fmt.Errorf("%w: %d symbols", ErrFlexTooLarge, len(symbols))
+	}
+
+	var bubbles []flexComponent
+	for chunk := range slices.Chunk(symbols, flexRowsPerBubble) {
+		rows := make([]flexComponent, 0, len(chunk))
+		for _, symbol := range chunk {
+			quote := quotes[symbol]
+			change := flexText("–", "sm", flexChangeColor(quote.ChangePercent))
+			if quote.ChangePercent != 0 {
+				change.Text = fmt.Sprintf("%s %+.2f%%", flexChangeArrow(quote.ChangePercent), quote.ChangePercent)
+			}
+			change.Align = "end"
+			price := flexText(models.FormatPrice(symbol, quote.Price), "sm", "")
+			price.Align = "end"
+			symbolText := flexText(symbol, "sm", "")
+			symbolText.Weight = "bold"
+
+			rows = append(rows, flexComponent{Type: "box", Layout: "horizontal", Contents: []flexComponent{symbolText, price, change}})
+		}
+		bubbles = append(bubbles, flexBubble([]flexComponent{flexTitle(title)}, rows))
+	}
+	return flexCarousel(bubbles), nil
+}
+
+// buildFlexAlerts lays price alerts out as one bubble each, with the change colored by direction; verbose adds
+// the volume and headline
+func buildFlexAlerts(title string, alerts []models.PriceAlert, verbose bool) (flexComponent, error) {
+	if len(alerts) > flexMaxBubbles {
+		return flexComponent{}, fmt.Errorf("%w: %d alerts", ErrFlexTooLarge, len(alerts))
+	}
+
+	bubbles := make([]flexComponent, 0, len(alerts))
+	for _, alert := range alerts {
+		color := flexChangeColor(alert.PercentChange)
+		change := flexText(fmt.Sprintf("%s %+.2f%%", flexChangeArrow(alert.PercentChange), alert.PercentChange), "xxl", color)
+		change.Weight = "bold"
+		prices := flexText(fmt.Sprintf("%s → %s", models.FormatPrice(alert.Symbol, alert.PreviousPrice), models.FormatPrice(alert.Symbol, alert.CurrentPrice)), "md", "")
+		prices.Margin = "md"
+
+		body := []flexComponent{change, prices}
+		if verbose && alert.Volume > 0 {
+			body = append(body, flexText("📊 "+formatVolume(alert.Volume), "sm", flexColorMuted))
+		}
+		if verbose && alert.Headline != "" {
+			headline := flexText("📰 "+alert.Headline, "sm", flexColorMuted)
+			headline.Wrap = true
+			headline.Margin = "md"
+			body = append(body, headline)
+		}
+
+		header := []flexComponent{flexText(title, "xs", flexColorMuted), flexTitle(alert.Symbol)}
+		bubbles = append(bubbles, flexBubble(header, body))
+	}
+	return flexCarousel(bubbles), nil
+}
+
+// flexBubble builds a bubble from vertical header and body boxes
+func flexBubble(header, body []flexComponent) flexComponent {
+	return flexComponent{
+		Type:   "bubble",
+		Header: &flexComponent{Type: "box", Layout: "vertical", Contents: header},
+		Body:   &flexComponent{Type: "box", Layout: "vertical", Spacing: "sm", Contents: body},
+	}
+}
+
+// flexTitle builds a bold heading
+func flexTitle(text string) flexComponent {
+	title := flexText(text, "lg", "")
+	title.Weight = "bold"
+	title.Wrap = true
+	return title
+}
+
+// flexCarousel returns a lone bubble as is and puts several in a carousel
+func flexCarousel(bubbles []flexComponent) flexComponent {
+	if len(bubbles) == 1 {
+		return bubbles[0]
+	}
+	return flexComponent{Type: "carousel", Contents: bubbles}
+}
+
+// flexText builds a text component; Line rejects empty text, so a blank one becomes a dash
+func flexText(text, size, color string) flexComponent {
+	if strings.TrimSpace(text) == "" {
+		text = "–"
+	}
+	return flexComponent{Type: "text", Text: text, Size: size, Color: color}
+}
+
+// flexChangeColor colors gains green and losses red
+func flexChangeColor(change float64) string {
+	switch {
+	case change > 0:
+		return flexColorUp
+	case change < 0:
+		return flexColorDown
+	default:
+		return flexColorUnchanged
+	}
+}
+
+// flexChangeArrow points the way a price moved
+func flexChangeArrow(change float64) string {
+	switch {
+	case change > 0:
+		return "▲"
+	case change < 0:
+		return "▼"
+	default:
+		return "–"
+	}
+}
+
+// firstLine returns the first non-empty line of a text, used to title Flex bubbles after the text template
+func firstLine(text string) string {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
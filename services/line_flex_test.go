@@ -0,0 +1,151 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"stock-bot/models"
+)
+
+// flexQuotes returns n quotes named S000, S001, ... in order
+func flexQuotes(n int) map[string]models.Quote {
+	quotes := make(map[string]models.Quote, n)
+	for i := range n {
+		quotes[fmt.Sprintf("S%03d", i)] = models.Quote{Price: 100, ChangePercent: 1}
+	}
+	return quotes
+}
+
+// flexBubbles returns the bubbles of a bubble or carousel
+func flexBubbles(c flexComponent) []flexComponent {
+	if c.Type == "carousel" {
+		return c.Contents
+	}
+	return []flexComponent{c}
+}
+
+func TestBuildFlexReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		quotes  map[string]models.Quote
+		rows    []int // Rows of each bubble
+		wantErr error
+	}{
+		{"empty report", nil, nil, ErrMessagePreparation},
+		{"single bubble", flexQuotes(3), []int{3}, nil},
+		{"full bubble", flexQuotes(flexRowsPerBubble), []int{flexRowsPerBubble}, nil},
+		{"continues in a second bubble", flexQuotes(flexRowsPerBubble + 1), []int{flexRowsPerBubble, 1}, nil},
+		{"largest report", flexQuotes(flexMaxBubbles * flexRowsPerBubble), []int{20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20}, nil},
+		{"too many symbols", flexQuotes(flexMaxBubbles*flexRowsPerBubble + 1), nil, ErrFlexTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := buildFlexReport("Daily report", tt.quotes)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			bubbles := flexBubbles(report)
+			if len(bubbles) != len(tt.rows) {
+				t.Fatalf("%d bubbles, want %d", len(bubbles), len(tt.rows))
+			}
+			row := 0
+			for i, bubble := range bubbles {
+				if got := bubble.Header.Contents[0].Text; got != "Daily report" {
+					t.Errorf("bubble %d title = %q", i, got)
+				}
+				if got := len(bubble.Body.Contents); got != tt.rows[i] {
+					t.Errorf("bubble %d has %d rows, want %d", i, got, tt.rows[i])
+				}
+				for _, r := range bubble.Body.Contents {
+					if want := fmt.Sprintf("S%03d", row); r.Contents[0].Text != want {
+						t.Errorf("row %d is %s, want %s", row, r.Contents[0].Text, want)
+					}
+					row++
+				}
+			}
+		})
+	}
+}
+
+func TestBuildFlexReportChange(t *testing.T) {
+	tests := []struct {
+		change    float64
+		wantText  string
+		wantColor string
+	}{
+		{1.234, "▲ +1.23%", flexColorUp},
+		{-2.5, "▼ -2.50%", flexColorDown},
+		{0, "–", flexColorUnchanged},
+	}
+
+	for _, tt := range tests {
+		report, err := buildFlexReport("Daily report", map[string]models.Quote{"AAPL": {Price: 150, ChangePercent: tt.change}})
+		if err != nil {
+			t.Fatalf("buildFlexReport: %v", err)
+		}
+		change := report.Body.Contents[0].Contents[2]
+		if change.Text != tt.wantText || change.Color != tt.wantColor {
+			t.Errorf("change %v shown as %q in %s, want %q in %s", tt.change, change.Text, change.Color, tt.wantText, tt.wantColor)
+		}
+	}
+}
+
+func TestBuildFlexAlerts(t *testing.T) {
+	alert := models.PriceAlert{Symbol: "AAPL", PercentChange: -6.2, PreviousPrice: 160, CurrentPrice: 150.08, Volume: 1_200_000, Headline: "Apple falls"}
+	alerts := func(n int) []models.PriceAlert {
+		list := make([]models.PriceAlert, n)
+		for i := range list {
+			list[i] = alert
+		}
+		return list
+	}
+
+	tests := []struct {
+		name     string
+		alerts   []models.PriceAlert
+		verbose  bool
+		bubbles  int
+		bodyRows int
+		wantErr  error
+	}{
+		{"single alert", alerts(1), false, 1, 2, nil},
+		{"verbose adds volume and headline", alerts(1), true, 1, 4, nil},
+		{"verbose without details", []models.PriceAlert{{Symbol: "AAPL", PercentChange: 5}}, true, 1, 2, nil},
+		{"carousel", alerts(flexMaxBubbles), false, flexMaxBubbles, 2, nil},
+		{"too many alerts", alerts(flexMaxBubbles + 1), false, 0, 0, ErrFlexTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildFlexAlerts("Price alert", tt.alerts, tt.verbose)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			bubbles := flexBubbles(got)
+			if len(bubbles) != tt.bubbles {
+				t.Fatalf("%d bubbles, want %d", len(bubbles), tt.bubbles)
+			}
+			for i, bubble := range bubbles {
+				if symbol := bubble.Header.Contents[1].Text; symbol != tt.alerts[i].Symbol {
+					t.Errorf("bubble %d is titled %q, want %q", i, symbol, tt.alerts[i].Symbol)
+				}
+				if rows := len(bubble.Body.Contents); rows != tt.bodyRows {
+					t.Errorf("bubble %d has %d rows, want %d", i, rows, tt.bodyRows)
+				}
+			}
+			if change := bubbles[0].Body.Contents[0]; change.Color != flexChangeColor(tt.alerts[0].PercentChange) {
+				t.Errorf("change colored %s", change.Color)
+			}
+		})
+	}
+}
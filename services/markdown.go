@@ -0,0 +1,48 @@
+package services
+
+import "strings"
+
+// Telegram parse mode of the rich messages; unlike legacy Markdown, every reserved character can be escaped
+const telegramParseMode = "MarkdownV2"
+
+// markdownV2Escaper escapes the characters MarkdownV2 reserves outside entities, the backslash first
+var markdownV2Escaper = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "~", `\~`, "`", "\\`",
+	">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// markdownV2CodeEscaper escapes the characters MarkdownV2 reserves inside code and pre blocks
+var markdownV2CodeEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+
+// EscapeMarkdownV2 escapes text for use as plain text, or inside bold and italic entities, of a MarkdownV2 message
+func EscapeMarkdownV2(text string) string {
+	return markdownV2Escaper.Replace(text)
+}
+
+// EscapeMarkdownV2Code escapes text for use inside a MarkdownV2 code or pre block
+func EscapeMarkdownV2Code(text string) string {
+	return markdownV2CodeEscaper.Replace(text)
+}
+
+// stripMarkdownV2 turns a MarkdownV2 message into plain text for channels without markup: escapes are resolved,
+// and bold, italic, strikethrough and code markers are dropped
+func stripMarkdownV2(text string) string {
+	text = strings.ReplaceAll(text, "```\n", "")
+	text = strings.ReplaceAll(text, "```", "")
+
+	var plain strings.Builder
+	escaped := false
+	for _, r := range text {
+		switch {
+		case escaped:
+			plain.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*' || r == '_' || r == '~' || r == '`':
+		default:
+			plain.WriteRune(r)
+		}
+	}
+	return plain.String()
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"stock-bot/models"
+)
+
+func TestEscapeMarkdownV2(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "AAPL", "AAPL"},
+		{"underscore", "BRK_B", `BRK\_B`},
+		{"index caret and dot", "^GSPC.", `^GSPC\.`},
+		{"share class dash", "BRK-B", `BRK\-B`},
+		{"bold marker", "2*3", `2\*3`},
+		{"link brackets", "[x](y)", `\[x\]\(y\)`},
+		{"price with change", "$210.50 (+1.20%)", `$210\.50 \(\+1\.20%\)`},
+		{"negative change", "-3.5%", `\-3\.5%`},
+		{"exclamation", "Up!", `Up\!`},
+		{"backslash", `a\b`, `a\\b`},
+		{"backtick", "a`b", "a\\`b"},
+		{"quote and pipe", "> a | b", `\> a \| b`},
+		{"heading and equals", "#1 = ~x~", `\#1 \= \~x\~`},
+		{"braces", "{x}", `\{x\}`},
+		{"emoji and unicode", "🟢 ₩3000 → €", "🟢 ₩3000 → €"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EscapeMarkdownV2(tt.in); got != tt.want {
+				t.Errorf("EscapeMarkdownV2(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeMarkdownV2Code(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"reserved outside code are kept", "BRK_B *1.5* [-]", "BRK_B *1.5* [-]"},
+		{"backtick", "a`b", "a\\`b"},
+		{"backslash", `a\b`, `a\\b`},
+		{"backslash before backtick", "\\`", "\\\\\\`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EscapeMarkdownV2Code(tt.in); got != tt.want {
+				t.Errorf("EscapeMarkdownV2Code(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTelegramReportTemplate(t *testing.T) {
+	got, err := mustLoadDefaultTemplates("telegram").RenderReport(map[string]models.Quote{
+		"BRK_B": {Symbol: "BRK_B", Price: 410.5, ChangePercent: -1.25},
+	})
+	if err != nil {
+		t.Fatalf("RenderReport() error = %v", err)
+	}
+	want := "📊 *Daily Stock Report*\n\n*BRK\\_B*: $410\\.50 \\(\\-1\\.25%\\)\n"
+	if got != want {
+		t.Errorf("RenderReport() = %q, want %q", got, want)
+	}
+}
+
+func TestTelegramAlertsTemplate(t *testing.T) {
+	templates := mustLoadDefaultTemplates("telegram")
+	alert := models.PriceAlert{
+		Symbol:        "^GSPC",
+		PreviousPrice: 5000,
+		CurrentPrice:  5150,
+		PercentChange: 3,
+		Headline:      "S&P 500 jumps [update]: *record* close!",
+	}
+
+	got, err := templates.RenderAlerts([]models.PriceAlert{alert}, true)
+	if err != nil {
+		t.Fatalf("RenderAlerts() error = %v", err)
+	}
+	for _, want := range []string{
+		"⚠️ *Significant Price Changes Detected*\n\n",
+		"*^GSPC*: 🟢 Increased by *3\\.00%*\n",
+		"📰 S&P 500 jumps \\[update\\]: \\*record\\* close\\!\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("RenderAlerts() = %q, want it to contain %q", got, want)
+		}
+	}
+
+	if brief, _ := templates.RenderAlerts([]models.PriceAlert{alert}, false); strings.Contains(brief, "📰") {
+		t.Errorf("RenderAlerts() without verbose = %q, want no headline", brief)
+	}
+}
+
+func TestStripMarkdownV2(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"bold and escapes", `*AAPL* $210\.50 \(\+1\.20%\)`, "AAPL $210.50 (+1.20%)"},
+		{"italic", `_closing prices_`, "closing prices"},
+		{"code block", "```\nDate  Close\n```", "Date  Close\n"},
+		{"escaped markers are kept", `BRK\_B 2\*3`, "BRK_B 2*3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripMarkdownV2(tt.in); got != tt.want {
+				t.Errorf("stripMarkdownV2(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	// Escaping and stripping gives the original text back
+	for _, text := range []string{"BRK_B", "^GSPC.", "[x](y) = ~x~ | #1!", `a\b`, "a`b"} {
+		if got := stripMarkdownV2(EscapeMarkdownV2(text)); got != text {
+			t.Errorf("stripMarkdownV2(EscapeMarkdownV2(%q)) = %q", text, got)
+		}
+	}
+}
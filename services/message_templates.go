@@ -0,0 +1,143 @@
+package services
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"stock-bot/i18n"
+	"stock-bot/models"
+)
+
+// ErrTemplateLoad is returned when a message template can't be read or parsed
+var ErrTemplateLoad = errors.New("failed to load message template")
+
+// Template files of each channel, under templates/<channel>/ built in and under the configured directory;
+// translations are named with the language before the extension, e.g. report.ko.tmpl
+const (
+	reportTemplateName = "report"
+	alertsTemplateName = "alerts"
+)
+
+// defaultTemplates holds the built-in report and alert templates of each channel
+//
+//go:embed templates
+var defaultTemplates embed.FS
+
+// MessageTemplates renders the rich daily report and price alerts of one channel from text/template files
+type MessageTemplates struct {
+	report  *template.Template
+	alerts  *template.Template
+	printer *i18n.Printer // Labels outside the templates, such as alert buttons and plain-text columns, in the templates' language
+}
+
+// ReportData is what a report template is executed with; ranging over Quotes visits the symbols in order
+type ReportData struct {
+	Quotes map[string]models.Quote
+}
+
+// AlertsData is what an alerts template is executed with; Verbose asks for the volume and headline of each alert
+type AlertsData struct {
+	Alerts  []models.PriceAlert
+	Verbose bool
+}
+
+// LoadMessageTemplates returns the templates of a channel (telegram, line) in a language, taking report.tmpl and
+// alerts.tmpl, or their translation such as report.ko.tmpl, from dir/<channel>/ when present and the built-in
+// templates otherwise
+func LoadMessageTemplates(channel, dir string, language i18n.Language) (*MessageTemplates, error) {
+	funcs := templateFuncs(channel)
+
+	// A translation is preferred over the English template, and a custom template over a built-in one
+	files := func(name string) []string {
+		if language == "" || language == i18n.English {
+			return []string{name + ".tmpl"}
+		}
+		return []string{name + "." + string(language) + ".tmpl", name + ".tmpl"}
+	}
+
+	load := func(name string) (*template.Template, error) {
+		if dir != "" {
+			for _, file := range files(name) {
+				data, err := os.ReadFile(filepath.Join(dir, channel, file))
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				if err != nil {
+					return nil, fmt.Errorf("%w: %v", ErrTemplateLoad, err)
+				}
+				tmpl, err := template.New(file).Funcs(funcs).Parse(string(data))
+				if err != nil {
+					return nil, fmt.Errorf("%w: %v", ErrTemplateLoad, err)
+				}
+				return tmpl, nil
+			}
+		}
+
+		for _, file := range files(name) {
+			data, err := defaultTemplates.ReadFile("templates/" + channel + "/" + file)
+			if err != nil {
+				continue
+			}
+			return template.New(file).Funcs(funcs).Parse(string(data))
+		}
+		return nil, fmt.Errorf("%w: no %s template for channel %q", ErrTemplateLoad, name, channel)
+	}
+
+	report, err := load(reportTemplateName)
+	if err != nil {
+		return nil, err
+	}
+	alerts, err := load(alertsTemplateName)
+	if err != nil {
+		return nil, err
+	}
+	return &MessageTemplates{report: report, alerts: alerts, printer: i18n.NewPrinter(language)}, nil
+}
+
+// mustLoadDefaultTemplates returns the built-in templates of a channel, which are known to parse
+func mustLoadDefaultTemplates(channel string) *MessageTemplates {
+	templates, err := LoadMessageTemplates(channel, "", i18n.English)
+	if err != nil {
+		panic(err)
+	}
+	return templates
+}
+
+// templateFuncs returns the functions available to templates; escape makes text safe for the channel's markup
+func templateFuncs(channel string) template.FuncMap {
+	escape := func(text string) string { return text }
+	if channel == "telegram" {
+		escape = EscapeMarkdownV2
+	}
+
+	return template.FuncMap{
+		"escape": escape,
+		"price":  models.FormatPrice,
+		"quote":  models.FormatQuote,
+		"volume": formatVolume,
+		"upper":  strings.ToUpper,
+	}
+}
+
+// RenderReport renders the daily report
+func (mt *MessageTemplates) RenderReport(quotes map[string]models.Quote) (string, error) {
+	var message strings.Builder
+	if err := mt.report.Execute(&message, ReportData{Quotes: quotes}); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+	return message.String(), nil
+}
+
+// RenderAlerts renders price alerts
+func (mt *MessageTemplates) RenderAlerts(alerts []models.PriceAlert, verbose bool) (string, error) {
+	var message strings.Builder
+	if err := mt.alerts.Execute(&message, AlertsData{Alerts: alerts, Verbose: verbose}); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+	return message.String(), nil
+}
@@ -5,9 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
-	"strings"
+	"strconv"
 	"sync"
 	"time"
 
@@ -22,17 +23,67 @@ var (
 	ErrChatIDNotSet       = errors.New("chat ID not set")
 	ErrMessagePreparation = errors.New("failed to prepare message")
 	ErrMessageSending     = errors.New("failed to send message")
+	ErrMessageRejected    = errors.New("message rejected")
 )
 
+// StatusError is a messaging API response with an error status, with the wait the API asked for before retrying
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+// newStatusError reads the status and the Retry-After header, given in seconds or as a date, of a failed response;
+// without the header the wait is taken from a Telegram-style JSON body's parameters.retry_after
+func newStatusError(resp *http.Response) *StatusError {
+	statusErr := &StatusError{StatusCode: resp.StatusCode}
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			statusErr.RetryAfter = time.Duration(seconds) * time.Second
+		} else if at, err := http.ParseTime(value); err == nil {
+			statusErr.RetryAfter = time.Until(at)
+		}
+		return statusErr
+	}
+
+	var body struct {
+		Parameters struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&body); err == nil && body.Parameters.RetryAfter > 0 {
+		statusErr.RetryAfter = time.Duration(body.Parameters.RetryAfter) * time.Second
+	}
+	return statusErr
+}
+
+// Error describes the status
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("received status code %d", e.StatusCode)
+}
+
 // Messenger interface defines messaging services
 type Messenger interface {
-	SendMessage(prices map[string]string, wg *sync.WaitGroup) error
+	SendMessage(quotes map[string]models.Quote, wg *sync.WaitGroup) error
 	SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGroup) error
+	SendText(text string, wg *sync.WaitGroup) error
+}
+
+// ChatMessenger is implemented by messengers that can address individual chats
+type ChatMessenger interface {
+	ForChat(chatID string) Messenger
+}
+
+// AlertStyler is implemented by messengers whose alert verbosity can be chosen per recipient
+type AlertStyler interface {
+	WithAlertStyle(style models.AlertStyle) Messenger
 }
 
 // LineMessenger implements Line messaging service
 type LineMessenger struct {
-	token string
+	token      string
+	format     MessageFormat
+	alertStyle models.AlertStyle
+	templates  *MessageTemplates
 }
 
 // NewLineMessenger creates a new instance of LineMessenger
@@ -40,11 +91,26 @@ func NewLineMessenger(token string) (*LineMessenger, error) {
 	if token == "" {
 		return nil, ErrTokenNotSet
 	}
-	return &LineMessenger{token: token}, nil
+	return &LineMessenger{token: token, format: FormatRich, alertStyle: models.AlertStandard, templates: mustLoadDefaultTemplates("line")}, nil
+}
+
+// SetFormat selects how reports and alerts are rendered
+func (lm *LineMessenger) SetFormat(format MessageFormat) {
+	lm.format = format
+}
+
+// SetTemplates replaces the templates of the rich report and alerts
+func (lm *LineMessenger) SetTemplates(templates *MessageTemplates) {
+	lm.templates = templates
+}
+
+// SetAlertStyle selects how much detail alerts carry
+func (lm *LineMessenger) SetAlertStyle(style models.AlertStyle) {
+	lm.alertStyle = style
 }
 
 // SendMessage sends stock price information via Line
-func (lm *LineMessenger) SendMessage(prices map[string]string, wg *sync.WaitGroup) error {
+func (lm *LineMessenger) SendMessage(quotes map[string]models.Quote, wg *sync.WaitGroup) error {
 	if wg != nil {
 		defer wg.Done()
 	}
@@ -53,95 +119,91 @@ func (lm *LineMessenger) SendMessage(prices map[string]string, wg *sync.WaitGrou
 		return ErrTokenNotSet
 	}
 
-	retryKey := uuid.NewString()
-	var message strings.Builder
-	message.WriteString("📊 Daily Stock Report\n\n")
+	if lm.format == FormatPlain {
+		return lm.broadcast(formatPlainReport(quotes, lm.templates.printer), "push")
+	}
 
-	for symbol, price := range prices {
-		message.WriteString(fmt.Sprintf("%s: %s\n", symbol, price))
+	message, err := lm.templates.RenderReport(quotes)
+	if err != nil {
+		return err
+	}
+	if lm.format == FormatFlex {
+		flex, err := buildFlexReport(firstLine(message), quotes)
+		return lm.broadcastFlex(message, flex, err, "push")
 	}
+	return lm.broadcast(message, "push")
+}
 
-	payload := map[string]interface{}{
-		"messages": []map[string]string{
-			{
-				"type": "text",
-				"text": message.String(),
-			},
-		},
+// SendAlerts sends stock price change alerts via Line
+func (lm *LineMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
 	}
 
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	if len(alerts) == 0 {
+		return nil
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	req, err := http.NewRequest("POST", "https://api.line.me/v2/bot/message/broadcast", bytes.NewBuffer(jsonPayload))
-	if err != nil {
-		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	if lm.token == "" {
+		return ErrTokenNotSet
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", lm.token))
-	req.Header.Set("X-Line-Retry-Key", retryKey)
+	if lm.alertStyle == models.AlertCompact {
+		return lm.broadcast(formatCompactAlerts(alerts), "alert push")
+	}
+	if lm.format == FormatPlain {
+		return lm.broadcast(formatPlainAlerts(alerts, lm.alertStyle == models.AlertVerbose, lm.templates.printer), "alert push")
+	}
 
-	resp, err := client.Do(req)
+	message, err := lm.templates.RenderAlerts(alerts, lm.alertStyle == models.AlertVerbose)
 	if err != nil {
-		return fmt.Errorf("%w: %v", ErrMessageSending, err)
+		return err
 	}
-	defer resp.Body.Close()
-
-	log.Printf("LINE Bot push response: %s", resp.Status)
-
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("%w: received status code %d", ErrMessageSending, resp.StatusCode)
+	if lm.format == FormatFlex {
+		flex, err := buildFlexAlerts(firstLine(message), alerts, lm.alertStyle == models.AlertVerbose)
+		return lm.broadcastFlex(message, flex, err, "alert push")
 	}
-
-	return nil
+	return lm.broadcast(message, "alert push")
 }
 
-// SendAlerts sends stock price change alerts via Line
-func (lm *LineMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGroup) error {
+// SendText broadcasts an arbitrary text message via Line
+func (lm *LineMessenger) SendText(text string, wg *sync.WaitGroup) error {
 	if wg != nil {
 		defer wg.Done()
 	}
 
-	if len(alerts) == 0 {
-		return nil
-	}
-
 	if lm.token == "" {
 		return ErrTokenNotSet
 	}
 
-	retryKey := uuid.NewString()
-	var message strings.Builder
-	message.WriteString("⚠️ Significant Price Changes Detected\n\n")
+	return lm.broadcast(text, "text push")
+}
 
-	for _, alert := range alerts {
-		direction := "🔴 Decreased"
-		if alert.PercentChange > 0 {
-			direction = "🟢 Increased"
+// broadcastFlex sends a Flex Message to all Line followers, with the text as its notification preview; the text
+// is sent instead when the Flex Message couldn't be built or Line rejected it
+func (lm *LineMessenger) broadcastFlex(text string, flex flexComponent, buildErr error, label string) error {
+	if buildErr == nil {
+		err := lm.broadcastMessages([]any{flexMessage(text, flex)}, "flex "+label)
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadRequest {
+			return err
 		}
-
-		message.WriteString(fmt.Sprintf("%s: %s by %.2f%%\n",
-			alert.Symbol,
-			direction,
-			alert.PercentChange,
-		))
-		message.WriteString(fmt.Sprintf("Previous: $%.2f → Current: $%.2f\n\n",
-			alert.PreviousPrice,
-			alert.CurrentPrice,
-		))
+		buildErr = err
 	}
+	slog.Warn("Line Flex Message failed, sending text instead", "kind", label, "error", buildErr)
+	return lm.broadcast(text, label)
+}
 
+// broadcast sends a text message to all Line followers
+func (lm *LineMessenger) broadcast(text, label string) error {
+	return lm.broadcastMessages([]any{map[string]string{"type": "text", "text": text}}, label)
+}
+
+// broadcastMessages sends messages to all Line followers
+func (lm *LineMessenger) broadcastMessages(messages []any, label string) error {
+	retryKey := uuid.NewString()
 	payload := map[string]interface{}{
-		"messages": []map[string]string{
-			{
-				"type": "text",
-				"text": message.String(),
-			},
-		},
+		"messages": messages,
 	}
 
 	jsonPayload, err := json.Marshal(payload)
@@ -165,10 +227,10 @@ func (lm *LineMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGro
 	}
 	defer resp.Body.Close()
 
-	log.Printf("LINE Bot alert push response: %s", resp.Status)
+	slog.Debug("LINE Bot response", "kind", label, "status", resp.Status)
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("%w: received status code %d", ErrMessageSending, resp.StatusCode)
+		return fmt.Errorf("%w: %w", ErrMessageSending, newStatusError(resp))
 	}
 
 	return nil
@@ -176,8 +238,11 @@ func (lm *LineMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGro
 
 // TelegramMessenger implements Telegram messaging service
 type TelegramMessenger struct {
-	token  string
-	chatID string
+	token      string
+	chatID     string
+	format     MessageFormat
+	alertStyle models.AlertStyle
+	templates  *MessageTemplates
 }
 
 // NewTelegramMessenger creates a new instance of TelegramMessenger
@@ -188,11 +253,36 @@ func NewTelegramMessenger(token, chatID string) (*TelegramMessenger, error) {
 	if chatID == "" {
 		return nil, ErrChatIDNotSet
 	}
-	return &TelegramMessenger{token: token, chatID: chatID}, nil
+	return &TelegramMessenger{token: token, chatID: chatID, format: FormatRich, alertStyle: models.AlertStandard, templates: mustLoadDefaultTemplates("telegram")}, nil
+}
+
+// SetFormat selects how reports and alerts are rendered
+func (tm *TelegramMessenger) SetFormat(format MessageFormat) {
+	tm.format = format
+}
+
+// SetTemplates replaces the templates of the rich report and alerts; they render MarkdownV2
+func (tm *TelegramMessenger) SetTemplates(templates *MessageTemplates) {
+	tm.templates = templates
+}
+
+// SetAlertStyle selects how much detail alerts carry by default
+func (tm *TelegramMessenger) SetAlertStyle(style models.AlertStyle) {
+	tm.alertStyle = style
+}
+
+// ForChat returns a TelegramMessenger that sends to another chat with the same bot
+func (tm *TelegramMessenger) ForChat(chatID string) Messenger {
+	return &TelegramMessenger{token: tm.token, chatID: chatID, format: tm.format, alertStyle: tm.alertStyle, templates: tm.templates}
+}
+
+// WithAlertStyle returns a TelegramMessenger for the same chat with a different alert style
+func (tm *TelegramMessenger) WithAlertStyle(style models.AlertStyle) Messenger {
+	return &TelegramMessenger{token: tm.token, chatID: tm.chatID, format: tm.format, alertStyle: style, templates: tm.templates}
 }
 
 // SendMessage sends stock price information via Telegram
-func (tm *TelegramMessenger) SendMessage(prices map[string]string, wg *sync.WaitGroup) error {
+func (tm *TelegramMessenger) SendMessage(quotes map[string]models.Quote, wg *sync.WaitGroup) error {
 	if wg != nil {
 		defer wg.Done()
 	}
@@ -204,14 +294,15 @@ func (tm *TelegramMessenger) SendMessage(prices map[string]string, wg *sync.Wait
 		return ErrChatIDNotSet
 	}
 
-	var message strings.Builder
-	message.WriteString("📊 *Daily Stock Report*\n\n")
-
-	for symbol, price := range prices {
-		message.WriteString(fmt.Sprintf("*%s*: %s\n", symbol, price))
+	if tm.format == FormatPlain {
+		return tm.sendTelegramMessage(formatPlainReport(quotes, tm.templates.printer), "", nil)
 	}
 
-	return tm.sendTelegramMessage(message.String())
+	message, err := tm.templates.RenderReport(quotes)
+	if err != nil {
+		return err
+	}
+	return tm.sendTelegramMessage(message, telegramParseMode, nil)
 }
 
 // SendAlerts sends stock price change alerts via Telegram
@@ -231,35 +322,49 @@ func (tm *TelegramMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.Wai
 		return ErrChatIDNotSet
 	}
 
-	var message strings.Builder
-	message.WriteString("⚠️ *Significant Price Changes Detected*\n\n")
+	keyboard := alertKeyboard(alerts, tm.templates.printer)
+	if tm.alertStyle == models.AlertCompact {
+		return tm.sendTelegramMessage(formatCompactAlerts(alerts), "", keyboard)
+	}
+	if tm.format == FormatPlain {
+		return tm.sendTelegramMessage(formatPlainAlerts(alerts, tm.alertStyle == models.AlertVerbose, tm.templates.printer), "", keyboard)
+	}
 
-	for _, alert := range alerts {
-		direction := "🔴 Decreased"
-		if alert.PercentChange > 0 {
-			direction = "🟢 Increased"
-		}
+	message, err := tm.templates.RenderAlerts(alerts, tm.alertStyle == models.AlertVerbose)
+	if err != nil {
+		return err
+	}
+	return tm.sendTelegramMessage(message, telegramParseMode, keyboard)
+}
+
+// SendText sends an arbitrary plain text message via Telegram
+func (tm *TelegramMessenger) SendText(text string, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
 
-		message.WriteString(fmt.Sprintf("*%s*: %s by *%.2f%%*\n",
-			alert.Symbol,
-			direction,
-			alert.PercentChange,
-		))
-		message.WriteString(fmt.Sprintf("  Previous: $%.2f → Current: $%.2f\n\n",
-			alert.PreviousPrice,
-			alert.CurrentPrice,
-		))
+	if tm.token == "" {
+		return ErrTokenNotSet
+	}
+	if tm.chatID == "" {
+		return ErrChatIDNotSet
 	}
 
-	return tm.sendTelegramMessage(message.String())
+	return tm.sendTelegramMessage(text, "", nil)
 }
 
-// sendTelegramMessage handles sending messages to Telegram
-func (tm *TelegramMessenger) sendTelegramMessage(message string) error {
-	payload := map[string]string{
-		"chat_id":    tm.chatID,
-		"text":       message,
-		"parse_mode": "Markdown",
+// sendTelegramMessage handles sending messages to Telegram; an empty parseMode sends plain text, and a keyboard
+// adds buttons below the message
+func (tm *TelegramMessenger) sendTelegramMessage(message, parseMode string, keyboard *inlineKeyboard) error {
+	payload := map[string]any{
+		"chat_id": tm.chatID,
+		"text":    message,
+	}
+	if parseMode != "" {
+		payload["parse_mode"] = parseMode
+	}
+	if keyboard != nil {
+		payload["reply_markup"] = keyboard
 	}
 
 	jsonPayload, err := json.Marshal(payload)
@@ -281,10 +386,10 @@ func (tm *TelegramMessenger) sendTelegramMessage(message string) error {
 	}
 	defer resp.Body.Close()
 
-	log.Printf("Telegram Bot push response: %s", resp.Status)
+	slog.Debug("Telegram Bot push response", "status", resp.Status)
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("%w: received status code %d", ErrMessageSending, resp.StatusCode)
+		return fmt.Errorf("%w: %w", ErrMessageSending, newStatusError(resp))
 	}
 
 	return nil
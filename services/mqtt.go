@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Error definitions for MQTT publishing
+var (
+	ErrMQTTConnection = errors.New("failed to connect to MQTT broker")
+)
+
+// mqttTimeout bounds connecting and waiting for publish acknowledgements
+const mqttTimeout = 10 * time.Second
+
+// MQTTPublisher publishes quotes, alerts and reports to an MQTT broker
+type MQTTPublisher struct {
+	client          mqtt.Client
+	qos             byte
+	prefix          string
+	discoveryPrefix string
+}
+
+// NewMQTTPublisher connects to the broker and creates a new MQTTPublisher
+func NewMQTTPublisher(config models.MQTTConfig) (*MQTTPublisher, error) {
+	if config.BrokerURL == "" {
+		return nil, fmt.Errorf("%w: broker URL not set", ErrMQTTConnection)
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(config.BrokerURL).
+		SetClientID(fmt.Sprintf("stock-bot-%d", time.Now().UnixNano())).
+		SetUsername(config.Username).
+		SetPassword(config.Password).
+		SetAutoReconnect(true).
+		SetConnectTimeout(mqttTimeout)
+
+	client := mqtt.NewClient(opts)
+	token := client.Connect()
+	if !token.WaitTimeout(mqttTimeout) {
+		return nil, fmt.Errorf("%w: connection timed out", ErrMQTTConnection)
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMQTTConnection, err)
+	}
+
+	return &MQTTPublisher{
+		client:          client,
+		qos:             config.QoS,
+		prefix:          config.TopicPrefix,
+		discoveryPrefix: config.DiscoveryPrefix,
+	}, nil
+}
+
+// Name returns the integration name
+func (mp *MQTTPublisher) Name() string {
+	return "mqtt"
+}
+
+// Publish sends the event JSON to its topic, e.g. stockbot/quotes/AAPL or stockbot/alerts/TSLA
+func (mp *MQTTPublisher) Publish(ctx context.Context, event models.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err)
+	}
+
+	// Latest quotes are retained so dashboards show a value as soon as they subscribe
+	var topic string
+	retained := false
+	switch event.Type {
+	case models.EventQuote:
+		topic = fmt.Sprintf("%s/quotes/%s", mp.prefix, event.Symbol)
+		retained = true
+	case models.EventAlert:
+		topic = fmt.Sprintf("%s/alerts/%s", mp.prefix, event.Symbol)
+	default:
+		topic = fmt.Sprintf("%s/%s", mp.prefix, event.Type)
+	}
+
+	token := mp.client.Publish(topic, mp.qos, retained, payload)
+	select {
+	case <-token.Done():
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %v", ErrPublishFailed, ctx.Err())
+	case <-time.After(mqttTimeout):
+		return fmt.Errorf("%w: %s publish timed out", ErrPublishFailed, topic)
+	}
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err)
+	}
+	return nil
+}
+
+// haSensorConfig is a Home Assistant MQTT discovery payload for a sensor entity
+type haSensorConfig struct {
+	Name                string   `json:"name"`
+	UniqueID            string   `json:"unique_id"`
+	StateTopic          string   `json:"state_topic"`
+	ValueTemplate       string   `json:"value_template"`
+	JSONAttributesTopic string   `json:"json_attributes_topic,omitempty"`
+	UnitOfMeasurement   string   `json:"unit_of_measurement"`
+	StateClass          string   `json:"state_class"`
+	Icon                string   `json:"icon"`
+	Device              haDevice `json:"device"`
+}
+
+// haDevice groups the sensors of one symbol in Home Assistant
+type haDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+}
+
+// PublishDiscovery announces a price and a change sensor per symbol through Home Assistant MQTT discovery
+func (mp *MQTTPublisher) PublishDiscovery(symbols []string) error {
+	var errs []error
+	for _, symbol := range symbols {
+		id := "stockbot_" + strings.ToLower(strings.NewReplacer(".", "_", "-", "_", "^", "", "=", "_").Replace(symbol))
+		stateTopic := fmt.Sprintf("%s/quotes/%s", mp.prefix, symbol)
+		device := haDevice{Identifiers: []string{id}, Name: symbol, Manufacturer: "Stock Bot"}
+
+		sensors := map[string]haSensorConfig{
+			"price": {
+				Name:                "Price",
+				UniqueID:            id + "_price",
+				StateTopic:          stateTopic,
+				ValueTemplate:       "{{ value_json.currentPrice }}",
+				JSONAttributesTopic: stateTopic,
+				UnitOfMeasurement:   models.CurrencyFor(symbol),
+				StateClass:          "measurement",
+				Icon:                "mdi:finance",
+				Device:              device,
+			},
+			"change": {
+				Name:              "Change",
+				UniqueID:          id + "_change",
+				StateTopic:        stateTopic,
+				ValueTemplate:     "{{ value_json.percentChange | default(0) | round(2) }}",
+				UnitOfMeasurement: "%",
+				StateClass:        "measurement",
+				Icon:              "mdi:swap-vertical",
+				Device:            device,
+			},
+		}
+
+		for object, sensor := range sensors {
+			payload, err := json.Marshal(sensor)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			// Discovery configs are retained so Home Assistant picks them up after restarts
+			topic := fmt.Sprintf("%s/sensor/%s/%s/config", mp.discoveryPrefix, id, object)
+			token := mp.client.Publish(topic, mp.qos, true, payload)
+			if !token.WaitTimeout(mqttTimeout) {
+				errs = append(errs, fmt.Errorf("%w: %s publish timed out", ErrPublishFailed, topic))
+				continue
+			}
+			if err := token.Error(); err != nil {
+				errs = append(errs, fmt.Errorf("%w: %v", ErrPublishFailed, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close disconnects from the broker
+func (mp *MQTTPublisher) Close() {
+	mp.client.Disconnect(250)
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+)
+
+// PreviousCloser looks up the stored close of the session before the one a time falls in
+type PreviousCloser interface {
+	PreviousClose(symbol string, t time.Time) (float64, error)
+}
+
+// MultiSource tries its price sources in order for each symbol; each quote names the source that answered
+type MultiSource struct {
+	sources        []PriceSource
+	cache          QuoteCache
+	previousCloses PreviousCloser
+}
+
+// NewMultiSource creates a fallback chain over the given sources, tried in the order given
+func NewMultiSource(sources ...PriceSource) *MultiSource {
+	return &MultiSource{sources: sources}
+}
+
+// SetCache keeps every fetched quote in the cache, for FetchCached to serve within the cache's lifetime
+func (ms *MultiSource) SetCache(cache QuoteCache) {
+	ms.cache = cache
+}
+
+// SetPreviousCloses fills in the change of quotes whose source reports none, such as Google Finance pages, from
+// the stored previous close
+func (ms *MultiSource) SetPreviousCloses(closes PreviousCloser) {
+	ms.previousCloses = closes
+}
+
+// fillChange derives a quote's change from the stored previous close when its source left it out
+func (ms *MultiSource) fillChange(symbol string, quote *models.Quote) {
+	if ms.previousCloses == nil || quote.Change != 0 || quote.ChangePercent != 0 {
+		return
+	}
+	previousClose, err := ms.previousCloses.PreviousClose(symbol, quote.Timestamp)
+	if err != nil || previousClose == 0 {
+		return
+	}
+	quote.Change = quote.Price - previousClose
+	quote.ChangePercent = quote.Change / previousClose * 100
+}
+
+// Name lists the chained sources, e.g. "yahoo-api>chromedp"
+func (ms *MultiSource) Name() string {
+	names := make([]string, 0, len(ms.sources))
+	for _, source := range ms.sources {
+		names = append(names, source.Name())
+	}
+	return strings.Join(names, ">")
+}
+
+// FetchCached returns a quote fetched within the cache's lifetime, or fetches one; for chat commands, where a quote
+// a few minutes old will do, while closing prices and alerts use Fetch
+func (ms *MultiSource) FetchCached(ctx context.Context, symbol string) (models.Quote, error) {
+	if ms.cache != nil {
+		if quote, ok := ms.cache.Get(symbol); ok {
+			slog.Debug("Serving cached quote", "symbol", symbol, "source", quote.Source)
+			return quote, nil
+		}
+	}
+	return ms.Fetch(ctx, symbol)
+}
+
+// Fetch returns the quote of the first source that succeeds, with its Source set to that source's name
+func (ms *MultiSource) Fetch(ctx context.Context, symbol string) (models.Quote, error) {
+	if len(ms.sources) == 0 {
+		return models.Quote{}, fmt.Errorf("%w: no price sources configured", ErrPriceFetchFailed)
+	}
+
+	err := fmt.Errorf("%w: no price source covers %s", ErrPriceFetchFailed, symbol)
+	for _, source := range ms.sources {
+		// Sources limited to other asset classes are skipped without a request or a warning
+		if filter, ok := source.(SymbolFilter); ok && !filter.Supports(symbol) {
+			continue
+		}
+
+		start := time.Now()
+		var quote models.Quote
+		quote, err = source.Fetch(ctx, symbol)
+		if err == nil {
+			quote.Source = source.Name()
+			ms.fillChange(symbol, &quote)
+			if ms.cache != nil {
+				ms.cache.Set(quote)
+			}
+			return quote, nil
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+		slog.Warn("Error fetching price from source", "symbol", symbol, "source", source.Name(), "duration", time.Since(start), "error", err)
+	}
+
+	// The last source's error is the most telling, as it is usually the most thorough one
+	return models.Quote{}, err
+}
+
+// FetchConcurrent fetches prices for multiple symbols concurrently; once ctx ends no further symbols are started, and
+// the results of the ones already started are returned with ctx's error
+func (ms *MultiSource) FetchConcurrent(ctx context.Context, tickers []string, maxConcurrency int) (map[string]models.PriceResult, error) {
+	// Semaphore to limit concurrency
+	sem := make(chan struct{}, maxConcurrency)
+
+	// Results channel
+	results := make(chan models.PriceResult, len(tickers))
+
+	// waitgroup
+	var wg sync.WaitGroup
+
+	// Start goroutine for each ticker; the semaphore is acquired before launching
+	// so tickers start in the order given
+	var cancelled error
+launch:
+	for _, ticker := range tickers {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			cancelled = ctx.Err()
+			break launch
+		}
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			quote, err := ms.Fetch(ctx, symbol)
+			results <- models.PriceResult{Symbol: symbol, Quote: quote, Error: err}
+		}(ticker)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Collect all results
+	priceMap := make(map[string]models.PriceResult)
+	for result := range results {
+		priceMap[result.Symbol] = result
+	}
+
+	return priceMap, cancelled
+}
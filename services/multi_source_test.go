@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"stock-bot/models"
+)
+
+// countingSource quotes every symbol at a fixed price and counts its requests
+type countingSource struct {
+	fetches int
+}
+
+func (s *countingSource) Name() string {
+	return "counting"
+}
+
+func (s *countingSource) Fetch(_ context.Context, symbol string) (models.Quote, error) {
+	s.fetches++
+	return models.Quote{Symbol: symbol, Price: 100, Timestamp: time.Now()}, nil
+}
+
+func TestMultiSourceCache(t *testing.T) {
+	tests := []struct {
+		name        string
+		fetch       func(ms *MultiSource) (models.Quote, error)
+		wantFetches int
+	}{
+		{"Fetch always asks the sources", func(ms *MultiSource) (models.Quote, error) { return ms.Fetch(context.Background(), "AAPL") }, 3},
+		{"FetchCached reuses the cached quote", func(ms *MultiSource) (models.Quote, error) { return ms.FetchCached(context.Background(), "AAPL") }, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &countingSource{}
+			ms := NewMultiSource(source)
+			ms.SetCache(NewMemoryQuoteCache(time.Minute))
+
+			for i := 0; i < 3; i++ {
+				quote, err := tt.fetch(ms)
+				if err != nil || quote.Price != 100 {
+					t.Fatalf("fetch = %+v, %v", quote, err)
+				}
+			}
+			if source.fetches != tt.wantFetches {
+				t.Errorf("source fetched %d times, want %d", source.fetches, tt.wantFetches)
+			}
+		})
+	}
+}
+
+// staticCloses returns a fixed previous close, or ErrNoClosingPriceFound when it is zero
+type staticCloses float64
+
+func (c staticCloses) PreviousClose(symbol string, _ time.Time) (float64, error) {
+	if c == 0 {
+		return 0, ErrNoClosingPriceFound
+	}
+	return float64(c), nil
+}
+
+// changeSource quotes every symbol at 100 with a fixed change
+type changeSource float64
+
+func (s changeSource) Name() string {
+	return "change"
+}
+
+func (s changeSource) Fetch(_ context.Context, symbol string) (models.Quote, error) {
+	return models.Quote{Symbol: symbol, Price: 100, Change: float64(s), ChangePercent: float64(s), Timestamp: time.Now()}, nil
+}
+
+func TestMultiSourceFillsChange(t *testing.T) {
+	tests := []struct {
+		name          string
+		change        float64
+		previousClose staticCloses
+		wantChange    float64
+		wantPercent   float64
+	}{
+		{"derived from the previous close", 0, 80, 20, 25},
+		{"reported change kept", 5, 80, 5, 5},
+		{"no stored close", 0, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms := NewMultiSource(changeSource(tt.change))
+			ms.SetPreviousCloses(tt.previousClose)
+
+			quote, err := ms.Fetch(context.Background(), "AAPL")
+			if err != nil {
+				t.Fatalf("Fetch: %v", err)
+			}
+			if quote.Change != tt.wantChange || quote.ChangePercent != tt.wantPercent {
+				t.Errorf("change = %v (%v%%), want %v (%v%%)", quote.Change, quote.ChangePercent, tt.wantChange, tt.wantPercent)
+			}
+		})
+	}
+}
+
+// blockingSource ignores cancellation and holds every fetch until release is closed
+type blockingSource struct {
+	mu      sync.Mutex
+	started []string
+	first   chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSource) Name() string {
+	return "blocking"
+}
+
+func (s *blockingSource) Fetch(_ context.Context, symbol string) (models.Quote, error) {
+	s.mu.Lock()
+	s.started = append(s.started, symbol)
+	if len(s.started) == 1 {
+		close(s.first)
+	}
+	s.mu.Unlock()
+	<-s.release
+	return models.Quote{Symbol: symbol, Price: 100, Timestamp: time.Now()}, nil
+}
+
+func TestFetchConcurrentStopsStartingOnCancel(t *testing.T) {
+	source := &blockingSource{first: make(chan struct{}), release: make(chan struct{})}
+	ms := NewMultiSource(source)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	type outcome struct {
+		results map[string]models.PriceResult
+		err     error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		results, err := ms.FetchConcurrent(ctx, []string{"AAPL", "MSFT", "NVDA"}, 1)
+		done <- outcome{results, err}
+	}()
+
+	<-source.first
+	cancel()
+	close(source.release)
+
+	select {
+	case got := <-done:
+		if !errors.Is(got.err, context.Canceled) {
+			t.Errorf("error = %v, want context.Canceled", got.err)
+		}
+		if _, ok := got.results["AAPL"]; !ok || len(got.results) != 1 {
+			t.Errorf("results = %v, want only the fetch already started", got.results)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("FetchConcurrent outlived its context")
+	}
+	if len(source.started) != 1 {
+		t.Errorf("started fetches for %v after the context ended", source.started)
+	}
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Outbox retry settings
+const (
+	outboxLease       = 2 * time.Minute    // How long a message being sent is left alone by sweeps
+	outboxMaxAge      = 24 * time.Hour     // Undelivered messages older than this are no longer retried
+	outboxAlertMaxAge = 30 * time.Minute   // Undelivered alerts older than this are dropped, as mutes set since aren't checked
+	outboxMaxAttempts = 10                 // Failed sends after which a message is no longer retried
+	outboxRetention   = 7 * 24 * time.Hour // How long messages are kept for inspection
+)
+
+// ErrAlertExpired is recorded for undelivered alerts dropped from the outbox because they are too old to resend
+var ErrAlertExpired = errors.New("alert too old to resend")
+
+// OutboxStore persists outgoing messages until they are delivered
+type OutboxStore interface {
+	// Add records a message about to be sent
+	Add(ctx context.Context, message models.OutboxMessage) error
+	// MarkDelivered records that a message was sent
+	MarkDelivered(ctx context.Context, id string) error
+	// MarkFailed records a failed send, making the message due for the next sweep
+	MarkFailed(ctx context.Context, id string, sendErr error) error
+	// MarkDead records that a message won't be retried, and why
+	MarkDead(ctx context.Context, id string, reason error) error
+	// ClaimPending locks and returns the undelivered messages that are due for a retry
+	ClaimPending(ctx context.Context, now time.Time) ([]models.OutboxMessage, error)
+}
+
+// MongoOutbox keeps outgoing messages in the outbox collection, removed a week after they were created
+type MongoOutbox struct {
+	collection *mongo.Collection
+}
+
+// NewMongoOutbox creates the outbox collection indexes and returns the store
+func NewMongoOutbox(db *Database) (*MongoOutbox, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("outbox")
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "createdAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(int32(outboxRetention.Seconds()))},
+		{Keys: bson.D{{Key: "deliveredAt", Value: 1}, {Key: "lockedUntil", Value: 1}}},
+	}
+	if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	return &MongoOutbox{collection: collection}, nil
+}
+
+// Add inserts a message, locked for the send that follows
+func (o *MongoOutbox) Add(ctx context.Context, message models.OutboxMessage) error {
+	if _, err := o.collection.InsertOne(ctx, message); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// MarkDelivered sets the delivery time of a message
+func (o *MongoOutbox) MarkDelivered(ctx context.Context, id string) error {
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "deliveredAt", Value: time.Now()}}}}
+	if _, err := o.collection.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// MarkFailed counts a failed attempt and unlocks the message
+func (o *MongoOutbox) MarkFailed(ctx context.Context, id string, sendErr error) error {
+	update := bson.D{
+		{Key: "$inc", Value: bson.D{{Key: "attempts", Value: 1}}},
+		{Key: "$set", Value: bson.D{{Key: "lastError", Value: sendErr.Error()}, {Key: "lockedUntil", Value: time.Time{}}}},
+	}
+	if _, err := o.collection.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// MarkDead sets the time a message was given up on, keeping it for inspection
+func (o *MongoOutbox) MarkDead(ctx context.Context, id string, reason error) error {
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "deadAt", Value: time.Now()},
+		{Key: "lastError", Value: reason.Error()},
+		{Key: "lockedUntil", Value: time.Time{}},
+	}}}
+	if _, err := o.collection.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// ClaimPending locks the undelivered, unlocked messages young enough to retry, oldest first; each one is
+// claimed on its own so instances sweeping at the same time don't send it twice
+func (o *MongoOutbox) ClaimPending(ctx context.Context, now time.Time) ([]models.OutboxMessage, error) {
+	filter := bson.D{
+		{Key: "deliveredAt", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: "deadAt", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: "lockedUntil", Value: bson.D{{Key: "$lt", Value: now}}},
+		{Key: "createdAt", Value: bson.D{{Key: "$gte", Value: now.Add(-outboxMaxAge)}}},
+		{Key: "attempts", Value: bson.D{{Key: "$lt", Value: outboxMaxAttempts}}},
+	}
+	cursor, err := o.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	var pending []models.OutboxMessage
+	if err := cursor.All(ctx, &pending); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	claimed := pending[:0]
+	for _, message := range pending {
+		claim := bson.D{{Key: "_id", Value: message.ID}, {Key: "lockedUntil", Value: message.LockedUntil}}
+		update := bson.D{{Key: "$set", Value: bson.D{{Key: "lockedUntil", Value: now.Add(outboxLease)}}}}
+		result, err := o.collection.UpdateOne(ctx, claim, update)
+		if err != nil {
+			return claimed, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+		}
+		if result.ModifiedCount == 1 {
+			claimed = append(claimed, message)
+		}
+	}
+	return claimed, nil
+}
+
+// OutboxSkipper is implemented by messengers whose sends are never persisted for a later retry
+type OutboxSkipper interface {
+	SkipsOutbox() bool
+}
+
+// outboxMessenger persists every message before sending it and marks it delivered afterwards
+type outboxMessenger struct {
+	inner      Messenger
+	store      OutboxStore
+	channel    string
+	chatID     string
+	alertStyle models.AlertStyle
+}
+
+// SendMessage records and sends the daily report
+func (om *outboxMessenger) SendMessage(quotes map[string]models.Quote, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return om.record(models.OutboxReport, quotes, func() error {
+		return om.inner.SendMessage(quotes, nil)
+	})
+}
+
+// SendAlerts records and sends price alerts
+func (om *outboxMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	if len(alerts) == 0 {
+		return om.inner.SendAlerts(alerts, nil)
+	}
+	return om.record(models.OutboxAlerts, alerts, func() error {
+		return om.inner.SendAlerts(alerts, nil)
+	})
+}
+
+// SendText records and sends a text message
+func (om *outboxMessenger) SendText(text string, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return om.record(models.OutboxText, text, func() error {
+		return om.inner.SendText(text, nil)
+	})
+}
+
+// record persists the message, sends it and records the outcome; a message that can't be persisted is still sent
+func (om *outboxMessenger) record(method string, payload any, send func() error) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+
+	now := time.Now()
+	message := models.OutboxMessage{
+		ID:          uuid.NewString(),
+		Channel:     om.channel,
+		ChatID:      om.chatID,
+		AlertStyle:  om.alertStyle,
+		Method:      method,
+		Payload:     string(data),
+		CreatedAt:   now,
+		LockedUntil: now.Add(outboxLease),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := om.store.Add(ctx, message); err != nil {
+		slog.Error("Error saving message to outbox, sending anyway", "error", err)
+		return send()
+	}
+
+	sendErr := send()
+
+	resultCtx, resultCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer resultCancel()
+	if sendErr != nil {
+		if err := markUndelivered(resultCtx, om.store, message.ID, sendErr); err != nil {
+			slog.Error("Error recording failed send in outbox", "error", err)
+		}
+		return sendErr
+	}
+	if err := om.store.MarkDelivered(resultCtx, message.ID); err != nil {
+		slog.Error("Error marking outbox message delivered", "error", err)
+	}
+	return nil
+}
+
+// markUndelivered records a failed send: a message the service rejected, e.g. for bad markup or a blocked bot, or
+// one that can't be rebuilt is given up on, anything else is retried by a later sweep
+func markUndelivered(ctx context.Context, store OutboxStore, id string, sendErr error) error {
+	var statusErr *StatusError
+	permanent := errors.Is(sendErr, ErrMessagePreparation)
+	if errors.As(sendErr, &statusErr) {
+		_, transient := retryDelay(sendErr, 0)
+		permanent = !transient
+	}
+
+	if permanent {
+		slog.Warn("Message rejected, not retrying", "id", id, "error", sendErr)
+		return store.MarkDead(ctx, id, sendErr)
+	}
+	return store.MarkFailed(ctx, id, sendErr)
+}
+
+// RetryOutbox sends the undelivered messages that are due again, through the messenger of their channel and chat,
+// and returns how many were delivered and how many failed again
+func (d *Delivery) RetryOutbox() (delivered, failed int) {
+	if d.outbox == nil {
+		return 0, 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pending, err := d.outbox.ClaimPending(ctx, time.Now())
+	if err != nil {
+		slog.Error("Error loading undelivered messages", "error", err)
+	}
+
+	for _, message := range pending {
+		resultCtx, resultCancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+		// An alert sent late could reach a chat that muted the symbol since, so stale ones are dropped
+		if message.Method == models.OutboxAlerts && time.Since(message.CreatedAt) > outboxAlertMaxAge {
+			slog.Info("Dropping undelivered alert", "channel", message.Channel, "chat_id", message.ChatID, "age", time.Since(message.CreatedAt).Round(time.Minute))
+			if err := d.outbox.MarkDead(resultCtx, message.ID, ErrAlertExpired); err != nil {
+				slog.Error("Error recording dropped alert in outbox", "error", err)
+			}
+			resultCancel()
+			continue
+		}
+
+		sendErr := d.resend(message)
+		if sendErr != nil {
+			failed++
+			slog.Warn("Retrying undelivered message failed", "channel", message.Channel, "chat_id", message.ChatID, "kind", message.Method, "attempts", message.Attempts+1, "error", sendErr)
+			if err := markUndelivered(resultCtx, d.outbox, message.ID, sendErr); err != nil {
+				slog.Error("Error recording failed send in outbox", "error", err)
+			}
+		} else {
+			delivered++
+			if err := d.outbox.MarkDelivered(resultCtx, message.ID); err != nil {
+				slog.Error("Error marking outbox message delivered", "error", err)
+			}
+		}
+		resultCancel()
+	}
+
+	if delivered+failed > 0 {
+		slog.Info("Retried undelivered messages", "delivered", delivered, "failed", failed)
+	}
+	return delivered, failed
+}
+
+// resend sends an outbox message again through its channel, addressed and styled as it was the first time
+func (d *Delivery) resend(message models.OutboxMessage) error {
+	m := d.channel(message.Channel)
+	if m == nil {
+		return fmt.Errorf("%w: channel %q is no longer configured", ErrMessageSending, message.Channel)
+	}
+
+	recipient := broadcastRecipient
+	if message.ChatID != "" {
+		chats, ok := m.(ChatMessenger)
+		if !ok {
+			return fmt.Errorf("%w: channel %q can no longer address chats", ErrMessageSending, message.Channel)
+		}
+		m = chats.ForChat(message.ChatID)
+		recipient = message.ChatID
+	}
+	if styler, ok := m.(AlertStyler); ok && message.AlertStyle != "" {
+		m = styler.WithAlertStyle(message.AlertStyle)
+	}
+	m = d.dedupFor(m, recipientKey(message.Channel, recipient))
+
+	switch message.Method {
+	case models.OutboxReport:
+		var quotes map[string]models.Quote
+		if err := json.Unmarshal([]byte(message.Payload), &quotes); err != nil {
+			return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+		}
+		return m.SendMessage(quotes, nil)
+	case models.OutboxAlerts:
+		var alerts []models.PriceAlert
+		if err := json.Unmarshal([]byte(message.Payload), &alerts); err != nil {
+			return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+		}
+		return m.SendAlerts(alerts, nil)
+	case models.OutboxText:
+		var text string
+		if err := json.Unmarshal([]byte(message.Payload), &text); err != nil {
+			return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+		}
+		return m.SendText(text, nil)
+	default:
+		return fmt.Errorf("%w: unknown outbox method %q", ErrMessagePreparation, message.Method)
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"stock-bot/models"
+)
+
+// memOutbox keeps outbox messages in memory
+type memOutbox struct {
+	mu       sync.Mutex
+	messages map[string]*models.OutboxMessage
+}
+
+func newMemOutbox(messages ...models.OutboxMessage) *memOutbox {
+	o := &memOutbox{messages: make(map[string]*models.OutboxMessage)}
+	for _, message := range messages {
+		o.messages[message.ID] = &message
+	}
+	return o
+}
+
+func (o *memOutbox) Add(_ context.Context, message models.OutboxMessage) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages[message.ID] = &message
+	return nil
+}
+
+func (o *memOutbox) MarkDelivered(_ context.Context, id string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	now := time.Now()
+	o.messages[id].DeliveredAt = &now
+	return nil
+}
+
+func (o *memOutbox) MarkFailed(_ context.Context, id string, sendErr error) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages[id].Attempts++
+	o.messages[id].LastError = sendErr.Error()
+	return nil
+}
+
+func (o *memOutbox) MarkDead(_ context.Context, id string, reason error) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	now := time.Now()
+	o.messages[id].DeadAt = &now
+	o.messages[id].LastError = reason.Error()
+	return nil
+}
+
+func (o *memOutbox) ClaimPending(_ context.Context, _ time.Time) ([]models.OutboxMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var pending []models.OutboxMessage
+	for _, message := range o.messages {
+		if message.DeliveredAt == nil && message.DeadAt == nil {
+			pending = append(pending, *message)
+		}
+	}
+	return pending, nil
+}
+
+// only returns the single message of the outbox
+func (o *memOutbox) only(t *testing.T) models.OutboxMessage {
+	t.Helper()
+	if len(o.messages) != 1 {
+		t.Fatalf("outbox holds %d messages, want 1", len(o.messages))
+	}
+	for _, message := range o.messages {
+		return *message
+	}
+	return models.OutboxMessage{}
+}
+
+func TestOutboxMessengerFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantDead bool
+	}{
+		{"network failure", fmt.Errorf("%w: connection reset", ErrMessageSending), false},
+		{"server error", fmt.Errorf("%w: %w", ErrMessageSending, &StatusError{StatusCode: http.StatusBadGateway}), false},
+		{"rate limited", fmt.Errorf("%w: %w", ErrMessageSending, &StatusError{StatusCode: http.StatusTooManyRequests}), false},
+		{"paused channel", fmt.Errorf("%w: telegram", ErrChannelPaused), false},
+		{"bad markup", fmt.Errorf("%w: %w", ErrMessageSending, &StatusError{StatusCode: http.StatusBadRequest}), true},
+		{"bot blocked", fmt.Errorf("%w: %w", ErrMessageSending, &StatusError{StatusCode: http.StatusForbidden}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemOutbox()
+			om := &outboxMessenger{inner: &countingMessenger{err: tt.err}, store: store}
+
+			if err := om.SendText("hello", nil); err == nil {
+				t.Fatal("SendText succeeded, want the inner error")
+			}
+			message := store.only(t)
+			if dead := message.DeadAt != nil; dead != tt.wantDead {
+				t.Errorf("dead = %v, want %v", dead, tt.wantDead)
+			}
+			if message.DeadAt == nil && message.Attempts != 1 {
+				t.Errorf("attempts = %d, want 1", message.Attempts)
+			}
+		})
+	}
+}
+
+func TestRetryOutboxDropsStaleAlerts(t *testing.T) {
+	alerts := `[{"symbol":"AAPL","percentChange":3}]`
+	tests := []struct {
+		name       string
+		message    models.OutboxMessage
+		wantSent   int
+		wantStatus string
+	}{
+		{"fresh alert", models.OutboxMessage{Method: models.OutboxAlerts, Payload: alerts, CreatedAt: time.Now().Add(-time.Minute)}, 1, "delivered"},
+		{"stale alert", models.OutboxMessage{Method: models.OutboxAlerts, Payload: alerts, CreatedAt: time.Now().Add(-2 * outboxAlertMaxAge)}, 0, "dead"},
+		{"old report", models.OutboxMessage{Method: models.OutboxText, Payload: `"report"`, CreatedAt: time.Now().Add(-2 * outboxAlertMaxAge)}, 1, "delivered"},
+		{"unreadable payload", models.OutboxMessage{Method: models.OutboxText, Payload: `{`, CreatedAt: time.Now()}, 0, "dead"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.message.ID = "1"
+			store := newMemOutbox(tt.message)
+			inner := &countingMessenger{}
+			d := NewDelivery(inner, nil, "")
+			d.SetOutbox(store)
+
+			d.RetryOutbox()
+
+			if sent := inner.alerts + inner.texts; sent != tt.wantSent {
+				t.Errorf("sent %d messages, want %d", sent, tt.wantSent)
+			}
+			message := store.only(t)
+			status := "pending"
+			switch {
+			case message.DeliveredAt != nil:
+				status = "delivered"
+			case message.DeadAt != nil:
+				status = "dead"
+			}
+			if status != tt.wantStatus {
+				t.Errorf("message is %s, want %s", status, tt.wantStatus)
+			}
+		})
+	}
+}
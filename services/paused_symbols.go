@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// PauseSymbol records that fetching a symbol is suspended
+func (db *Database) PauseSymbol(paused models.PausedSymbol) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("paused_symbols")
+	if err != nil {
+		return err
+	}
+
+	filter := bson.D{{Key: "symbol", Value: paused.Symbol}}
+	if _, err := collection.ReplaceOne(ctx, filter, paused, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// ResumeSymbol removes a symbol from the paused list and reports whether it was paused
+func (db *Database) ResumeSymbol(symbol string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("paused_symbols")
+	if err != nil {
+		return false, err
+	}
+
+	result, err := collection.DeleteOne(ctx, bson.D{{Key: "symbol", Value: symbol}})
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// GetPausedSymbols returns every symbol whose fetching is suspended
+func (db *Database) GetPausedSymbols() ([]models.PausedSymbol, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("paused_symbols")
+	if !ok {
+		return nil, nil
+	}
+
+	cursor, err := collection.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	defer cursor.Close(ctx)
+
+	var paused []models.PausedSymbol
+	if err := cursor.All(ctx, &paused); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return paused, nil
+}
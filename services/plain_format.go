@@ -0,0 +1,115 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"stock-bot/i18n"
+	"stock-bot/models"
+)
+
+// MessageFormat selects how a messenger renders reports and alerts
+type MessageFormat string
+
+// Supported message formats
+const (
+	FormatRich  MessageFormat = "rich"  // Emoji and markdown
+	FormatPlain MessageFormat = "plain" // Aligned monospace columns for e-ink displays, terminals and SMS
+	FormatFlex  MessageFormat = "flex"  // Line Flex Message bubbles with colored changes
+)
+
+// ParseMessageFormat validates a configured message format, defaulting to rich
+func ParseMessageFormat(value string) (MessageFormat, error) {
+	switch MessageFormat(strings.ToLower(strings.TrimSpace(value))) {
+	case "", FormatRich:
+		return FormatRich, nil
+	case FormatPlain:
+		return FormatPlain, nil
+	case FormatFlex:
+		return FormatFlex, nil
+	default:
+		return FormatRich, fmt.Errorf("unknown message format %q (use %s, %s or %s)", value, FormatRich, FormatPlain, FormatFlex)
+	}
+}
+
+// formatPlainReport renders the daily report as aligned columns sorted by symbol, labeled in the printer's language
+func formatPlainReport(quotes map[string]models.Quote, printer *i18n.Printer) string {
+	symbols := make([]string, 0, len(quotes))
+	for symbol := range quotes {
+		symbols = append(symbols, symbol)
+	}
+	slices.Sort(symbols)
+
+	rows := [][]string{{printer.Text("SYMBOL"), printer.Text("PRICE"), printer.Text("CHG%"), printer.Text("CCY")}}
+	for _, symbol := range symbols {
+		quote := quotes[symbol]
+		rows = append(rows, []string{
+			symbol,
+			strconv.FormatFloat(quote.Price, 'f', 2, 64),
+			fmt.Sprintf("%+.2f", quote.ChangePercent),
+			models.CurrencyFor(symbol),
+		})
+	}
+
+	return printer.Sprintf("DAILY STOCK REPORT %s", time.Now().Format("2006-01-02")) + "\n" + formatColumns(rows)
+}
+
+// formatPlainAlerts renders price alerts as aligned columns labeled in the printer's language; verbose adds volume
+// and headlines
+func formatPlainAlerts(alerts []models.PriceAlert, verbose bool, printer *i18n.Printer) string {
+	header := []string{printer.Text("SYMBOL"), printer.Text("PREV"), printer.Text("NOW"), printer.Text("CHG%")}
+	if verbose {
+		header = append(header, printer.Text("VOL"))
+	}
+
+	rows := [][]string{header}
+	var headlines []string
+	for _, alert := range alerts {
+		row := []string{
+			alert.Symbol,
+			strconv.FormatFloat(alert.PreviousPrice, 'f', 2, 64),
+			strconv.FormatFloat(alert.CurrentPrice, 'f', 2, 64),
+			fmt.Sprintf("%+.2f", alert.PercentChange),
+		}
+		if verbose {
+			row = append(row, formatVolume(alert.Volume))
+			if alert.Headline != "" {
+				headlines = append(headlines, alert.Symbol+": "+alert.Headline)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	text := printer.Text("PRICE ALERTS") + "\n" + formatColumns(rows)
+	if len(headlines) > 0 {
+		text += "\n" + strings.Join(headlines, "\n") + "\n"
+	}
+	return text
+}
+
+// formatColumns left-aligns the first column and right-aligns the others, counting wide characters such as Hangul
+// as two columns
+func formatColumns(rows [][]string) string {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], i18n.DisplayWidth(cell))
+		}
+	}
+
+	var out strings.Builder
+	for _, row := range rows {
+		for i, cell := range row {
+			if i == 0 {
+				out.WriteString(i18n.PadRight(cell, widths[i]))
+			} else {
+				out.WriteString("  " + i18n.PadLeft(cell, widths[i]))
+			}
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"testing"
+
+	"stock-bot/i18n"
+	"stock-bot/models"
+)
+
+func TestFormatPlainAlertsLanguage(t *testing.T) {
+	alerts := []models.PriceAlert{{Symbol: "NVDA", PreviousPrice: 126.2, CurrentPrice: 118.4, PercentChange: -6.18, Volume: 12_345_678}}
+
+	tests := []struct {
+		language i18n.Language
+		want     string
+	}{
+		{i18n.English, "PRICE ALERTS\n" +
+			"SYMBOL    PREV     NOW   CHG%    VOL\n" +
+			"NVDA    126.20  118.40  -6.18  12.3M\n"},
+		// Hangul labels take two columns each, so the values stay under them in a monospace font
+		{i18n.Korean, "가격 알림\n" +
+			"종목    이전    현재  변동%  거래량\n" +
+			"NVDA  126.20  118.40  -6.18   12.3M\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.language), func(t *testing.T) {
+			if got := formatPlainAlerts(alerts, true, i18n.NewPrinter(tt.language)); got != tt.want {
+				t.Errorf("formatPlainAlerts =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// GetPortfolio retrieves the portfolio of a chat, empty if it has none yet
+func (db *Database) GetPortfolio(chatID string) (models.Portfolio, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("portfolios")
+	if err != nil {
+		return models.Portfolio{}, err
+	}
+
+	portfolio := models.Portfolio{ChatID: chatID}
+	err = collection.FindOne(ctx, bson.D{{Key: "chatId", Value: chatID}}).Decode(&portfolio)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return portfolio, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return portfolio, nil
+}
+
+// SavePortfolio creates or replaces the portfolio of a chat, removing it once it holds nothing
+func (db *Database) SavePortfolio(portfolio models.Portfolio) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("portfolios")
+	if err != nil {
+		return err
+	}
+	filter := bson.D{{Key: "chatId", Value: portfolio.ChatID}}
+
+	if len(portfolio.Holdings) == 0 {
+		if _, err := collection.DeleteOne(ctx, filter); err != nil {
+			return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+		}
+		return nil
+	}
+
+	portfolio.UpdatedAt = time.Now()
+	if _, err := collection.ReplaceOne(ctx, filter, portfolio, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// ListPortfolios retrieves the portfolios of every chat, keyed by chat ID
+func (db *Database) ListPortfolios() (map[string]models.Portfolio, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("portfolios")
+	if !ok {
+		return nil, nil
+	}
+
+	cursor, err := collection.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	defer cursor.Close(ctx)
+
+	var stored []models.Portfolio
+	if err := cursor.All(ctx, &stored); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	portfolios := make(map[string]models.Portfolio, len(stored))
+	for _, portfolio := range stored {
+		portfolios[portfolio.ChatID] = portfolio
+	}
+	return portfolios, nil
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgreSQL related error definitions
+var (
+	ErrPostgresConnection  = errors.New("failed to connect to PostgreSQL")
+	ErrPostgresQueryFailed = errors.New("PostgreSQL query failed")
+)
+
+// postgresSchema creates the price and watchlist tables and their indexes when they don't exist yet;
+// the partial unique index keeps one historical close per symbol and day
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS prices (
+	id         BIGSERIAL PRIMARY KEY,
+	symbol     TEXT NOT NULL,
+	price      DOUBLE PRECISION NOT NULL,
+	source     TEXT NOT NULL DEFAULT '',
+	volume     BIGINT NOT NULL DEFAULT 0,
+	timestamp  TIMESTAMPTZ NOT NULL,
+	is_closing BOOLEAN NOT NULL
+);
+CREATE INDEX IF NOT EXISTS prices_symbol_closing_timestamp ON prices (symbol, is_closing, timestamp DESC);
+CREATE INDEX IF NOT EXISTS prices_symbol_timestamp ON prices (symbol, timestamp DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS prices_closing_day ON prices (symbol, timestamp) WHERE is_closing;
+
+CREATE TABLE IF NOT EXISTS watchlist (
+	id       BIGSERIAL PRIMARY KEY,
+	symbol   TEXT NOT NULL UNIQUE,
+	added_by TEXT NOT NULL,
+	added_at TIMESTAMPTZ NOT NULL
+);
+`
+
+// PostgresStore keeps price history and the watchlist in PostgreSQL
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresStore connects to PostgreSQL and creates the tables on the first start
+func NewPostgresStore(url string) (*PostgresStore, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPostgresConnection, err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("%w: %v", ErrPostgresConnection, err)
+	}
+	if _, err := pool.Exec(ctx, postgresSchema); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+
+	return &PostgresStore{pool: pool}, nil
+}
+
+// SavePrice saves a quote's price, source and session volume to PostgreSQL
+func (s *PostgresStore) SavePrice(quote models.Quote, isClosing bool, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := s.pool.Exec(ctx,
+		`INSERT INTO prices (symbol, price, source, volume, timestamp, is_closing) VALUES ($1, $2, $3, $4, $5, $6)`,
+		quote.Symbol, quote.Price, quote.Source, quote.Volume, time.Now(), isClosing)
+	if err != nil {
+		slog.Error("Failed to insert stock data", "error", err)
+		return fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+
+	slog.Debug("Saved price to PostgreSQL", "symbol", quote.Symbol, "price", quote.Price, "source", quote.Source, "volume", quote.Volume, "closing", isClosing)
+	return nil
+}
+
+// SavePrices saves several price records one at a time, so a failed record doesn't stop the others
+func (s *PostgresStore) SavePrices(records []models.MongoDTO) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Each row is its own statement, as a batch runs in one implicit transaction that a single failure rolls back
+	var failed int
+	var lastErr error
+	for _, record := range records {
+		_, err := s.pool.Exec(ctx,
+			`INSERT INTO prices (symbol, price, source, volume, timestamp, is_closing) VALUES ($1, $2, $3, $4, $5, $6)`,
+			record.Symbol, float64(record.Price), record.Source, record.Volume, record.Timestamp, record.IsClosing)
+		if err != nil {
+			failed++
+			lastErr = err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d prices not saved: %v", ErrPostgresQueryFailed, failed, len(records), lastErr)
+	}
+
+	slog.Debug("Saved prices to PostgreSQL", "count", len(records))
+	return nil
+}
+
+// GetLatestClosingPrice retrieves the latest closing price for a specific stock
+func (s *PostgresStore) GetLatestClosingPrice(symbol string) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var price float64
+	err := s.pool.QueryRow(ctx,
+		`SELECT price FROM prices WHERE symbol = $1 AND is_closing ORDER BY timestamp DESC LIMIT 1`,
+		symbol).Scan(&price)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("%w: %s", ErrNoClosingPriceFound, symbol)
+		}
+		return 0, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+	return price, nil
+}
+
+// GetLatestClosingPrices retrieves the latest closing price of several stocks in one query;
+// symbols without a stored close are left out of the result
+func (s *PostgresStore) GetLatestClosingPrices(symbols []string) (map[string]float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := s.pool.Query(ctx,
+		`SELECT DISTINCT ON (symbol) symbol, price FROM prices
+		WHERE symbol = ANY($1) AND is_closing ORDER BY symbol, timestamp DESC`,
+		symbols)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+	defer rows.Close()
+
+	closes := make(map[string]float64, len(symbols))
+	for rows.Next() {
+		var symbol string
+		var price float64
+		if err := rows.Scan(&symbol, &price); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+		}
+		closes[symbol] = price
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+	return closes, nil
+}
+
+// GetPriceHistory retrieves the closing prices of a specific stock over the last days, oldest first
+func (s *PostgresStore) GetPriceHistory(symbol string, days int) ([]models.MongoDTO, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	startDate := time.Now().AddDate(0, 0, -days)
+	rows, err := s.pool.Query(ctx,
+		`SELECT price, source, volume, timestamp FROM prices
+		WHERE symbol = $1 AND is_closing AND timestamp >= $2 ORDER BY timestamp`,
+		symbol, startDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+	defer rows.Close()
+
+	var results []models.MongoDTO
+	for rows.Next() {
+		record := models.MongoDTO{Symbol: symbol, IsClosing: true}
+		var price float64
+		if err := rows.Scan(&price, &record.Source, &record.Volume, &record.Timestamp); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+		}
+		record.Price = models.StoredPrice(price)
+		results = append(results, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+	return results, nil
+}
+
+// SaveHistoricalCloses stores the daily closing prices of a symbol that aren't stored yet and returns
+// the number of new records
+func (s *PostgresStore) SaveHistoricalCloses(symbol string, points []models.PricePoint) (int, error) {
+	if len(points) == 0 {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Closes of the same day are stored at different times of it, so the days already stored are looked up and skipped
+	from, to := closesSpan(points)
+	rows, err := s.pool.Query(ctx, `SELECT timestamp FROM prices WHERE symbol = $1 AND is_closing AND timestamp BETWEEN $2 AND $3`,
+		symbol, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+	stored, err := pgx.CollectRows(rows, pgx.RowTo[time.Time])
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+	fresh := newCloses(symbol, points, stored)
+	if len(fresh) == 0 {
+		return 0, nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, point := range fresh {
+		batch.Queue(`INSERT INTO prices (symbol, price, volume, timestamp, is_closing) VALUES ($1, $2, $3, $4, TRUE)
+			ON CONFLICT (symbol, timestamp) WHERE is_closing DO NOTHING`,
+			symbol, point.Close, point.Volume, point.Timestamp)
+	}
+
+	results := s.pool.SendBatch(ctx, batch)
+	defer results.Close()
+
+	inserted := 0
+	for range fresh {
+		tag, err := results.Exec()
+		if err != nil {
+			return inserted, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+		}
+		inserted += int(tag.RowsAffected())
+	}
+
+	slog.Info("Saved historical closes to PostgreSQL", "symbol", symbol, "count", inserted)
+	return inserted, nil
+}
+
+// DeleteIntradayPricesBefore removes intraday prices recorded before the cutoff and returns how many were removed;
+// closing prices are never removed
+func (s *PostgresStore) DeleteIntradayPricesBefore(cutoff time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `DELETE FROM prices WHERE NOT is_closing AND timestamp < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetWatchlist returns the monitored symbols in the order they were added, seeding the defaults into an empty watchlist
+func (s *PostgresStore) GetWatchlist(defaults []string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var count int
+	if err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM watchlist`).Scan(&count); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+	if count == 0 && len(defaults) > 0 {
+		now := time.Now()
+		batch := &pgx.Batch{}
+		for _, symbol := range defaults {
+			batch.Queue(`INSERT INTO watchlist (symbol, added_by, added_at) VALUES ($1, 'default', $2) ON CONFLICT (symbol) DO NOTHING`, symbol, now)
+		}
+		if err := s.pool.SendBatch(ctx, batch).Close(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+		}
+	}
+
+	rows, err := s.pool.Query(ctx, `SELECT symbol FROM watchlist ORDER BY added_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+	symbols, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+	return symbols, nil
+}
+
+// AddToWatchlist adds a symbol to the watchlist and reports whether it was new
+func (s *PostgresStore) AddToWatchlist(symbol, addedBy string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Only insert when missing, so the original position in the list is kept
+	tag, err := s.pool.Exec(ctx,
+		`INSERT INTO watchlist (symbol, added_by, added_at) VALUES ($1, $2, $3) ON CONFLICT (symbol) DO NOTHING`,
+		symbol, addedBy, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RemoveFromWatchlist removes a symbol from the watchlist and reports whether it was there
+func (s *PostgresStore) RemoveFromWatchlist(symbol string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `DELETE FROM watchlist WHERE symbol = $1`, symbol)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RemoveFromWatchlistAddedBy removes a symbol from the watchlist if addedBy added it, and reports whether it was removed
+func (s *PostgresStore) RemoveFromWatchlistAddedBy(symbol, addedBy string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tag, err := s.pool.Exec(ctx, `DELETE FROM watchlist WHERE symbol = $1 AND added_by = $2`, symbol, addedBy)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrPostgresQueryFailed, err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Ping checks that PostgreSQL is reachable
+func (s *PostgresStore) Ping(ctx context.Context) error {
+	if err := s.pool.Ping(ctx); err != nil {
+		return fmt.Errorf("%w: %v", ErrPostgresConnection, err)
+	}
+	return nil
+}
+
+// Close closes the connection pool
+func (s *PostgresStore) Close() error {
+	s.pool.Close()
+	return nil
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// EnsurePriceCollection creates the stocks collection as a time-series collection of prices per symbol
+// when it doesn't exist yet, and reports whether it is one; a regular collection from an earlier version
+// is kept as it is, as it can't be converted in place
+func (db *Database) EnsurePriceCollection() (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if db.client == nil {
+		return false, ErrMongoNotConfigured
+	}
+	database := db.client.Database("stock_data")
+
+	specs, err := database.ListCollectionSpecifications(ctx, bson.D{{Key: "name", Value: "stocks"}})
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	if len(specs) > 0 {
+		return specs[0].Type == "timeseries", nil
+	}
+
+	// The server picks the bucket granularity, and measurements of one symbol are stored together
+	timeSeries := options.TimeSeries().SetTimeField("timestamp").SetMetaField("symbol")
+	if err := database.CreateCollection(ctx, "stocks", options.CreateCollection().SetTimeSeriesOptions(timeSeries)); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	slog.Info("Created time-series price collection")
+	return true, nil
+}
@@ -4,11 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"os"
-	"os/signal"
-	"sync"
-	"syscall"
+	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"stock-bot/models"
@@ -23,29 +21,21 @@ var (
 	ErrBrowserTimeout   = errors.New("browser operation timed out")
 )
 
-// Global allocator and browser context to reuse across requests
-var (
-	globalAllocCtx      context.Context
-	globalAllocCancel   context.CancelFunc
-	globalBrowserCtx    context.Context
-	globalBrowserCancel context.CancelFunc
-	setupOnce           sync.Once
-	cleanupOnce         sync.Once
-	browserMutex        sync.Mutex
-)
-
 // PriceFetcher collects stock price information
 type PriceFetcher struct {
-	Opts          []chromedp.ExecAllocatorOption
-	FetchTimeout  time.Duration
-	MaxRetries    int
-	RetryInterval time.Duration
+	Opts           []chromedp.ExecAllocatorOption
+	FetchTimeout   time.Duration // Bounds a whole fetch, retries included
+	AttemptTimeout time.Duration // Bounds a single page load, so a stuck tab is closed well before the fetch gives up
+	MaxRetries     int
+	RetryInterval  time.Duration
+	Fallback       *HTTPScraper // Used when the headless browser is unavailable
+	Browser        *BrowserPool // Tabs of the shared headless browser
+	profiles       *scrapeProfiles
 }
 
-// setupGlobalBrowser initializes the global browser instance
-func setupGlobalBrowser() {
-	// Create allocator context
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+// browserOptions returns the Chrome flags of the headless browser
+func browserOptions() []chromedp.ExecAllocatorOption {
+	return append(chromedp.DefaultExecAllocatorOptions[:],
 		chromedp.DisableGPU,
 		chromedp.NoDefaultBrowserCheck,
 		chromedp.NoFirstRun,
@@ -62,89 +52,80 @@ func setupGlobalBrowser() {
 		chromedp.Flag("disable-web-security", true),
 		chromedp.Flag("no-default-browser-check", true),
 	)
-
-	// Create a background context for the allocator
-	globalAllocCtx, globalAllocCancel = chromedp.NewExecAllocator(context.Background(), opts...)
-
-	// Create a browser context
-	globalBrowserCtx, globalBrowserCancel = chromedp.NewContext(
-		globalAllocCtx,
-		chromedp.WithLogf(log.Printf),
-	)
-
-	// Start the browser
-	if err := chromedp.Run(globalBrowserCtx); err != nil {
-		log.Printf("Error starting browser: %v", err)
-	}
-
-	// Set up signal handling for cleanup
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		log.Println("Received termination signal, cleaning up browser")
-		cleanupGlobalBrowser()
-		os.Exit(0)
-	}()
-}
-
-// cleanupGlobalBrowser properly closes the browser to prevent zombie processes
-func cleanupGlobalBrowser() {
-	cleanupOnce.Do(func() {
-		log.Println("Cleaning up global browser")
-		if globalBrowserCancel != nil {
-			globalBrowserCancel()
-		}
-		if globalAllocCancel != nil {
-			globalAllocCancel()
-		}
-	})
 }
 
 // NewPriceFetcher creates a new PriceFetcher instance
 func NewPriceFetcher() *PriceFetcher {
-	// Initialize the global browser if it hasn't been done yet
-	setupOnce.Do(setupGlobalBrowser)
+	profiles := newScrapeProfiles()
+	fallback := NewHTTPScraper()
+	fallback.profiles = profiles
 
 	return &PriceFetcher{
-		FetchTimeout:  2 * time.Minute,
-		MaxRetries:    3,
-		RetryInterval: 5 * time.Second,
+		FetchTimeout:   2 * time.Minute,
+		AttemptTimeout: 30 * time.Second,
+		MaxRetries:     3,
+		RetryInterval:  5 * time.Second,
+		Fallback:       fallback,
+		Browser:        NewBrowserPool(browserOptions(), models.DefaultConfig().Browser),
+		profiles:       profiles,
 	}
 }
 
-// FetchPrice extracts stock price from a given URL
+// SetScrapeProfiles replaces the sites and selectors quotes are scraped with; the HTTP fallback shares them, along
+// with the health of each profile
+func (pf *PriceFetcher) SetScrapeProfiles(profiles []models.ScrapeProfile) {
+	pf.profiles.set(profiles)
+}
+
+// SetBrowserLimits replaces the browser pool with one bounded by the limits; call it before the first fetch
+func (pf *PriceFetcher) SetBrowserLimits(limits models.BrowserConfig) {
+	pf.Browser = NewBrowserPool(browserOptions(), limits)
+}
+
+// browserAvailable starts the browser on first use and reports whether it started successfully
+func (pf *PriceFetcher) browserAvailable() bool {
+	return pf.Browser.Available() || pf.Fallback == nil
+}
+
+// Name identifies the scraping path in logs
+func (pf *PriceFetcher) Name() string {
+	return "chromedp"
+}
+
+// Fetch returns a quote for a symbol, so the fetcher can be used as a PriceSource
+func (pf *PriceFetcher) Fetch(ctx context.Context, symbol string) (models.Quote, error) {
+	return pf.FetchQuote(ctx, symbol)
+}
+
+// FetchPrice extracts stock price from a given URL, with the price selectors of every scrape profile
 func (pf *PriceFetcher) FetchPrice(ctx context.Context, url string) (string, error) {
+	if !pf.browserAvailable() {
+		return pf.Fallback.FetchPrice(ctx, url)
+	}
+
 	var price string
 	var err error
-	log.Printf("Fetching price from %s", url)
+	slog.Debug("Fetching price", "url", url)
+
+	ctx, cancel := context.WithTimeout(ctx, pf.FetchTimeout)
+	defer cancel()
 
 	// Add retry logic
 	for attempt := 0; attempt < pf.MaxRetries; attempt++ {
 		if attempt > 0 {
-			log.Printf("Retry attempt %d for %s", attempt, url)
-			time.Sleep(pf.RetryInterval)
+			slog.Debug("Retry attempt", "attempt", attempt, "url", url)
+			select {
+			case <-time.After(pf.RetryInterval):
+			case <-ctx.Done():
+				return "", fmt.Errorf("%w: %v", ErrPriceFetchFailed, ctx.Err())
+			}
 		}
 
-		// Create a new tab context from the global browser context
-		browserMutex.Lock()
-		tabCtx, tabCancel := chromedp.NewContext(globalBrowserCtx)
-		browserMutex.Unlock()
-
-		// Add timeout to the tab context
-		tabTimeoutCtx, cancel := context.WithTimeout(tabCtx, pf.FetchTimeout)
-
-		// Always cancel the contexts when done with this iteration
-		defer func() {
-			cancel()
-			tabCancel()
-		}()
-
-		// Execute the actions in the tab with timeout
-		err = chromedp.Run(tabTimeoutCtx,
+		selector := strings.Join(pf.profiles.priceSelectors(), ", ")
+		err = pf.runInTab(ctx,
 			chromedp.Navigate(url),
-			chromedp.WaitVisible(`span[data-testid="qsp-price"]`, chromedp.ByQuery),
-			chromedp.Text(`span[data-testid="qsp-price"]`, &price, chromedp.ByQuery),
+			chromedp.WaitVisible(selector, chromedp.ByQuery),
+			chromedp.Text(selector, &price, chromedp.ByQuery),
 		)
 
 		// Return immediately on success
@@ -152,14 +133,17 @@ func (pf *PriceFetcher) FetchPrice(ctx context.Context, url string) (string, err
 			return price, nil
 		}
 
-		// Retry on context cancellation/timeout
+		// Retry when only the attempt timed out; the whole fetch is over once ctx ends
 		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-			log.Printf("Browser operation timed out for %s, retrying...", url)
+			if ctx.Err() != nil {
+				break
+			}
+			slog.Warn("Browser operation timed out, retrying", "url", url)
 			continue
 		}
 
 		// Log other errors and retry
-		log.Printf("Error fetching price from %s: %v", url, err)
+		slog.Error("Error fetching price", "url", url, "error", err)
 	}
 
 	// If all retries fail
@@ -175,68 +159,136 @@ func (pf *PriceFetcher) FetchPrice(ctx context.Context, url string) (string, err
 	return price, nil
 }
 
-// FetchPriceConcurrent fetches prices for multiple stocks concurrently
-func (pf *PriceFetcher) FetchPriceConcurrent(ctx context.Context, tickers []string, maxConcurrency int) (map[string]models.PriceResult, error) {
-	// Semaphore to limit concurrency
-	sem := make(chan struct{}, maxConcurrency)
+// quoteFields holds the raw text scraped with a profile's selectors
+type quoteFields struct {
+	Price         string `json:"price"`
+	Change        string `json:"change"`
+	ChangePercent string `json:"changePercent"`
+	Volume        string `json:"volume"`
+}
+
+// FetchQuote extracts a detailed quote (price, day change and volume) for a symbol, trying each scrape profile until
+// one reads a price, healthier profiles first
+func (pf *PriceFetcher) FetchQuote(ctx context.Context, symbol string) (models.Quote, error) {
+	if !pf.browserAvailable() {
+		return pf.Fallback.FetchQuote(ctx, symbol)
+	}
 
-	// Results channel
-	results := make(chan models.PriceResult, len(tickers))
+	ctx, cancel := context.WithTimeout(ctx, pf.FetchTimeout)
+	defer cancel()
 
-	// waitgroup
-	var wg sync.WaitGroup
+	return pf.profiles.fetchQuote(ctx, symbol, func(profile models.ScrapeProfile) (models.Quote, error) {
+		return pf.profiles.fetchFirstURL(profile, symbol, func(page string) (models.Quote, error) {
+			return pf.fetchPageQuote(ctx, symbol, profile, page)
+		})
+	})
+}
 
-	// Create URL mapping
-	urls := GetURLs(tickers)
+// ScrapeProfileStats returns how often each scrape profile read a quote
+func (pf *PriceFetcher) ScrapeProfileStats() map[string]ScrapeProfileStats {
+	return pf.profiles.stats()
+}
 
-	// Start goroutine for each ticker
-	for _, ticker := range tickers {
-		wg.Add(1)
-		go func(symbol string) {
-			defer wg.Done()
+// fetchPageQuote scrapes a symbol's quote from one of a profile's pages, retrying failed page loads
+func (pf *PriceFetcher) fetchPageQuote(ctx context.Context, symbol string, profile models.ScrapeProfile, url string) (models.Quote, error) {
+	slog.Debug("Fetching quote", "url", url, "profile", profile.Name)
 
-			// Acquire semaphore
-			sem <- struct{}{}
-			defer func() { <-sem }()
+	var err error
+	for attempt := 0; attempt < pf.MaxRetries; attempt++ {
+		if attempt > 0 {
+			slog.Debug("Retry attempt", "attempt", attempt, "url", url)
+			select {
+			case <-time.After(pf.RetryInterval):
+			case <-ctx.Done():
+				return models.Quote{}, fmt.Errorf("%w: %v", ErrPriceFetchFailed, ctx.Err())
+			}
+		}
 
-			url := urls[symbol]
+		var fields quoteFields
+		err = pf.runInTab(ctx,
+			chromedp.Navigate(url),
+			profileWait(profile),
+			chromedp.Evaluate(profileScript(profile), &fields),
+		)
+		if err != nil {
+			slog.Error("Error fetching quote", "url", url, "error", err)
+			continue
+		}
 
-			// Fetch price using the global browser context
-			price, err := pf.FetchPrice(ctx, url)
+		if fields.Price == "" {
+			return models.Quote{}, ErrElementNotFound
+		}
 
-			// Send results
-			results <- models.PriceResult{
-				Symbol: symbol,
-				Price:  price,
-				Error:  err,
-			}
-		}(ticker)
+		return parseQuoteFields(symbol, fields)
+	}
+
+	return models.Quote{}, fmt.Errorf("%w: %v", ErrPriceFetchFailed, err)
+}
+
+// runInTab runs one attempt in a pooled tab, bounded by both ctx and the attempt timeout. The tab and its
+// contexts are released before it returns, so a retry loop never holds more than one tab per fetch
+func (pf *PriceFetcher) runInTab(ctx context.Context, actions ...chromedp.Action) error {
+	tab, err := pf.Browser.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	attemptCtx, cancel := context.WithTimeout(tab.Context(), pf.AttemptTimeout)
+
+	// Stop the tab as soon as the caller gives up
+	stop := context.AfterFunc(ctx, cancel)
+	err = chromedp.Run(attemptCtx, actions...)
+	stop()
+	cancel()
+
+	// A failed tab may be stuck mid-load, so it is closed rather than reused
+	pf.Browser.Release(tab, err == nil)
+	return err
+}
+
+// parseQuoteFields converts scraped quote text into a Quote
+func parseQuoteFields(symbol string, fields quoteFields) (models.Quote, error) {
+	price, err := ParsePrice(fields.Price)
+	if err != nil {
+		return models.Quote{}, err
 	}
 
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	quote := models.Quote{
+		Symbol:    symbol,
+		Price:     price,
+		Currency:  models.CurrencyFor(symbol),
+		Timestamp: time.Now(),
+	}
 
-	// Collect all results
-	priceMap := make(map[string]models.PriceResult)
-	for result := range results {
-		priceMap[result.Symbol] = result
+	// Change and volume are optional; missing values are left at zero
+	if change, err := ParsePrice(fields.Change); err == nil {
+		quote.Change = change
+	}
+	if changePercent, err := ParsePrice(fields.ChangePercent); err == nil {
+		quote.ChangePercent = changePercent
+	}
+	if volume, err := ParsePrice(fields.Volume); err == nil {
+		quote.Volume = int64(volume)
 	}
 
-	return priceMap, nil
+	return quote, nil
 }
 
-// GetURLs creates a URL map for a list of tickers
-func GetURLs(tickers []string) map[string]string {
-	urls := make(map[string]string)
-	for _, t := range tickers {
-		urls[t] = fmt.Sprintf("https://finance.yahoo.com/quote/%s/", t)
+// ParsePrice parses scraped numeric text such as "1,234.56", "+1.20" or "(-0.85%)"
+func ParsePrice(text string) (float64, error) {
+	cleaned := strings.NewReplacer(",", "", "(", "", ")", "", "%", "", "+", "").Replace(strings.TrimSpace(text))
+	if cleaned == "" {
+		return 0, ErrInvalidPriceFormat
+	}
+
+	value, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidPriceFormat, err)
 	}
-	return urls
+	return value, nil
 }
 
-// Cleanup should be called when the application is shutting down
+// Cleanup should be called when the application is shutting down; it closes the browser so no Chrome
+// processes are left behind
 func (pf *PriceFetcher) Cleanup() {
-	cleanupGlobalBrowser()
+	pf.Browser.Close()
 }
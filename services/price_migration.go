@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// legacyPriceFilter matches price records written before prices were stored as numbers
+var legacyPriceFilter = bson.D{{Key: "price", Value: bson.D{{Key: "$type", Value: "string"}}}}
+
+// CountStringPrices returns the number of price records whose price is still stored as a string
+func (db *Database) CountStringPrices(ctx context.Context) (int64, error) {
+	collection, err := db.collection("stocks")
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := collection.CountDocuments(ctx, legacyPriceFilter)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return count, nil
+}
+
+// MigrateStringPrices converts string prices to doubles on the server, dropping thousands separators,
+// and returns the number of records converted; strings that aren't numbers are left as they are
+func (db *Database) MigrateStringPrices(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	collection, err := db.collection("stocks")
+	if err != nil {
+		return 0, err
+	}
+
+	// An update pipeline converts every record in one round trip (MongoDB 4.4 or later)
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{{Key: "price", Value: bson.D{{Key: "$convert", Value: bson.D{
+			{Key: "input", Value: bson.D{{Key: "$replaceAll", Value: bson.D{
+				{Key: "input", Value: bson.D{{Key: "$trim", Value: bson.D{{Key: "input", Value: "$price"}}}}},
+				{Key: "find", Value: ","},
+				{Key: "replacement", Value: ""},
+			}}}},
+			{Key: "to", Value: "double"},
+			{Key: "onError", Value: "$price"},
+		}}}}}}},
+	}
+
+	result, err := collection.UpdateMany(ctx, legacyPriceFilter, update)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	slog.Info("Converted string prices", "matched", result.MatchedCount, "converted", result.ModifiedCount)
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"stock-bot/models"
+)
+
+// PriceSource provides current quotes for symbols from a single data provider
+type PriceSource interface {
+	// Name identifies the source in logs
+	Name() string
+	// Fetch returns the current quote of a symbol
+	Fetch(ctx context.Context, symbol string) (models.Quote, error)
+}
+
+// SymbolFilter is implemented by price sources that only cover some symbols, such as crypto-only APIs
+type SymbolFilter interface {
+	// Supports reports whether the source can quote a symbol
+	Supports(symbol string) bool
+}
+
+// YahooAPISource reads quotes from the Yahoo Finance chart JSON API over plain HTTP, without a browser
+type YahooAPISource struct {
+	client *http.Client
+}
+
+// NewYahooAPISource creates a new YahooAPISource instance
+func NewYahooAPISource() *YahooAPISource {
+	return &YahooAPISource{
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Name identifies the Yahoo JSON API source
+func (ys *YahooAPISource) Name() string {
+	return "yahoo-api"
+}
+
+// Fetch returns the latest regular-market quote of a symbol
+func (ys *YahooAPISource) Fetch(ctx context.Context, symbol string) (models.Quote, error) {
+	query := url.Values{}
+	query.Set("range", "1d")
+	query.Set("interval", "1d")
+	endpoint := fmt.Sprintf("https://query1.finance.yahoo.com/v8/finance/chart/%s?%s", url.PathEscape(symbol), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return models.Quote{}, fmt.Errorf("%w: %v", ErrPriceFetchFailed, err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; stock-bot)")
+
+	resp, err := ys.client.Do(req)
+	if err != nil {
+		return models.Quote{}, fmt.Errorf("%w: %v", ErrPriceFetchFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return models.Quote{}, fmt.Errorf("%w: %s", ErrElementNotFound, symbol)
+	}
+	if resp.StatusCode >= 400 {
+		return models.Quote{}, fmt.Errorf("%w: received status code %d", ErrPriceFetchFailed, resp.StatusCode)
+	}
+
+	var chart yahooChartResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chart); err != nil {
+		return models.Quote{}, fmt.Errorf("%w: %v", ErrPriceFetchFailed, err)
+	}
+
+	if chart.Chart.Error != nil {
+		return models.Quote{}, fmt.Errorf("%w: %s", ErrElementNotFound, chart.Chart.Error.Description)
+	}
+	if len(chart.Chart.Result) == 0 || chart.Chart.Result[0].Meta.RegularMarketPrice == 0 {
+		return models.Quote{}, fmt.Errorf("%w: empty response for %s", ErrElementNotFound, symbol)
+	}
+
+	meta := chart.Chart.Result[0].Meta
+	quote := models.Quote{
+		Symbol:    symbol,
+		Price:     meta.RegularMarketPrice,
+		Volume:    meta.RegularMarketVolume,
+		Currency:  models.CurrencyFor(symbol),
+		Timestamp: time.Now(),
+	}
+	if meta.RegularMarketTime > 0 {
+		quote.Timestamp = time.Unix(meta.RegularMarketTime, 0)
+	}
+
+	// With a one-day range the chart's previous close is the prior session's close
+	if meta.ChartPreviousClose > 0 {
+		quote.Change = meta.RegularMarketPrice - meta.ChartPreviousClose
+		quote.ChangePercent = quote.Change / meta.ChartPreviousClose * 100
+	}
+
+	// The session open, from the meta when given, else from the day's bar
+	quote.Open = meta.RegularMarketOpen
+	if bars := chart.Chart.Result[0].Indicators.Quote; quote.Open == 0 && len(bars) > 0 {
+		if opens := bars[0].Open; len(opens) > 0 && opens[len(opens)-1] != nil {
+			quote.Open = *opens[len(opens)-1]
+		}
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// AddPriceTarget stores a new price target, armed unless its start price already reached the level, and returns
+// it with its ID
+func (db *Database) AddPriceTarget(target models.PriceTarget) (models.PriceTarget, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("price_targets")
+	if err != nil {
+		return models.PriceTarget{}, err
+	}
+
+	target.ID = bson.NewObjectID().Hex()
+	target.Arm()
+	target.CreatedAt = time.Now()
+	if _, err := collection.InsertOne(ctx, target); err != nil {
+		return models.PriceTarget{}, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return target, nil
+}
+
+// UpdatePriceTarget saves the armed state and trigger time of a price target
+func (db *Database) UpdatePriceTarget(target models.PriceTarget) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("price_targets")
+	if err != nil {
+		return err
+	}
+
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "armed", Value: target.Armed},
+		{Key: "triggeredAt", Value: target.TriggeredAt},
+	}}}
+	if _, err := collection.UpdateByID(ctx, target.ID, update); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// DeletePriceTarget removes a price target
+func (db *Database) DeletePriceTarget(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("price_targets")
+	if err != nil {
+		return err
+	}
+
+	if _, err := collection.DeleteOne(ctx, bson.D{{Key: "_id", Value: id}}); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// ListPriceTargets returns the price targets of a chat, or of every chat when chatID is empty, oldest first
+func (db *Database) ListPriceTargets(chatID string) ([]models.PriceTarget, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("price_targets")
+	if !ok {
+		return nil, nil
+	}
+
+	filter := bson.D{}
+	if chatID != "" {
+		filter = bson.D{{Key: "chatId", Value: chatID}}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	defer cursor.Close(ctx)
+
+	var targets []models.PriceTarget
+	if err := cursor.All(ctx, &targets); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return targets, nil
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"stock-bot/models"
+)
+
+// Error definitions for outbound integrations
+var (
+	ErrPublishFailed = errors.New("failed to publish event")
+)
+
+// Publisher forwards bot events to an external integration
+type Publisher interface {
+	Name() string
+	Publish(ctx context.Context, event models.Event) error
+}
+
+// Publishers fans events out to every configured integration
+type Publishers []Publisher
+
+// Publish sends the event to every publisher and joins their errors
+func (ps Publishers) Publish(ctx context.Context, event models.Event) error {
+	var errs []error
+	for _, publisher := range ps {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", publisher.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
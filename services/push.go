@@ -0,0 +1,276 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+)
+
+// Push notification limits
+const (
+	pushoverMaxMessage = 1024 // Longest Pushover message, in characters
+	pushoverMaxTitle   = 250  // Longest Pushover title, in characters
+	ntfyMaxMessage     = 4096 // Longest ntfy message before it is turned into an attachment, in bytes
+)
+
+// pushPriority is how insistently a push notification is delivered to the phone
+type pushPriority int
+
+// Push priorities, from a silent daily report to an alert on a move of twice the high-priority percentage
+const (
+	pushLow pushPriority = iota
+	pushNormal
+	pushHigh
+	pushUrgent
+)
+
+// alertPriority maps the largest move among alerts to a priority: high from highPercent and urgent from twice
+// that; a highPercent of 0 keeps every alert at normal priority
+func alertPriority(alerts []models.PriceAlert, highPercent float64) pushPriority {
+	largest := 0.0
+	for _, alert := range alerts {
+		largest = max(largest, math.Abs(alert.PercentChange))
+	}
+	switch {
+	case highPercent <= 0:
+		return pushNormal
+	case largest >= 2*highPercent:
+		return pushUrgent
+	case largest >= highPercent:
+		return pushHigh
+	default:
+		return pushNormal
+	}
+}
+
+// pushNotification is a rendered report, alert or text ready to be pushed
+type pushNotification struct {
+	title    string
+	message  string
+	priority pushPriority
+	tags     []string // ntfy emoji shortcodes shown before the title
+}
+
+// pushReport renders the daily report as one line per symbol, sent at low priority
+func pushReport(quotes map[string]models.Quote) pushNotification {
+	lines := make([]string, 0, len(quotes))
+	for _, symbol := range slices.Sorted(maps.Keys(quotes)) {
+		lines = append(lines, symbol+": "+models.FormatQuote(quotes[symbol]))
+	}
+	return pushNotification{
+		title:    "Daily Stock Report",
+		message:  strings.Join(lines, "\n"),
+		priority: pushLow,
+		tags:     []string{"bar_chart"},
+	}
+}
+
+// pushAlerts renders price alerts as compact lines, titled with the alerted symbols
+func pushAlerts(alerts []models.PriceAlert, highPercent float64) pushNotification {
+	var symbols []string
+	for _, alert := range alerts {
+		if !slices.Contains(symbols, alert.Symbol) {
+			symbols = append(symbols, alert.Symbol)
+		}
+	}
+
+	tag := "chart_with_upwards_trend"
+	if alerts[0].PercentChange < 0 {
+		tag = "chart_with_downwards_trend"
+	}
+	return pushNotification{
+		title:    "Price alert: " + strings.Join(symbols, ", "),
+		message:  formatCompactAlerts(alerts),
+		priority: alertPriority(alerts, highPercent),
+		tags:     []string{tag},
+	}
+}
+
+// truncateRunes shortens a text to a number of characters, ending it with an ellipsis
+func truncateRunes(text string, limit int) string {
+	if runes := []rune(text); len(runes) > limit {
+		return string(runes[:limit-1]) + "…"
+	}
+	return text
+}
+
+// PushoverMessenger sends notifications through the Pushover API
+type PushoverMessenger struct {
+	token       string
+	user        string
+	highPercent float64
+	client      *http.Client
+}
+
+// NewPushoverMessenger creates a PushoverMessenger for an application token and a user or group key; alerts moving
+// at least highPercent are sent at high priority
+func NewPushoverMessenger(token, user string, highPercent float64) (*PushoverMessenger, error) {
+	if token == "" {
+		return nil, ErrTokenNotSet
+	}
+	if user == "" {
+		return nil, ErrChatIDNotSet
+	}
+	return &PushoverMessenger{token: token, user: user, highPercent: highPercent, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// SendMessage pushes the daily report quietly
+func (pm *PushoverMessenger) SendMessage(quotes map[string]models.Quote, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return pm.push(pushReport(quotes))
+}
+
+// SendAlerts pushes price alerts, at high priority for large moves
+func (pm *PushoverMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+	return pm.push(pushAlerts(alerts, pm.highPercent))
+}
+
+// SendText pushes an arbitrary text message
+func (pm *PushoverMessenger) SendText(text string, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return pm.push(pushNotification{message: text, priority: pushNormal})
+}
+
+// push posts a notification to the Pushover messages endpoint. Emergency priority needs acknowledgement
+// settings, so urgent notifications are sent at high priority
+func (pm *PushoverMessenger) push(notification pushNotification) error {
+	priority := map[pushPriority]string{pushLow: "-1", pushNormal: "0", pushHigh: "1", pushUrgent: "1"}[notification.priority]
+	form := url.Values{
+		"token":    {pm.token},
+		"user":     {pm.user},
+		"message":  {truncateRunes(notification.message, pushoverMaxMessage)},
+		"priority": {priority},
+	}
+	if notification.title != "" {
+		form.Set("title", truncateRunes(notification.title, pushoverMaxTitle))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.pushover.net/1/messages.json", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := pm.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessageSending, err)
+	}
+	defer resp.Body.Close()
+
+	slog.Debug("Pushover response", "priority", priority, "status", resp.Status)
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%w: %w", ErrMessageSending, newStatusError(resp))
+	}
+	return nil
+}
+
+// NtfyMessenger publishes notifications to an ntfy topic, on ntfy.sh or a self-hosted server
+type NtfyMessenger struct {
+	topicURL    string
+	token       string
+	highPercent float64
+	client      *http.Client
+}
+
+// NewNtfyMessenger creates an NtfyMessenger for a topic URL such as https://ntfy.sh/my-stocks; the access token
+// is only needed for protected topics. Alerts moving at least highPercent are sent at high priority
+func NewNtfyMessenger(topicURL, token string, highPercent float64) (*NtfyMessenger, error) {
+	if topicURL == "" {
+		return nil, ErrChatIDNotSet
+	}
+	return &NtfyMessenger{topicURL: topicURL, token: token, highPercent: highPercent, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+// SendMessage publishes the daily report quietly
+func (nm *NtfyMessenger) SendMessage(quotes map[string]models.Quote, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return nm.publish(pushReport(quotes))
+}
+
+// SendAlerts publishes price alerts, at high or urgent priority for large moves
+func (nm *NtfyMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+	return nm.publish(pushAlerts(alerts, nm.highPercent))
+}
+
+// SendText publishes an arbitrary text message
+func (nm *NtfyMessenger) SendText(text string, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return nm.publish(pushNotification{message: text, priority: pushNormal})
+}
+
+// publish posts a notification to the topic; the title is RFC 2047 encoded as headers only carry ASCII reliably
+func (nm *NtfyMessenger) publish(notification pushNotification) error {
+	message := notification.message
+	if len(message) > ntfyMaxMessage {
+		message = strings.ToValidUTF8(message[:ntfyMaxMessage-len("…")], "") + "…"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", nm.topicURL, bytes.NewBufferString(message))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+
+	// ntfy priorities run from 1 (min) to 5 (max); 3 is the default
+	priority := strconv.Itoa(int(notification.priority) + 2)
+	req.Header.Set("Priority", priority)
+	if notification.title != "" {
+		req.Header.Set("Title", mime.QEncoding.Encode("utf-8", notification.title))
+	}
+	if len(notification.tags) > 0 {
+		req.Header.Set("Tags", strings.Join(notification.tags, ","))
+	}
+	if nm.token != "" {
+		req.Header.Set("Authorization", "Bearer "+nm.token)
+	}
+
+	resp, err := nm.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessageSending, err)
+	}
+	defer resp.Body.Close()
+
+	slog.Debug("ntfy response", "priority", priority, "status", resp.Status)
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%w: %w", ErrMessageSending, newStatusError(resp))
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"testing"
+
+	"stock-bot/models"
+)
+
+func TestAlertPriority(t *testing.T) {
+	alerts := func(changes ...float64) []models.PriceAlert {
+		var list []models.PriceAlert
+		for _, change := range changes {
+			list = append(list, models.PriceAlert{Symbol: "AAPL", PercentChange: change})
+		}
+		return list
+	}
+
+	tests := []struct {
+		name        string
+		alerts      []models.PriceAlert
+		highPercent float64
+		want        pushPriority
+	}{
+		{"below the high percentage", alerts(5, -7.9), 8, pushNormal},
+		{"at the high percentage", alerts(8), 8, pushHigh},
+		{"drop counts like a rise", alerts(-9), 8, pushHigh},
+		{"largest move decides", alerts(1, -16, 3), 8, pushUrgent},
+		{"just under urgent", alerts(15.99), 8, pushHigh},
+		{"no alerts", nil, 8, pushNormal},
+		{"priorities off", alerts(50), 0, pushNormal},
+		{"negative high percentage", alerts(50), -1, pushNormal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := alertPriority(tt.alerts, tt.highPercent); got != tt.want {
+				t.Errorf("alertPriority = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+)
+
+// QuoteCache keeps recently fetched quotes for a short time
+type QuoteCache interface {
+	// Get returns the cached quote for a symbol if it has not expired
+	Get(symbol string) (models.Quote, bool)
+	// Set stores a quote in the cache
+	Set(quote models.Quote)
+}
+
+// MemoryQuoteCache keeps recently fetched quotes in memory for a short time
+type MemoryQuoteCache struct {
+	ttl    time.Duration
+	mu     sync.RWMutex
+	quotes map[string]models.Quote
+}
+
+// NewMemoryQuoteCache creates a new MemoryQuoteCache whose entries expire after ttl
+func NewMemoryQuoteCache(ttl time.Duration) *MemoryQuoteCache {
+	return &MemoryQuoteCache{
+		ttl:    ttl,
+		quotes: make(map[string]models.Quote),
+	}
+}
+
+// Get returns the cached quote for a symbol if it has not expired
+func (qc *MemoryQuoteCache) Get(symbol string) (models.Quote, bool) {
+	qc.mu.RLock()
+	defer qc.mu.RUnlock()
+
+	quote, ok := qc.quotes[strings.ToUpper(symbol)]
+	if !ok || time.Since(quote.Timestamp) > qc.ttl {
+		return models.Quote{}, false
+	}
+	return quote, true
+}
+
+// Set stores a quote in the cache
+func (qc *MemoryQuoteCache) Set(quote models.Quote) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	qc.quotes[strings.ToUpper(quote.Symbol)] = quote
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis related error definitions
+var (
+	ErrRedisConnection = errors.New("failed to connect to Redis")
+)
+
+// redisCacheTimeout bounds each cache lookup, so a slow Redis falls back to fetching instead of delaying it
+const redisCacheTimeout = 500 * time.Millisecond
+
+// RedisQuoteCache keeps the latest quote per symbol in Redis, shared by every instance and kept across restarts
+type RedisQuoteCache struct {
+	client *redis.Client
+	ttl    time.Duration
+	prefix string
+}
+
+// NewRedisQuoteCache connects to the Redis server at url (redis://host:6379/0) and returns a cache whose
+// entries expire after ttl
+func NewRedisQuoteCache(url string, ttl time.Duration) (*RedisQuoteCache, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRedisConnection, err)
+	}
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("%w: %v", ErrRedisConnection, err)
+	}
+
+	return &RedisQuoteCache{client: client, ttl: ttl, prefix: "stockbot:quote:"}, nil
+}
+
+// Get returns the cached quote for a symbol; errors are logged and treated as a miss
+func (rc *RedisQuoteCache) Get(symbol string) (models.Quote, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisCacheTimeout)
+	defer cancel()
+
+	data, err := rc.client.Get(ctx, rc.prefix+strings.ToUpper(symbol)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			slog.Warn("Error reading cached quote", "symbol", symbol, "error", err)
+		}
+		return models.Quote{}, false
+	}
+
+	var quote models.Quote
+	if err := json.Unmarshal(data, &quote); err != nil {
+		slog.Warn("Error decoding cached quote", "symbol", symbol, "error", err)
+		return models.Quote{}, false
+	}
+	return quote, true
+}
+
+// Set stores a quote until the cache lifetime passes
+func (rc *RedisQuoteCache) Set(quote models.Quote) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisCacheTimeout)
+	defer cancel()
+
+	data, err := json.Marshal(quote)
+	if err != nil {
+		return
+	}
+	if err := rc.client.Set(ctx, rc.prefix+strings.ToUpper(quote.Symbol), data, rc.ttl).Err(); err != nil {
+		slog.Warn("Error caching quote", "symbol", quote.Symbol, "error", err)
+	}
+}
+
+// Close closes the Redis connection
+func (rc *RedisQuoteCache) Close() error {
+	return rc.client.Close()
+}
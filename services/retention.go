@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// DeleteIntradayPricesBefore removes intraday prices recorded before the cutoff and returns how many were removed;
+// closing prices are never removed
+func (s *MongoStore) DeleteIntradayPricesBefore(cutoff time.Time) (int64, error) {
+	// A first run over a long history can delete many documents
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	collection := s.client.Database("stock_data").Collection("stocks")
+
+	filter := bson.D{
+		{Key: "isClosing", Value: false},
+		{Key: "timestamp", Value: bson.D{{Key: "$lt", Value: cutoff}}},
+	}
+	result, err := collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return result.DeletedCount, nil
+}
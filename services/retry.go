@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+)
+
+// Retry and circuit breaker settings for messenger sends
+const (
+	sendRetries       = 3                // Retries after the first attempt of a failed send
+	sendBackoff       = time.Second      // Wait before the first retry, doubled for each further one
+	sendMaxRetryAfter = 30 * time.Second // Longer waits asked for by a Retry-After header fail the send instead
+	breakerThreshold  = 5                // Consecutive failed sends that pause a channel
+	breakerCooldown   = 5 * time.Minute  // How long a paused channel rejects sends before trying again
+)
+
+// ErrChannelPaused is returned while a messaging channel is paused after repeated failures
+var ErrChannelPaused = errors.New("messaging channel paused after repeated failures")
+
+// circuitBreaker pauses a channel after consecutive failed sends, then lets a send through once the cooldown has passed
+type circuitBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// allow reports whether a send may be attempted
+func (cb *circuitBreaker) allow(now time.Time) bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return !now.Before(cb.openUntil)
+}
+
+// success closes the breaker
+func (cb *circuitBreaker) success() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.failures = 0
+	cb.openUntil = time.Time{}
+}
+
+// failure counts a failed send and reports whether it paused the channel
+func (cb *circuitBreaker) failure(now time.Time) bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.failures++
+	if cb.failures < breakerThreshold {
+		return false
+	}
+	cb.openUntil = now.Add(breakerCooldown)
+	return true
+}
+
+// RetryingMessenger retries failed sends of a messenger with exponential backoff, honoring Retry-After,
+// and pauses the channel after repeated failures; waits between attempts end when its context is cancelled
+type RetryingMessenger struct {
+	ctx     context.Context
+	name    string
+	inner   Messenger
+	breaker *circuitBreaker
+}
+
+// retryingChatMessenger is a RetryingMessenger around a messenger that addresses individual chats
+type retryingChatMessenger struct {
+	*RetryingMessenger
+}
+
+// NewRetryingMessenger wraps a messenger of the named channel; the result addresses individual chats
+// when the wrapped messenger does
+func NewRetryingMessenger(ctx context.Context, name string, inner Messenger) Messenger {
+	return wrapRetrying(ctx, name, inner, &circuitBreaker{})
+}
+
+// wrapRetrying wraps a messenger with a breaker shared by every chat of its channel
+func wrapRetrying(ctx context.Context, name string, inner Messenger, breaker *circuitBreaker) Messenger {
+	rm := &RetryingMessenger{ctx: ctx, name: name, inner: inner, breaker: breaker}
+	if _, ok := inner.(ChatMessenger); ok {
+		return retryingChatMessenger{rm}
+	}
+	return rm
+}
+
+// ForChat returns a retrying messenger for one chat, sharing the channel's breaker
+func (rc retryingChatMessenger) ForChat(chatID string) Messenger {
+	return wrapRetrying(rc.ctx, rc.name, rc.inner.(ChatMessenger).ForChat(chatID), rc.breaker)
+}
+
+// WithAlertStyle returns a retrying messenger with the alert style applied, when the wrapped messenger supports one
+func (rm *RetryingMessenger) WithAlertStyle(style models.AlertStyle) Messenger {
+	inner := rm.inner
+	if styler, ok := inner.(AlertStyler); ok {
+		inner = styler.WithAlertStyle(style)
+	}
+	return wrapRetrying(rm.ctx, rm.name, inner, rm.breaker)
+}
+
+// SendMessage sends the daily report, retrying transient failures
+func (rm *RetryingMessenger) SendMessage(quotes map[string]models.Quote, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return rm.send(func() error {
+		return rm.inner.SendMessage(quotes, nil)
+	})
+}
+
+// SendAlerts sends price alerts, retrying transient failures
+func (rm *RetryingMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return rm.send(func() error {
+		return rm.inner.SendAlerts(alerts, nil)
+	})
+}
+
+// SendText sends a text message, retrying transient failures
+func (rm *RetryingMessenger) SendText(text string, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return rm.send(func() error {
+		return rm.inner.SendText(text, nil)
+	})
+}
+
+// send runs a send until it succeeds, fails permanently or runs out of retries; only transient failures
+// count toward pausing the channel, as a rejected message says nothing about the channel's health
+func (rm *RetryingMessenger) send(send func() error) error {
+	if !rm.breaker.allow(time.Now()) {
+		return fmt.Errorf("%w: %s", ErrChannelPaused, rm.name)
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = send(); err == nil {
+			rm.breaker.success()
+			return nil
+		}
+
+		wait, transient := retryDelay(err, attempt)
+		if !transient {
+			return err
+		}
+		if attempt == sendRetries || wait > sendMaxRetryAfter {
+			break
+		}
+		slog.Warn("Retrying message send", "channel", rm.name, "attempt", attempt+1, "wait", wait, "error", err)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-rm.ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+
+	if rm.breaker.failure(time.Now()) {
+		slog.Error("Pausing messaging channel after repeated failures", "channel", rm.name, "cooldown", breakerCooldown, "error", err)
+	}
+	return err
+}
+
+// retryDelay reports whether a send error is transient, and how long to wait before the next attempt:
+// the API's Retry-After when given, otherwise an exponential backoff
+func retryDelay(err error, attempt int) (time.Duration, bool) {
+	backoff := sendBackoff << attempt
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		if statusErr.StatusCode != http.StatusTooManyRequests && statusErr.StatusCode < 500 {
+			return 0, false
+		}
+		if statusErr.RetryAfter > 0 {
+			return statusErr.RetryAfter, true
+		}
+		return backoff, true
+	}
+
+	// Anything else that failed in transit, such as a timeout or a dropped connection
+	if errors.Is(err, ErrMessageSending) {
+		return backoff, true
+	}
+	return 0, false
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		attempt       int
+		wantWait      time.Duration
+		wantTransient bool
+	}{
+		{"network failure", fmt.Errorf("%w: timeout", ErrMessageSending), 0, sendBackoff, true},
+		{"backoff doubles", fmt.Errorf("%w: timeout", ErrMessageSending), 2, 4 * sendBackoff, true},
+		{"server error", &StatusError{StatusCode: http.StatusServiceUnavailable}, 1, 2 * sendBackoff, true},
+		{"retry after", &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 7 * time.Second}, 0, 7 * time.Second, true},
+		{"wrapped status", fmt.Errorf("%w: %w", ErrMessageSending, &StatusError{StatusCode: http.StatusTooManyRequests}), 0, sendBackoff, true},
+		{"bad request", fmt.Errorf("%w: %w", ErrMessageSending, &StatusError{StatusCode: http.StatusBadRequest}), 0, 0, false},
+		{"forbidden", &StatusError{StatusCode: http.StatusForbidden}, 0, 0, false},
+		{"preparation", fmt.Errorf("%w: bad template", ErrMessagePreparation), 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, transient := retryDelay(tt.err, tt.attempt)
+			if wait != tt.wantWait || transient != tt.wantTransient {
+				t.Errorf("retryDelay = %v, %v, want %v, %v", wait, transient, tt.wantWait, tt.wantTransient)
+			}
+		})
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		failures  int
+		succeed   bool
+		at        time.Time
+		wantAllow bool
+	}{
+		{"closed", 0, false, now, true},
+		{"below the threshold", breakerThreshold - 1, false, now, true},
+		{"open", breakerThreshold, false, now, false},
+		{"open until the cooldown", breakerThreshold, false, now.Add(breakerCooldown - time.Second), false},
+		{"half open after the cooldown", breakerThreshold, false, now.Add(breakerCooldown), true},
+		{"closed by a success", breakerThreshold, true, now, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cb := &circuitBreaker{}
+			for i := 0; i < tt.failures; i++ {
+				opened := cb.failure(now)
+				if wantOpened := i+1 >= breakerThreshold; opened != wantOpened {
+					t.Fatalf("failure %d opened = %v, want %v", i+1, opened, wantOpened)
+				}
+			}
+			if tt.succeed {
+				cb.success()
+			}
+			if got := cb.allow(tt.at); got != tt.wantAllow {
+				t.Errorf("allow = %v, want %v", got, tt.wantAllow)
+			}
+		})
+	}
+}
+
+func TestNewStatusErrorRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		body   string
+		want   time.Duration
+	}{
+		{"header", "12", "", 12 * time.Second},
+		{"header wins over the body", "3", `{"parameters":{"retry_after":40}}`, 3 * time.Second},
+		{"telegram body", "", `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 5","parameters":{"retry_after":5}}`, 5 * time.Second},
+		{"no hint", "", `{"ok":false,"error_code":429}`, 0},
+		{"not json", "", "Too Many Requests", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(tt.body))}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+			if got := newStatusError(resp).RetryAfter; got != tt.want {
+				t.Errorf("RetryAfter = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryingMessengerStopsWaitingOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	inner := &countingMessenger{err: &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 20 * time.Second}}
+	rm := NewRetryingMessenger(ctx, "test", inner)
+
+	done := make(chan error, 1)
+	go func() { done <- rm.SendText("hello", nil) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) {
+			t.Errorf("SendText = %v, want the last send error", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("SendText kept waiting after its context was cancelled")
+	}
+	if inner.texts != 1 {
+		t.Errorf("sent %d times, want 1", inner.texts)
+	}
+}
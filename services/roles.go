@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// GetRole returns the role granted to a Telegram user, or an empty string when none was
+func (db *Database) GetRole(userID string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Without MongoDB only the configured admins have a role
+	collection, ok := db.optionalCollection("roles")
+	if !ok {
+		return "", nil
+	}
+
+	var role models.UserRole
+	err := collection.FindOne(ctx, bson.D{{Key: "userId", Value: userID}}).Decode(&role)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", nil
+		}
+		return "", fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return role.Role, nil
+}
+
+// SetRole grants a role to a Telegram user; roles are kept apart from the chat records, which are keyed by chat
+func (db *Database) SetRole(userID, role string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("roles")
+	if err != nil {
+		return err
+	}
+
+	record := models.UserRole{UserID: userID, Role: role, UpdatedAt: time.Now()}
+	filter := bson.D{{Key: "userId", Value: userID}}
+	if _, err := collection.ReplaceOne(ctx, filter, record, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Kinds of documents in the scheduler_state collection
+const (
+	stateKindJob   = "job"
+	stateKindAlert = "alert"
+	stateKindSMS   = "sms"
+)
+
+// localState keeps the scheduler state in process without MongoDB, so it holds until the next restart
+type localState struct {
+	mu       sync.Mutex
+	jobRuns  map[string]string
+	smsCount map[string]int // Texts claimed per day; this process is then the only one counting
+}
+
+// SaveJobRun records the date a scheduled job last ran
+func (db *Database) SaveJobRun(job, date string) error {
+	if !db.UsesMongo() {
+		db.local.mu.Lock()
+		defer db.local.mu.Unlock()
+		if db.local.jobRuns == nil {
+			db.local.jobRuns = make(map[string]string)
+		}
+		db.local.jobRuns[job] = date
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("scheduler_state")
+	if err != nil {
+		return err
+	}
+
+	filter := bson.D{{Key: "_id", Value: stateKindJob + ":" + job}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "kind", Value: stateKindJob},
+		{Key: "job", Value: job},
+		{Key: "date", Value: date},
+	}}}
+	if _, err := collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true)); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// GetJobRuns returns the date each scheduled job last ran, keyed by job name
+func (db *Database) GetJobRuns() (map[string]string, error) {
+	if !db.UsesMongo() {
+		db.local.mu.Lock()
+		defer db.local.mu.Unlock()
+		return maps.Clone(db.local.jobRuns), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("scheduler_state")
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := collection.Find(ctx, bson.D{{Key: "kind", Value: stateKindJob}})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	defer cursor.Close(ctx)
+
+	var runs []struct {
+		Job  string `bson:"job"`
+		Date string `bson:"date"`
+	}
+	if err := cursor.All(ctx, &runs); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	dates := make(map[string]string, len(runs))
+	for _, run := range runs {
+		dates[run.Job] = run.Date
+	}
+	return dates, nil
+}
+
+// ClaimSMS counts a text message about to be sent on a day if fewer than limit were counted, and reports whether it
+// was. The check and the increment are one update, so racing sends and instances can't exceed the limit
+func (db *Database) ClaimSMS(day string, limit int) (bool, error) {
+	if limit <= 0 {
+		return false, nil
+	}
+	if !db.UsesMongo() {
+		db.local.mu.Lock()
+		defer db.local.mu.Unlock()
+		if db.local.smsCount[day] >= limit {
+			return false, nil
+		}
+		// Only today's count matters, so earlier days are dropped
+		db.local.smsCount = map[string]int{day: db.local.smsCount[day] + 1}
+		return true, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("scheduler_state")
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: stateKindSMS + ":" + day},
+		{Key: "count", Value: bson.D{{Key: "$lt", Value: limit}}},
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{{Key: "kind", Value: stateKindSMS}, {Key: "date", Value: day}}},
+		{Key: "$inc", Value: bson.D{{Key: "count", Value: 1}}},
+	}
+	_, err = collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// The day's counter exists but is at the limit, so the upsert tried to insert a second one
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return true, nil
+}
+
+// ReleaseSMS uncounts a text message claimed for a day that wasn't sent
+func (db *Database) ReleaseSMS(day string) error {
+	if !db.UsesMongo() {
+		db.local.mu.Lock()
+		defer db.local.mu.Unlock()
+		if db.local.smsCount[day] > 0 {
+			db.local.smsCount[day]--
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("scheduler_state")
+	if err != nil {
+		return err
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: stateKindSMS + ":" + day},
+		{Key: "count", Value: bson.D{{Key: "$gt", Value: 0}}},
+	}
+	update := bson.D{{Key: "$inc", Value: bson.D{{Key: "count", Value: -1}}}}
+	if _, err := collection.UpdateOne(ctx, filter, update); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// SaveSentAlert records when a price alert for a symbol was sent to a chat
+func (db *Database) SaveSentAlert(alert models.SentAlert) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("scheduler_state")
+	if !ok {
+		return nil
+	}
+
+	key := alert.ChatID + "/" + alert.Symbol
+	if alert.Basis != models.BasisPreviousClose {
+		key += "@" + string(alert.Basis)
+	}
+	filter := bson.D{{Key: "_id", Value: stateKindAlert + ":" + key}}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "kind", Value: stateKindAlert},
+		{Key: "chatId", Value: alert.ChatID},
+		{Key: "symbol", Value: alert.Symbol},
+		{Key: "sentAt", Value: alert.SentAt},
+		{Key: "percentChange", Value: alert.PercentChange},
+		{Key: "basis", Value: alert.Basis},
+	}}}
+	if _, err := collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true)); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// GetSentAlerts returns the recorded price alerts
+func (db *Database) GetSentAlerts() ([]models.SentAlert, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("scheduler_state")
+	if !ok {
+		return nil, nil
+	}
+
+	cursor, err := collection.Find(ctx, bson.D{{Key: "kind", Value: stateKindAlert}})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	defer cursor.Close(ctx)
+
+	var alerts []models.SentAlert
+	if err := cursor.All(ctx, &alerts); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return alerts, nil
+}
+
+// DeleteSentAlertsBefore removes the price alerts recorded before the cutoff
+func (db *Database) DeleteSentAlertsBefore(cutoff time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("scheduler_state")
+	if !ok {
+		return nil
+	}
+
+	filter := bson.D{
+		{Key: "kind", Value: stateKindAlert},
+		{Key: "sentAt", Value: bson.D{{Key: "$lt", Value: cutoff}}},
+	}
+	if _, err := collection.DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
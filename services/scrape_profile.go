@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chromedp/chromedp"
+
+	"stock-bot/models"
+)
+
+// profileScriptTemplate reads each quote field with the first selector matching a non-empty element, without waiting
+// on optional elements; %s is the JSON object of selector lists
+const profileScriptTemplate = `((selectors) => {
+	const text = (list) => {
+		for (const selector of list || []) {
+			const el = document.querySelector(selector);
+			const value = el ? el.textContent.trim() : "";
+			if (value) return value;
+		}
+		return "";
+	};
+	return {
+		price: text(selectors.price),
+		change: text(selectors.change),
+		changePercent: text(selectors.changePercent),
+		volume: text(selectors.volume),
+	};
+})(%s)`
+
+// Scrape profile health settings
+const (
+	profileFailureLimit = 3         // Failures in a row for a symbol after which a profile is tried after the others for it
+	profileDemotion     = time.Hour // How long such a profile is tried last before it gets another chance to go first
+	profileMinAttempts  = 10        // Attempts before a profile's success rate is compared with the others'
+	profileRateMargin   = 0.1       // How much healthier a profile must be to be tried before one configured ahead of it
+	profileRateWeight   = 0.05      // Weight of the latest outcome in the success rate, so it follows recent health
+)
+
+// scrapeProfiles holds the profiles a scraper tries and how healthy each is. The list is swapped whole when the
+// config changes, so fetches in flight keep the list they started with
+type scrapeProfiles struct {
+	current atomic.Pointer[[]models.ScrapeProfile]
+
+	mu     sync.Mutex
+	health map[string]*profileHealth // By profile name
+	pages  map[pageKey]string        // Page that last had the quote, for profiles with several pages per symbol
+}
+
+// pageKey identifies a symbol's pages of one profile
+type pageKey struct {
+	profile string
+	symbol  string
+}
+
+// profileHealth tracks the outcomes of one profile's fetches
+type profileHealth struct {
+	attempts  int
+	successes int
+	rate      float64                    // Moving success rate, weighted towards recent fetches
+	failing   map[string]*symbolFailures // By symbol
+}
+
+// symbolFailures counts a profile's failures in a row for one symbol
+type symbolFailures struct {
+	count int
+	last  time.Time
+}
+
+// demoted reports whether a profile has failed for a symbol often and recently enough to be tried last
+func (h *profileHealth) demoted(symbol string) bool {
+	failures := h.failing[symbol]
+	return failures != nil && failures.count >= profileFailureLimit && time.Since(failures.last) < profileDemotion
+}
+
+// ScrapeProfileStats is the health of a scrape profile, for the status endpoint
+type ScrapeProfileStats struct {
+	Attempts       int      `json:"attempts"`
+	Successes      int      `json:"successes"`
+	SuccessRate    float64  `json:"successRate"`
+	FailingSymbols []string `json:"failingSymbols,omitempty"`
+}
+
+// newScrapeProfiles creates a profile list starting with the built-in Yahoo profile
+func newScrapeProfiles() *scrapeProfiles {
+	sp := &scrapeProfiles{health: make(map[string]*profileHealth), pages: make(map[pageKey]string)}
+	sp.set(models.DefaultScrapeProfiles())
+	return sp
+}
+
+// list returns the profiles in the order they are tried
+func (sp *scrapeProfiles) list() []models.ScrapeProfile {
+	return *sp.current.Load()
+}
+
+// set replaces the profiles; an empty list keeps the built-in ones
+func (sp *scrapeProfiles) set(profiles []models.ScrapeProfile) {
+	if len(profiles) == 0 {
+		profiles = models.DefaultScrapeProfiles()
+	}
+	sp.current.Store(&profiles)
+}
+
+// ordered returns the profiles in the order to try them for a symbol: profiles failing repeatedly for it go last, and
+// a profile with a clearly better success rate goes ahead of one configured before it
+func (sp *scrapeProfiles) ordered(symbol string) []models.ScrapeProfile {
+	profiles := slices.Clone(sp.list())
+
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	demoted := func(profile models.ScrapeProfile) bool {
+		health := sp.health[profile.Name]
+		return health != nil && health.demoted(symbol)
+	}
+	rate := func(profile models.ScrapeProfile) (float64, bool) {
+		health := sp.health[profile.Name]
+		if health == nil || health.attempts < profileMinAttempts {
+			return 0, false
+		}
+		return health.rate, true
+	}
+	slices.SortStableFunc(profiles, func(a, b models.ScrapeProfile) int {
+		if demotedA, demotedB := demoted(a), demoted(b); demotedA != demotedB {
+			if demotedA {
+				return 1
+			}
+			return -1
+		}
+		rateA, knownA := rate(a)
+		rateB, knownB := rate(b)
+		switch {
+		case !knownA || !knownB:
+			return 0
+		case rateA > rateB+profileRateMargin:
+			return -1
+		case rateB > rateA+profileRateMargin:
+			return 1
+		}
+		return 0
+	})
+	return profiles
+}
+
+// record notes the outcome of a profile's fetch for a symbol
+func (sp *scrapeProfiles) record(profile, symbol string, ok bool) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	health := sp.health[profile]
+	if health == nil {
+		health = &profileHealth{rate: 1, failing: make(map[string]*symbolFailures)}
+		sp.health[profile] = health
+	}
+	health.attempts++
+	outcome := 0.0
+	if ok {
+		health.successes++
+		outcome = 1
+		delete(health.failing, symbol)
+	} else {
+		failures := health.failing[symbol]
+		if failures == nil {
+			failures = &symbolFailures{}
+			health.failing[symbol] = failures
+		}
+		failures.count++
+		failures.last = time.Now()
+		if failures.count == profileFailureLimit {
+			slog.Warn("Scrape profile keeps failing for symbol, trying the others first", "profile", profile, "symbol", symbol)
+		}
+	}
+	health.rate += profileRateWeight * (outcome - health.rate)
+}
+
+// stats returns the health of every profile that has fetched
+func (sp *scrapeProfiles) stats() map[string]ScrapeProfileStats {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	stats := make(map[string]ScrapeProfileStats, len(sp.health))
+	for name, health := range sp.health {
+		var failing []string
+		for symbol := range health.failing {
+			if health.demoted(symbol) {
+				failing = append(failing, symbol)
+			}
+		}
+		slices.Sort(failing)
+		stats[name] = ScrapeProfileStats{
+			Attempts:       health.attempts,
+			Successes:      health.successes,
+			SuccessRate:    health.rate,
+			FailingSymbols: failing,
+		}
+	}
+	return stats
+}
+
+// fetchQuote reads a symbol's quote with each profile in turn until one succeeds, recording every outcome; fetch
+// reads the quote with a single profile
+func (sp *scrapeProfiles) fetchQuote(ctx context.Context, symbol string, fetch func(models.ScrapeProfile) (models.Quote, error)) (models.Quote, error) {
+	err := ErrElementNotFound
+	for _, profile := range sp.ordered(symbol) {
+		if len(profileURLs(profile, symbol)) == 0 {
+			continue
+		}
+
+		var quote models.Quote
+		quote, err = fetch(profile)
+		if ctx.Err() != nil {
+			// Running out of time says nothing about the profile
+			return models.Quote{}, err
+		}
+		sp.record(profile.Name, symbol, err == nil)
+		if err == nil {
+			return quote, nil
+		}
+		slog.Warn("Scrape profile failed", "profile", profile.Name, "symbol", symbol, "error", err)
+	}
+	return models.Quote{}, err
+}
+
+// priceSelectors returns the price selectors of every profile, for reading a page whose site isn't known
+func (sp *scrapeProfiles) priceSelectors() []string {
+	var selectors []string
+	for _, profile := range sp.list() {
+		selectors = append(selectors, profile.Price...)
+	}
+	return selectors
+}
+
+// profileURLs returns the page URLs a profile may have a symbol's quote on, most likely first; none when the profile
+// can't quote the symbol
+func profileURLs(profile models.ScrapeProfile, symbol string) []string {
+	if !strings.Contains(profile.URL, "{google}") {
+		return []string{strings.ReplaceAll(profile.URL, "{symbol}", url.PathEscape(symbol))}
+	}
+
+	var urls []string
+	for _, ticker := range models.GoogleFinanceTickers(symbol) {
+		page := strings.ReplaceAll(profile.URL, "{google}", url.PathEscape(ticker))
+		urls = append(urls, strings.ReplaceAll(page, "{symbol}", url.PathEscape(symbol)))
+	}
+	return urls
+}
+
+// fetchFirstURL reads a quote from each of a profile's pages for a symbol until one has it, starting with the page
+// that had it last time, so a NYSE listing isn't looked up on NASDAQ first on every fetch; fetch reads a single page
+func (sp *scrapeProfiles) fetchFirstURL(profile models.ScrapeProfile, symbol string, fetch func(page string) (models.Quote, error)) (models.Quote, error) {
+	key := pageKey{profile: profile.Name, symbol: symbol}
+	pages := profileURLs(profile, symbol)
+	sp.mu.Lock()
+	if i := slices.Index(pages, sp.pages[key]); i > 0 {
+		remembered := pages[i]
+		pages = append([]string{remembered}, slices.Delete(pages, i, i+1)...)
+	}
+	sp.mu.Unlock()
+
+	err := ErrElementNotFound
+	for _, page := range pages {
+		var quote models.Quote
+		quote, err = fetch(page)
+		if err == nil && len(pages) > 1 {
+			sp.mu.Lock()
+			sp.pages[key] = page
+			sp.mu.Unlock()
+		}
+		if err == nil || !errors.Is(err, ErrElementNotFound) {
+			return quote, err
+		}
+	}
+	return models.Quote{}, err
+}
+
+// profileScript builds the script reading a profile's quote fields in the browser
+func profileScript(profile models.ScrapeProfile) string {
+	selectors, _ := json.Marshal(map[string][]string{
+		"price":         profile.Price,
+		"change":        profile.Change,
+		"changePercent": profile.ChangePercent,
+		"volume":        profile.Volume,
+	})
+	return fmt.Sprintf(profileScriptTemplate, selectors)
+}
+
+// profileWait returns the action that waits until a profile's page can be read
+func profileWait(profile models.ScrapeProfile) chromedp.Action {
+	if delay, err := profile.WaitDelay(); err == nil && delay > 0 {
+		return chromedp.Sleep(delay)
+	}
+	if profile.Wait == models.WaitLoad {
+		return chromedp.WaitReady("body", chromedp.ByQuery)
+	}
+	// A selector list matches as soon as any of the price selectors does
+	return chromedp.WaitVisible(strings.Join(profile.Price, ", "), chromedp.ByQuery)
+}
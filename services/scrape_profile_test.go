@@ -0,0 +1,98 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"stock-bot/models"
+)
+
+func TestFetchFirstURLRemembersPage(t *testing.T) {
+	sp := newScrapeProfiles()
+	google := models.DefaultScrapeProfiles()[1]
+	nyse := "https://www.google.com/finance/quote/KO:NYSE"
+
+	var visited []string
+	fetch := func(page string) (models.Quote, error) {
+		visited = append(visited, page)
+		if page != nyse {
+			return models.Quote{}, ErrElementNotFound
+		}
+		return models.Quote{Symbol: "KO", Price: 60}, nil
+	}
+
+	for _, want := range []int{2, 1} {
+		visited = nil
+		if _, err := sp.fetchFirstURL(google, "KO", fetch); err != nil {
+			t.Fatalf("fetchFirstURL: %v", err)
+		}
+		if len(visited) != want || visited[len(visited)-1] != nyse {
+			t.Errorf("visited %v, want %d pages ending with the NYSE one", visited, want)
+		}
+	}
+}
+
+func TestScrapeProfilesOrdered(t *testing.T) {
+	failTimes := func(sp *scrapeProfiles, profile, symbol string, n int) {
+		for range n {
+			sp.record(profile, symbol, false)
+		}
+	}
+	// Outcomes on other symbols move a profile's success rate without demoting it for AAPL
+	rateOf := func(sp *scrapeProfiles, profile string, successes, failures int) {
+		for i := range successes {
+			sp.record(profile, fmt.Sprintf("S%d", i), true)
+		}
+		for i := range failures {
+			sp.record(profile, fmt.Sprintf("F%d", i), false)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		setup func(sp *scrapeProfiles)
+		want  []string
+	}{
+		{"configured order", func(sp *scrapeProfiles) {}, []string{"yahoo", "google"}},
+		{"failing for the symbol", func(sp *scrapeProfiles) { failTimes(sp, "yahoo", "AAPL", profileFailureLimit) }, []string{"google", "yahoo"}},
+		{"too few failures", func(sp *scrapeProfiles) { failTimes(sp, "yahoo", "AAPL", profileFailureLimit-1) }, []string{"yahoo", "google"}},
+		{"failing for another symbol", func(sp *scrapeProfiles) { failTimes(sp, "yahoo", "MSFT", profileFailureLimit) }, []string{"yahoo", "google"}},
+		{"demotion expired", func(sp *scrapeProfiles) {
+			failTimes(sp, "yahoo", "AAPL", profileFailureLimit)
+			sp.health["yahoo"].failing["AAPL"].last = time.Now().Add(-profileDemotion)
+		}, []string{"yahoo", "google"}},
+		{"both failing keeps the order", func(sp *scrapeProfiles) {
+			failTimes(sp, "yahoo", "AAPL", profileFailureLimit)
+			failTimes(sp, "google", "AAPL", profileFailureLimit)
+		}, []string{"yahoo", "google"}},
+		{"clearly healthier", func(sp *scrapeProfiles) {
+			rateOf(sp, "yahoo", 0, profileMinAttempts)
+			rateOf(sp, "google", profileMinAttempts, 0)
+		}, []string{"google", "yahoo"}},
+		{"healthier within the margin", func(sp *scrapeProfiles) {
+			rateOf(sp, "yahoo", profileMinAttempts-1, 1)
+			rateOf(sp, "google", profileMinAttempts, 0)
+		}, []string{"yahoo", "google"}},
+		{"too few attempts to compare", func(sp *scrapeProfiles) {
+			rateOf(sp, "yahoo", 0, profileMinAttempts-1)
+			rateOf(sp, "google", profileMinAttempts, 0)
+		}, []string{"yahoo", "google"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sp := newScrapeProfiles()
+			tt.setup(sp)
+
+			var names []string
+			for _, profile := range sp.ordered("AAPL") {
+				names = append(names, profile.Name)
+			}
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("ordered(AAPL) = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Words that lean a financial headline positive or negative
+var (
+	positiveHeadlineWords = map[string]bool{
+		"beat": true, "beats": true, "surge": true, "surges": true, "soar": true, "soars": true,
+		"jump": true, "jumps": true, "rally": true, "rallies": true, "gain": true, "gains": true,
+		"record": true, "upgrade": true, "upgraded": true, "outperform": true, "bullish": true,
+		"growth": true, "raises": true, "strong": true, "profit": true, "buyback": true, "approval": true,
+	}
+	negativeHeadlineWords = map[string]bool{
+		"miss": true, "misses": true, "plunge": true, "plunges": true, "sink": true, "sinks": true,
+		"fall": true, "falls": true, "drop": true, "drops": true, "slump": true, "downgrade": true,
+		"downgraded": true, "underperform": true, "bearish": true, "cut": true, "cuts": true, "weak": true,
+		"loss": true, "lawsuit": true, "probe": true, "recall": true, "layoffs": true, "warning": true,
+	}
+)
+
+// HeadlineSentiment scores news headlines from -1 (negative) to 1 (positive) by counting loaded words;
+// it reports false when no headline contains any
+func HeadlineSentiment(headlines []string) (float64, bool) {
+	positive, negative := 0, 0
+	for _, headline := range headlines {
+		words := strings.FieldsFunc(strings.ToLower(headline), func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})
+		for _, word := range words {
+			switch {
+			case positiveHeadlineWords[word]:
+				positive++
+			case negativeHeadlineWords[word]:
+				negative++
+			}
+		}
+	}
+
+	if positive+negative == 0 {
+		return 0, false
+	}
+	return float64(positive-negative) / float64(positive+negative), true
+}
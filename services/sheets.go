@@ -0,0 +1,134 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"stock-bot/models"
+
+	"golang.org/x/oauth2/jwt"
+)
+
+// Error definitions for Google Sheets export
+var (
+	ErrSheetsCredentials = errors.New("invalid Google service account credentials")
+	ErrSheetsExport      = errors.New("failed to append to Google Sheet")
+)
+
+// Sheet tabs rows are appended to
+const (
+	sheetCloses = "Closes"
+	sheetAlerts = "Alerts"
+)
+
+// serviceAccountKey holds the fields of a Google service account JSON key used for token signing
+type serviceAccountKey struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// SheetsExporter appends daily closes and alerts to a Google Sheet
+type SheetsExporter struct {
+	client        *http.Client
+	spreadsheetID string
+}
+
+// NewSheetsExporter creates a SheetsExporter authenticated with a service account key file
+func NewSheetsExporter(ctx context.Context, credentialsFile, spreadsheetID string) (*SheetsExporter, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSheetsCredentials, err)
+	}
+
+	var key serviceAccountKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSheetsCredentials, err)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, fmt.Errorf("%w: missing client_email or private_key", ErrSheetsCredentials)
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	config := &jwt.Config{
+		Email:      key.ClientEmail,
+		PrivateKey: []byte(key.PrivateKey),
+		TokenURL:   key.TokenURI,
+		Scopes:     []string{"https://www.googleapis.com/auth/spreadsheets"},
+	}
+
+	client := config.Client(ctx)
+	client.Timeout = 15 * time.Second
+
+	return &SheetsExporter{
+		client:        client,
+		spreadsheetID: spreadsheetID,
+	}, nil
+}
+
+// AppendCloses appends one row per symbol with the day's closing price
+func (se *SheetsExporter) AppendCloses(ctx context.Context, date time.Time, quotes map[string]models.Quote) error {
+	rows := make([][]interface{}, 0, len(quotes))
+	for _, symbol := range models.Tickers() {
+		if quote, ok := quotes[symbol]; ok {
+			rows = append(rows, []interface{}{date.Format("2006-01-02"), symbol, quote.Price})
+		}
+	}
+	return se.appendRows(ctx, sheetCloses, rows)
+}
+
+// AppendAlerts appends one row per price alert
+func (se *SheetsExporter) AppendAlerts(ctx context.Context, alerts []models.PriceAlert) error {
+	rows := make([][]interface{}, 0, len(alerts))
+	for _, alert := range alerts {
+		rows = append(rows, []interface{}{
+			alert.Timestamp.Format("2006-01-02 15:04:05"),
+			alert.Symbol,
+			alert.PreviousPrice,
+			alert.CurrentPrice,
+			fmt.Sprintf("%.2f%%", alert.PercentChange),
+		})
+	}
+	return se.appendRows(ctx, sheetAlerts, rows)
+}
+
+// appendRows adds rows after the last row of a sheet tab
+func (se *SheetsExporter) appendRows(ctx context.Context, sheet string, rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"values": rows})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSheetsExport, err)
+	}
+
+	endpoint := fmt.Sprintf("https://sheets.googleapis.com/v4/spreadsheets/%s/values/%s:append?valueInputOption=USER_ENTERED&insertDataOption=INSERT_ROWS",
+		url.PathEscape(se.spreadsheetID), url.PathEscape(sheet+"!A1"))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSheetsExport, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := se.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSheetsExport, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%w: %s received status code %d", ErrSheetsExport, sheet, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Error definitions for short interest data
+var (
+	ErrShortInterestUnavailable = errors.New("short interest data unavailable")
+)
+
+// ShortInterestFetcher downloads biweekly short interest data from the Nasdaq quote API
+type ShortInterestFetcher struct {
+	client *http.Client
+}
+
+// nasdaqShortInterestResponse is the subset of the Nasdaq short interest response used by the bot
+type nasdaqShortInterestResponse struct {
+	Data *struct {
+		ShortInterestTable *struct {
+			Rows []struct {
+				SettlementDate      string      `json:"settlementDate"`
+				Interest            string      `json:"interest"`
+				AvgDailyShareVolume string      `json:"avgDailyShareVolume"`
+				DaysToCover         json.Number `json:"daysToCover"`
+			} `json:"rows"`
+		} `json:"shortInterestTable"`
+	} `json:"data"`
+}
+
+// NewShortInterestFetcher creates a new ShortInterestFetcher instance
+func NewShortInterestFetcher() *ShortInterestFetcher {
+	return &ShortInterestFetcher{
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// Fetch returns the published short interest history for a symbol, newest first
+func (sf *ShortInterestFetcher) Fetch(ctx context.Context, symbol string) ([]models.ShortInterest, error) {
+	finraSymbol, ok := models.ShortInterestSymbol(symbol)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a US listing", ErrShortInterestUnavailable, symbol)
+	}
+	endpoint := fmt.Sprintf("https://api.nasdaq.com/api/quote/%s/short-interest?assetClass=stocks", url.PathEscape(finraSymbol))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrShortInterestUnavailable, err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; stock-bot)")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := sf.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrShortInterestUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("%w: received status code %d", ErrShortInterestUnavailable, resp.StatusCode)
+	}
+
+	var body nasdaqShortInterestResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrShortInterestUnavailable, err)
+	}
+	if body.Data == nil || body.Data.ShortInterestTable == nil {
+		return nil, fmt.Errorf("%w: no data for %s", ErrShortInterestUnavailable, symbol)
+	}
+
+	var records []models.ShortInterest
+	for _, row := range body.Data.ShortInterestTable.Rows {
+		settlement, err := time.Parse("01/02/2006", row.SettlementDate)
+		if err != nil {
+			continue
+		}
+		interest, err := ParsePrice(row.Interest)
+		if err != nil {
+			continue
+		}
+
+		record := models.ShortInterest{
+			Symbol:         symbol,
+			SettlementDate: settlement,
+			ShortInterest:  int64(interest),
+			FetchedAt:      time.Now(),
+		}
+		if volume, err := ParsePrice(row.AvgDailyShareVolume); err == nil {
+			record.AvgDailyVolume = int64(volume)
+		}
+		if daysToCover, err := row.DaysToCover.Float64(); err == nil {
+			record.DaysToCover = daysToCover
+		}
+		records = append(records, record)
+	}
+
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: no rows for %s", ErrShortInterestUnavailable, symbol)
+	}
+	return records, nil
+}
+
+// SaveShortInterest upserts short interest records keyed by symbol and settlement date
+func (db *Database) SaveShortInterest(records []models.ShortInterest) (int, error) {
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("short_interest")
+	if err != nil {
+		return 0, err
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(records))
+	for _, record := range records {
+		filter := bson.D{
+			{Key: "symbol", Value: record.Symbol},
+			{Key: "settlementDate", Value: record.SettlementDate},
+		}
+		writes = append(writes, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(record).SetUpsert(true))
+	}
+
+	result, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	slog.Info("Saved new short interest records", "symbol", records[0].Symbol, "count", result.UpsertedCount)
+	return int(result.UpsertedCount), nil
+}
+
+// GetShortInterestHistory returns the most recent short interest records for a symbol, newest first
+func (db *Database) GetShortInterestHistory(symbol string, limit int) ([]models.ShortInterest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("short_interest")
+	if !ok {
+		return nil, nil
+	}
+
+	filter := bson.D{{Key: "symbol", Value: symbol}}
+	opts := options.Find().SetSort(bson.D{{Key: "settlementDate", Value: -1}}).SetLimit(int64(limit))
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []models.ShortInterest
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"stock-bot/indicators"
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Signal scoring constants
+const (
+	signalMinCloses     = 20  // Closes needed before a signal is evaluated
+	signalBuyThreshold  = 0.3 // Composite score at or above which a signal is a buy
+	signalSellThreshold = -0.3
+	signalRSIPeriod     = 14
+)
+
+// EvaluateSignal combines trend, RSI, volume and headline sentiment into a composite signal;
+// it reports false when there is not enough history
+func EvaluateSignal(symbol string, points []models.PricePoint, headlines []string, weights models.SignalWeights, now time.Time) (models.Signal, bool) {
+	if len(points) < signalMinCloses {
+		return models.Signal{}, false
+	}
+
+	closes := make([]float64, len(points))
+	volumes := make([]float64, len(points))
+	for i, point := range points {
+		closes[i] = point.Close
+		volumes[i] = float64(point.Volume)
+	}
+	last := closes[len(closes)-1]
+
+	signal := models.Signal{
+		Symbol:     symbol,
+		Date:       points[len(points)-1].Timestamp.Format("2006-01-02"),
+		Price:      last,
+		Components: make(map[string]float64),
+		CreatedAt:  now,
+	}
+
+	// Trend: price above its 20-day average, and the 20-day above the 50-day when available
+	if sma20, ok := indicators.SMA(closes, 20); ok {
+		trend := clampScore((last - sma20) / sma20 * 20)
+		if sma50, ok := indicators.SMA(closes, 50); ok {
+			trend = (trend + clampScore((sma20-sma50)/sma50*20)) / 2
+		}
+		signal.Components[models.SignalTrend] = trend
+	}
+
+	// RSI: oversold readings lean buy, overbought readings lean sell
+	if rsi, ok := indicators.RSI(closes, signalRSIPeriod); ok {
+		signal.RSI = rsi
+		signal.Components[models.SignalRSI] = clampScore((50 - rsi) / 20)
+	}
+
+	// Volume: above-average volume confirms the direction of the last move
+	if avg, ok := indicators.SMA(volumes[:len(volumes)-1], 20); ok && avg > 0 {
+		ratio := volumes[len(volumes)-1] / avg
+		volume := 0.0
+		if ratio > 1 {
+			volume = math.Copysign(clampScore(ratio-1), last-closes[len(closes)-2])
+		}
+		signal.Components[models.SignalVolume] = volume
+	}
+
+	if sentiment, ok := HeadlineSentiment(headlines); ok {
+		signal.Components[models.SignalSentiment] = sentiment
+	}
+
+	// Weight only the components that could be computed
+	var total, weightSum float64
+	for name, score := range signal.Components {
+		total += score * weights[name]
+		weightSum += weights[name]
+	}
+	if weightSum > 0 {
+		signal.Score = total / weightSum
+	}
+
+	switch {
+	case signal.Score >= signalBuyThreshold:
+		signal.Action = models.SignalBuy
+	case signal.Score <= signalSellThreshold:
+		signal.Action = models.SignalSell
+	default:
+		signal.Action = models.SignalWatch
+	}
+	return signal, true
+}
+
+// clampScore limits a score to the range -1 to 1
+func clampScore(v float64) float64 {
+	return math.Max(-1, math.Min(1, v))
+}
+
+// SaveSignals stores evaluated signals, replacing any earlier evaluation of the same symbol and day
+func (db *Database) SaveSignals(signals []models.Signal) error {
+	if len(signals) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("signals")
+	if !ok {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(signals))
+	for _, signal := range signals {
+		filter := bson.D{{Key: "symbol", Value: signal.Symbol}, {Key: "date", Value: signal.Date}}
+		writes = append(writes, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(signal).SetUpsert(true))
+	}
+
+	if _, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/i18n"
+	"stock-bot/models"
+)
+
+// slackFieldsPerSection is the most fields Block Kit allows in one section block
+const slackFieldsPerSection = 10
+
+// Attachment colors for rising and falling prices
+const (
+	slackColorUp   = "#2eb886"
+	slackColorDown = "#e01e5a"
+)
+
+// SlackMessenger posts Block Kit messages to Slack through an incoming webhook or chat.postMessage
+type SlackMessenger struct {
+	webhookURL string
+	token      string
+	channel    string
+	alertStyle models.AlertStyle
+	printer    *i18n.Printer
+	client     *http.Client
+}
+
+// NewSlackMessenger creates a SlackMessenger; a webhook URL is used if given, otherwise a bot token and channel
+func NewSlackMessenger(webhookURL, token, channel string) (*SlackMessenger, error) {
+	if webhookURL == "" && token == "" {
+		return nil, ErrTokenNotSet
+	}
+	if webhookURL == "" && channel == "" {
+		return nil, ErrChatIDNotSet
+	}
+	return &SlackMessenger{
+		webhookURL: webhookURL,
+		token:      token,
+		channel:    channel,
+		alertStyle: models.AlertStandard,
+		printer:    i18n.NewPrinter(i18n.English),
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// SetAlertStyle selects how much detail alerts carry
+func (sm *SlackMessenger) SetAlertStyle(style models.AlertStyle) {
+	sm.alertStyle = style
+}
+
+// SetLanguage selects the language of report and alert labels
+func (sm *SlackMessenger) SetLanguage(language i18n.Language) {
+	sm.printer = i18n.NewPrinter(language)
+}
+
+// SendMessage posts the daily report as sections with one field per symbol
+func (sm *SlackMessenger) SendMessage(quotes map[string]models.Quote, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	symbols := make([]string, 0, len(quotes))
+	for symbol := range quotes {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	title := sm.printer.Text("📊 Daily Stock Report")
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]string{"type": "plain_text", "text": title},
+		},
+	}
+	for start := 0; start < len(symbols); start += slackFieldsPerSection {
+		end := min(start+slackFieldsPerSection, len(symbols))
+		var fields []map[string]string
+		for _, symbol := range symbols[start:end] {
+			fields = append(fields, map[string]string{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("*%s*\n%s", slackEscape(symbol), slackEscape(models.FormatQuote(quotes[symbol]))),
+			})
+		}
+		blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
+	}
+
+	return sm.post(map[string]interface{}{
+		"text":   title,
+		"blocks": blocks,
+	}, "report")
+}
+
+// SendAlerts posts one colored attachment per alert, green for rises and red for drops
+func (sm *SlackMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	if sm.alertStyle == models.AlertCompact {
+		return sm.post(map[string]interface{}{"text": slackEscape(formatCompactAlerts(alerts))}, "alert")
+	}
+
+	attachments := make([]map[string]interface{}, 0, len(alerts))
+	for _, alert := range alerts {
+		color, direction := slackColorDown, sm.printer.Text("🔴 Decreased")
+		if alert.PercentChange > 0 {
+			color, direction = slackColorUp, sm.printer.Text("🟢 Increased")
+		}
+
+		var text strings.Builder
+		text.WriteString(sm.printer.Sprintf("*%s*: %s by *%.2f%%*\n%s: %s → Current: %s\n",
+			slackEscape(alert.Symbol),
+			direction,
+			alert.PercentChange,
+			referenceLabel(alert, sm.printer),
+			models.FormatPrice(alert.Symbol, alert.PreviousPrice),
+			models.FormatPrice(alert.Symbol, alert.CurrentPrice),
+		))
+		if sm.alertStyle == models.AlertVerbose {
+			var details strings.Builder
+			writeAlertDetails(&details, alert, "", sm.printer)
+			text.WriteString(slackEscape(details.String()))
+		}
+
+		attachments = append(attachments, map[string]interface{}{
+			"color": color,
+			"blocks": []map[string]interface{}{
+				{
+					"type": "section",
+					"text": map[string]string{"type": "mrkdwn", "text": text.String()},
+				},
+			},
+		})
+	}
+
+	return sm.post(map[string]interface{}{
+		"text":        sm.printer.Text("⚠️ Significant Price Changes Detected"),
+		"attachments": attachments,
+	}, "alert")
+}
+
+// SendText posts an arbitrary text message
+func (sm *SlackMessenger) SendText(text string, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	return sm.post(map[string]interface{}{"text": slackEscape(text)}, "text")
+}
+
+// slackAPIResponse is the status part of a Slack Web API response
+type slackAPIResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// post sends a message payload to the webhook, or to chat.postMessage for the configured channel
+func (sm *SlackMessenger) post(payload map[string]interface{}, label string) error {
+	endpoint := sm.webhookURL
+	if endpoint == "" {
+		endpoint = "https://slack.com/api/chat.postMessage"
+		payload["channel"] = sm.channel
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if sm.webhookURL == "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", sm.token))
+	}
+
+	resp, err := sm.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessageSending, err)
+	}
+	defer resp.Body.Close()
+
+	slog.Debug("Slack response", "kind", label, "status", resp.Status)
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%w: %w", ErrMessageSending, newStatusError(resp))
+	}
+
+	// The Web API reports failures in the body with status 200
+	if sm.webhookURL == "" {
+		var result slackAPIResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return fmt.Errorf("%w: %v", ErrMessageSending, err)
+		}
+		if !result.OK {
+			return fmt.Errorf("%w: %s", ErrMessageRejected, result.Error)
+		}
+	}
+
+	return nil
+}
+
+// slackEscape escapes the characters Slack treats as control sequences in message text
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
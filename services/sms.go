@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+)
+
+// twilioMaxBody is the longest SMS body sent; Twilio splits longer bodies into segments billed one by one
+const twilioMaxBody = 320
+
+// SMSCounter counts the text messages sent each day, so the daily cap holds across restarts and instances
+type SMSCounter interface {
+	// ClaimSMS takes one of the limit texts of a day (YYYY-MM-DD) in a single step and reports whether one was left
+	ClaimSMS(day string, limit int) (bool, error)
+	// ReleaseSMS hands back a text claimed for a day that wasn't sent
+	ReleaseSMS(day string) error
+}
+
+// TwilioMessenger texts critical price alerts through Twilio; reports and other messages are left to the
+// other channels
+type TwilioMessenger struct {
+	accountSID      string
+	authToken       string
+	from            string
+	to              []string
+	criticalPercent float64
+	dailyCap        int
+	location        *time.Location
+	counter         SMSCounter
+	client          *http.Client
+}
+
+// NewTwilioMessenger creates a TwilioMessenger texting alerts that moved at least criticalPercent to each
+// number in to, sending no more than dailyCap texts a day in the location's calendar
+func NewTwilioMessenger(config models.TwilioConfig, location *time.Location, counter SMSCounter) (*TwilioMessenger, error) {
+	if config.AccountSID == "" || config.AuthToken == "" {
+		return nil, ErrTokenNotSet
+	}
+	if config.From == "" || len(config.To) == 0 {
+		return nil, ErrChatIDNotSet
+	}
+	return &TwilioMessenger{
+		accountSID:      config.AccountSID,
+		authToken:       config.AuthToken,
+		from:            config.From,
+		to:              config.To,
+		criticalPercent: config.CriticalPercent,
+		dailyCap:        config.DailyCap,
+		location:        location,
+		counter:         counter,
+		client:          &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// SendMessage leaves the daily report to the other channels
+func (tm *TwilioMessenger) SendMessage(quotes map[string]models.Quote, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return nil
+}
+
+// SkipsOutbox keeps texts out of the outbox: each retry is billed again, and a critical alert texted late is
+// worse than none
+func (tm *TwilioMessenger) SkipsOutbox() bool {
+	return true
+}
+
+// SendText leaves notices to the other channels
+func (tm *TwilioMessenger) SendText(text string, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+	return nil
+}
+
+// SendAlerts texts the alerts that reached the critical threshold to every number, within the daily cap.
+// Texts cost money, so a number that failed is logged and the send only fails when no text went out,
+// keeping retries from texting the others twice
+func (tm *TwilioMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	var critical []models.PriceAlert
+	for _, alert := range alerts {
+		if math.Abs(alert.PercentChange) >= tm.criticalPercent {
+			critical = append(critical, alert)
+		}
+	}
+	if len(critical) == 0 {
+		return nil
+	}
+
+	body := formatCompactAlerts(critical)
+	if runes := []rune(body); len(runes) > twilioMaxBody {
+		body = string(runes[:twilioMaxBody-1]) + "…"
+	}
+
+	day := time.Now().In(tm.location).Format("2006-01-02")
+	var sent int
+	var lastErr error
+	for _, to := range tm.to {
+		// A slot is claimed before sending, so concurrent sends and other instances can't overshoot the cap
+		claimed, err := tm.counter.ClaimSMS(day, tm.dailyCap)
+		if err != nil {
+			return fmt.Errorf("%w: counting texts: %v", ErrMessagePreparation, err)
+		}
+		if !claimed {
+			slog.Warn("Daily SMS cap reached, critical alert not texted", "cap", tm.dailyCap, "to", to)
+			continue
+		}
+
+		if err := tm.send(to, body); err != nil {
+			slog.Error("Error sending SMS", "to", to, "error", err)
+			lastErr = err
+
+			// Only texts that went out count towards the cap
+			if err := tm.counter.ReleaseSMS(day); err != nil {
+				slog.Error("Error releasing unsent SMS", "to", to, "error", err)
+			}
+			continue
+		}
+		sent++
+	}
+
+	if sent == 0 && lastErr != nil {
+		return lastErr
+	}
+	return nil
+}
+
+// send texts a body to one number through the Twilio Messages API
+func (tm *TwilioMessenger) send(to, body string) error {
+	form := url.Values{"To": {to}, "From": {tm.from}, "Body": {body}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	endpoint := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json", url.PathEscape(tm.accountSID))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(tm.accountSID, tm.authToken)
+
+	resp, err := tm.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessageSending, err)
+	}
+	defer resp.Body.Close()
+
+	slog.Debug("Twilio response", "status", resp.Status)
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%w: %w", ErrMessageSending, newStatusError(resp))
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stock-bot/models"
+)
+
+// memSMSCounter counts texts in memory
+type memSMSCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func newMemSMSCounter() *memSMSCounter {
+	return &memSMSCounter{counts: make(map[string]int)}
+}
+
+func (c *memSMSCounter) ClaimSMS(day string, limit int) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts[day] >= limit {
+		return false, nil
+	}
+	c.counts[day]++
+	return true, nil
+}
+
+func (c *memSMSCounter) ReleaseSMS(day string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts[day] > 0 {
+		c.counts[day]--
+	}
+	return nil
+}
+
+// twilioReplies answers Twilio requests with the status set for each number
+type twilioReplies map[string]int
+
+func (r twilioReplies) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.ParseForm(); err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: r[req.PostForm.Get("To")], Body: io.NopCloser(strings.NewReader("{}")), Header: http.Header{}}, nil
+}
+
+func TestTwilioMessengerCountsSentTexts(t *testing.T) {
+	alerts := []models.PriceAlert{{Symbol: "AAPL", PercentChange: -12, CurrentPrice: 150}}
+
+	tests := []struct {
+		name      string
+		replies   twilioReplies
+		sentToday int
+		dailyCap  int
+		wantCount int
+		wantErr   bool
+	}{
+		{"all sent", twilioReplies{"+1": 201, "+2": 201}, 0, 10, 2, false},
+		{"failed text isn't counted", twilioReplies{"+1": 201, "+2": 500}, 0, 10, 1, false},
+		{"every text failed", twilioReplies{"+1": 500, "+2": 500}, 0, 10, 0, true},
+		{"cap reached part way", twilioReplies{"+1": 201, "+2": 201}, 9, 10, 10, false},
+		{"cap already reached", twilioReplies{"+1": 201, "+2": 201}, 10, 10, 10, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			day := time.Now().UTC().Format("2006-01-02")
+			counter := newMemSMSCounter()
+			counter.counts[day] = tt.sentToday
+			tm, err := NewTwilioMessenger(models.TwilioConfig{
+				AccountSID: "AC1", AuthToken: "token", From: "+0", To: []string{"+1", "+2"}, CriticalPercent: 10, DailyCap: tt.dailyCap,
+			}, time.UTC, counter)
+			if err != nil {
+				t.Fatalf("NewTwilioMessenger: %v", err)
+			}
+			tm.client = &http.Client{Transport: tt.replies}
+
+			if err := tm.SendAlerts(alerts, nil); (err != nil) != tt.wantErr {
+				t.Fatalf("SendAlerts error = %v, want error %v", err, tt.wantErr)
+			}
+			if counter.counts[day] != tt.wantCount {
+				t.Errorf("count = %d, want %d", counter.counts[day], tt.wantCount)
+			}
+		})
+	}
+}
+
+// countingTwilio accepts every text and counts the ones it was sent
+type countingTwilio struct{ sent atomic.Int32 }
+
+func (c *countingTwilio) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.sent.Add(1)
+	return &http.Response{StatusCode: 201, Body: io.NopCloser(strings.NewReader("{}")), Header: http.Header{}}, nil
+}
+
+func TestTwilioMessengerHoldsCapUnderConcurrentSends(t *testing.T) {
+	alerts := []models.PriceAlert{{Symbol: "AAPL", PercentChange: -12, CurrentPrice: 150}}
+	counter := newMemSMSCounter()
+	tm, err := NewTwilioMessenger(models.TwilioConfig{
+		AccountSID: "AC1", AuthToken: "token", From: "+0", To: []string{"+1"}, CriticalPercent: 10, DailyCap: 3,
+	}, time.UTC, counter)
+	if err != nil {
+		t.Fatalf("NewTwilioMessenger: %v", err)
+	}
+	transport := &countingTwilio{}
+	tm.client = &http.Client{Transport: transport}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = tm.SendAlerts(alerts, nil)
+		}()
+	}
+	wg.Wait()
+
+	if got := transport.sent.Load(); got != 3 {
+		t.Errorf("sent %d texts, want 3", got)
+	}
+}
+
+func TestDeliverySkipsOutboxForSMS(t *testing.T) {
+	tm, err := NewTwilioMessenger(models.TwilioConfig{AccountSID: "AC1", AuthToken: "token", From: "+0", To: []string{"+1"}, CriticalPercent: 10}, time.UTC, newMemSMSCounter())
+	if err != nil {
+		t.Fatalf("NewTwilioMessenger: %v", err)
+	}
+	store := newMemOutbox()
+	d := NewDelivery(tm, NewStoreDatabase(nil), "")
+	d.SetOutbox(store)
+
+	if err := d.SendText("Market closed early", nil); err != nil {
+		t.Fatalf("SendText: %v", err)
+	}
+	if len(store.messages) != 0 {
+		t.Errorf("outbox holds %d messages, want none for SMS", len(store.messages))
+	}
+}
@@ -0,0 +1,401 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLite related error definitions
+var (
+	ErrSQLiteOpen        = errors.New("failed to open SQLite database")
+	ErrSQLiteQueryFailed = errors.New("SQLite query failed")
+)
+
+// sqliteSchema creates the price and watchlist tables and their indexes when they don't exist yet;
+// timestamps are Unix milliseconds, and the partial unique index keeps one historical close per symbol and day
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS prices (
+	id         INTEGER PRIMARY KEY,
+	symbol     TEXT NOT NULL,
+	price      REAL NOT NULL,
+	source     TEXT NOT NULL DEFAULT '',
+	volume     INTEGER NOT NULL DEFAULT 0,
+	timestamp  INTEGER NOT NULL,
+	is_closing INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS prices_symbol_closing_timestamp ON prices (symbol, is_closing, timestamp DESC);
+CREATE INDEX IF NOT EXISTS prices_symbol_timestamp ON prices (symbol, timestamp DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS prices_closing_day ON prices (symbol, timestamp) WHERE is_closing = 1;
+
+CREATE TABLE IF NOT EXISTS watchlist (
+	id       INTEGER PRIMARY KEY,
+	symbol   TEXT NOT NULL UNIQUE,
+	added_by TEXT NOT NULL,
+	added_at INTEGER NOT NULL
+);
+`
+
+// SQLiteStore keeps price history and the watchlist in a local SQLite file
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens or creates the SQLite file at path and creates the tables on the first start
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// WAL lets reports read while prices are written; writers wait for each other instead of failing
+	dsn := "file:" + path + "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSQLiteOpen, err)
+	}
+	// SQLite has a single writer, so one connection avoids lock contention between concurrent saves
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%w: %v", ErrSQLiteOpen, err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// SavePrice saves a quote's price, source and session volume to SQLite
+func (s *SQLiteStore) SavePrice(quote models.Quote, isClosing bool, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO prices (symbol, price, source, volume, timestamp, is_closing) VALUES (?, ?, ?, ?, ?, ?)`,
+		quote.Symbol, quote.Price, quote.Source, quote.Volume, time.Now().UnixMilli(), isClosing)
+	if err != nil {
+		slog.Error("Failed to insert stock data", "error", err)
+		return fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+
+	slog.Debug("Saved price to SQLite", "symbol", quote.Symbol, "price", quote.Price, "source", quote.Source, "volume", quote.Volume, "closing", isClosing)
+	return nil
+}
+
+// SavePrices saves several price records in one transaction; a failed record doesn't stop the others
+func (s *SQLiteStore) SavePrices(records []models.MongoDTO) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	defer tx.Rollback()
+
+	var failed int
+	var lastErr error
+	for _, record := range records {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO prices (symbol, price, source, volume, timestamp, is_closing) VALUES (?, ?, ?, ?, ?, ?)`,
+			record.Symbol, float64(record.Price), record.Source, record.Volume, record.Timestamp.UnixMilli(), record.IsClosing)
+		if err != nil {
+			failed++
+			lastErr = err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d prices not saved: %v", ErrSQLiteQueryFailed, failed, len(records), lastErr)
+	}
+
+	slog.Debug("Saved prices to SQLite", "count", len(records))
+	return nil
+}
+
+// GetLatestClosingPrice retrieves the latest closing price for a specific stock
+func (s *SQLiteStore) GetLatestClosingPrice(symbol string) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var price float64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT price FROM prices WHERE symbol = ? AND is_closing = 1 ORDER BY timestamp DESC LIMIT 1`,
+		symbol).Scan(&price)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%w: %s", ErrNoClosingPriceFound, symbol)
+		}
+		return 0, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	return price, nil
+}
+
+// GetLatestClosingPrices retrieves the latest closing price of several stocks in one query;
+// symbols without a stored close are left out of the result
+func (s *SQLiteStore) GetLatestClosingPrices(symbols []string) (map[string]float64, error) {
+	closes := make(map[string]float64, len(symbols))
+	if len(symbols) == 0 {
+		return closes, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// A bare column next to MAX() takes its value from the row holding the maximum
+	args := make([]any, 0, len(symbols))
+	for _, symbol := range symbols {
+		args = append(args, symbol)
+	}
+	query := `SELECT symbol, price, MAX(timestamp) FROM prices
+		WHERE is_closing = 1 AND symbol IN (?` + strings.Repeat(", ?", len(symbols)-1) + `) GROUP BY symbol`
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var symbol string
+		var price float64
+		var timestamp int64
+		if err := rows.Scan(&symbol, &price, &timestamp); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+		}
+		closes[symbol] = price
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	return closes, nil
+}
+
+// GetPriceHistory retrieves the closing prices of a specific stock over the last days, oldest first
+func (s *SQLiteStore) GetPriceHistory(symbol string, days int) ([]models.MongoDTO, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	startDate := time.Now().AddDate(0, 0, -days)
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT price, source, volume, timestamp FROM prices
+		WHERE symbol = ? AND is_closing = 1 AND timestamp >= ? ORDER BY timestamp`,
+		symbol, startDate.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	defer rows.Close()
+
+	var results []models.MongoDTO
+	for rows.Next() {
+		record := models.MongoDTO{Symbol: symbol, IsClosing: true}
+		var price float64
+		var timestamp int64
+		if err := rows.Scan(&price, &record.Source, &record.Volume, &timestamp); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+		}
+		record.Price = models.StoredPrice(price)
+		record.Timestamp = time.UnixMilli(timestamp)
+		results = append(results, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	return results, nil
+}
+
+// SaveHistoricalCloses stores the daily closing prices of a symbol that aren't stored yet and returns
+// the number of new records
+func (s *SQLiteStore) SaveHistoricalCloses(symbol string, points []models.PricePoint) (int, error) {
+	if len(points) == 0 {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// One transaction, as SQLite syncs the file on every commit
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	defer tx.Rollback()
+
+	// Closes of the same day are stored at different times of it, so the days already stored are looked up and skipped
+	from, to := closesSpan(points)
+	rows, err := tx.QueryContext(ctx, `SELECT timestamp FROM prices WHERE symbol = ? AND is_closing = 1 AND timestamp BETWEEN ? AND ?`,
+		symbol, from.UnixMilli(), to.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	var stored []time.Time
+	for rows.Next() {
+		var timestamp int64
+		if err := rows.Scan(&timestamp); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+		}
+		stored = append(stored, time.UnixMilli(timestamp))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+
+	inserted := 0
+	for _, point := range newCloses(symbol, points, stored) {
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO prices (symbol, price, volume, timestamp, is_closing) VALUES (?, ?, ?, ?, 1)
+			ON CONFLICT (symbol, timestamp) WHERE is_closing = 1 DO NOTHING`,
+			symbol, point.Close, point.Volume, point.Timestamp.UnixMilli())
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+		}
+		if affected, err := result.RowsAffected(); err == nil {
+			inserted += int(affected)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+
+	slog.Info("Saved historical closes to SQLite", "symbol", symbol, "count", inserted)
+	return inserted, nil
+}
+
+// DeleteIntradayPricesBefore removes intraday prices recorded before the cutoff and returns how many were removed;
+// closing prices are never removed
+func (s *SQLiteStore) DeleteIntradayPricesBefore(cutoff time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM prices WHERE is_closing = 0 AND timestamp < ?`, cutoff.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	return removed, nil
+}
+
+// GetWatchlist returns the monitored symbols in the order they were added, seeding the defaults into an empty watchlist
+func (s *SQLiteStore) GetWatchlist(defaults []string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var count int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM watchlist`).Scan(&count); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	if count == 0 && len(defaults) > 0 {
+		now := time.Now().UnixMilli()
+		for _, symbol := range defaults {
+			if _, err := s.db.ExecContext(ctx,
+				`INSERT INTO watchlist (symbol, added_by, added_at) VALUES (?, 'default', ?) ON CONFLICT (symbol) DO NOTHING`,
+				symbol, now); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+			}
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT symbol FROM watchlist ORDER BY added_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	defer rows.Close()
+
+	var symbols []string
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+		}
+		symbols = append(symbols, symbol)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	return symbols, nil
+}
+
+// AddToWatchlist adds a symbol to the watchlist and reports whether it was new
+func (s *SQLiteStore) AddToWatchlist(symbol, addedBy string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Only insert when missing, so the original position in the list is kept
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO watchlist (symbol, added_by, added_at) VALUES (?, ?, ?) ON CONFLICT (symbol) DO NOTHING`,
+		symbol, addedBy, time.Now().UnixMilli())
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	added, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	return added > 0, nil
+}
+
+// RemoveFromWatchlist removes a symbol from the watchlist and reports whether it was there
+func (s *SQLiteStore) RemoveFromWatchlist(symbol string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM watchlist WHERE symbol = ?`, symbol)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	return removed > 0, nil
+}
+
+// RemoveFromWatchlistAddedBy removes a symbol from the watchlist if addedBy added it, and reports whether it was removed
+func (s *SQLiteStore) RemoveFromWatchlistAddedBy(symbol, addedBy string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM watchlist WHERE symbol = ? AND added_by = ?`, symbol, addedBy)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrSQLiteQueryFailed, err)
+	}
+	return removed > 0, nil
+}
+
+// Ping checks that the SQLite file can be read
+func (s *SQLiteStore) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%w: %v", ErrSQLiteOpen, err)
+	}
+	return nil
+}
+
+// Close closes the SQLite file
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSQLiteRemoveFromWatchlistAddedBy(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "stock-bot.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer store.Close()
+
+	for symbol, addedBy := range map[string]string{"AAPL": "config", "NVDA": "12345"} {
+		if _, err := store.AddToWatchlist(symbol, addedBy); err != nil {
+			t.Fatalf("AddToWatchlist(%s): %v", symbol, err)
+		}
+	}
+
+	// A symbol an admin added stays when the config file drops it
+	for _, symbol := range []string{"AAPL", "NVDA"} {
+		if _, err := store.RemoveFromWatchlistAddedBy(symbol, "config"); err != nil {
+			t.Fatalf("RemoveFromWatchlistAddedBy(%s): %v", symbol, err)
+		}
+	}
+
+	symbols, err := store.GetWatchlist(nil)
+	if err != nil {
+		t.Fatalf("GetWatchlist: %v", err)
+	}
+	if !slices.Equal(symbols, []string{"NVDA"}) {
+		t.Errorf("watchlist = %v, want [NVDA]", symbols)
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+)
+
+// stooqSuffixes maps Yahoo exchange suffixes to stooq market suffixes
+var stooqSuffixes = map[string]string{
+	".L":  ".uk",
+	".DE": ".de",
+	".T":  ".jp",
+}
+
+// stooqIndices maps Yahoo index symbols to stooq index symbols
+var stooqIndices = map[string]string{
+	"^GSPC":  "^spx",
+	"^DJI":   "^dji",
+	"^IXIC":  "^ndq",
+	"^N225":  "^nkx",
+	"^FTSE":  "^ukx",
+	"^GDAXI": "^dax",
+	"^FCHI":  "^cac",
+	"^KS11":  "^kospi",
+}
+
+// StooqHistory downloads daily price history as CSV from stooq.com, which needs no API key and keeps decades of closes
+type StooqHistory struct {
+	client *http.Client
+}
+
+// NewStooqHistory creates a new StooqHistory instance
+func NewStooqHistory() *StooqHistory {
+	return &StooqHistory{
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Name identifies the stooq history source
+func (sh *StooqHistory) Name() string {
+	return "stooq"
+}
+
+// FetchDailyCloses returns daily closing prices for a symbol between from and to
+func (sh *StooqHistory) FetchDailyCloses(ctx context.Context, symbol string, from, to time.Time) ([]models.PricePoint, error) {
+	code, ok := stooqSymbol(symbol)
+	if !ok {
+		return nil, fmt.Errorf("%w: stooq has no listing for %s", ErrHistoryUnavailable, symbol)
+	}
+
+	query := url.Values{}
+	query.Set("s", code)
+	query.Set("i", "d")
+	query.Set("d1", from.Format("20060102"))
+	query.Set("d2", to.Format("20060102"))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://stooq.com/q/d/l/?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHistoryUnavailable, err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; stock-bot)")
+
+	resp, err := sh.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHistoryUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("%w: received status code %d", ErrHistoryUnavailable, resp.StatusCode)
+	}
+
+	// Unknown symbols get a "No data" page instead of a CSV
+	points, err := ParseHistoryCSV(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHistoryUnavailable, err)
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("%w: empty response for %s", ErrHistoryUnavailable, symbol)
+	}
+	return points, nil
+}
+
+// stooqSymbol converts a Yahoo symbol to its stooq code, e.g. AAPL to aapl.us and EURUSD=X to eurusd; Korean
+// listings and unknown indices aren't on stooq
+func stooqSymbol(symbol string) (string, bool) {
+	if models.IsIndex(symbol) {
+		code, ok := stooqIndices[symbol]
+		return code, ok
+	}
+	switch models.AssetClassOf(symbol) {
+	case models.AssetFX:
+		base, quote, ok := models.ParseFXPair(symbol)
+		return strings.ToLower(base + quote), ok
+	case models.AssetCrypto:
+		return strings.ToLower(strings.ReplaceAll(symbol, "-", "")), true
+	}
+
+	for suffix, market := range stooqSuffixes {
+		if code, found := strings.CutSuffix(symbol, suffix); found {
+			return strings.ToLower(code) + market, true
+		}
+	}
+	if strings.Contains(symbol, ".") {
+		return "", false
+	}
+	// Class shares: BRK-B on Yahoo is brk-b.us on stooq
+	return strings.ToLower(symbol) + ".us", true
+}
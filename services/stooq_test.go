@@ -0,0 +1,33 @@
+package services
+
+import "testing"
+
+func TestStooqSymbol(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   string
+		ok     bool
+	}{
+		{"AAPL", "aapl.us", true},
+		{"BRK-B", "brk-b.us", true},
+		{"VOD.L", "vod.uk", true},
+		{"SAP.DE", "sap.de", true},
+		{"7203.T", "7203.jp", true},
+		{"EURUSD=X", "eurusd", true},
+		{"BTC-USD", "btcusd", true},
+		{"^GSPC", "^spx", true},
+		{"^KS11", "^kospi", true},
+		{"^VIX", "", false},
+		{"005930.KS", "", false},
+		{"MC.PA", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			got, ok := stooqSymbol(tt.symbol)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("stooqSymbol(%s) = %q, %v, want %q, %v", tt.symbol, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
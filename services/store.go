@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Store keeps price history and the watchlist; MongoDB is the default backend and PostgreSQL can replace it
+type Store interface {
+	// SavePrice saves a quote's price, source and session volume
+	SavePrice(quote models.Quote, isClosing bool, wg *sync.WaitGroup) error
+	// SavePrices saves every price record it can, even when some fail, and returns an error counting the ones that
+	// weren't saved
+	SavePrices(records []models.MongoDTO) error
+	// GetLatestClosingPrice returns the latest closing price of a symbol, or ErrNoClosingPriceFound
+	GetLatestClosingPrice(symbol string) (float64, error)
+	// GetLatestClosingPrices returns the latest closing price of several symbols; symbols without one are left out
+	GetLatestClosingPrices(symbols []string) (map[string]float64, error)
+	// GetPriceHistory returns the closing prices of a symbol over the last days, oldest first
+	GetPriceHistory(symbol string, days int) ([]models.MongoDTO, error)
+	// SaveHistoricalCloses stores the daily closes of trading days without a stored close and returns how many were new
+	SaveHistoricalCloses(symbol string, points []models.PricePoint) (int, error)
+	// DeleteIntradayPricesBefore removes intraday prices older than the cutoff and returns how many were removed
+	DeleteIntradayPricesBefore(cutoff time.Time) (int64, error)
+	// GetWatchlist returns the monitored symbols in the order they were added, seeding the defaults when empty
+	GetWatchlist(defaults []string) ([]string, error)
+	// AddToWatchlist adds a symbol and reports whether it was new
+	AddToWatchlist(symbol, addedBy string) (bool, error)
+	// RemoveFromWatchlist removes a symbol and reports whether it was there
+	RemoveFromWatchlist(symbol string) (bool, error)
+	// RemoveFromWatchlistAddedBy removes a symbol only if addedBy added it, and reports whether it was removed
+	RemoveFromWatchlistAddedBy(symbol, addedBy string) (bool, error)
+	// Ping checks that the backend is reachable
+	Ping(ctx context.Context) error
+	// Close releases the backend's connections
+	Close() error
+}
+
+// newCloses returns the points of trading days that have no stored close, one per day; stored holds the timestamps of
+// the symbol's closes within closesSpan of the points. A captured close, a Yahoo bar at the session open and a
+// CSV row at midnight UTC of the same day are one close
+func newCloses(symbol string, points []models.PricePoint, stored []time.Time) []models.PricePoint {
+	days := make(map[string]bool, len(stored))
+	for _, timestamp := range stored {
+		days[models.TradingDate(symbol, timestamp)] = true
+	}
+
+	var fresh []models.PricePoint
+	for _, point := range points {
+		day := models.TradingDate(symbol, point.Timestamp)
+		if days[day] {
+			continue
+		}
+		days[day] = true
+		fresh = append(fresh, point)
+	}
+	return fresh
+}
+
+// closesSpan returns the time range holding every close stored for the trading days of the points
+func closesSpan(points []models.PricePoint) (time.Time, time.Time) {
+	from, to := points[0].Timestamp, points[0].Timestamp
+	for _, point := range points[1:] {
+		if point.Timestamp.Before(from) {
+			from = point.Timestamp
+		}
+		if point.Timestamp.After(to) {
+			to = point.Timestamp
+		}
+	}
+	// A trading day spans at most a day and a half of UTC time around any of its timestamps
+	return from.Add(-36 * time.Hour), to.Add(36 * time.Hour)
+}
+
+// MongoStore keeps price history in the stocks collection and the watchlist in the watchlist collection
+type MongoStore struct {
+	client *mongo.Client
+}
+
+// Ping checks that MongoDB is reachable
+func (s *MongoStore) Ping(ctx context.Context) error {
+	if err := s.client.Ping(ctx, nil); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoConnection, err)
+	}
+	return nil
+}
+
+// Close leaves the connection to the Database that shares it
+func (s *MongoStore) Close() error {
+	return nil
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"stock-bot/models"
+)
+
+func TestNewCloses(t *testing.T) {
+	day := func(d, hour, minute int) time.Time { return time.Date(2025, 3, d, hour, minute, 0, 0, time.UTC) }
+	// Closes captured at 16:10 New York time on the 12th and 13th
+	stored := []time.Time{day(12, 20, 10), day(13, 20, 10)}
+
+	points := []models.PricePoint{
+		{Timestamp: day(11, 13, 30), Close: 1}, // Yahoo bar of a day not stored
+		{Timestamp: day(12, 13, 30), Close: 2}, // Yahoo bar of a captured day
+		{Timestamp: day(13, 0, 0), Close: 3},   // CSV row of a captured day
+		{Timestamp: day(14, 0, 0), Close: 4},   // CSV row of a day not stored
+		{Timestamp: day(14, 13, 30), Close: 5}, // Second row of that day
+	}
+
+	got := newCloses("AAPL", points, stored)
+	if len(got) != 2 || got[0].Close != 1 || got[1].Close != 4 {
+		t.Errorf("newCloses = %v, want the closes of the 11th and 14th", got)
+	}
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"stock-bot/models"
+)
+
+// Error definitions for symbol lookups
+var (
+	ErrSymbolSearchFailed = errors.New("symbol search failed")
+	ErrSymbolNotFound     = errors.New("no matching symbol found")
+)
+
+// SymbolSearcher looks up tickers by name or identifier via the Yahoo Finance search API
+type SymbolSearcher struct {
+	client *http.Client
+}
+
+// yahooSearchResponse is the subset of the Yahoo search API response used for symbol lookups
+type yahooSearchResponse struct {
+	Quotes []struct {
+		Symbol    string `json:"symbol"`
+		ShortName string `json:"shortname"`
+		LongName  string `json:"longname"`
+		Exchange  string `json:"exchDisp"`
+		QuoteType string `json:"quoteType"`
+	} `json:"quotes"`
+	News []struct {
+		Title string `json:"title"`
+	} `json:"news"`
+}
+
+// NewSymbolSearcher creates a new SymbolSearcher instance
+func NewSymbolSearcher() *SymbolSearcher {
+	return &SymbolSearcher{
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Search returns up to limit instruments matching a name, ticker, ISIN or CUSIP
+func (ss *SymbolSearcher) Search(ctx context.Context, query string, limit int) ([]models.SymbolMatch, error) {
+	result, err := ss.search(ctx, query, limit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []models.SymbolMatch
+	for _, quote := range result.Quotes {
+		if quote.Symbol == "" {
+			continue
+		}
+		name := quote.LongName
+		if name == "" {
+			name = quote.ShortName
+		}
+		matches = append(matches, models.SymbolMatch{
+			Symbol:   quote.Symbol,
+			Name:     name,
+			Exchange: quote.Exchange,
+			Type:     quote.QuoteType,
+		})
+		if len(matches) == limit {
+			break
+		}
+	}
+	return matches, nil
+}
+
+// Headline returns the title of the latest news article about a symbol, or "" if there is none
+func (ss *SymbolSearcher) Headline(ctx context.Context, symbol string) (string, error) {
+	headlines, err := ss.Headlines(ctx, symbol, 1)
+	if err != nil || len(headlines) == 0 {
+		return "", err
+	}
+	return headlines[0], nil
+}
+
+// Headlines returns the titles of up to limit recent news articles about a symbol
+func (ss *SymbolSearcher) Headlines(ctx context.Context, symbol string, limit int) ([]string, error) {
+	result, err := ss.search(ctx, symbol, 0, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	headlines := make([]string, 0, len(result.News))
+	for _, article := range result.News {
+		headlines = append(headlines, article.Title)
+	}
+	return headlines, nil
+}
+
+// search calls the Yahoo search API for quotes and news articles
+func (ss *SymbolSearcher) search(ctx context.Context, query string, quotes, news int) (yahooSearchResponse, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("quotesCount", fmt.Sprintf("%d", quotes))
+	params.Set("newsCount", fmt.Sprintf("%d", news))
+	endpoint := "https://query2.finance.yahoo.com/v1/finance/search?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return yahooSearchResponse{}, fmt.Errorf("%w: %v", ErrSymbolSearchFailed, err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; stock-bot)")
+
+	resp, err := ss.client.Do(req)
+	if err != nil {
+		return yahooSearchResponse{}, fmt.Errorf("%w: %v", ErrSymbolSearchFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return yahooSearchResponse{}, fmt.Errorf("%w: received status code %d", ErrSymbolSearchFailed, resp.StatusCode)
+	}
+
+	var result yahooSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return yahooSearchResponse{}, fmt.Errorf("%w: %v", ErrSymbolSearchFailed, err)
+	}
+
+	return result, nil
+}
+
+// ResolveKRXCode returns the Yahoo ticker of a bare six-digit Korea Exchange code, with the .KS suffix for a
+// KOSPI listing or .KQ for a KOSDAQ one
+func (ss *SymbolSearcher) ResolveKRXCode(ctx context.Context, code string) (string, error) {
+	matches, err := ss.Search(ctx, code, 5)
+	if err != nil {
+		return "", err
+	}
+	for _, match := range matches {
+		if match.Symbol == code+".KS" || match.Symbol == code+".KQ" {
+			return match.Symbol, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrSymbolNotFound, code)
+}
+
+// ResolveIdentifier maps an ISIN or CUSIP to the provider's ticker
+func (ss *SymbolSearcher) ResolveIdentifier(ctx context.Context, identifier string) (models.Instrument, error) {
+	instrument := models.Instrument{ResolvedAt: time.Now()}
+	query := identifier
+	switch {
+	case models.IsISIN(identifier):
+		instrument.ISIN = identifier
+	case models.IsCUSIP(identifier):
+		// The search API indexes ISINs, so US CUSIPs are looked up through their ISIN
+		instrument.CUSIP = identifier
+		instrument.ISIN = models.CUSIPToISIN(identifier)
+		query = instrument.ISIN
+	default:
+		return models.Instrument{}, fmt.Errorf("%w: %s is not an ISIN or CUSIP", ErrSymbolNotFound, identifier)
+	}
+
+	matches, err := ss.Search(ctx, query, 1)
+	if err != nil {
+		return models.Instrument{}, err
+	}
+	if len(matches) == 0 {
+		return models.Instrument{}, fmt.Errorf("%w: %s", ErrSymbolNotFound, identifier)
+	}
+
+	instrument.Symbol = matches[0].Symbol
+	instrument.Name = matches[0].Name
+	instrument.Exchange = matches[0].Exchange
+	return instrument, nil
+}
@@ -0,0 +1,449 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Error definitions related to the interactive bot
+var (
+	ErrTelegramAPI = errors.New("telegram API error")
+)
+
+// Long polling settings for getUpdates
+const (
+	telegramPollTimeout = 30 * time.Second
+	telegramRetryDelay  = 5 * time.Second
+)
+
+// BotCommand is a chat command received from a Telegram or Line user
+type BotCommand struct {
+	Channel  string // Messaging channel the command came from; empty for Telegram
+	ChatID   string
+	UserID   string
+	Username string
+	Name     string   // Command name without the leading slash, lower-cased
+	Args     []string // Whitespace separated arguments
+	Text     string   // Full message text
+	Group    bool     // Sent in a group chat rather than a one-to-one chat
+	Mention  bool     // The message mentions the bot or replies to one of its messages
+}
+
+// CommandHandler handles a single bot command
+type CommandHandler func(ctx context.Context, cmd BotCommand) error
+
+// ChatBot receives chat commands and replies to the chats they came from
+type ChatBot interface {
+	Handle(name string, handler CommandHandler)
+	HandleText(handler CommandHandler)
+	Reply(ctx context.Context, chatID, text string) error
+	ReplyMarkdown(ctx context.Context, chatID, text string) error
+	ReplyPhoto(ctx context.Context, chatID string, photo []byte, caption string) error
+}
+
+// TelegramBot receives commands from Telegram chats via long polling
+type TelegramBot struct {
+	token    string
+	username string // Bot username without the "@", used to spot mentions in group chats
+	client   *http.Client
+	offset   int
+	mu       sync.RWMutex
+	handlers map[string]CommandHandler
+	fallback CommandHandler
+	running  sync.WaitGroup
+}
+
+// telegramUpdate is the subset of the Telegram Update object used by the bot
+type telegramUpdate struct {
+	UpdateID int `json:"update_id"`
+	Message  *struct {
+		Text string `json:"text"`
+		From struct {
+			ID       int64  `json:"id"`
+			Username string `json:"username"`
+		} `json:"from"`
+		Chat struct {
+			ID   int64  `json:"id"`
+			Type string `json:"type"`
+		} `json:"chat"`
+		ReplyToMessage *struct {
+			From struct {
+				Username string `json:"username"`
+			} `json:"from"`
+		} `json:"reply_to_message"`
+	} `json:"message"`
+	MyChatMember *struct {
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+		From struct {
+			ID       int64  `json:"id"`
+			Username string `json:"username"`
+		} `json:"from"`
+		OldChatMember struct {
+			Status string `json:"status"`
+		} `json:"old_chat_member"`
+		NewChatMember struct {
+			Status string `json:"status"`
+		} `json:"new_chat_member"`
+	} `json:"my_chat_member"`
+	CallbackQuery *struct {
+		ID   string `json:"id"`
+		Data string `json:"data"`
+		From struct {
+			ID       int64  `json:"id"`
+			Username string `json:"username"`
+		} `json:"from"`
+		Message *struct {
+			Chat struct {
+				ID int64 `json:"id"`
+			} `json:"chat"`
+		} `json:"message"`
+	} `json:"callback_query"`
+}
+
+// startCommand is the command dispatched when the bot is added to a chat
+const startCommand = "start"
+
+// telegramResponse is the common envelope of Telegram Bot API responses
+type telegramResponse struct {
+	OK          bool            `json:"ok"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+}
+
+// NewTelegramBot creates a new instance of TelegramBot
+func NewTelegramBot(token string) (*TelegramBot, error) {
+	if token == "" {
+		return nil, ErrTokenNotSet
+	}
+	return &TelegramBot{
+		token:    token,
+		client:   &http.Client{Timeout: telegramPollTimeout + 10*time.Second},
+		handlers: make(map[string]CommandHandler),
+	}, nil
+}
+
+// Handle registers a handler for a command name (without the leading slash)
+func (tb *TelegramBot) Handle(name string, handler CommandHandler) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.handlers[strings.ToLower(name)] = handler
+}
+
+// HandleText registers a handler for plain (non-command) messages
+func (tb *TelegramBot) HandleText(handler CommandHandler) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.fallback = handler
+}
+
+// Run polls Telegram for updates until the context is cancelled
+func (tb *TelegramBot) Run(ctx context.Context) {
+	slog.Info("Telegram bot command loop started")
+
+	// The username lets group messages that mention the bot through; without it only cues in the text count
+	var me struct {
+		Username string `json:"username"`
+	}
+	if err := tb.call(ctx, "getMe", map[string]string{}, &me); err != nil {
+		slog.Warn("Error looking up the Telegram bot username", "error", err)
+	}
+	tb.username = me.Username
+
+	for {
+		updates, err := tb.getUpdates(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				slog.Info("Telegram bot command loop stopped")
+				return
+			}
+
+			slog.Error("Error polling Telegram updates", "error", err)
+			select {
+			case <-time.After(telegramRetryDelay):
+				continue
+			case <-ctx.Done():
+				slog.Info("Telegram bot command loop stopped")
+				return
+			}
+		}
+
+		for _, update := range updates {
+			tb.offset = update.UpdateID + 1
+			tb.dispatch(ctx, update)
+		}
+	}
+}
+
+// Wait blocks until the command handlers still running have finished
+func (tb *TelegramBot) Wait() {
+	tb.running.Wait()
+}
+
+// dispatch routes an update to its registered command handler, or to the text handler for plain messages
+func (tb *TelegramBot) dispatch(ctx context.Context, update telegramUpdate) {
+	if update.MyChatMember != nil {
+		tb.dispatchMembership(ctx, update)
+		return
+	}
+	if update.CallbackQuery != nil {
+		tb.dispatchCallback(ctx, update)
+		return
+	}
+
+	if update.Message == nil || strings.TrimSpace(update.Message.Text) == "" {
+		return
+	}
+
+	cmd := BotCommand{Text: update.Message.Text}
+	if strings.HasPrefix(update.Message.Text, "/") {
+		cmd = parseBotCommand(update.Message.Text)
+	}
+	cmd.ChatID = strconv.FormatInt(update.Message.Chat.ID, 10)
+	cmd.UserID = strconv.FormatInt(update.Message.From.ID, 10)
+	cmd.Username = update.Message.From.Username
+	cmd.Group = update.Message.Chat.Type != "private"
+	cmd.Mention = tb.mentioned(update)
+
+	tb.mu.RLock()
+	handler, ok := tb.handlers[cmd.Name]
+	if cmd.Name == "" {
+		handler, ok = tb.fallback, tb.fallback != nil
+	}
+	tb.mu.RUnlock()
+
+	if !ok {
+		if cmd.Name != "" {
+			slog.Info("Ignoring unknown command", "command", cmd.Name, "chat", cmd.ChatID)
+		}
+		return
+	}
+
+	if cmd.Name != "" {
+		slog.Info("Handling command", "command", cmd.Name, "chat", cmd.ChatID)
+	}
+	tb.run(ctx, handler, cmd)
+}
+
+// mentioned reports whether a message names the bot with "@username" or replies to one of its messages
+func (tb *TelegramBot) mentioned(update telegramUpdate) bool {
+	if tb.username == "" {
+		return false
+	}
+	message := update.Message
+	if message.ReplyToMessage != nil && strings.EqualFold(message.ReplyToMessage.From.Username, tb.username) {
+		return true
+	}
+	return strings.Contains(strings.ToLower(message.Text), "@"+strings.ToLower(tb.username))
+}
+
+// dispatchMembership treats the bot being added to a chat as a /start command
+func (tb *TelegramBot) dispatchMembership(ctx context.Context, update telegramUpdate) {
+	member := update.MyChatMember
+	joined := (member.OldChatMember.Status == "left" || member.OldChatMember.Status == "kicked") &&
+		(member.NewChatMember.Status == "member" || member.NewChatMember.Status == "administrator")
+	if !joined {
+		return
+	}
+
+	tb.mu.RLock()
+	handler, ok := tb.handlers[startCommand]
+	tb.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	cmd := BotCommand{
+		ChatID:   strconv.FormatInt(member.Chat.ID, 10),
+		UserID:   strconv.FormatInt(member.From.ID, 10),
+		Username: member.From.Username,
+		Name:     startCommand,
+	}
+	slog.Info("Bot added to chat", "chat", cmd.ChatID)
+	tb.run(ctx, handler, cmd)
+}
+
+// dispatchCallback runs the command carried in the callback data of a pressed button, such as "mute NVDA today",
+// as if it had been sent to the chat the button's message is in
+func (tb *TelegramBot) dispatchCallback(ctx context.Context, update telegramUpdate) {
+	query := update.CallbackQuery
+
+	// Answering stops the button's loading indicator; the command replies in the chat
+	if err := tb.call(ctx, "answerCallbackQuery", map[string]string{"callback_query_id": query.ID}, nil); err != nil {
+		slog.Warn("Error answering Telegram callback query", "error", err)
+	}
+	if query.Message == nil || strings.TrimSpace(query.Data) == "" {
+		return
+	}
+
+	cmd := parseBotCommand("/" + query.Data)
+	cmd.ChatID = strconv.FormatInt(query.Message.Chat.ID, 10)
+	cmd.UserID = strconv.FormatInt(query.From.ID, 10)
+	cmd.Username = query.From.Username
+
+	tb.mu.RLock()
+	handler, ok := tb.handlers[cmd.Name]
+	tb.mu.RUnlock()
+	if !ok {
+		slog.Info("Ignoring unknown button command", "command", cmd.Name, "chat", cmd.ChatID)
+		return
+	}
+
+	slog.Info("Handling button", "command", cmd.Name, "chat", cmd.ChatID)
+	tb.run(ctx, handler, cmd)
+}
+
+// run executes a handler in the background and reports its error back to the chat.
+// Handlers outlive the polling context so a reply in progress at shutdown is still sent
+func (tb *TelegramBot) run(ctx context.Context, handler CommandHandler, cmd BotCommand) {
+	ctx = context.WithoutCancel(ctx)
+	tb.running.Add(1)
+	go func() {
+		defer tb.running.Done()
+		if err := handler(ctx, cmd); err != nil {
+			slog.Error("Error handling command", "command", cmd.Name, "error", err)
+			if replyErr := tb.Reply(ctx, cmd.ChatID, fmt.Sprintf("⚠️ %v", err)); replyErr != nil {
+				slog.Error("Error sending command error reply", "error", replyErr)
+			}
+		}
+	}()
+}
+
+// parseBotCommand splits a message such as "/price@MyBot aapl" into its name and arguments
+func parseBotCommand(text string) BotCommand {
+	fields := strings.Fields(text)
+	name := strings.TrimPrefix(fields[0], "/")
+
+	// Strip the "@BotName" suffix used in group chats
+	if at := strings.Index(name, "@"); at >= 0 {
+		name = name[:at]
+	}
+
+	return BotCommand{
+		Name: strings.ToLower(name),
+		Args: fields[1:],
+		Text: text,
+	}
+}
+
+// Reply sends a plain text message to a chat
+func (tb *TelegramBot) Reply(ctx context.Context, chatID, text string) error {
+	payload := map[string]string{
+		"chat_id": chatID,
+		"text":    text,
+	}
+	return tb.call(ctx, "sendMessage", payload, nil)
+}
+
+// ReplyMarkdown sends a MarkdownV2 formatted message to a chat; text outside entities must be escaped
+// with EscapeMarkdownV2
+func (tb *TelegramBot) ReplyMarkdown(ctx context.Context, chatID, text string) error {
+	payload := map[string]string{
+		"chat_id":    chatID,
+		"text":       text,
+		"parse_mode": telegramParseMode,
+	}
+	return tb.call(ctx, "sendMessage", payload, nil)
+}
+
+// ReplyPhoto sends a PNG image with a caption to a chat
+func (tb *TelegramBot) ReplyPhoto(ctx context.Context, chatID string, photo []byte, caption string) error {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+
+	if err := form.WriteField("chat_id", chatID); err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+	if err := form.WriteField("caption", caption); err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+
+	part, err := form.CreateFormFile("photo", "chart.png")
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+	if _, err := part.Write(photo); err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+	if err := form.Close(); err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendPhoto", tb.token)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, &body)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	return tb.do(req, "sendPhoto", nil)
+}
+
+// getUpdates long-polls Telegram for new updates
+func (tb *TelegramBot) getUpdates(ctx context.Context) ([]telegramUpdate, error) {
+	payload := map[string]interface{}{
+		"offset":          tb.offset,
+		"timeout":         int(telegramPollTimeout.Seconds()),
+		"allowed_updates": []string{"message", "my_chat_member", "callback_query"},
+	}
+
+	var updates []telegramUpdate
+	if err := tb.call(ctx, "getUpdates", payload, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// call invokes a Telegram Bot API method with a JSON payload
+func (tb *TelegramBot) call(ctx context.Context, method string, payload interface{}, result interface{}) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+
+	url := fmt.Sprintf("https://api.telegram.org/bot%s/%s", tb.token, method)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return tb.do(req, method, result)
+}
+
+// do sends a prepared API request and decodes the response envelope into result
+func (tb *TelegramBot) do(req *http.Request, method string, result interface{}) error {
+	resp, err := tb.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessageSending, err)
+	}
+	defer resp.Body.Close()
+
+	var envelope telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrTelegramAPI, method, err)
+	}
+	if !envelope.OK {
+		return fmt.Errorf("%w: %s: %s", ErrTelegramAPI, method, envelope.Description)
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(envelope.Result, result); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrTelegramAPI, method, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"slices"
+
+	"stock-bot/i18n"
+	"stock-bot/models"
+)
+
+// Alert button settings
+const (
+	alertButtonMaxSymbols   = 5  // Alerted symbols that get a row of buttons; the rest of a long alert list has none
+	telegramMaxCallbackData = 64 // Longest callback data Telegram accepts, in bytes
+)
+
+// inlineKeyboard is the reply markup of buttons shown below a Telegram message
+type inlineKeyboard struct {
+	InlineKeyboard [][]inlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// inlineKeyboardButton is a button that sends its callback data back to the bot when pressed
+type inlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// alertKeyboard builds a row of buttons per alerted symbol to mute it for the rest of the day, chart it or list its
+// last 30 closes; the callback data of each button is the chat command it runs
+func alertKeyboard(alerts []models.PriceAlert, printer *i18n.Printer) *inlineKeyboard {
+	var symbols []string
+	for _, alert := range alerts {
+		if !slices.Contains(symbols, alert.Symbol) && len(symbols) < alertButtonMaxSymbols {
+			symbols = append(symbols, alert.Symbol)
+		}
+	}
+
+	keyboard := &inlineKeyboard{}
+	for _, symbol := range symbols {
+		row := []inlineKeyboardButton{
+			{Text: printer.Sprintf("🔕 Mute %s today", symbol), CallbackData: "mute " + symbol + " today"},
+			{Text: printer.Sprintf("📈 %s chart", symbol), CallbackData: "chart " + symbol + " 1m"},
+			{Text: printer.Sprintf("📜 %s 30 days", symbol), CallbackData: "history " + symbol + " 30"},
+		}
+		if slices.ContainsFunc(row, func(button inlineKeyboardButton) bool {
+			return len(button.CallbackData) > telegramMaxCallbackData
+		}) {
+			continue
+		}
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, row)
+	}
+
+	if len(keyboard.InlineKeyboard) == 0 {
+		return nil
+	}
+	return keyboard
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"stock-bot/i18n"
+	"stock-bot/models"
+)
+
+func TestAlertKeyboard(t *testing.T) {
+	// The history button's data is the longest, 11 bytes besides the symbol
+	longest := strings.Repeat("A", telegramMaxCallbackData-11)
+	alertsFor := func(symbols ...string) []models.PriceAlert {
+		var alerts []models.PriceAlert
+		for _, symbol := range symbols {
+			alerts = append(alerts, models.PriceAlert{Symbol: symbol, PercentChange: 5})
+		}
+		return alerts
+	}
+
+	tests := []struct {
+		name   string
+		alerts []models.PriceAlert
+		want   []string // Symbol of each row
+	}{
+		{"no alerts", nil, nil},
+		{"one row per symbol", alertsFor("AAPL", "MSFT"), []string{"AAPL", "MSFT"}},
+		{"repeated symbol", alertsFor("AAPL", "AAPL", "MSFT"), []string{"AAPL", "MSFT"}},
+		{"first five symbols", alertsFor("A", "B", "C", "D", "E", "F"), []string{"A", "B", "C", "D", "E"}},
+		{"callback data at the limit", alertsFor(longest), []string{longest}},
+		{"callback data over the limit", alertsFor(longest+"A", "AAPL"), []string{"AAPL"}},
+		{"every symbol over the limit", alertsFor(longest + "A"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyboard := alertKeyboard(tt.alerts, i18n.NewPrinter(i18n.English))
+			if tt.want == nil {
+				if keyboard != nil {
+					t.Fatalf("keyboard = %+v, want none", keyboard)
+				}
+				return
+			}
+			if keyboard == nil {
+				t.Fatal("no keyboard")
+			}
+
+			var symbols []string
+			for _, row := range keyboard.InlineKeyboard {
+				if len(row) != 3 {
+					t.Errorf("row has %d buttons, want 3", len(row))
+				}
+				for _, button := range row {
+					if len(button.CallbackData) > telegramMaxCallbackData {
+						t.Errorf("callback data %q is %d bytes", button.CallbackData, len(button.CallbackData))
+					}
+				}
+				symbols = append(symbols, strings.Fields(row[0].CallbackData)[1])
+			}
+			if !slices.Equal(symbols, tt.want) {
+				t.Errorf("rows for %v, want %v", symbols, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertKeyboardCommands(t *testing.T) {
+	keyboard := alertKeyboard([]models.PriceAlert{{Symbol: "AAPL"}}, i18n.NewPrinter(i18n.English))
+	want := []string{"mute AAPL today", "chart AAPL 1m", "history AAPL 30"}
+	for i, button := range keyboard.InlineKeyboard[0] {
+		if button.CallbackData != want[i] {
+			t.Errorf("button %d runs %q, want %q", i, button.CallbackData, want[i])
+		}
+		if !strings.Contains(button.Text, "AAPL") {
+			t.Errorf("button %d is labelled %q without the symbol", i, button.Text)
+		}
+	}
+}
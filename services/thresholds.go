@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// SetAlertThreshold persists an alert threshold for a symbol, or the global one when symbol is empty
+func (db *Database) SetAlertThreshold(symbol string, percent float64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if symbol == "" {
+		symbol = models.GlobalThresholdKey
+	}
+
+	collection, err := db.collection("alert_thresholds")
+	if err != nil {
+		return err
+	}
+	threshold := models.AlertThreshold{
+		Symbol:    symbol,
+		Percent:   percent,
+		UpdatedAt: time.Now(),
+	}
+
+	filter := bson.D{{Key: "symbol", Value: symbol}}
+	_, err = collection.ReplaceOne(ctx, filter, threshold, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	slog.Info("Saved alert threshold", "symbol", symbol, "percent", percent)
+	return nil
+}
+
+// GetAlertThresholds loads the stored thresholds, falling back to defaultPercent for the global threshold
+func (db *Database) GetAlertThresholds(defaultPercent float64) (models.AlertThresholds, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	thresholds := models.AlertThresholds{
+		Global:  defaultPercent,
+		Symbols: make(map[string]float64),
+	}
+
+	collection, ok := db.optionalCollection("alert_thresholds")
+	if !ok {
+		return thresholds, nil
+	}
+	cursor, err := collection.Find(ctx, bson.D{})
+	if err != nil {
+		return thresholds, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	defer cursor.Close(ctx)
+
+	var stored []models.AlertThreshold
+	if err := cursor.All(ctx, &stored); err != nil {
+		return thresholds, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	for _, threshold := range stored {
+		if threshold.Symbol == models.GlobalThresholdKey {
+			thresholds.Global = threshold.Percent
+			continue
+		}
+		thresholds.Symbols[threshold.Symbol] = threshold.Percent
+	}
+
+	return thresholds, nil
+}
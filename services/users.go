@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Error definitions for user records
+var (
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// GetUser retrieves the user record of a chat
+func (db *Database) GetUser(chatID string) (models.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("users")
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var user models.User
+	err = collection.FindOne(ctx, bson.D{{Key: "chatId", Value: chatID}}).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.User{}, fmt.Errorf("%w: %s", ErrUserNotFound, chatID)
+		}
+		return models.User{}, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	return user, nil
+}
+
+// SaveUser creates or replaces the user record of a chat
+func (db *Database) SaveUser(user models.User) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, err := db.collection("users")
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	user.UpdatedAt = now
+
+	filter := bson.D{{Key: "chatId", Value: user.ChatID}}
+	_, err = collection.ReplaceOne(ctx, filter, user, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	slog.Info("Saved user record", "chat", user.ChatID)
+	return nil
+}
+
+// ListUsers retrieves all user records
+func (db *Database) ListUsers() ([]models.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("users")
+	if !ok {
+		return nil, nil
+	}
+
+	cursor, err := collection.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	return users, nil
+}
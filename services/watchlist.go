@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// GetWatchlist returns the monitored symbols in the order they were added, seeding the defaults into an empty watchlist
+func (s *MongoStore) GetWatchlist(defaults []string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := s.client.Database("stock_data").Collection("watchlist")
+
+	count, err := collection.CountDocuments(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	if count == 0 && len(defaults) > 0 {
+		now := time.Now()
+		entries := make([]models.WatchlistEntry, 0, len(defaults))
+		for _, symbol := range defaults {
+			entries = append(entries, models.WatchlistEntry{Symbol: symbol, AddedBy: "default", AddedAt: now})
+		}
+		if _, err := collection.InsertMany(ctx, entries); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+		}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "addedAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	defer cursor.Close(ctx)
+
+	var entries []models.WatchlistEntry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	symbols := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		symbols = append(symbols, entry.Symbol)
+	}
+	return symbols, nil
+}
+
+// AddToWatchlist adds a symbol to the watchlist and reports whether it was new
+func (s *MongoStore) AddToWatchlist(symbol, addedBy string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := s.client.Database("stock_data").Collection("watchlist")
+
+	// Only insert when missing, so the original position in the list is kept
+	entry := models.WatchlistEntry{Symbol: symbol, AddedBy: addedBy, AddedAt: time.Now()}
+	filter := bson.D{{Key: "symbol", Value: symbol}}
+	update := bson.D{{Key: "$setOnInsert", Value: entry}}
+	result, err := collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return result.UpsertedCount > 0, nil
+}
+
+// RemoveFromWatchlist removes a symbol from the watchlist and reports whether it was there
+func (s *MongoStore) RemoveFromWatchlist(symbol string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := s.client.Database("stock_data").Collection("watchlist")
+
+	result, err := collection.DeleteOne(ctx, bson.D{{Key: "symbol", Value: symbol}})
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return result.DeletedCount > 0, nil
+}
+
+// RemoveFromWatchlistAddedBy removes a symbol from the watchlist if addedBy added it, and reports whether it was removed
+func (s *MongoStore) RemoveFromWatchlistAddedBy(symbol, addedBy string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := s.client.Database("stock_data").Collection("watchlist")
+
+	result, err := collection.DeleteOne(ctx, bson.D{{Key: "symbol", Value: symbol}, {Key: "addedBy", Value: addedBy}})
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return result.DeletedCount > 0, nil
+}
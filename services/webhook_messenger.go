@@ -0,0 +1,158 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+)
+
+// Webhook request headers
+const (
+	webhookSignatureHeader = "X-StockBot-Signature" // "sha256=" and the hex HMAC-SHA256 of the timestamp, a dot and the body
+	webhookTimestampHeader = "X-StockBot-Timestamp" // Unix seconds the request was signed at
+	webhookEventHeader     = "X-StockBot-Event"     // Payload type: report, alerts or text
+)
+
+// Webhook payload types
+const (
+	webhookReport = "report"
+	webhookAlerts = "alerts"
+	webhookText   = "text"
+)
+
+// WebhookMessenger posts reports, alerts and texts as JSON to a user-configured URL, signed with a shared secret
+type WebhookMessenger struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// WebhookPayload is the JSON body posted to webhook URLs; only the field of its type is set
+type WebhookPayload struct {
+	ID        string              `json:"id"` // Same for every attempt at the same message, so receivers can drop repeats
+	Type      string              `json:"type"`
+	Timestamp time.Time           `json:"timestamp"`
+	Quotes    []models.Quote      `json:"quotes,omitempty"`
+	Alerts    []models.PriceAlert `json:"alerts,omitempty"`
+	Text      string              `json:"text,omitempty"`
+}
+
+// NewWebhookMessenger creates a WebhookMessenger posting to one URL; requests are left unsigned without a secret.
+// Each URL gets its own messenger, so a retry of one that failed doesn't post to the others again
+func NewWebhookMessenger(url, secret string) (*WebhookMessenger, error) {
+	if url == "" {
+		return nil, ErrTokenNotSet
+	}
+	return &WebhookMessenger{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// SendMessage posts the daily report with its quotes in symbol order
+func (wm *WebhookMessenger) SendMessage(quotes map[string]models.Quote, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	payload := WebhookPayload{Type: webhookReport}
+	for _, symbol := range slices.Sorted(maps.Keys(quotes)) {
+		quote := quotes[symbol]
+		quote.Symbol = symbol
+		payload.Quotes = append(payload.Quotes, quote)
+	}
+	return wm.post(payload)
+}
+
+// SendAlerts posts price alerts
+func (wm *WebhookMessenger) SendAlerts(alerts []models.PriceAlert, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	if len(alerts) == 0 {
+		return nil
+	}
+	return wm.post(WebhookPayload{Type: webhookAlerts, Alerts: alerts})
+}
+
+// SendText posts an arbitrary text message
+func (wm *WebhookMessenger) SendText(text string, wg *sync.WaitGroup) error {
+	if wg != nil {
+		defer wg.Done()
+	}
+
+	return wm.post(WebhookPayload{Type: webhookText, Text: text})
+}
+
+// post sends the payload, signed when a secret is set
+func (wm *WebhookMessenger) post(payload WebhookPayload) error {
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+	digest := sha256.Sum256(content)
+	payload.ID = hex.EncodeToString(digest[:16])
+	payload.Timestamp = time.Now().UTC()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+
+	return wm.send(payload.Type, body)
+}
+
+// send posts a signed body to the URL
+func (wm *WebhookMessenger) send(event string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", wm.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessagePreparation, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, event)
+	if wm.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(webhookTimestampHeader, timestamp)
+		req.Header.Set(webhookSignatureHeader, "sha256="+signWebhook(wm.secret, timestamp, body))
+	}
+
+	resp, err := wm.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrMessageSending, err)
+	}
+	defer resp.Body.Close()
+
+	slog.Debug("Webhook response", "kind", event, "status", resp.Status)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %w", ErrMessageSending, newStatusError(resp))
+	}
+	return nil
+}
+
+// signWebhook returns the hex HMAC-SHA256 a webhook request is signed with, computed over the timestamp header,
+// a dot and the body; receivers recompute it to check a request came from the bot
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
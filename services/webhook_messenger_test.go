@@ -0,0 +1,76 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSignWebhook(t *testing.T) {
+	// Expected values computed independently with openssl dgst -sha256 -hmac
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		want      string
+	}{
+		{"text payload", "secret", "1700000000", `{"type":"text","text":"hi"}`, "7bf170ddbaccaf3fd888ede6782a151aa75ec80dce3f4460774598f223b64b56"},
+		{"empty body", "Jefe", "1700000000", "", "80201faaa9258f8cfe66f88ad9fc60a52f73dd0e34d0aa77e8903d388732fc5e"},
+		{"empty secret", "", "0", "{}", "4fa6c2486692767ff3eb0ad23d9638df613add15a49b8ffc0a606879b90a6f25"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := signWebhook(tt.secret, tt.timestamp, []byte(tt.body)); got != tt.want {
+				t.Errorf("signWebhook = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookMessengerSignsRequests(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		signed bool
+	}{
+		{"with a secret", "secret", true},
+		{"without a secret", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header http.Header
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header
+				body, _ = io.ReadAll(r.Body)
+			}))
+			defer server.Close()
+
+			wm, err := NewWebhookMessenger(server.URL, tt.secret)
+			if err != nil {
+				t.Fatalf("NewWebhookMessenger: %v", err)
+			}
+			if err := wm.SendText("Market closed early", nil); err != nil {
+				t.Fatalf("SendText: %v", err)
+			}
+
+			if got := header.Get(webhookEventHeader); got != webhookText {
+				t.Errorf("%s = %q, want %q", webhookEventHeader, got, webhookText)
+			}
+			signature := header.Get(webhookSignatureHeader)
+			if !tt.signed {
+				if signature != "" {
+					t.Errorf("unsigned request has signature %q", signature)
+				}
+				return
+			}
+			if want := "sha256=" + signWebhook(tt.secret, header.Get(webhookTimestampHeader), body); signature != want {
+				t.Errorf("signature = %q, want %q", signature, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+)
+
+// Error definitions related to automation webhooks
+var (
+	ErrInvalidWebhookKey = errors.New("invalid IFTTT webhook key")
+	ErrInvalidHookURL    = errors.New("invalid webhook URL")
+)
+
+// iftttEventPrefix is prepended to event types to form IFTTT event names, e.g. stockbot_alert
+const iftttEventPrefix = "stockbot_"
+
+// IFTTTPublisher fires IFTTT Webhooks events
+type IFTTTPublisher struct {
+	key    string
+	client *http.Client
+}
+
+// NewIFTTTPublisher creates a new IFTTTPublisher for a Webhooks service key
+func NewIFTTTPublisher(key string) (*IFTTTPublisher, error) {
+	if key == "" {
+		return nil, ErrTokenNotSet
+	}
+	// Keys are letters, digits, dashes and underscores; anything else is usually a pasted URL or stray whitespace
+	if strings.IndexFunc(key, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+	}) >= 0 {
+		return nil, ErrInvalidWebhookKey
+	}
+	return &IFTTTPublisher{
+		key:    key,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name returns the integration name
+func (ip *IFTTTPublisher) Name() string {
+	return "ifttt"
+}
+
+// Publish fires the IFTTT event for the event type; value1-3 carry the most useful fields as ingredients
+func (ip *IFTTTPublisher) Publish(ctx context.Context, event models.Event) error {
+	// Individual quotes are too frequent for automation services
+	if event.Type == models.EventQuote {
+		return nil
+	}
+
+	payload := struct {
+		models.Event
+		Value1 string `json:"value1"`
+		Value2 string `json:"value2"`
+		Value3 string `json:"value3"`
+	}{Event: event}
+
+	switch event.Type {
+	case models.EventAlert:
+		payload.Value1 = event.Symbol
+		payload.Value2 = fmt.Sprintf("%.2f", event.PercentChange)
+		payload.Value3 = fmt.Sprintf("%.2f", event.CurrentPrice)
+	case models.EventReport:
+		payload.Value1 = event.Timestamp.Format("2006-01-02")
+		payload.Value2 = fmt.Sprintf("%d", len(event.Prices))
+	}
+
+	endpoint := fmt.Sprintf("https://maker.ifttt.com/trigger/%s/with/key/%s",
+		url.PathEscape(iftttEventPrefix+string(event.Type)), url.PathEscape(ip.key))
+	return postJSON(ctx, ip.client, endpoint, payload)
+}
+
+// ZapierPublisher posts events to a Zapier catch hook
+type ZapierPublisher struct {
+	hookURL string
+	client  *http.Client
+}
+
+// NewZapierPublisher creates a new ZapierPublisher for a catch hook URL
+func NewZapierPublisher(hookURL string) (*ZapierPublisher, error) {
+	if hookURL == "" {
+		return nil, ErrTokenNotSet
+	}
+	if parsed, err := url.Parse(hookURL); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return nil, ErrInvalidHookURL
+	}
+	return &ZapierPublisher{
+		hookURL: hookURL,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Name returns the integration name
+func (zp *ZapierPublisher) Name() string {
+	return "zapier"
+}
+
+// Publish posts the event JSON to the catch hook
+func (zp *ZapierPublisher) Publish(ctx context.Context, event models.Event) error {
+	if event.Type == models.EventQuote {
+		return nil
+	}
+	return postJSON(ctx, zp.client, zp.hookURL, event)
+}
+
+// postJSON sends a JSON body and treats any non-2xx response as a failure
+func postJSON(ctx context.Context, client *http.Client, endpoint string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: received status code %d", ErrPublishFailed, resp.StatusCode)
+	}
+	return nil
+}
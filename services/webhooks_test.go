@@ -0,0 +1,47 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewIFTTTPublisher(t *testing.T) {
+	tests := []struct {
+		key     string
+		wantErr error
+	}{
+		{"dQw4w9-WgXcQ_42", nil},
+		{"", ErrTokenNotSet},
+		{"dQw4w9WgXcQ ", ErrInvalidWebhookKey},
+		{"https://maker.ifttt.com/use/dQw4w9WgXcQ", ErrInvalidWebhookKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if _, err := NewIFTTTPublisher(tt.key); !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewIFTTTPublisher(%q) error = %v, want %v", tt.key, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewZapierPublisher(t *testing.T) {
+	tests := []struct {
+		hookURL string
+		wantErr error
+	}{
+		{"https://hooks.zapier.com/hooks/catch/123/abc/", nil},
+		{"", ErrTokenNotSet},
+		{"hooks.zapier.com/hooks/catch/123/abc/", ErrInvalidHookURL},
+		{"https://", ErrInvalidHookURL},
+		{"ftp://hooks.zapier.com/", ErrInvalidHookURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hookURL, func(t *testing.T) {
+			if _, err := NewZapierPublisher(tt.hookURL); !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewZapierPublisher(%q) error = %v, want %v", tt.hookURL, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"stock-bot/models"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// SaveYearRange creates or replaces the 52-week range of a symbol
+func (db *Database) SaveYearRange(r models.YearRange) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("year_ranges")
+	if !ok {
+		return nil
+	}
+
+	r.UpdatedAt = time.Now()
+	filter := bson.D{{Key: "symbol", Value: r.Symbol}}
+	if _, err := collection.ReplaceOne(ctx, filter, r, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	return nil
+}
+
+// GetYearRanges returns the stored 52-week ranges keyed by symbol
+func (db *Database) GetYearRanges() (map[string]models.YearRange, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection, ok := db.optionalCollection("year_ranges")
+	if !ok {
+		return nil, nil
+	}
+
+	cursor, err := collection.Find(ctx, bson.D{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+	defer cursor.Close(ctx)
+
+	var stored []models.YearRange
+	if err := cursor.All(ctx, &stored); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMongoQueryFailed, err)
+	}
+
+	ranges := make(map[string]models.YearRange, len(stored))
+	for _, r := range stored {
+		ranges[r.Symbol] = r
+	}
+	return ranges, nil
+}
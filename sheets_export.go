@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// Global Google Sheets exporter, nil when no sheet is configured
+var sheetsExporter *services.SheetsExporter
+
+// exportDailyCloses appends the daily report prices to the shared sheet
+func exportDailyCloses(ctx context.Context, quotes map[string]models.Quote) {
+	if sheetsExporter == nil {
+		return
+	}
+	if err := sheetsExporter.AppendCloses(ctx, time.Now(), quotes); err != nil {
+		slog.Error("Error exporting daily closes to Google Sheets", "error", err)
+	}
+}
+
+// exportAlerts appends sent price alerts to the shared sheet
+func exportAlerts(ctx context.Context, alerts []models.PriceAlert) {
+	if sheetsExporter == nil {
+		return
+	}
+	if err := sheetsExporter.AppendAlerts(ctx, alerts); err != nil {
+		slog.Error("Error exporting alerts to Google Sheets", "error", err)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"stock-bot/services"
+)
+
+// shutdownTimeout bounds how long in-flight messages may take to finish on shutdown
+const shutdownTimeout = 30 * time.Second
+
+// background tracks goroutines that may still be delivering messages when shutdown starts
+var background sync.WaitGroup
+
+// setupSignalHandler cancels the root context on SIGINT or SIGTERM; a second signal exits immediately
+func setupSignalHandler(cancel context.CancelFunc) {
+	c := make(chan os.Signal, 2)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c
+		slog.Info("Received termination signal, shutting down")
+		cancel()
+
+		<-c
+		slog.Warn("Received second termination signal, exiting immediately")
+		os.Exit(1)
+	}()
+}
+
+// shutdown waits for in-flight messages, then closes the publishers, the quote cache, the database and the browser, in that order
+func shutdown(db *services.Database) {
+	done := make(chan struct{})
+	go func() {
+		background.Wait()
+		close(done)
+	}()
+
+	slog.Info("Waiting for in-flight messages")
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout):
+		slog.Warn("Gave up waiting for in-flight messages", "timeout", shutdownTimeout)
+	}
+
+	closePublishers()
+
+	if redisQuoteCache != nil {
+		if err := redisQuoteCache.Close(); err != nil {
+			slog.Error("Error closing quote cache", "error", err)
+		}
+	}
+
+	if err := db.Close(); err != nil {
+		slog.Error("Error closing database connection", "error", err)
+	}
+
+	if priceFetcher != nil {
+		slog.Info("Cleaning up browser resources")
+		priceFetcher.Cleanup()
+	}
+
+	slog.Info("Gracefully shut down")
+}
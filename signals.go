@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// signalHeadlineCount is how many recent headlines feed the sentiment component
+const signalHeadlineCount = 8
+
+// sendSignalsReport evaluates composite trading signals from the daily closes, stores them and delivers the Signals report
+func sendSignalsReport(ctx context.Context, db *services.Database, delivery *services.Delivery, config models.Config, closes map[string][]models.PricePoint) {
+	search := services.NewSymbolSearcher()
+	now := time.Now()
+
+	var signals []models.Signal
+	for symbol, points := range closes {
+		searchCtx, cancel := context.WithTimeout(ctx, commandFetchTimeout)
+		headlines, err := search.Headlines(searchCtx, symbol, signalHeadlineCount)
+		cancel()
+		if err != nil {
+			slog.Error("Error fetching headlines for signal", "symbol", symbol, "error", err)
+		}
+
+		if signal, ok := services.EvaluateSignal(symbol, points, headlines, config.SignalWeights, now); ok {
+			signals = append(signals, signal)
+		}
+	}
+	if len(signals) == 0 {
+		return
+	}
+
+	if err := db.SaveSignals(signals); err != nil {
+		slog.Error("Error saving signals", "error", err)
+	}
+
+	slices.SortFunc(signals, func(a, b models.Signal) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		default:
+			return strings.Compare(a.Symbol, b.Symbol)
+		}
+	})
+
+	message := formatSignals(signals)
+	if _, err := delivery.Deliver(models.KindSignals, func(m services.Messenger, _ models.User) error {
+		return m.SendText(message, nil)
+	}); err != nil {
+		slog.Error("Error sending signals report", "error", err)
+	}
+}
+
+// formatSignals renders the Signals report, strongest buy first
+func formatSignals(signals []models.Signal) string {
+	icons := map[models.SignalAction]string{
+		models.SignalBuy:   "🟢",
+		models.SignalWatch: "🟡",
+		models.SignalSell:  "🔴",
+	}
+
+	var message strings.Builder
+	message.WriteString("🧭 Signals\n\n")
+	for _, signal := range signals {
+		message.WriteString(fmt.Sprintf("%s %s %s (%+.2f)\n", icons[signal.Action], strings.ToUpper(string(signal.Action)), signal.Symbol, signal.Score))
+
+		var parts []string
+		for _, name := range []string{models.SignalTrend, models.SignalRSI, models.SignalVolume, models.SignalSentiment} {
+			if score, ok := signal.Components[name]; ok {
+				parts = append(parts, fmt.Sprintf("%s %+.2f", name, score))
+			}
+		}
+		message.WriteString("   " + strings.Join(parts, " · ") + "\n")
+	}
+	message.WriteString("\nComposite of trend, RSI, volume and news sentiment. Not investment advice.")
+	return message.String()
+}
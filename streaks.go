@@ -0,0 +1,106 @@
+package main
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// streakReportMin is the shortest streak listed in the morning briefing
+const streakReportMin = 3
+
+// Streaks measured for the latest daily report, shown in the morning briefing
+var latestStreaks []models.Streak
+
+// Start of the streak each symbol was last alerted for, so a streak is only announced once
+var streakAlerted = make(map[string]time.Time)
+
+// checkStreaks measures each symbol's run of up or down closes and alerts when one reaches the configured length
+func checkStreaks(delivery *services.Delivery, config models.Config, closes map[string][]models.PricePoint) {
+	now := time.Now()
+
+	var streaks []models.Streak
+	for symbol, points := range closes {
+		if streak := models.CurrentStreak(symbol, points); streak.Days > 0 {
+			streaks = append(streaks, streak)
+		}
+	}
+
+	slices.SortFunc(streaks, func(a, b models.Streak) int {
+		if a.Days != b.Days {
+			return b.Days - a.Days
+		}
+		return strings.Compare(a.Symbol, b.Symbol)
+	})
+	latestStreaks = streaks
+
+	if config.StreakAlertDays <= 0 {
+		return
+	}
+
+	var reached []models.Streak
+	for _, streak := range streaks {
+		if streak.Days < config.StreakAlertDays || streakAlerted[streak.Symbol].Equal(streak.Start) {
+			continue
+		}
+		streakAlerted[streak.Symbol] = streak.Start
+		reached = append(reached, streak)
+	}
+	if len(reached) == 0 {
+		return
+	}
+
+	slog.Info("Sending streak alerts", "count", len(reached))
+	_, err := delivery.Deliver(models.KindAlert, func(m services.Messenger, user models.User) error {
+		message := formatStreakAlerts(reached, user, now)
+		if message == "" {
+			return nil
+		}
+		return m.SendText(message, nil)
+	})
+	if err != nil {
+		slog.Error("Error sending streak alerts", "error", err)
+	}
+}
+
+// formatStreakAlerts renders the streaks that reached the alert length for a recipient, skipping symbols they don't
+// watch or muted
+func formatStreakAlerts(reached []models.Streak, user models.User, now time.Time) string {
+	var lines []string
+	for _, streak := range reached {
+		if user.Watches(streak.Symbol) && !user.IsMuted(streak.Symbol, now) {
+			lines = append(lines, formatStreak(streak))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return locale.Text("📏 Streak Alert") + "\n\n" + strings.Join(lines, "\n")
+}
+
+// streaksSection lists the current multi-day streaks for the morning briefing
+func streaksSection() string {
+	var section strings.Builder
+	for _, streak := range latestStreaks {
+		if streak.Days >= streakReportMin {
+			section.WriteString(formatStreak(streak) + "\n")
+		}
+	}
+
+	if section.Len() == 0 {
+		return ""
+	}
+	return locale.Text("📏 Streaks") + "\n" + section.String()
+}
+
+// formatStreak describes a streak, e.g. "🔴 TSLA: 5 down days in a row (-12.40%)"
+func formatStreak(streak models.Streak) string {
+	if !streak.Up {
+		return locale.Sprintf("🔴 %s: %d down days in a row (%+.2f%%)", streak.Symbol, streak.Days, streak.PercentChange)
+	}
+	return locale.Sprintf("🟢 %s: %d up days in a row (%+.2f%%)", streak.Symbol, streak.Days, streak.PercentChange)
+}
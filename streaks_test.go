@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"stock-bot/i18n"
+	"stock-bot/models"
+)
+
+func TestAlertsSkipUnwatchedSymbols(t *testing.T) {
+	now := time.Now()
+	streaks := []models.Streak{{Symbol: "AAPL", Days: 5, Up: true, PercentChange: 4.2}}
+	ratings := []models.AnalystRating{{Symbol: "AAPL", Action: models.RatingUpgrade, Firm: "Morgan Stanley", FromGrade: "Hold", ToGrade: "Buy"}}
+	trades := []models.InsiderTrade{{Symbol: "AAPL", Insider: "Tim Cook", TransactionType: models.InsiderPurchase, Shares: 1000, Price: 190}}
+
+	tests := []struct {
+		name string
+		user models.User
+		want bool
+	}{
+		{"no watchlist", models.User{ChatID: "1"}, true},
+		{"watching the symbol", models.User{ChatID: "1", Watchlist: []string{"MSFT", "AAPL"}}, true},
+		{"watching others", models.User{ChatID: "1", Watchlist: []string{"MSFT"}}, false},
+		{"muted", models.User{ChatID: "1", MutedUntil: map[string]time.Time{"AAPL": {}}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := map[string]string{
+				"streak":  formatStreakAlerts(streaks, tt.user, now),
+				"analyst": formatAnalystAlerts(ratings, tt.user),
+				"insider": formatInsiderAlerts(trades, tt.user),
+			}
+			for kind, message := range messages {
+				if got := strings.Contains(message, "AAPL"); got != tt.want {
+					t.Errorf("%s alert mentions AAPL = %v, want %v:\n%s", kind, got, tt.want, message)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatStreakAlertsLanguage(t *testing.T) {
+	defer func(previous *i18n.Printer) { locale = previous }(locale)
+
+	reached := []models.Streak{
+		{Symbol: "AAPL", Days: 5, Up: true, PercentChange: 4.2},
+		{Symbol: "TSLA", Days: 4, PercentChange: -12.4},
+	}
+
+	tests := []struct {
+		language i18n.Language
+		want     string
+	}{
+		{i18n.English, "📏 Streak Alert\n\n🟢 AAPL: 5 up days in a row (+4.20%)\n🔴 TSLA: 4 down days in a row (-12.40%)"},
+		{i18n.Korean, "📏 연속 등락 알림\n\n🟢 AAPL: 5일 연속 상승 (+4.20%)\n🔴 TSLA: 4일 연속 하락 (-12.40%)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.language), func(t *testing.T) {
+			locale = i18n.NewPrinter(tt.language)
+			if got := formatStreakAlerts(reached, models.User{ChatID: "1"}, time.Now()); got != tt.want {
+				t.Errorf("formatStreakAlerts = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// Summary report constants
+const (
+	monthlyReportDay   = 1  // Sent on the morning of the first day of the month, after its last close
+	summaryHistoryDays = 75 // Stored closes loaded for a month's change and the 30-day range before it
+	summaryTopCount    = 3  // Best and worst performers listed
+)
+
+// Month of the last monthly report, to avoid sending it twice
+var lastMonthlyReportMonth string
+
+// loadStoredCloses reads the stored closing prices of symbols over the last days, one per date:
+// a captured close and an imported one for the same day count once, the later one winning
+func loadStoredCloses(db *services.Database, symbols []string, days int) map[string][]models.PricePoint {
+	closes := make(map[string][]models.PricePoint)
+	for _, symbol := range symbols {
+		records, err := db.GetPriceHistory(symbol, days)
+		if err != nil {
+			slog.Error("Error reading stored closes", "symbol", symbol, "error", err)
+			continue
+		}
+
+		var points []models.PricePoint
+		for _, record := range records {
+			point := models.PricePoint{Timestamp: record.Timestamp, Close: float64(record.Price), Volume: record.Volume}
+			if n := len(points); n > 0 && sameUTCDate(points[n-1].Timestamp, point.Timestamp) {
+				points[n-1] = point
+				continue
+			}
+			points = append(points, point)
+		}
+		closes[symbol] = points
+	}
+	return closes
+}
+
+// watchedCloses keeps the closes of the symbols a chat watches, so its summaries cover its watchlist as its daily
+// report does
+func watchedCloses(closes map[string][]models.PricePoint, user models.User) map[string][]models.PricePoint {
+	watched := make(map[string][]models.PricePoint, len(closes))
+	for symbol, points := range closes {
+		if user.Watches(symbol) {
+			watched[symbol] = points
+		}
+	}
+	return watched
+}
+
+// sameUTCDate reports whether two times fall on the same UTC date
+func sameUTCDate(a, b time.Time) bool {
+	return a.UTC().Format("2006-01-02") == b.UTC().Format("2006-01-02")
+}
+
+// performanceSection summarizes the stored closes since from: every symbol's change, the best and worst
+// performers, and the symbols closing at a new 30-day high or low; period is already in the configured language
+func performanceSection(closes map[string][]models.PricePoint, from time.Time, period string) string {
+	var moves []models.PeriodMove
+	for symbol, points := range closes {
+		if move, ok := models.MeasurePeriod(symbol, points, from); ok {
+			moves = append(moves, move)
+		}
+	}
+	if len(moves) == 0 {
+		return ""
+	}
+	slices.SortFunc(moves, func(a, b models.PeriodMove) int {
+		return cmp.Compare(b.PercentChange, a.PercentChange)
+	})
+
+	formatMove := func(move models.PeriodMove) string {
+		return fmt.Sprintf("%s: %s (%+.2f%%)", move.Symbol, models.FormatPrice(move.Symbol, move.End), move.PercentChange)
+	}
+
+	var sections []string
+
+	top := min(summaryTopCount, len(moves))
+	var best, worst []string
+	for _, move := range moves[:top] {
+		best = append(best, formatMove(move))
+	}
+	for i := len(moves) - 1; i >= len(moves)-top; i-- {
+		worst = append(worst, formatMove(moves[i]))
+	}
+	sections = append(sections, locale.Text("🏆 Best Performers")+"\n"+strings.Join(best, "\n"), locale.Text("🥀 Worst Performers")+"\n"+strings.Join(worst, "\n"))
+
+	var all, highs, lows []string
+	for _, move := range moves {
+		all = append(all, formatMove(move))
+		if move.RangeHigh {
+			highs = append(highs, formatMove(move))
+		}
+		if move.RangeLow {
+			lows = append(lows, formatMove(move))
+		}
+	}
+	sections = append(sections, locale.Sprintf("📊 %s Changes", period)+"\n"+strings.Join(all, "\n"))
+	if len(highs) > 0 {
+		sections = append(sections, locale.Text("🏔 New 30-Day Highs")+"\n"+strings.Join(highs, "\n"))
+	}
+	if len(lows) > 0 {
+		sections = append(sections, locale.Text("🕳 New 30-Day Lows")+"\n"+strings.Join(lows, "\n"))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// sendMonthlyReport delivers the past month's performance summary to subscribers
+func sendMonthlyReport(db *services.Database, delivery *services.Delivery, now time.Time) {
+	slog.Info("Building monthly report")
+
+	from := now.AddDate(0, -1, 0)
+	closes := loadStoredCloses(db, symbolHealth.active(models.Tickers()), summaryHistoryDays)
+	if performanceSection(closes, from, locale.Text("Month-over-Month")) == "" {
+		slog.Info("Monthly report has no content, skipping")
+		return
+	}
+
+	// Each chat only sees the symbols on its watchlist
+	if _, err := delivery.Deliver(models.KindMonthlyReport, func(m services.Messenger, user models.User) error {
+		section := performanceSection(watchedCloses(closes, user), from, locale.Text("Month-over-Month"))
+		if section == "" {
+			return nil
+		}
+		return m.SendText(locale.Sprintf("🗓 Monthly Summary, %s", from.Format(locale.Text("January 2006")))+"\n\n"+section, nil)
+	}); err != nil {
+		slog.Error("Error sending monthly report", "error", err)
+		return
+	}
+	slog.Info("Monthly report sent successfully")
+}
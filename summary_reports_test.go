@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"stock-bot/i18n"
+	"stock-bot/models"
+)
+
+func TestWatchedCloses(t *testing.T) {
+	points := []models.PricePoint{{Timestamp: time.Date(2025, time.March, 3, 21, 0, 0, 0, time.UTC), Close: 100}}
+	closes := map[string][]models.PricePoint{"AAPL": points, "MSFT": points, "NVDA": points}
+
+	tests := []struct {
+		name      string
+		watchlist []string
+		want      []string
+	}{
+		{"empty watchlist follows every symbol", nil, []string{"AAPL", "MSFT", "NVDA"}},
+		{"watchlist", []string{"AAPL", "NVDA"}, []string{"AAPL", "NVDA"}},
+		{"watching nothing stored", []string{"TSLA"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Sorted(maps.Keys(watchedCloses(closes, models.User{ChatID: "1", Watchlist: tt.watchlist})))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("watchedCloses = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPerformanceSectionLanguage(t *testing.T) {
+	defer func(previous *i18n.Printer) { locale = previous }(locale)
+
+	closes := map[string][]models.PricePoint{"AAPL": {
+		{Timestamp: time.Date(2025, time.March, 3, 21, 0, 0, 0, time.UTC), Close: 100},
+		{Timestamp: time.Date(2025, time.March, 10, 20, 0, 0, 0, time.UTC), Close: 110},
+	}}
+	from := time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC)
+	move := fmt.Sprintf("AAPL: %s (+10.00%%)", models.FormatPrice("AAPL", 110))
+
+	tests := []struct {
+		language i18n.Language
+		want     string
+	}{
+		{i18n.English, "🏆 Best Performers\n" + move + "\n\n🥀 Worst Performers\n" + move + "\n\n📊 Week-over-Week Changes\n" + move},
+		{i18n.Korean, "🏆 상승률 상위\n" + move + "\n\n🥀 상승률 하위\n" + move + "\n\n📊 전주 대비 등락률\n" + move},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.language), func(t *testing.T) {
+			locale = i18n.NewPrinter(tt.language)
+			if got := performanceSection(closes, from, locale.Text("Week-over-Week")); got != tt.want {
+				t.Errorf("performanceSection = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// symbolHealthTracker counts consecutive resolution failures and pauses symbols that look delisted; the failure
+// limit and the admins to notify are read from the configuration in effect
+type symbolHealthTracker struct {
+	db       *services.Database
+	delivery *services.Delivery
+
+	mu       sync.Mutex
+	failures map[string]int
+	paused   map[string]bool
+}
+
+// Global symbol health tracker, set up once the database and messenger are ready
+var symbolHealth *symbolHealthTracker
+
+// newSymbolHealthTracker creates a tracker and loads the symbols paused in earlier runs
+func newSymbolHealthTracker(db *services.Database, delivery *services.Delivery) *symbolHealthTracker {
+	tracker := &symbolHealthTracker{
+		db:       db,
+		delivery: delivery,
+		failures: make(map[string]int),
+		paused:   make(map[string]bool),
+	}
+
+	paused, err := db.GetPausedSymbols()
+	if err != nil {
+		slog.Error("Error loading paused symbols", "error", err)
+	}
+	for _, symbol := range paused {
+		tracker.paused[symbol.Symbol] = true
+	}
+	if len(paused) > 0 {
+		slog.Info("Fetching is paused for possibly delisted symbols", "count", len(paused))
+	}
+
+	return tracker
+}
+
+// active returns the symbols whose fetching is not paused
+func (t *symbolHealthTracker) active(symbols []string) []string {
+	if t == nil {
+		return symbols
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	active := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		if !t.paused[symbol] {
+			active = append(active, symbol)
+		}
+	}
+	return active
+}
+
+// record updates failure counts from a fetch cycle and pauses symbols that reached the limit
+func (t *symbolHealthTracker) record(results map[string]models.PriceResult) {
+	if t == nil {
+		return
+	}
+	config := currentConfig()
+	if config.DelistFailureLimit <= 0 {
+		return
+	}
+
+	// A cycle where nothing resolved points at the network or the browser, not at the symbols
+	anySucceeded := false
+	for _, result := range results {
+		if result.Error == nil {
+			anySucceeded = true
+			break
+		}
+	}
+	if !anySucceeded {
+		return
+	}
+
+	t.mu.Lock()
+	var newlyPaused []models.PausedSymbol
+	for symbol, result := range results {
+		if result.Error == nil {
+			delete(t.failures, symbol)
+			continue
+		}
+
+		t.failures[symbol]++
+		if t.failures[symbol] < config.DelistFailureLimit {
+			continue
+		}
+
+		newlyPaused = append(newlyPaused, models.PausedSymbol{
+			Symbol:    symbol,
+			Failures:  t.failures[symbol],
+			LastError: result.Error.Error(),
+			PausedAt:  time.Now(),
+		})
+		t.paused[symbol] = true
+		delete(t.failures, symbol)
+	}
+	t.mu.Unlock()
+
+	for _, paused := range newlyPaused {
+		slog.Warn("Pausing symbol after consecutive failures", "symbol", paused.Symbol, "failures", paused.Failures, "last_error", paused.LastError)
+		if err := t.db.PauseSymbol(paused); err != nil {
+			slog.Error("Error saving paused symbol", "symbol", paused.Symbol, "error", err)
+		}
+
+		message := fmt.Sprintf("⚠️ %s could not be resolved %d times in a row and may be delisted. Fetching it is paused; send /resume %s to try again.\nLast error: %s",
+			paused.Symbol, paused.Failures, paused.Symbol, paused.LastError)
+		if err := t.delivery.NotifyChats(config.AdminUserIDs, message); err != nil {
+			slog.Error("Error notifying admins about paused symbol", "symbol", paused.Symbol, "error", err)
+		}
+	}
+}
+
+// resume lifts the pause on a symbol and reports whether it was paused
+func (t *symbolHealthTracker) resume(symbol string) (bool, error) {
+	resumed, err := t.db.ResumeSymbol(symbol)
+	if err != nil {
+		return false, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	wasPaused := t.paused[symbol]
+	delete(t.paused, symbol)
+	delete(t.failures, symbol)
+	return resumed || wasPaused, nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// volumeAverageDays is the number of stored sessions the average volume is taken over
+const volumeAverageDays = 20
+
+// Average volumes by symbol with the date they were computed, and the date each symbol's unusual volume was last alerted
+var (
+	volumeMu       sync.Mutex
+	volumeAverages = make(map[string]volumeAverage)
+	volumeAlerted  = make(map[string]string)
+)
+
+// volumeAverage is a symbol's average session volume, computed once a day from the stored closes
+type volumeAverage struct {
+	date    string
+	average int64
+}
+
+// unusualVolume is a session volume well above its symbol's average, with the price move it came with
+type unusualVolume struct {
+	symbol        string
+	volume        int64
+	average       int64
+	percentChange float64
+	hasChange     bool
+}
+
+// checkUnusualVolume alerts once a day per symbol when the session volume so far reaches the configured
+// multiple of its 20-day average, showing the price move alongside so a move's conviction can be judged
+func checkUnusualVolume(db *services.Database, delivery *services.Delivery, quotes map[string]models.Quote, previousCloses map[string]float64) {
+	multiple := currentConfig().VolumeAlertMultiple
+	if multiple <= 0 {
+		return
+	}
+
+	now := time.Now()
+	today := now.Format("2006-01-02")
+
+	var symbols []string
+	for symbol, quote := range quotes {
+		// Crypto volume is a rolling 24 hours in the quote currency, which doesn't compare with stored sessions
+		if quote.Volume > 0 && models.AssetClassOf(symbol) != models.AssetCrypto {
+			symbols = append(symbols, symbol)
+		}
+	}
+	averages := averageVolumes(db, symbols, today)
+
+	var unusual []unusualVolume
+	volumeMu.Lock()
+	for _, symbol := range symbols {
+		average, ok := averages[symbol]
+		quote := quotes[symbol]
+		volume := quote.Volume
+		if !ok || volumeAlerted[symbol] == today || float64(volume) < multiple*float64(average) {
+			continue
+		}
+		volumeAlerted[symbol] = today
+
+		u := unusualVolume{symbol: symbol, volume: volume, average: average}
+		if previous := previousCloses[symbol]; previous > 0 {
+			u.percentChange = (quote.Price - previous) / previous * 100
+			u.hasChange = true
+		}
+		unusual = append(unusual, u)
+		slog.Info("Unusual volume detected", "symbol", symbol, "volume", volume, "average", average)
+	}
+	volumeMu.Unlock()
+	if len(unusual) == 0 {
+		return
+	}
+
+	_, err := delivery.Deliver(models.KindAlert, func(m services.Messenger, user models.User) error {
+		var lines []string
+		for _, u := range unusual {
+			if user.Watches(u.symbol) && !user.IsMuted(u.symbol, now) {
+				lines = append(lines, formatUnusualVolume(u))
+			}
+		}
+		if len(lines) == 0 {
+			return nil
+		}
+		return m.SendText(locale.Text("📊 Unusual Volume")+"\n\n"+strings.Join(lines, "\n"), nil)
+	})
+	if err != nil {
+		slog.Error("Error sending unusual volume alerts", "error", err)
+	}
+}
+
+// averageVolumes returns the average volume of the last 20 stored sessions before today for each symbol,
+// leaving out symbols with fewer sessions carrying a volume
+func averageVolumes(db *services.Database, symbols []string, today string) map[string]int64 {
+	volumeMu.Lock()
+	averages := make(map[string]int64)
+	var stale []string
+	for _, symbol := range symbols {
+		if cached, ok := volumeAverages[symbol]; ok && cached.date == today {
+			if cached.average > 0 {
+				averages[symbol] = cached.average
+			}
+			continue
+		}
+		stale = append(stale, symbol)
+	}
+	volumeMu.Unlock()
+	if len(stale) == 0 {
+		return averages
+	}
+
+	// Twice the sessions in calendar days covers weekends and holidays
+	closes := loadStoredCloses(db, stale, 2*volumeAverageDays)
+	now := time.Now()
+
+	volumeMu.Lock()
+	defer volumeMu.Unlock()
+	for _, symbol := range stale {
+		var volumes []int64
+		for _, point := range closes[symbol] {
+			if point.Volume > 0 && !sameUTCDate(point.Timestamp, now) {
+				volumes = append(volumes, point.Volume)
+			}
+		}
+
+		average := int64(0)
+		if len(volumes) >= volumeAverageDays {
+			var total int64
+			for _, volume := range volumes[len(volumes)-volumeAverageDays:] {
+				total += volume
+			}
+			average = total / volumeAverageDays
+			averages[symbol] = average
+		}
+		volumeAverages[symbol] = volumeAverage{date: today, average: average}
+	}
+	return averages
+}
+
+// formatUnusualVolume renders a volume alert, e.g. "NVDA 45,210,000 shares, 3.4× the 20-day average of 13,297,000 (+6.20%)"
+func formatUnusualVolume(u unusualVolume) string {
+	line := locale.Sprintf("%s %s shares, %.1f× the %d-day average of %s", u.symbol, formatVolume(u.volume),
+		float64(u.volume)/float64(u.average), volumeAverageDays, formatVolume(u.average))
+	if u.hasChange {
+		line += fmt.Sprintf(" (%+.2f%%)", u.percentChange)
+	}
+	return line
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// tickerValidationTimeout bounds the startup check of each configured symbol
+const tickerValidationTimeout = 30 * time.Second
+
+// tickerValidationWorkers is how many configured symbols are checked at once
+const tickerValidationWorkers = 4
+
+// refreshWatchlist reloads the monitored symbols, the global watchlist plus every chat's own and the symbols of
+// price targets, so changes apply on the next scheduler run
+func refreshWatchlist(db *services.Database) {
+	symbols, err := db.GetWatchlist(models.DefaultTickers)
+	if err != nil {
+		slog.Error("Error loading watchlist, keeping the current symbols", "count", len(models.Tickers()), "error", err)
+		return
+	}
+
+	// Symbols that chats follow on their own watchlists are monitored too
+	users, err := db.ListUsers()
+	if err != nil {
+		slog.Error("Error loading chat watchlists", "error", err)
+	}
+	for _, user := range users {
+		for _, symbol := range user.Watchlist {
+			if !slices.Contains(symbols, symbol) {
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+
+	// Price targets are checked against the monitored prices, so their symbols are monitored too
+	targets, err := db.ListPriceTargets("")
+	if err != nil {
+		slog.Error("Error loading price target symbols", "error", err)
+	}
+	for _, target := range targets {
+		if !slices.Contains(symbols, target.Symbol) {
+			symbols = append(symbols, target.Symbol)
+		}
+	}
+
+	if len(symbols) == 0 || slices.Equal(symbols, models.Tickers()) {
+		return
+	}
+
+	models.SetTickers(symbols)
+	slog.Info("Watchlist loaded", "count", len(symbols))
+	publishDiscovery()
+}
+
+// validateTickers checks configured symbols against the price source and drops the ones it cannot find.
+// Symbols that fail for other reasons, e.g. a network error, are kept so a provider outage at startup doesn't empty the list
+func validateTickers(ctx context.Context, symbols []string) (valid, notFound []string) {
+	found := make([]bool, len(symbols))
+	sem := make(chan struct{}, tickerValidationWorkers)
+	var wg sync.WaitGroup
+	for i, symbol := range symbols {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			fetchCtx, cancel := context.WithTimeout(ctx, tickerValidationTimeout)
+			_, err := priceSource.Fetch(fetchCtx, symbol)
+			cancel()
+
+			switch {
+			case err == nil:
+				found[i] = true
+			case errors.Is(err, services.ErrElementNotFound):
+				slog.Warn("Configured ticker not found, skipping", "symbol", symbol, "source", priceSource.Name())
+			default:
+				slog.Warn("Could not validate configured ticker, keeping it", "symbol", symbol, "error", err)
+				found[i] = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, symbol := range symbols {
+		if found[i] {
+			valid = append(valid, symbol)
+		} else {
+			notFound = append(notFound, symbol)
+		}
+	}
+	slog.Info("Validated configured tickers", "valid", len(valid), "configured", len(symbols))
+	return valid, notFound
+}
+
+// mergeConfiguredTickers adds the configured symbols the price source confirms to the stored watchlist, and takes
+// out the ones it cannot find, which an empty watchlist was seeded with before they were checked
+func mergeConfiguredTickers(ctx context.Context, db *services.Database, symbols []string) {
+	valid, notFound := validateTickers(ctx, symbols)
+	for _, symbol := range valid {
+		if _, err := db.AddToWatchlist(symbol, "config"); err != nil {
+			slog.Error("Error adding to the watchlist", "symbol", symbol, "error", err)
+		}
+	}
+	for _, symbol := range notFound {
+		if _, err := db.RemoveFromWatchlist(symbol); err != nil {
+			slog.Error("Error removing from the watchlist", "symbol", symbol, "error", err)
+		}
+	}
+	refreshWatchlist(db)
+}
+
+// handleAdd adds a symbol to the monitored watchlist
+func (h *commandHandlers) handleAdd(ctx context.Context, cmd services.BotCommand) error {
+	if len(cmd.Args) == 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /add SYMBOL"))
+	}
+
+	symbol, ok := h.resolveSymbol(ctx, cmd.Args[0])
+	if !ok {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Invalid symbol: %s", cmd.Args[0]))
+	}
+
+	added, err := h.db.AddToWatchlist(symbol, cmd.UserID)
+	if err != nil {
+		return fmt.Errorf("could not add %s: %w", symbol, err)
+	}
+	if !added {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("%s is already on the watchlist.", symbol))
+	}
+
+	slog.Info("Added to the watchlist", "symbol", symbol, "user", cmd.UserID)
+	return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("✅ Added %s to the watchlist; it is checked from the next scheduled run.", symbol))
+}
+
+// handleRemove removes a symbol from the monitored watchlist
+func (h *commandHandlers) handleRemove(ctx context.Context, cmd services.BotCommand) error {
+	if len(cmd.Args) == 0 {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Text("Usage: /remove SYMBOL"))
+	}
+
+	symbol, ok := h.resolveSymbol(ctx, cmd.Args[0])
+	if !ok {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("Invalid symbol: %s", cmd.Args[0]))
+	}
+
+	removed, err := h.db.RemoveFromWatchlist(symbol)
+	if err != nil {
+		return fmt.Errorf("could not remove %s: %w", symbol, err)
+	}
+	if !removed {
+		return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("%s is not on the watchlist.", symbol))
+	}
+
+	slog.Info("Removed from the watchlist", "symbol", symbol, "user", cmd.UserID)
+	return h.bot.Reply(ctx, cmd.ChatID, locale.Sprintf("🗑 Removed %s from the watchlist from the next scheduled run.", symbol))
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// Weekly report constants
+const (
+	weeklyReportDay              = time.Saturday // Sent on the morning after the Friday US close
+	shortInterestReleaseDays     = 14            // Short interest is published twice a month
+	shortInterestChangeThreshold = 10.0          // Percent change in short interest worth reporting
+)
+
+// Date of the last weekly report, to avoid sending it twice on the same day
+var lastWeeklyReportDate string
+
+// Shared short interest client
+var shortInterestFetcher = services.NewShortInterestFetcher()
+
+// sendWeeklyReport assembles the weekly summary sections and delivers them to subscribers
+func sendWeeklyReport(ctx context.Context, db *services.Database, delivery *services.Delivery) {
+	slog.Info("Building weekly report")
+
+	from := time.Now().AddDate(0, 0, -7)
+	closes := loadStoredCloses(db, symbolHealth.active(models.Tickers()), summaryHistoryDays)
+
+	// Dividends and short interest are shared; the performance section is built per chat
+	var shared []string
+	if section := dividendsSection(ctx, db, time.Now()); section != "" {
+		shared = append(shared, section)
+	}
+	if section := shortInterestSection(db); section != "" {
+		shared = append(shared, section)
+	}
+
+	if len(shared) == 0 && performanceSection(closes, from, locale.Text("Week-over-Week")) == "" {
+		slog.Info("Weekly report has no content, skipping")
+		return
+	}
+
+	if _, err := delivery.Deliver(models.KindWeeklyReport, func(m services.Messenger, user models.User) error {
+		sections := shared
+		if section := performanceSection(watchedCloses(closes, user), from, locale.Text("Week-over-Week")); section != "" {
+			sections = append([]string{section}, shared...)
+		}
+		if len(sections) == 0 {
+			return nil
+		}
+		return m.SendText(locale.Text("🗓 Weekly Summary")+"\n\n"+strings.Join(sections, "\n\n"), nil)
+	}); err != nil {
+		slog.Error("Error sending weekly report", "error", err)
+		return
+	}
+	slog.Info("Weekly report sent successfully")
+}
+
+// ingestShortInterest fetches short interest for watched symbols whose next biweekly release is due
+func ingestShortInterest(ctx context.Context, db *services.Database) {
+	for _, symbol := range models.Tickers() {
+		// Short interest is only published for US listings, share classes such as BRK-B included
+		if _, ok := models.ShortInterestSymbol(symbol); !ok {
+			continue
+		}
+
+		latest, err := db.GetShortInterestHistory(symbol, 1)
+		if err != nil {
+			slog.Error("Error reading short interest", "symbol", symbol, "error", err)
+			continue
+		}
+		if len(latest) > 0 && time.Since(latest[0].SettlementDate) < shortInterestReleaseDays*24*time.Hour {
+			continue
+		}
+
+		records, err := shortInterestFetcher.Fetch(ctx, symbol)
+		if err != nil {
+			slog.Error("Error fetching short interest", "symbol", symbol, "error", err)
+			continue
+		}
+		if _, err := db.SaveShortInterest(records); err != nil {
+			slog.Error("Error saving short interest", "symbol", symbol, "error", err)
+		}
+	}
+}
+
+// shortInterestSection lists symbols whose short interest changed notably since the prior release
+func shortInterestSection(db *services.Database) string {
+	var lines []string
+	for _, symbol := range models.Tickers() {
+		history, err := db.GetShortInterestHistory(symbol, 2)
+		if err != nil || len(history) < 2 || history[1].ShortInterest == 0 {
+			continue
+		}
+
+		current, previous := history[0], history[1]
+		change := float64(current.ShortInterest-previous.ShortInterest) / float64(previous.ShortInterest) * 100
+		if math.Abs(change) < shortInterestChangeThreshold {
+			continue
+		}
+
+		lines = append(lines, locale.Sprintf("%s: %s shares short (%+.1f%%), %.1f days to cover (as of %s)",
+			symbol,
+			formatVolume(current.ShortInterest),
+			change,
+			current.DaysToCover,
+			current.SettlementDate.Format(locale.Text("Jan 2")),
+		))
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return locale.Text("📉 Short Interest Changes") + "\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"stock-bot/models"
+	"stock-bot/services"
+)
+
+// yearRangeCoverage is how far back stored closes must reach before a 52-week range is trusted;
+// shorter histories are completed from the history source first
+const yearRangeCoverage = 358 * 24 * time.Hour
+
+// Rolling 52-week ranges by symbol, restored from MongoDB on startup and checked by every realtime update
+var (
+	yearRangesMu sync.Mutex
+	yearRanges   = make(map[string]models.YearRange)
+)
+
+// yearExtreme is a new 52-week high or low and the extreme it broke
+type yearExtreme struct {
+	symbol  string
+	high    bool
+	price   float64
+	prior   float64
+	priorAt time.Time
+}
+
+// loadYearRanges restores the stored 52-week ranges
+func loadYearRanges(db *services.Database) {
+	ranges, err := db.GetYearRanges()
+	if err != nil {
+		slog.Error("Error loading 52-week ranges", "error", err)
+		return
+	}
+
+	yearRangesMu.Lock()
+	defer yearRangesMu.Unlock()
+	yearRanges = ranges
+}
+
+// refreshYearRanges recomputes each active symbol's 52-week range from its stored closes, so extremes
+// older than a year roll off
+func refreshYearRanges(ctx context.Context, db *services.Database) {
+	now := time.Now()
+	from := now.AddDate(0, 0, -7*models.YearWeeks)
+	history := services.NewHistoryFetcher()
+
+	symbols := symbolHealth.active(models.Tickers())
+	closes := loadStoredCloses(db, symbols, 7*models.YearWeeks)
+	for _, symbol := range symbols {
+		points := closes[symbol]
+
+		// A range over a partial year would make every close look like an extreme
+		if len(points) == 0 || now.Sub(points[0].Timestamp) < yearRangeCoverage {
+			fetchCtx, cancel := context.WithTimeout(ctx, commandFetchTimeout)
+			fetched, err := history.FetchDailyCloses(fetchCtx, symbol, from, now)
+			cancel()
+			if err != nil {
+				slog.Error("Error fetching a year of closes", "symbol", symbol, "error", err)
+				continue
+			}
+			if _, err := db.SaveHistoricalCloses(symbol, models.CompletedCloses(symbol, fetched, now)); err != nil {
+				slog.Error("Error saving closes", "symbol", symbol, "error", err)
+			}
+			points = fetched
+		}
+
+		r, ok := models.YearRangeOf(symbol, points)
+		if !ok {
+			continue
+		}
+
+		yearRangesMu.Lock()
+		previous := yearRanges[symbol]
+		r.HighAlerted, r.LowAlerted = previous.HighAlerted, previous.LowAlerted
+		yearRanges[symbol] = r
+		yearRangesMu.Unlock()
+
+		if err := db.SaveYearRange(r); err != nil {
+			slog.Error("Error saving 52-week range", "symbol", symbol, "error", err)
+		}
+	}
+}
+
+// checkYearExtremes alerts when a price breaks its symbol's 52-week high or low, once per symbol and
+// direction a day, and moves the range along with the price
+func checkYearExtremes(db *services.Database, delivery *services.Delivery, quotes map[string]models.Quote) {
+	now := time.Now()
+	today := now.Format("2006-01-02")
+
+	var extremes []yearExtreme
+	var changed []models.YearRange
+	yearRangesMu.Lock()
+	for symbol, quote := range quotes {
+		r, ok := yearRanges[symbol]
+		if !ok {
+			continue
+		}
+		price := quote.Price
+
+		switch {
+		case price > r.High:
+			if r.HighAlerted != today {
+				extremes = append(extremes, yearExtreme{symbol: symbol, high: true, price: price, prior: r.High, priorAt: r.HighAt})
+				r.HighAlerted = today
+			}
+			r.High, r.HighAt = price, now
+		case price < r.Low:
+			if r.LowAlerted != today {
+				extremes = append(extremes, yearExtreme{symbol: symbol, price: price, prior: r.Low, priorAt: r.LowAt})
+				r.LowAlerted = today
+			}
+			r.Low, r.LowAt = price, now
+		default:
+			continue
+		}
+		yearRanges[symbol] = r
+		changed = append(changed, r)
+	}
+	yearRangesMu.Unlock()
+
+	for _, r := range changed {
+		if err := db.SaveYearRange(r); err != nil {
+			slog.Error("Error saving 52-week range", "symbol", r.Symbol, "error", err)
+		}
+	}
+	if len(extremes) == 0 {
+		return
+	}
+
+	_, err := delivery.Deliver(models.KindAlert, func(m services.Messenger, user models.User) error {
+		var lines []string
+		for _, extreme := range extremes {
+			if user.Watches(extreme.symbol) && !user.IsMuted(extreme.symbol, now) {
+				lines = append(lines, formatYearExtreme(extreme))
+			}
+		}
+		if len(lines) == 0 {
+			return nil
+		}
+		return m.SendText(locale.Text("📐 52-Week Extremes")+"\n\n"+strings.Join(lines, "\n"), nil)
+	})
+	if err != nil {
+		slog.Error("Error sending 52-week extreme alerts", "error", err)
+		return
+	}
+	slog.Info("52-week extreme alerts sent", "count", len(extremes))
+}
+
+// formatYearExtreme renders a new extreme with its distance from the prior one
+func formatYearExtreme(extreme yearExtreme) string {
+	format := "🚀 %s new 52-week high at %s, %.2f%% above the prior high of %s (%s)"
+	distance := (extreme.price - extreme.prior) / extreme.prior * 100
+	if !extreme.high {
+		format = "🕳 %s new 52-week low at %s, %.2f%% below the prior low of %s (%s)"
+		distance = -distance
+	}
+	return locale.Sprintf(format, extreme.symbol, models.FormatPrice(extreme.symbol, extreme.price), distance,
+		models.FormatPrice(extreme.symbol, extreme.prior), extreme.priorAt.Format(locale.Text("Jan 2, 2006")))
+}